@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lance13c/tod/internal/browser"
+	"github.com/lance13c/tod/internal/llm"
+	"github.com/lance13c/tod/internal/testing"
+	"github.com/lance13c/tod/internal/ui/views"
+	"github.com/spf13/cobra"
+)
+
+var discoverPageCmd = &cobra.Command{
+	Use:   "discover (--file <page.html> | --url <url>)",
+	Short: "Discover the actions on a page from a saved HTML file or a URL",
+	Long: `Discover the user actions on a page and optionally generate tests for them.
+
+With --file the page is read from saved HTML without launching Chrome. Its
+selectors come from the file alone and can't be checked against a live page,
+so they are reported as unverified. With --url the page is loaded in headless
+Chrome. Without an AI provider the page's interactive elements are listed
+instead.
+
+Actions are checked against the tests in testing.test_dir. With --json the
+actions are printed to stdout as a JSON array and everything else goes to
+stderr, and --threshold fails the command when more actions than that are
+untested, for use in CI.`,
+	Example: `  tod discover --file page.html
+  tod discover --file page.html --generate > tests/e2e/page.spec.ts
+  tod discover --url https://staging.example.com/login --json --threshold 0 > actions.json`,
+	Args: cobra.NoArgs,
+	RunE: runDiscoverPage,
+}
+
+var (
+	discoverFile      string
+	discoverURL       string
+	discoverGenerate  bool
+	discoverJSON      bool
+	discoverThreshold int
+)
+
+func init() {
+	rootCmd.AddCommand(discoverPageCmd)
+
+	discoverPageCmd.Flags().StringVar(&discoverFile, "file", "", "Saved HTML page to discover actions in")
+	discoverPageCmd.Flags().StringVar(&discoverURL, "url", "", "Page to load in headless Chrome and discover actions in")
+	discoverPageCmd.Flags().BoolVar(&discoverGenerate, "generate", false, "Generate tests for the discovered actions")
+	discoverPageCmd.Flags().BoolVar(&discoverJSON, "json", false, "Print the discovered actions to stdout as JSON")
+	discoverPageCmd.Flags().IntVar(&discoverThreshold, "threshold", -1, "Fail when more than this many actions are untested (-1 to never fail)")
+	discoverPageCmd.MarkFlagsOneRequired("file", "url")
+	discoverPageCmd.MarkFlagsMutuallyExclusive("file", "url")
+	discoverPageCmd.MarkFlagsMutuallyExclusive("json", "generate")
+}
+
+func runDiscoverPage(cmd *cobra.Command, args []string) error {
+	if todConfig == nil {
+		return fmt.Errorf("Tod is not initialized in this project, run 'tod init' first")
+	}
+	cfg := todConfig
+
+	var client llm.Client
+	if cfg.AI.APIKey != "" || cfg.AI.Provider == "local" || cfg.AI.Provider == "custom" {
+		var err error
+		client, err = llm.NewClient(llm.Provider(cfg.AI.Provider), cfg.AI.APIKey, cfg.AI.ClientOptions())
+		if err != nil {
+			return fmt.Errorf("failed to create %s client: %w", cfg.AI.Provider, err)
+		}
+	}
+	if client == nil && discoverGenerate {
+		return fmt.Errorf("generating tests needs an AI provider, set ai.api_key")
+	}
+
+	// Progress goes to stderr so generated tests or JSON can be redirected
+	// to a file
+	out := os.Stdout
+	if discoverGenerate || discoverJSON {
+		out = os.Stderr
+	}
+
+	existingTests, err := testing.LoadExistingTests(cfg.Testing.TestDir, cfg.Testing.Pattern)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Couldn't read existing tests, treating every action as untested: %v\n", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	discovery := testing.NewActionDiscovery(client, ".")
+	discovery.FragileThreshold = cfg.Testing.FragileSelectorThreshold
+	discovery.Retries = cfg.AI.DiscoveryRetries
+	discovery.OnRetry = func(retry, retries int, err error) {
+		fmt.Fprintf(out, "🔄 Retrying analysis (%d/%d) after: %v\n", retry, retries, err)
+	}
+
+	source := discoverFile
+	var actions []testing.DiscoveredAction
+	if discoverURL != "" {
+		source = discoverURL
+		fmt.Fprintf(out, "Loading %s in headless Chrome...\n", discoverURL)
+		manager, err := browser.NewChromeDPManager("", views.BrowserLaunchOptions(cfg, true))
+		if err != nil {
+			return fmt.Errorf("failed to launch Chrome: %w", err)
+		}
+		defer manager.Close()
+		discovery.Browser = manager
+		actions, err = discovery.DiscoverActionsFromURL(ctx, discoverURL, existingTests)
+		if err != nil {
+			return err
+		}
+	} else {
+		actions, err = discovery.DiscoverActionsFromFile(ctx, discoverFile, existingTests)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Partially covered actions count as untested, as they do for IsTested
+	coverage := discovery.CoverageReport(actions, existingTests)
+	untested := coverage.Partial + coverage.Untested
+
+	if discoverJSON {
+		if actions == nil {
+			actions = []testing.DiscoveredAction{}
+		}
+		data, err := json.MarshalIndent(actions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode actions: %w", err)
+		}
+		fmt.Println(string(data))
+		fmt.Fprintf(out, "Discovered %d actions in %s: %s.\n", len(actions), source, coverageSummary(coverage))
+		return checkUntestedThreshold(untested)
+	}
+
+	if len(actions) == 0 {
+		fmt.Fprintf(out, "No actions found in %s.\n", source)
+		return nil
+	}
+	fmt.Fprintf(out, "Discovered %d actions in %s: %s\n\n", len(actions), source, coverageSummary(coverage))
+	for i, action := range actions {
+		covered := coverage.Actions[i]
+		mark := " "
+		switch covered.Status {
+		case testing.CoverageCovered:
+			mark = "✓"
+		case testing.CoveragePartial:
+			mark = "~"
+		}
+		fmt.Fprintf(out, "  %s %d. [%s] %s\n", mark, i+1, action.Priority, action.Description)
+		if covered.Status == testing.CoveragePartial {
+			fmt.Fprintf(out, "       partly tested (%.0f%%): %s\n", covered.Score*100, covered.Reason)
+		}
+		if action.Selector == "" {
+			continue
+		}
+		fmt.Fprintf(out, "       %s\n", action.Selector)
+		if browser.IsFragileSelector(action.Selector, cfg.Testing.FragileSelectorThreshold) {
+			_, reasons := browser.ScoreSelectorStability(action.Selector)
+			fmt.Fprintf(out, "       ⚠ fragile: %s\n", strings.Join(reasons, "; "))
+		}
+	}
+	fmt.Fprintln(out)
+	if discoverFile != "" {
+		fmt.Fprintln(out, "⚠️  Selectors are unverified: they come from the saved HTML and weren't checked against a live page.")
+	}
+
+	if discoverGenerate {
+		code, err := discovery.GenerateTestSuggestions(ctx, actions, cfg.Testing.Framework, cfg.Testing.Style)
+		if err != nil {
+			return err
+		}
+		fmt.Println(code)
+	}
+	return checkUntestedThreshold(untested)
+}
+
+// coverageSummary counts the actions in each coverage status
+func coverageSummary(report testing.CoverageReport) string {
+	return fmt.Sprintf("%d covered, %d partly tested, %d untested", report.Covered, report.Partial, report.Untested)
+}
+
+// checkUntestedThreshold fails when more actions are untested than
+// --threshold allows
+func checkUntestedThreshold(untested int) error {
+	if discoverThreshold >= 0 && untested > discoverThreshold {
+		return fmt.Errorf("%d untested actions, more than the threshold of %d", untested, discoverThreshold)
+	}
+	return nil
+}
@@ -327,7 +327,7 @@ func runSpecificFlow(cmd *cobra.Command, args []string) {
 	// Set variables if provided
 	flowContext := &core.FlowContext{
 		Environment: todConfig.Current,
-		BaseURL:     todConfig.GetCurrentEnv().BaseURL,
+		BaseURL:     todConfig.GetCurrentEnv().HomeURL(),
 		Config:      todConfig,
 		Variables:   vars,
 	}
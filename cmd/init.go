@@ -321,6 +321,12 @@ func createNonInteractiveConfig(cmd *cobra.Command, cwd string, existingConfig *
 		detectedFramework, err := detector.DetectFramework()
 		if err == nil && detectedFramework != nil {
 			fmt.Printf("Detected: %s v%s (%s)\n", detectedFramework.DisplayName, detectedFramework.Version, detectedFramework.Language)
+			if detected, err := testing.DetectProjectFramework(cwd); err == nil && len(detected.Candidates) > 1 {
+				fmt.Println("Other frameworks found:")
+				for _, c := range detected.Candidates[1:] {
+					fmt.Printf("  - %s (%s, %s, %.0f%% confidence)\n", c.DisplayName, c.Kind, c.Dir, c.Confidence*100)
+				}
+			}
 			framework = detectedFramework.Name
 			if language == "typescript" { // Only override if still default
 				language = detectedFramework.Language
@@ -335,9 +341,10 @@ func createNonInteractiveConfig(cmd *cobra.Command, cwd string, existingConfig *
 	now := time.Now()
 	newConfig := &config.Config{
 		AI: config.AIConfig{
-			Provider: selectedModel.Provider,
-			APIKey:   aiKey,
-			Model:    selectedModel.ModelName,
+			Provider:         selectedModel.Provider,
+			APIKey:           aiKey,
+			Model:            selectedModel.ModelName,
+			DiscoveryRetries: config.DefaultDiscoveryRetries,
 		},
 		Testing: config.TestingConfig{
 			Framework: framework,
@@ -348,8 +355,9 @@ func createNonInteractiveConfig(cmd *cobra.Command, cwd string, existingConfig *
 			Pattern:   getStringOrDefault(baseConfig.Testing.Pattern, "*.spec.ts"),
 			Template:  baseConfig.Testing.Template,
 		},
-		Envs:    make(map[string]config.EnvConfig),
-		Current: "development",
+		Envs:     make(map[string]config.EnvConfig),
+		Current:  "development",
+		Matching: config.DefaultMatchingConfig(),
 		Meta: config.MetaConfig{
 			Version:   "1.0.0",
 			CreatedAt: now,
@@ -363,6 +371,9 @@ func createNonInteractiveConfig(cmd *cobra.Command, cwd string, existingConfig *
 			newConfig.Envs[name] = env
 		}
 		newConfig.Current = existingConfig.Current
+		newConfig.Matching = existingConfig.Matching
+		newConfig.Session = existingConfig.Session
+		newConfig.Safety = existingConfig.Safety
 		// Copy AI settings that weren't overridden
 		if newConfig.AI.Endpoint == "" {
 			newConfig.AI.Endpoint = existingConfig.AI.Endpoint
@@ -389,6 +400,7 @@ func createNonInteractiveConfig(cmd *cobra.Command, cwd string, existingConfig *
 		currentEnv.Headers = existingEnv.Headers
 		currentEnv.Auth = existingEnv.Auth
 		currentEnv.Cookies = existingEnv.Cookies
+		currentEnv.BasePath = existingEnv.BasePath
 	}
 
 	newConfig.Envs[currentEnvName] = currentEnv
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lance13c/tod/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models [filter]",
+	Short: "List the models available from the configured AI provider",
+	Long: `List the model names the configured AI provider accepts for ai.model,
+with context window and price per 1M tokens where known. This only queries
+the provider's model list, so it costs nothing.`,
+	Example: `  tod models
+  tod models sonnet`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runModels,
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+}
+
+func runModels(cmd *cobra.Command, args []string) error {
+	if todConfig == nil {
+		return fmt.Errorf("Tod is not initialized in this project, run 'tod init' first")
+	}
+	cfg := todConfig
+
+	options := cfg.AI.ClientOptions()
+	// The point is to find a valid model, so don't reject the configured one
+	options["allow_unknown_models"] = true
+	if cfg.AI.Endpoint != "" {
+		options["endpoint"] = cfg.AI.Endpoint
+	}
+
+	client, err := llm.NewClient(llm.Provider(cfg.AI.Provider), cfg.AI.APIKey, options)
+	if err != nil {
+		return fmt.Errorf("failed to create %s client: %w", cfg.AI.Provider, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	models, err := client.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		filter := strings.ToLower(args[0])
+		var matched []llm.ModelInfo
+		for _, model := range models {
+			if strings.Contains(strings.ToLower(model.ID), filter) || strings.Contains(strings.ToLower(model.Name), filter) {
+				matched = append(matched, model)
+			}
+		}
+		models = matched
+	}
+	if len(models) == 0 {
+		fmt.Printf("No %s models found.\n", cfg.AI.Provider)
+		return nil
+	}
+
+	fmt.Printf("%s models (* is ai.model):\n\n", cfg.AI.Provider)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  MODEL\tCONTEXT\tINPUT/1M\tOUTPUT/1M")
+	for _, model := range models {
+		marker := " "
+		if model.ID == cfg.AI.Model {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\n", marker, model.ID,
+			formatContextWindow(model.ContextWindow), formatModelPrice(model.InputCost), formatModelPrice(model.OutputCost))
+	}
+	return w.Flush()
+}
+
+// formatContextWindow formats a context window in tokens, "-" when unknown
+func formatContextWindow(tokens int) string {
+	if tokens == 0 {
+		return "-"
+	}
+	return llm.FormatTokens(int64(tokens))
+}
+
+// formatModelPrice formats a price per 1M tokens, "-" when unknown
+func formatModelPrice(price float64) string {
+	if price == 0 {
+		return "-"
+	}
+	return llm.FormatCost(price)
+}
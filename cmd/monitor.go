@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	"github.com/lance13c/tod/internal/browser"
@@ -32,7 +33,8 @@ and automatically navigates the connected Chrome DevTools browser to those links
 This is useful for testing authentication flows that use magic links.
 
 Prerequisites:
-  1. Chrome must be running with debugging enabled (port 9222)
+  1. Chrome must be running with debugging enabled (browser.debug_port,
+     9222 by default)
   2. IMAP credentials must be configured in .tod/config.yaml or environment variables:
      
      Config file (.tod/config.yaml):
@@ -68,7 +70,7 @@ func init() {
 	monitorCmd.AddCommand(emailMonitorCmd)
 	
 	// Email monitor flags
-	emailMonitorCmd.Flags().String("chrome-port", "9222", "Chrome DevTools debugging port")
+	emailMonitorCmd.Flags().String("chrome-port", strconv.Itoa(browser.DefaultDebugPort), "Chrome DevTools debugging port (defaults to browser.debug_port)")
 	emailMonitorCmd.Flags().String("chrome-host", "localhost", "Chrome DevTools host")
 	emailMonitorCmd.Flags().Int("poll-interval", 5, "Email polling interval in seconds")
 	emailMonitorCmd.Flags().Bool("auto-nav", true, "Automatically navigate Chrome to detected magic links")
@@ -78,6 +80,9 @@ func runEmailMonitor(cmd *cobra.Command, args []string) {
 	// Get Chrome connection info
 	chromeHost, _ := cmd.Flags().GetString("chrome-host")
 	chromePort, _ := cmd.Flags().GetString("chrome-port")
+	if !cmd.Flags().Changed("chrome-port") && todConfig != nil && todConfig.Browser.DebugPort > 0 {
+		chromePort = strconv.Itoa(todConfig.Browser.DebugPort)
+	}
 	projectDir, _ := cmd.Flags().GetString("project")
 	if projectDir == "" {
 		projectDir = "."
@@ -108,8 +113,10 @@ func runEmailMonitor(cmd *cobra.Command, args []string) {
 		fmt.Println("    imap_host: imap.fastmail.com")
 		fmt.Println("    imap_port: 993")
 		fmt.Println("    imap_user: your-email@example.com")
-		fmt.Println("    imap_pass: your-app-password")
+		fmt.Println("    imap_app_password: your-app-password")
 		fmt.Println("    imap_secure: true")
+		fmt.Println("    imap_from: noreply@your-app.com   # optional sender filter")
+		fmt.Println("    imap_subject: Sign in            # optional subject filter")
 		fmt.Println("\nOr set environment variables:")
 		fmt.Println("  - IMAP_USER: Your email username")
 		fmt.Println("  - IMAP_PASS: Your email password")
@@ -129,7 +136,7 @@ func runEmailMonitor(cmd *cobra.Command, args []string) {
 		if err != nil {
 			fmt.Printf("⚠️ Chrome DevTools not available. Magic links will be logged but not navigated: %v\n", err)
 			fmt.Println("\nTo enable auto-navigation, start Chrome with debugging:")
-			fmt.Println("  /Applications/Google\\ Chrome.app/Contents/MacOS/Google\\ Chrome --remote-debugging-port=9222")
+			fmt.Printf("  /Applications/Google\\ Chrome.app/Contents/MacOS/Google\\ Chrome --remote-debugging-port=%s\n", chromePort)
 			autoNav = false
 		} else {
 			fmt.Printf("✅ Connected to Chrome DevTools at %s:%s\n", chromeHost, chromePort)
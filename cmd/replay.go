@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lance13c/tod/internal/ui/views"
+	"github.com/spf13/cobra"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <session>",
+	Short: "Re-run a saved session as a regression check",
+	Long: `Load a session saved with "save session", reconnect Chrome and re-issue
+each recorded step through the same path typed input takes, reporting whether
+each step still passes.
+
+Steps that failed when recorded are skipped, as are steps marked skippable,
+like waiting for a magic link. Set "skippable": true on a step in the session
+file, or use --skip, to skip others. Replay stops at the first failure unless
+--continue is passed.`,
+	Example: `  tod replay 20250101-120000
+  tod replay .tod/sessions/session-20250101-120000.json --continue
+  tod replay 20250101-120000 --skip 3 --skip "Sign in"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+var (
+	replayContinue bool
+	replaySkip     []string
+	replayHeadless bool
+)
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().BoolVar(&replayContinue, "continue", false, "Keep going after a failed step")
+	replayCmd.Flags().StringArrayVar(&replaySkip, "skip", nil, "Skip a step by number or by text in its target (repeatable)")
+	replayCmd.Flags().BoolVar(&replayHeadless, "headless", false, "Run Chrome headless regardless of browser.headless")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	if todConfig == nil {
+		return fmt.Errorf("Tod is not initialized in this project, run 'tod init' first")
+	}
+
+	session, err := views.LoadSavedSession(args[0])
+	if err != nil {
+		return err
+	}
+	if len(session.Actions) == 0 {
+		fmt.Println("Session has no recorded steps to replay.")
+		return nil
+	}
+
+	if replayHeadless {
+		todConfig.Browser.Headless = true
+	}
+
+	fmt.Printf("┌─ Replaying session %s (%d steps) ─┐\n", session.ID, len(session.Actions))
+	fmt.Println()
+
+	v := views.NewNavigationView(todConfig)
+	defer v.Cleanup()
+
+	results, err := v.Replay(session, views.ReplayOptions{
+		ContinueOnFailure: replayContinue,
+		Skip:              replaySkipMatcher(replaySkip),
+		OnResult:          printReplayResult,
+	})
+	if err != nil {
+		return err
+	}
+
+	var passed, failed, skipped int
+	for _, result := range results {
+		switch result.Status {
+		case views.ReplayPassed:
+			passed++
+		case views.ReplayFailed:
+			failed++
+		case views.ReplaySkipped:
+			skipped++
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("%d passed, %d failed, %d skipped", passed, failed, skipped)
+	if notRun := len(session.Actions) - len(results); notRun > 0 {
+		fmt.Printf(", %d not run", notRun)
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d steps failed", failed, len(session.Actions))
+	}
+	return nil
+}
+
+// printReplayResult prints one step's outcome as it finishes
+func printReplayResult(number int, result views.ReplayResult) {
+	step := result.Step
+	label := fmt.Sprintf("%d. %s %q", number, step.Verb, step.Target)
+
+	switch result.Status {
+	case views.ReplayPassed:
+		fmt.Printf("  ✓ %s\n", label)
+	case views.ReplayFailed:
+		fmt.Printf("  ✗ %s\n      %s\n", label, result.Detail)
+	default:
+		fmt.Printf("  ⏭ %s (%s)\n", label, result.Detail)
+	}
+}
+
+// replaySkipMatcher returns whether a step is selected by one of the --skip
+// values, a step number or text in the step's target
+func replaySkipMatcher(values []string) func(int, views.ExecutedStep) bool {
+	if len(values) == 0 {
+		return nil
+	}
+	return func(number int, step views.ExecutedStep) bool {
+		for _, value := range values {
+			if n, err := strconv.Atoi(value); err == nil {
+				if n == number {
+					return true
+				}
+				continue
+			}
+			if strings.Contains(strings.ToLower(step.Target), strings.ToLower(value)) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -44,9 +44,12 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .tod/config.yaml)")
 	rootCmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
+	rootCmd.PersistentFlags().String("log-level", "info", "level of messages written to .tod/logs/tod.log: debug, info, warn or error")
 	rootCmd.PersistentFlags().StringP("env", "e", "", "environment to use")
 	rootCmd.PersistentFlags().StringP("project", "p", ".", "project directory")
 	rootCmd.Flags().BoolP("version", "v", false, "show version information")
+	rootCmd.Flags().Bool("plan-only", false, "analyze pages and plan actions without executing anything")
+	rootCmd.Flags().Bool("dry-run", false, "show what browser actions would do without running them")
 }
 
 // initConfig reads in config file and ENV variables.
@@ -67,9 +70,15 @@ func initConfig() {
 		logging.Info("Tod logging initialized. Log file: %s", logging.GetLogger().GetLogPath())
 	}
 	
-	// Set log level based on verbose flag
-	if verbose {
-		logging.GetLogger().SetLevel(logging.DEBUG)
+	// Set log level from --log-level, or debug with --verbose
+	if logLevel := rootCmd.PersistentFlags().Lookup("log-level"); logLevel.Changed {
+		level, err := logging.ParseLevel(logLevel.Value.String())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		logging.SetLevel(level)
+	} else if verbose {
+		logging.SetLevel(logging.DEBUG)
 	}
 	
 	loader := config.NewLoader(projectDir)
@@ -156,6 +165,16 @@ func runTUI(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if planOnly, _ := cmd.Flags().GetBool("plan-only"); planOnly {
+		todConfig.PlanOnly = true
+		logging.Info("Plan-only mode: actions will be simulated, not executed")
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		todConfig.DryRun = true
+		logging.Info("Dry-run mode: browser actions will be logged, not performed")
+	}
+
 	if verbose {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Pre-TUI checks completed in %v\n", time.Since(startTime))
 	}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lance13c/tod/internal/database"
+	"github.com/lance13c/tod/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show captures, actions and LLM calls by day and domain",
+	Long: `Show what Tod recorded in this project's database over a period:
+pages captured, actions discovered and LLM interactions, broken down by
+day and by the domain of the captured pages.`,
+	Example: `  tod stats              # the last 7 days
+  tod stats --days 30
+  tod stats --from 2025-01-01 --to 2025-01-31`,
+	RunE: runStats,
+}
+
+var (
+	statsDays int
+	statsFrom string
+	statsTo   string
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().IntVar(&statsDays, "days", 7, "Number of days to show, ending today")
+	statsCmd.Flags().StringVar(&statsFrom, "from", "", "First day to show (YYYY-MM-DD), overrides --days")
+	statsCmd.Flags().StringVar(&statsTo, "to", "", "Last day to show (YYYY-MM-DD), defaults to today")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	from, to, err := statsPeriod()
+	if err != nil {
+		return err
+	}
+
+	projectDir, _ := cmd.Root().PersistentFlags().GetString("project")
+	db, err := database.New(database.ProjectPath(projectDir))
+	if err != nil {
+		return fmt.Errorf("failed to open project database: %w", err)
+	}
+	defer db.Close()
+
+	periods, err := db.GetStatisticsByPeriod(from, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("┌─ Activity %s to %s ─┐\n", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"))
+	fmt.Println()
+
+	if len(periods) == 0 {
+		fmt.Println("Nothing recorded in this period.")
+		return nil
+	}
+
+	fmt.Printf("%-12s %-30s %8s %8s %8s %12s\n", "Date", "Domain", "Captures", "Actions", "LLM", "LLM Cost")
+	fmt.Println("─────────────────────────────────────────────────────────────────────────────────────")
+
+	var total database.PeriodStats
+	for _, period := range periods {
+		domain := period.Domain
+		if domain == "" {
+			domain = "-"
+		}
+		fmt.Printf("%-12s %-30.30s %8d %8d %8d %12s\n",
+			period.Day,
+			domain,
+			period.Captures,
+			period.Actions,
+			period.LLMInteractions,
+			llm.FormatCost(period.LLMCost))
+
+		total.Captures += period.Captures
+		total.Actions += period.Actions
+		total.LLMInteractions += period.LLMInteractions
+		total.LLMCost += period.LLMCost
+	}
+
+	fmt.Println("─────────────────────────────────────────────────────────────────────────────────────")
+	fmt.Printf("%-12s %-30s %8d %8d %8d %12s\n", "Total", "",
+		total.Captures, total.Actions, total.LLMInteractions, llm.FormatCost(total.LLMCost))
+	return nil
+}
+
+// statsPeriod returns the start of the first day and the end of the last day
+// selected by the flags
+func statsPeriod() (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	to := today
+	if statsTo != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", statsTo, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to date %q, expected YYYY-MM-DD", statsTo)
+		}
+		to = parsed
+	}
+
+	if statsDays < 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("--days must be at least 1")
+	}
+	from := to.AddDate(0, 0, 1-statsDays)
+	if statsFrom != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", statsFrom, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from date %q, expected YYYY-MM-DD", statsFrom)
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from must not be after --to")
+	}
+
+	// Include all of the last day
+	return from, to.AddDate(0, 0, 1), nil
+}
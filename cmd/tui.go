@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lance13c/tod/internal/config"
@@ -39,6 +40,10 @@ func launchTUI(todConfig *config.Config) error {
 		
 		// Clean up all resources
 		m.CleanupAllViews()
+
+		if summary := m.ExitSummary(); summary != "" {
+			fmt.Println(summary)
+		}
 		
 		if shouldRestart {
 			return ErrRestartConfig
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/lance13c/tod/internal/config"
+	"github.com/lance13c/tod/internal/database"
 	"github.com/lance13c/tod/internal/llm"
 	"github.com/spf13/cobra"
 )
@@ -26,6 +27,7 @@ var (
 	usageDaily   bool
 	usageWeekly  bool
 	usageMonthly bool
+	usageProject bool
 	usageReset   bool
 	usageExport  string
 )
@@ -36,11 +38,17 @@ func init() {
 	usageCmd.Flags().BoolVar(&usageDaily, "daily", false, "Show daily usage statistics")
 	usageCmd.Flags().BoolVar(&usageWeekly, "weekly", false, "Show weekly usage statistics")
 	usageCmd.Flags().BoolVar(&usageMonthly, "monthly", false, "Show monthly usage statistics")
+	usageCmd.Flags().BoolVar(&usageProject, "project-history", false, "Show usage recorded for this project across sessions")
 	usageCmd.Flags().BoolVar(&usageReset, "reset", false, "Reset usage statistics")
 	usageCmd.Flags().StringVar(&usageExport, "export", "", "Export usage data to file (json, csv)")
 }
 
 func runUsage(cmd *cobra.Command, args []string) error {
+	if usageProject {
+		projectDir, _ := cmd.Root().PersistentFlags().GetString("project")
+		return displayProjectUsage(projectDir)
+	}
+
 	// Load current usage data
 	usageData, err := loadUsageData()
 	if err != nil {
@@ -272,6 +280,66 @@ func displayMonthlyUsage(usage *config.UsageConfig) {
 	fmt.Printf("%-10s %8s %12s %12s\n", "Total", "-", "-", llm.FormatCost(totalCost))
 }
 
+func displayProjectUsage(projectDir string) error {
+	db, err := database.New(database.ProjectPath(projectDir))
+	if err != nil {
+		return fmt.Errorf("failed to open project database: %w", err)
+	}
+	defer db.Close()
+
+	total, err := db.GetTotalUsage()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("┌─ LLM Usage - This Project ─┐")
+	fmt.Println("└─────────────────────────────┘")
+	fmt.Println()
+
+	if total.RequestCount == 0 {
+		fmt.Println("No LLM usage recorded for this project yet.")
+		return nil
+	}
+
+	fmt.Printf("Sessions:       %d\n", total.Sessions)
+	fmt.Printf("Total Requests: %d\n", total.RequestCount)
+	fmt.Printf("Total Tokens:   %s\n", llm.FormatTokens(total.TotalTokens))
+	fmt.Printf("Total Cost:     %s\n", llm.FormatCost(total.Cost))
+	fmt.Println()
+
+	byDay, err := db.GetUsageByDay()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-12s %8s %8s %12s %12s\n", "Date", "Sessions", "Requests", "Tokens", "Cost")
+	fmt.Println("────────────────────────────────────────────────────────────")
+	for _, day := range byDay {
+		fmt.Printf("%-12s %8d %8d %12s %12s\n",
+			day.Key,
+			day.Sessions,
+			day.RequestCount,
+			llm.FormatTokens(day.TotalTokens),
+			llm.FormatCost(day.Cost))
+	}
+	fmt.Println()
+
+	byModel, err := db.GetUsageByModel()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-40s %8s %12s %12s\n", "Provider/Model", "Requests", "Tokens", "Cost")
+	fmt.Println("────────────────────────────────────────────────────────────────────────────")
+	for _, model := range byModel {
+		fmt.Printf("%-40s %8d %12s %12s\n",
+			model.Key,
+			model.RequestCount,
+			llm.FormatTokens(model.TotalTokens),
+			llm.FormatCost(model.Cost))
+	}
+
+	return nil
+}
+
 func resetUsageData() error {
 	fmt.Print("Are you sure you want to reset all usage data? This cannot be undone. (y/N): ")
 	var response string
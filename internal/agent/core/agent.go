@@ -25,9 +25,7 @@ type DefaultFlowAgent struct {
 func NewFlowAgent(cfg *config.Config, projectRoot string) (*DefaultFlowAgent, error) {
 	// Create LLM client
 	provider := llm.Provider(cfg.AI.Provider)
-	llmClient, err := llm.NewClient(provider, cfg.AI.APIKey, map[string]interface{}{
-		"model": cfg.AI.Model,
-	})
+	llmClient, err := llm.NewClient(provider, cfg.AI.APIKey, cfg.AI.ClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM client: %w", err)
 	}
@@ -107,7 +107,7 @@ func (e *FlowExecutor) Execute(ctx context.Context, flow *Flow, ui UIProvider) (
 			}
 		}
 	} else {
-		ui.ShowError(fmt.Errorf(flow.FailureMessage))
+		ui.ShowError(fmt.Errorf("%s", flow.FailureMessage))
 	}
 
 	return result, nil
@@ -181,7 +181,7 @@ func (e *FlowExecutor) executeInputStep(ctx context.Context, step *Step, ui UIPr
 		}
 
 		if !validation.Valid {
-			ui.ShowError(fmt.Errorf(validation.Message))
+			ui.ShowError(fmt.Errorf("%s", validation.Message))
 			if len(validation.Suggestions) > 0 {
 				ui.ShowMessage("Suggestions:", StyleInfo)
 				for _, suggestion := range validation.Suggestions {
@@ -0,0 +1,88 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// inputRoles are the ARIA roles implied by input types
+var inputRoles = map[string]string{
+	"button":   "button",
+	"submit":   "button",
+	"reset":    "button",
+	"image":    "button",
+	"checkbox": "checkbox",
+	"radio":    "radio",
+	"range":    "slider",
+	"number":   "spinbutton",
+	"search":   "searchbox",
+	"text":     "textbox",
+	"email":    "textbox",
+	"tel":      "textbox",
+	"url":      "textbox",
+	"password": "textbox",
+}
+
+// implicitRole returns the ARIA role implied by an element's tag, or "" if
+// it has none
+func implicitRole(elem InteractiveElement) string {
+	switch elem.Tag {
+	case "a":
+		if elem.Href != "" {
+			return "link"
+		}
+	case "button":
+		return "button"
+	case "textarea":
+		return "textbox"
+	case "select":
+		return "combobox"
+	case "input":
+		inputType := strings.ToLower(elem.Type)
+		if inputType == "" {
+			inputType = "text"
+		}
+		return inputRoles[inputType]
+	}
+	return ""
+}
+
+// accessibleName approximates an element's accessible name from static HTML:
+// aria-label, then text content, then title and placeholder. Labels
+// referenced by aria-labelledby or <label for> need the live DOM.
+func accessibleName(elem InteractiveElement) string {
+	for _, name := range []string{elem.AriaLabel, elem.Text, elem.Title, elem.Placeholder} {
+		if name = strings.Join(strings.Fields(name), " "); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// PlaywrightLocator returns the most stable Playwright locator for the
+// element: getByRole when it has a role and accessible name, otherwise its
+// CSS selector
+func (e InteractiveElement) PlaywrightLocator() string {
+	if e.Role != "" && e.AccessibleName != "" {
+		return fmt.Sprintf("page.getByRole(%s, { name: %s })", QuoteJS(e.Role), QuoteJS(e.AccessibleName))
+	}
+	return fmt.Sprintf("page.locator(%s)", QuoteJS(e.Selector))
+}
+
+// jsEscaper escapes what can't appear as is in a single-quoted JavaScript
+// string
+var jsEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\u2028", `\u2028`,
+	"\u2029", `\u2029`,
+)
+
+// QuoteJS quotes s as a single-quoted JavaScript string. Values without line
+// breaks come out as valid CSS strings too, so it quotes attribute values in
+// selectors as well.
+func QuoteJS(s string) string {
+	return "'" + jsEscaper.Replace(s) + "'"
+}
@@ -0,0 +1,141 @@
+package browser
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/chromedp"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// BasicAuth holds HTTP Basic Auth credentials answered on the browser's behalf
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// basicAuthHandler answers HTTP auth challenges through the fetch domain
+type basicAuthHandler struct {
+	mu       sync.Mutex
+	creds    *BasicAuth
+	answered map[fetch.RequestID]bool // requests given credentials and not yet answered
+}
+
+// SetBasicAuth answers HTTP Basic Auth challenges with the given credentials
+// instead of leaving the browser's auth dialog blocking the page. An empty
+// user clears the credentials.
+func (m *ChromeDPManager) SetBasicAuth(user, pass string) error {
+	if m.basicAuth == nil {
+		if user == "" {
+			return nil
+		}
+		m.basicAuth = &basicAuthHandler{answered: make(map[fetch.RequestID]bool)}
+		for _, t := range m.attachedTabs() {
+			if err := m.basicAuth.attach(t.ctx); err != nil {
+				m.basicAuth = nil
+				return err
+			}
+		}
+	}
+
+	m.basicAuth.mu.Lock()
+	defer m.basicAuth.mu.Unlock()
+	if user == "" {
+		m.basicAuth.creds = nil
+	} else {
+		m.basicAuth.creds = &BasicAuth{Username: user, Password: pass}
+	}
+	return nil
+}
+
+// attach answers auth challenges in a tab
+func (h *basicAuthHandler) attach(ctx context.Context) error {
+	chromedp.ListenTarget(ctx, h.handleEvent(ctx))
+
+	// Auth challenges only reach us for intercepted requests. Intercepting
+	// at the response stage lets every request go out unpaused; only its
+	// response waits for handleEvent to continue it.
+	enable := fetch.Enable().
+		WithHandleAuthRequests(true).
+		WithPatterns([]*fetch.RequestPattern{{URLPattern: "*", RequestStage: fetch.RequestStageResponse}})
+	return chromedp.Run(ctx, enable)
+}
+
+// handleEvent returns the fetch event listener for a tab. Commands can't be
+// sent from the listener itself, so replies are sent from their own goroutines.
+func (h *basicAuthHandler) handleEvent(ctx context.Context) func(ev interface{}) {
+	return func(ev interface{}) {
+		switch e := ev.(type) {
+		case *fetch.EventRequestPaused:
+			// The response arrived, so the request's challenge is over
+			h.mu.Lock()
+			delete(h.answered, e.RequestID)
+			h.mu.Unlock()
+			go runFromListener(ctx, fetch.ContinueResponse(e.RequestID))
+
+		case *fetch.EventAuthRequired:
+			response := &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseCancelAuth}
+
+			h.mu.Lock()
+			// Answer each request once so wrong credentials don't loop forever
+			if h.creds != nil && !h.answered[e.RequestID] {
+				h.answered[e.RequestID] = true
+				response = &fetch.AuthChallengeResponse{
+					Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+					Username: h.creds.Username,
+					Password: h.creds.Password,
+				}
+			} else if h.answered[e.RequestID] {
+				delete(h.answered, e.RequestID)
+				logging.Warn("Basic auth credentials were rejected by %s", e.AuthChallenge.Origin)
+			}
+			h.mu.Unlock()
+
+			go runFromListener(ctx, fetch.ContinueWithAuth(e.RequestID, response))
+		}
+	}
+}
+
+// runFromListener sends a command to the tab of ctx. Event listeners use it
+// from a goroutine of their own, since they can't block chromedp's event loop.
+func runFromListener(ctx context.Context, action chromedp.Action) {
+	c := chromedp.FromContext(ctx)
+	if c == nil || c.Target == nil {
+		return
+	}
+	if err := action.Do(cdp.WithExecutor(ctx, c.Target)); err != nil {
+		logging.Debug("Command from event listener failed: %v", err)
+	}
+}
+
+// splitURLCredentials removes user:pass@ from a URL, returning the clean URL
+// and the credentials, if any
+func splitURLCredentials(rawURL string) (string, *BasicAuth) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL, nil
+	}
+	pass, _ := u.User.Password()
+	creds := &BasicAuth{Username: u.User.Username(), Password: pass}
+	u.User = nil
+	return u.String(), creds
+}
+
+// StripURLCredentials removes user:pass@ from a URL so it can be shown or
+// kept in history; the credentials stay with the browser's basic auth
+func StripURLCredentials(rawURL string) string {
+	clean, _ := splitURLCredentials(rawURL)
+	return clean
+}
+
+// redactURL hides any credentials in a URL so it can be logged
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
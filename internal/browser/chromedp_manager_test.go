@@ -0,0 +1,74 @@
+package browser
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestManager starts a headless Chrome on baseURL, skipping the test
+// where Chrome isn't installed
+func newTestManager(t *testing.T, baseURL string) *ChromeDPManager {
+	t.Helper()
+	if _, err := FindChrome(); err != nil {
+		t.Skip("Chrome not installed")
+	}
+
+	manager, err := NewChromeDPManager(baseURL, LaunchOptions{Headless: true})
+	if err != nil {
+		t.Fatalf("failed to start Chrome: %v", err)
+	}
+	t.Cleanup(manager.Close)
+	return manager
+}
+
+func TestFillFieldWithTestIDSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, `<html><body>
+<input data-testid="email" value="old@example.com">
+<script>
+document.querySelector('input').addEventListener('change', () => { window.changed = true; });
+</script>
+</body></html>`)
+	}))
+	t.Cleanup(server.Close)
+
+	manager := newTestManager(t, server.URL)
+	if err := manager.Navigate(server.URL); err != nil {
+		t.Fatalf("Navigate failed: %v", err)
+	}
+
+	elements, err := manager.ExtractInteractiveElements()
+	if err != nil {
+		t.Fatalf("ExtractInteractiveElements failed: %v", err)
+	}
+	var selector string
+	for _, elem := range elements {
+		if elem.Tag == "input" {
+			selector = elem.Selector
+		}
+	}
+	if selector != "[data-testid='email']" {
+		t.Fatalf("input selector = %q, want [data-testid='email']", selector)
+	}
+
+	if err := manager.FillFormField(selector, "ada@example.com"); err != nil {
+		t.Fatalf("FillFormField failed: %v", err)
+	}
+	var value string
+	if err := manager.ExecuteScript(`document.querySelector('input').value`, &value); err != nil {
+		t.Fatalf("ExecuteScript failed: %v", err)
+	}
+	if value != "ada@example.com" {
+		t.Errorf("field value = %q, want %q", value, "ada@example.com")
+	}
+	var changed bool
+	if err := manager.ExecuteScript(`window.changed === true`, &changed); err != nil {
+		t.Fatalf("ExecuteScript failed: %v", err)
+	}
+	if !changed {
+		t.Error("change event wasn't dispatched")
+	}
+}
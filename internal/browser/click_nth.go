@@ -0,0 +1,87 @@
+package browser
+
+import (
+	"fmt"
+
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// clickNthResult is what the SmartClickNth script reports back
+type clickNthResult struct {
+	Matches int    `json:"matches"`
+	Clicked bool   `json:"clicked"`
+	Href    string `json:"href"` // where the element links to, if it's in a link
+}
+
+// SmartClickNth clicks the index-th (0-based) of several elements that share
+// the same text, such as a row of "Edit" buttons. Visible clickable elements
+// whose text contains text are collected in document order; without text, the
+// elements matching selector are used instead. It returns whether an element
+// was clicked and how many matched, so callers can report an index out of range.
+func (m *ChromeDPManager) SmartClickNth(selector, text string, index int) (bool, int, error) {
+	if index < 0 {
+		return false, 0, fmt.Errorf("invalid element index %d", index)
+	}
+	if m.skipInDryRun("click match #%d of %q", index+1, text) {
+		// Matches aren't counted in dry-run mode, so any index is in range
+		return true, index + 1, nil
+	}
+
+	// Links are checked against the navigation guard before they're clicked
+	if m.navigationGuard != nil {
+		var found clickNthResult
+		if err := m.ExecuteScript(clickNthScript(selector, text, index, false), &found); err == nil && found.Href != "" {
+			if err := m.checkNavigation(found.Href); err != nil {
+				return false, found.Matches, err
+			}
+		}
+	}
+
+	var result clickNthResult
+	if err := m.ExecuteScript(clickNthScript(selector, text, index, true), &result); err != nil {
+		return false, 0, fmt.Errorf("failed to click match %d of %q: %w", index+1, text, err)
+	}
+
+	if result.Clicked {
+		logging.Debug("SmartClickNth: clicked match %d of %d for %q", index+1, result.Matches, text)
+	} else {
+		logging.Warn("SmartClickNth: wanted match %d of %q but found %d", index+1, text, result.Matches)
+	}
+	return result.Clicked, result.Matches, nil
+}
+
+// clickNthScript finds the element SmartClickNth picks and, if click is set,
+// clicks it
+func clickNthScript(selector, text string, index int, click bool) string {
+	return fmt.Sprintf(`
+		(() => {
+			const wanted = %s.replace(/\s+/g, ' ').trim().toLowerCase();
+			let candidates;
+			if (wanted) {
+				candidates = Array.from(document.querySelectorAll(
+					'a, button, [role="button"], [role="link"], input[type="submit"], input[type="button"]'
+				)).filter(el => {
+					const label = (el.textContent || el.value || el.getAttribute('aria-label') || '')
+						.replace(/\s+/g, ' ').trim().toLowerCase();
+					return label.includes(wanted);
+				});
+			} else {
+				candidates = Array.from(document.querySelectorAll(%s));
+			}
+			const visible = candidates.filter(el => el.offsetParent !== null || el.getClientRects().length > 0);
+			const index = %d;
+			if (index >= visible.length) {
+				return { matches: visible.length, clicked: false, href: '' };
+			}
+			const el = visible[index];
+			const link = el.closest('a[href]');
+			const href = link ? link.href : '';
+			if (!%t) {
+				return { matches: visible.length, clicked: false, href: href };
+			}
+			el.scrollIntoView({ block: 'center' });
+			el.click();
+			return { matches: visible.length, clicked: true, href: href };
+		})()
+	`, QuoteJS(text), QuoteJS(selector), index, click)
+}
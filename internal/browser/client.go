@@ -51,7 +51,7 @@ func NewClient(baseURL string) (*Client, error) {
 	}
 
 	// Determine debugging port from environment or use default
-	debugPort := "9222"
+	debugPort := strconv.Itoa(DefaultDebugPort)
 	if envPort := os.Getenv("BROWSER_DEBUG_PORT"); envPort != "" {
 		if _, err := strconv.Atoi(envPort); err == nil {
 			debugPort = envPort
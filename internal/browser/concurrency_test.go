@@ -0,0 +1,125 @@
+package browser
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// These tests are meant for go test -race: they exercise the manager from
+// several goroutines at once, as tea.Cmds and pollers do, without Chrome.
+
+// newFakeManager returns a manager with live contexts but no browser, enough
+// for the code paths that only look at its state
+func newFakeManager() *ChromeDPManager {
+	ctx, cancel := context.WithCancel(context.Background())
+	allocCtx, allocCancel := context.WithCancel(context.Background())
+	return &ChromeDPManager{
+		allocCtx:    allocCtx,
+		allocCancel: allocCancel,
+		ctx:         ctx,
+		cancel:      cancel,
+		rootCtx:     ctx,
+	}
+}
+
+func TestGlobalManagerConcurrentAccess(t *testing.T) {
+	manager := newFakeManager()
+	globalChromeDPManagerMu.Lock()
+	globalChromeDPManager = manager
+	globalChromeDPManagerMu.Unlock()
+	t.Cleanup(CloseGlobalChromeDPManager)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				got, err := GetGlobalChromeDPManager("", LaunchOptions{})
+				if err != nil || got != manager {
+					t.Errorf("GetGlobalChromeDPManager = %p, %v, want the existing manager", got, err)
+					return
+				}
+				currentGlobalChromeDPManager()
+				manager.IsCrashed()
+			}
+		}()
+	}
+	// Switching tabs replaces the active context while others read it
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			ctx, cancel := context.WithCancel(manager.browserCtx())
+			t.Cleanup(cancel)
+			manager.ctxMu.Lock()
+			manager.ctx = ctx
+			manager.ctxMu.Unlock()
+		}
+	}()
+	wg.Wait()
+
+	// Closing races with readers of the global
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if current := currentGlobalChromeDPManager(); current != nil {
+				current.IsCrashed()
+			}
+		}()
+	}
+	CloseGlobalChromeDPManager()
+	wg.Wait()
+
+	if currentGlobalChromeDPManager() != nil {
+		t.Error("global manager still set after CloseGlobalChromeDPManager")
+	}
+	if manager.IsCrashed() {
+		t.Error("a closed manager reports a crash")
+	}
+}
+
+func TestPollStopsWhenClosed(t *testing.T) {
+	manager := newFakeManager()
+
+	// Nobody reads the poll's channel, so sending blocks until Close
+	changes := make(chan HTMLChange)
+	sent := make(chan bool)
+	go func() {
+		sent <- manager.sendChange(changes, HTMLChange{HTML: "<p>changed</p>"})
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	manager.Close()
+
+	select {
+	case ok := <-sent:
+		if ok {
+			t.Error("sendChange reported the change delivered after Close")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("sendChange still blocked after Close")
+	}
+}
+
+func TestHTMLDifferConcurrentUse(t *testing.T) {
+	differ := NewHTMLDiffer()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				differ.HasChanged("<p>"+string(rune('a'+i))+"</p>", int64(j))
+				differ.HasSnapshot()
+				if j%10 == 0 {
+					differ.Reset()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,248 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// DefaultConsentSelectors are the accept buttons of common consent
+// frameworks: OneTrust, Cookiebot, TrustArc, Didomi, Quantcast and Osano
+var DefaultConsentSelectors = []string{
+	"#onetrust-accept-btn-handler",
+	"#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll",
+	"#CybotCookiebotDialogBodyButtonAccept",
+	"#truste-consent-button",
+	"#didomi-notice-agree-button",
+	".qc-cmp2-summary-buttons button[mode=primary]",
+	".osano-cm-accept-all",
+}
+
+// DefaultConsentContainers are the elements a consent banner is drawn in:
+// the consent frameworks' own, anything named for cookies or consent, and
+// dialogs. Buttons are only clicked by their text inside one of these, since
+// labels like "Accept" and "Got it" are common elsewhere on a page.
+var DefaultConsentContainers = []string{
+	"#onetrust-banner-sdk",
+	"#onetrust-consent-sdk",
+	"#CybotCookiebotDialog",
+	"#truste-consent-track",
+	"#didomi-host",
+	".qc-cmp2-container",
+	".osano-cm-window",
+	"[id*=cookie i]",
+	"[class*=cookie i]",
+	"[id*=consent i]",
+	"[class*=consent i]",
+	"[id*=gdpr i]",
+	"[class*=gdpr i]",
+	"[role=dialog]",
+	"[role=alertdialog]",
+	"[aria-modal=true]",
+}
+
+// DefaultConsentTexts are the labels of buttons that accept a consent
+// banner, matched case-insensitively against the whole label of buttons in
+// a DefaultConsentContainers element
+var DefaultConsentTexts = []string{
+	"Accept all",
+	"Accept all cookies",
+	"Accept cookies",
+	"Allow all",
+	"Allow all cookies",
+	"I agree",
+	"I accept",
+	"Agree",
+	"Accept",
+	"Got it",
+}
+
+// consentPollDuration is how long after a navigation a banner is waited
+// for, since most are injected once the page's scripts run
+const consentPollDuration = 1500 * time.Millisecond
+
+// consentPollInterval is how often the page is checked for a banner
+const consentPollInterval = 250 * time.Millisecond
+
+// consentSettings controls how consent banners are dismissed, set from
+// LaunchOptions
+type consentSettings struct {
+	auto      bool
+	selectors []string
+	texts     []string
+	dismissed map[string]bool // hosts a banner was dismissed on
+}
+
+// consentResult is what the consent script reports back
+type consentResult struct {
+	Clicked string `json:"clicked"`
+}
+
+// DismissConsentBanners clicks the accept button of a cookie consent banner,
+// trying the known consent frameworks' buttons and then buttons labelled like
+// "Accept all" inside a consent container, in the page and in every iframe,
+// where many banners live.
+// It reports whether a banner was dismissed.
+func (m *ChromeDPManager) DismissConsentBanners() (bool, error) {
+	if m.skipInDryRun("dismiss consent banners") {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	var frames []cdp.FrameID
+	err := m.run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		tree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		frames = frameIDs(tree)
+		return nil
+	}))
+	if err != nil {
+		return false, fmt.Errorf("failed to list frames: %w", err)
+	}
+
+	script := consentScript(m.consentSelectors(), m.consentTexts(), DefaultConsentContainers)
+	for _, frame := range frames {
+		clicked, err := m.clickConsentInFrame(ctx, frame, script)
+		if err != nil {
+			// Frames come and go as the page loads, so one failing isn't fatal
+			logging.Debug("Consent check failed in frame %s: %v", frame, err)
+			continue
+		}
+		if clicked != "" {
+			logging.Info("Dismissed consent banner by clicking %s", clicked)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// clickConsentInFrame runs the consent script in an isolated world of frame,
+// which can reach the frame's DOM even when it's on another origin, and
+// returns what it clicked
+func (m *ChromeDPManager) clickConsentInFrame(ctx context.Context, frame cdp.FrameID, script string) (string, error) {
+	var result consentResult
+	err := m.run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		contextID, err := page.CreateIsolatedWorld(frame).WithWorldName("tod-consent").Do(ctx)
+		if err != nil {
+			return err
+		}
+		res, exp, err := runtime.Evaluate(script).WithContextID(contextID).WithReturnByValue(true).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exp != nil {
+			return exp
+		}
+		return json.Unmarshal([]byte(res.Value), &result)
+	}))
+	return result.Clicked, err
+}
+
+// dismissConsentAfterNavigate waits briefly for a consent banner after a
+// navigation and dismisses it, when auto_dismiss_consent is on. Once one is
+// dismissed on a host its cookie keeps it away, so later pages there are
+// only checked once rather than waited on.
+func (m *ChromeDPManager) dismissConsentAfterNavigate(pageURL string) {
+	if !m.consent.auto {
+		return
+	}
+	host := pageURL
+	if u, err := url.Parse(pageURL); err == nil {
+		host = u.Host
+	}
+
+	deadline := time.Now().Add(consentPollDuration)
+	for {
+		dismissed, err := m.DismissConsentBanners()
+		if err != nil {
+			logging.Debug("Failed to dismiss consent banner: %v", err)
+			return
+		}
+		if dismissed {
+			if m.consent.dismissed == nil {
+				m.consent.dismissed = make(map[string]bool)
+			}
+			m.consent.dismissed[host] = true
+			return
+		}
+		if m.consent.dismissed[host] || time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(consentPollInterval)
+	}
+}
+
+func (m *ChromeDPManager) consentSelectors() []string {
+	return append(append([]string{}, m.consent.selectors...), DefaultConsentSelectors...)
+}
+
+func (m *ChromeDPManager) consentTexts() []string {
+	return append(append([]string{}, m.consent.texts...), DefaultConsentTexts...)
+}
+
+// frameIDs returns the IDs of every frame in tree, the main frame first
+func frameIDs(tree *page.FrameTree) []cdp.FrameID {
+	if tree == nil || tree.Frame == nil {
+		return nil
+	}
+	ids := []cdp.FrameID{tree.Frame.ID}
+	for _, child := range tree.ChildFrames {
+		ids = append(ids, frameIDs(child)...)
+	}
+	return ids
+}
+
+// consentScript returns a script that clicks the first visible consent
+// button matching selectors, or failing that labelled with one of texts
+// inside an element matching containers
+func consentScript(selectors, texts, containers []string) string {
+	selectorsJSON, _ := json.Marshal(selectors)
+	textsJSON, _ := json.Marshal(texts)
+	containersJSON, _ := json.Marshal(containers)
+	return fmt.Sprintf(`
+		(() => {
+			const selectors = %s;
+			const texts = %s.map(t => t.toLowerCase());
+			const containers = %s.join(', ');
+			const visible = el => {
+				const style = getComputedStyle(el);
+				return el.getClientRects().length > 0 && style.visibility !== 'hidden' && style.display !== 'none';
+			};
+			const describe = el => el.id ? '#' + el.id : '"' + (el.innerText || el.value || '').trim() + '"';
+
+			for (const selector of selectors) {
+				let el = null;
+				try { el = document.querySelector(selector); } catch (e) { continue; }
+				if (el && visible(el)) {
+					el.click();
+					return { clicked: describe(el) };
+				}
+			}
+
+			const candidates = Array.from(document.querySelectorAll('button, a, [role=button], input[type=button], input[type=submit]'))
+				.filter(el => el.closest(containers));
+			for (const text of texts) {
+				for (const el of candidates) {
+					const label = (el.innerText || el.value || el.getAttribute('aria-label') || '').replace(/\s+/g, ' ').trim().toLowerCase();
+					if (label === text && visible(el)) {
+						el.click();
+						return { clicked: describe(el) };
+					}
+				}
+			}
+			return { clicked: '' };
+		})()
+	`, selectorsJSON, textsJSON, containersJSON)
+}
@@ -0,0 +1,91 @@
+package browser
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/runtime"
+)
+
+// maxConsoleErrors bounds how many undrained console errors are kept
+const maxConsoleErrors = 50
+
+// consoleErrors collects console.error calls and uncaught exceptions
+type consoleErrors struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+// handleEvent records errors from runtime domain events. It runs on
+// chromedp's event loop, so it only appends and never blocks.
+func (c *consoleErrors) handleEvent(ev interface{}) {
+	var message string
+	switch e := ev.(type) {
+	case *runtime.EventConsoleAPICalled:
+		if e.Type != runtime.APITypeError && e.Type != runtime.APITypeAssert {
+			return
+		}
+		var parts []string
+		for _, arg := range e.Args {
+			parts = append(parts, remoteObjectText(arg))
+		}
+		message = strings.Join(parts, " ")
+	case *runtime.EventExceptionThrown:
+		if e.ExceptionDetails == nil {
+			return
+		}
+		message = e.ExceptionDetails.Text
+		if e.ExceptionDetails.Exception != nil && e.ExceptionDetails.Exception.Description != "" {
+			message = e.ExceptionDetails.Exception.Description
+		}
+	default:
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errors = append(c.errors, message)
+	if len(c.errors) > maxConsoleErrors {
+		c.errors = c.errors[len(c.errors)-maxConsoleErrors:]
+	}
+}
+
+// drain returns the collected errors and clears them
+func (c *consoleErrors) drain() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	drained := c.errors
+	c.errors = nil
+	return drained
+}
+
+// remoteObjectText renders a console argument as text
+func remoteObjectText(obj *runtime.RemoteObject) string {
+	if obj == nil {
+		return ""
+	}
+	if len(obj.Value) > 0 {
+		var s string
+		if err := json.Unmarshal(obj.Value, &s); err == nil {
+			return s
+		}
+		return string(obj.Value)
+	}
+	return obj.Description
+}
+
+// startConsoleErrorCapture collects console errors and uncaught exceptions.
+// listenTab attaches it to each tab.
+func (m *ChromeDPManager) startConsoleErrorCapture() {
+	m.consoleErrors = &consoleErrors{}
+}
+
+// DrainConsoleErrors returns the console errors and uncaught exceptions the
+// page logged since the last call
+func (m *ChromeDPManager) DrainConsoleErrors() []string {
+	if m.consoleErrors == nil {
+		return nil
+	}
+	return m.consoleErrors.drain()
+}
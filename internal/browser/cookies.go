@@ -0,0 +1,95 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Cookie is a browser cookie in a form that can be saved and restored
+type Cookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain"`
+	Path     string    `json:"path"`
+	Expires  time.Time `json:"expires,omitzero"` // zero for session cookies
+	HTTPOnly bool      `json:"http_only"`
+	Secure   bool      `json:"secure"`
+	SameSite string    `json:"same_site,omitempty"` // Strict, Lax or None
+}
+
+// Expired reports whether the cookie expired before now. Session cookies
+// never expire.
+func (c Cookie) Expired(now time.Time) bool {
+	return !c.Expires.IsZero() && c.Expires.Before(now)
+}
+
+// ExportCookies returns the cookies visible to the current page
+func (m *ChromeDPManager) ExportCookies() ([]Cookie, error) {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	var raw []*network.Cookie
+	if err := m.run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		raw, err = network.GetCookies().Do(ctx)
+		return err
+	})); err != nil {
+		return nil, fmt.Errorf("failed to read cookies: %w", err)
+	}
+
+	cookies := make([]Cookie, 0, len(raw))
+	for _, c := range raw {
+		cookie := Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			HTTPOnly: c.HTTPOnly,
+			Secure:   c.Secure,
+			SameSite: string(c.SameSite),
+		}
+		if !c.Session && c.Expires > 0 {
+			cookie.Expires = time.Unix(0, int64(c.Expires*float64(time.Second)))
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies, nil
+}
+
+// ImportCookies sets the given cookies in the browser, skipping any that
+// have already expired
+func (m *ChromeDPManager) ImportCookies(cookies []Cookie) error {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Element)
+	defer cancel()
+
+	now := time.Now()
+	return m.run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		for _, c := range cookies {
+			if c.Expired(now) {
+				continue
+			}
+
+			set := network.SetCookie(c.Name, c.Value).
+				WithDomain(c.Domain).
+				WithPath(c.Path).
+				WithHTTPOnly(c.HTTPOnly).
+				WithSecure(c.Secure)
+			if c.SameSite != "" {
+				set = set.WithSameSite(network.CookieSameSite(c.SameSite))
+			}
+			if !c.Expires.IsZero() {
+				expires := cdp.TimeSinceEpoch(c.Expires)
+				set = set.WithExpires(&expires)
+			}
+			if err := set.Do(ctx); err != nil {
+				return fmt.Errorf("failed to set cookie %s: %w", c.Name, err)
+			}
+		}
+		return nil
+	}))
+}
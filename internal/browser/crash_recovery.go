@@ -0,0 +1,95 @@
+package browser
+
+import (
+	"fmt"
+
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// MaxRelaunches is how many times EnsureAlive relaunches a crashed Chrome
+// before giving up, so a Chrome that crashes on start doesn't loop forever
+const MaxRelaunches = 3
+
+// EnsureAlive relaunches Chrome if it has crashed, with the options it was
+// launched with, and returns to the last page it was on. The manager keeps
+// working afterwards as if nothing happened, apart from state that lived in
+// the browser, like cookies of a session that wasn't saved. Running pollers
+// carry on against the new browser. It does nothing if Chrome is running,
+// and fails once Chrome has been relaunched MaxRelaunches times or was
+// closed on purpose.
+func (m *ChromeDPManager) EnsureAlive() error {
+	if m.closed.Load() {
+		return fmt.Errorf("Chrome was closed")
+	}
+	if !m.IsCrashed() {
+		return nil
+	}
+
+	m.recoverMu.Lock()
+	defer m.recoverMu.Unlock()
+
+	// Another caller may have relaunched it while this one waited
+	if !m.IsCrashed() {
+		return nil
+	}
+	if m.relaunches >= MaxRelaunches {
+		return fmt.Errorf("%w, gave up after relaunching it %d times", ErrBrowserCrashed, MaxRelaunches)
+	}
+	m.relaunches++
+
+	lastURL := m.baseURL
+	if last := m.lastURL.Load(); last != nil {
+		lastURL = *last
+	}
+	logging.Warn("Chrome crashed, relaunching (%d of %d) and returning to %s", m.relaunches, MaxRelaunches, redactURL(lastURL))
+
+	// Release what's left of the dead browser
+	m.ctxMu.RLock()
+	cancel, allocCancel := m.cancel, m.allocCancel
+	m.ctxMu.RUnlock()
+	cancel()
+	allocCancel()
+	var creds *BasicAuth
+	if m.basicAuth != nil {
+		m.basicAuth.mu.Lock()
+		creds = m.basicAuth.creds
+		m.basicAuth.mu.Unlock()
+		m.basicAuth = nil
+	}
+
+	if err := m.startChrome(m.emulation); err != nil {
+		return fmt.Errorf("failed to relaunch Chrome: %w", err)
+	}
+
+	// Restore what was set on the old browser
+	if creds != nil {
+		if err := m.SetBasicAuth(creds.Username, creds.Password); err != nil {
+			logging.Warn("Failed to restore basic auth after relaunch: %v", err)
+		}
+	}
+	if m.networkConditions != NoThrottling {
+		if err := m.SetNetworkConditions(m.networkConditions); err != nil {
+			logging.Warn("Failed to restore network conditions after relaunch: %v", err)
+		}
+	}
+
+	// Going back goes ahead in dry-run mode, as it only restores the page
+	if lastURL != "" {
+		if err := m.navigate(lastURL); err != nil {
+			logging.Warn("Failed to return to %s after relaunch: %v", redactURL(lastURL), err)
+		}
+	}
+
+	// Listeners on the new tab are attached by startChrome; inspect mode's
+	// is reattached here so its picks keep arriving on the same channel
+	if m.inspect != nil {
+		if err := m.attachInspect(m.inspect); err != nil {
+			logging.Warn("Failed to restore inspect mode after relaunch: %v", err)
+			m.inspect.close()
+			m.inspect = nil
+		}
+	}
+
+	logging.Info("Chrome relaunched on port %d", m.port)
+	return nil
+}
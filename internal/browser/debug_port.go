@@ -0,0 +1,62 @@
+package browser
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// DefaultDebugPort is the remote-debugging port Chrome is launched with when
+// browser.debug_port is not configured
+const DefaultDebugPort = 9222
+
+// resolveDebugPort returns the port to launch Chrome with: the requested port
+// (or DefaultDebugPort) if it's free, otherwise any free port
+func resolveDebugPort(port int) (int, error) {
+	if port <= 0 {
+		port = DefaultDebugPort
+	}
+	if PortAvailable(port) {
+		return port, nil
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("debug port %d is in use and no free port was found: %w", port, err)
+	}
+	free := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	logging.Warn("Debug port %d is in use, using port %d instead", port, free)
+	return free, nil
+}
+
+// PortAvailable reports whether a local TCP port can be bound
+func PortAvailable(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// Port returns the remote-debugging port Chrome was launched with
+func (m *ChromeDPManager) Port() int {
+	return m.port
+}
+
+// RunningDebugPort returns the remote-debugging port of the Chrome Tod is
+// driving, which may not be the configured one if that was in use. When
+// Tod's Chrome isn't running it returns configured, or DefaultDebugPort if
+// that's not set.
+func RunningDebugPort(configured int) int {
+	if manager := currentGlobalChromeDPManager(); manager != nil {
+		return manager.Port()
+	}
+	if configured > 0 {
+		return configured
+	}
+	return DefaultDebugPort
+}
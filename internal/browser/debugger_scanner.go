@@ -11,6 +11,7 @@ import (
 
 	"github.com/chromedp/cdproto/dom"
 	"github.com/chromedp/chromedp"
+	"github.com/lance13c/tod/internal/logging"
 )
 
 // DebuggerTarget represents a Chrome DevTools target
@@ -31,8 +32,11 @@ type DebuggerScanResult struct {
 
 // ScanForChromeDebugger scans common Chrome debugger ports for open instances
 func ScanForChromeDebugger() ([]DebuggerScanResult, error) {
-	// Common Chrome debugger ports (9222 first as it's our default)
-	ports := []int{9222, 9222, 9223, 9224, 9225}
+	// Common Chrome debugger ports, starting with the one our Chrome uses
+	ports := []int{DefaultDebugPort, 9223, 9224, 9225}
+	if manager := currentGlobalChromeDPManager(); manager != nil && manager.Port() != DefaultDebugPort {
+		ports = append([]int{manager.Port()}, ports...)
+	}
 	
 	var results []DebuggerScanResult
 	var lastError error
@@ -95,9 +99,9 @@ func getTargetsFromPortAndHost(port int, host string) ([]DebuggerTarget, error)
 	}
 	
 	// Log what we got for debugging
-	fmt.Fprintf(os.Stderr, "Got %d targets from %s:%d\n", len(targets), host, port)
+	logging.Debug("Got %d targets from %s:%d", len(targets), host, port)
 	for i, target := range targets {
-		fmt.Fprintf(os.Stderr, "  Target %d: Type=%s, URL=%s, WebSocket=%s\n", 
+		logging.Debug("  Target %d: Type=%s, URL=%s, WebSocket=%s",
 			i, target.Type, target.URL, target.WebSocketDebuggerURL)
 	}
 	
@@ -0,0 +1,83 @@
+package browser
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// maxDialogs bounds how many undrained dialogs are kept
+const maxDialogs = 20
+
+// Dialog is a native JS dialog (alert, confirm, prompt or beforeunload) that
+// was answered automatically
+type Dialog struct {
+	Type     string // alert, confirm, prompt or beforeunload
+	Message  string
+	Accepted bool
+}
+
+// dialogHandler answers JS dialogs, which would otherwise block the page
+// and every chromedp call until someone closed them
+type dialogHandler struct {
+	mu      sync.Mutex
+	accept  bool
+	dialogs []Dialog
+}
+
+// handleEvent answers dialogs in a tab as they open. Commands can't be sent
+// from the listener itself, so the reply is sent from its own goroutine.
+func (h *dialogHandler) handleEvent(ctx context.Context) func(ev interface{}) {
+	return func(ev interface{}) {
+		e, ok := ev.(*page.EventJavascriptDialogOpening)
+		if !ok {
+			return
+		}
+
+		h.mu.Lock()
+		accept := h.accept
+		h.dialogs = append(h.dialogs, Dialog{Type: string(e.Type), Message: e.Message, Accepted: accept})
+		if len(h.dialogs) > maxDialogs {
+			h.dialogs = h.dialogs[len(h.dialogs)-maxDialogs:]
+		}
+		h.mu.Unlock()
+
+		logging.Debug("Answering %s dialog (accept=%v): %s", e.Type, accept, e.Message)
+		handle := page.HandleJavaScriptDialog(accept)
+		if e.Type == page.DialogTypePrompt {
+			handle = handle.WithPromptText(e.DefaultPrompt)
+		}
+		go runFromListener(ctx, handle)
+	}
+}
+
+// startDialogHandling answers JS dialogs automatically, accepting them
+// unless dismiss is set. listenTab attaches it to each tab.
+func (m *ChromeDPManager) startDialogHandling(dismiss bool) {
+	m.dialogs = &dialogHandler{accept: !dismiss}
+}
+
+// SetDialogBehavior sets whether JS dialogs are accepted (OK) or dismissed
+// (Cancel) when they open
+func (m *ChromeDPManager) SetDialogBehavior(accept bool) {
+	if m.dialogs == nil {
+		return
+	}
+	m.dialogs.mu.Lock()
+	defer m.dialogs.mu.Unlock()
+	m.dialogs.accept = accept
+}
+
+// DrainDialogs returns the dialogs answered since the last call
+func (m *ChromeDPManager) DrainDialogs() []Dialog {
+	if m.dialogs == nil {
+		return nil
+	}
+	m.dialogs.mu.Lock()
+	defer m.dialogs.mu.Unlock()
+	drained := m.dialogs.dialogs
+	m.dialogs.dialogs = nil
+	return drained
+}
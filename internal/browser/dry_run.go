@@ -0,0 +1,61 @@
+package browser
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// dryRun records the actions skipped in dry-run mode
+type dryRun struct {
+	mu      sync.Mutex
+	skipped []string
+}
+
+// SetDryRun turns dry-run mode on or off. In dry-run mode the methods that
+// act on the page, such as Navigate, Click, FillFormField, Back, Reload and
+// ReplayRequest, record what they would have done and succeed without
+// touching it; reading the page still works, so action discovery is
+// unaffected.
+func (m *ChromeDPManager) SetDryRun(enabled bool) {
+	if !enabled {
+		m.dryRun = nil
+		return
+	}
+	if m.dryRun == nil {
+		m.dryRun = &dryRun{}
+	}
+}
+
+// IsDryRun reports whether actions are only being recorded
+func (m *ChromeDPManager) IsDryRun() bool {
+	return m.dryRun != nil
+}
+
+// DrainDryRun returns the actions skipped since the last call
+func (m *ChromeDPManager) DrainDryRun() []string {
+	if m.dryRun == nil {
+		return nil
+	}
+	m.dryRun.mu.Lock()
+	defer m.dryRun.mu.Unlock()
+	drained := m.dryRun.skipped
+	m.dryRun.skipped = nil
+	return drained
+}
+
+// skipInDryRun records the action in dry-run mode and reports whether the
+// caller should skip it
+func (m *ChromeDPManager) skipInDryRun(format string, args ...interface{}) bool {
+	if m.dryRun == nil {
+		return false
+	}
+	action := fmt.Sprintf(format, args...)
+	logging.Info("Dry run: would %s", action)
+
+	m.dryRun.mu.Lock()
+	defer m.dryRun.mu.Unlock()
+	m.dryRun.skipped = append(m.dryRun.skipped, action)
+	return true
+}
@@ -0,0 +1,116 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// Default window size when no viewport is emulated
+const (
+	defaultWindowWidth  = 1920
+	defaultWindowHeight = 1080
+)
+
+// mobileMaxTouchPoints is how many touch points emulated touch screens report
+const mobileMaxTouchPoints = 5
+
+// Emulation describes the device the page is shown as: its viewport, pixel
+// density, touch support and user agent. Zero fields keep Chrome's defaults.
+type Emulation struct {
+	Width             int
+	Height            int
+	DeviceScaleFactor float64
+	Mobile            bool // use the mobile viewport meta tag and scrollbars
+	Touch             bool // report a touch screen, for (pointer: coarse) and touch events
+	UserAgent         string
+}
+
+// devicePresets are the devices environments can name with "device"
+var devicePresets = map[string]Emulation{
+	"iphone-13": {
+		Width: 390, Height: 844, DeviceScaleFactor: 3, Mobile: true, Touch: true,
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.4 Mobile/15E148 Safari/604.1",
+	},
+	"pixel-7": {
+		Width: 412, Height: 915, DeviceScaleFactor: 2.625, Mobile: true, Touch: true,
+		UserAgent: "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Mobile Safari/537.36",
+	},
+}
+
+// DevicePreset returns the emulation of a named device, like "iphone-13"
+func DevicePreset(name string) (Emulation, error) {
+	preset, ok := devicePresets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Emulation{}, fmt.Errorf("unknown device %q, known devices: %s", name, strings.Join(DevicePresetNames(), ", "))
+	}
+	return preset, nil
+}
+
+// DevicePresetNames lists the device presets in alphabetical order
+func DevicePresetNames() []string {
+	names := make([]string, 0, len(devicePresets))
+	for name := range devicePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// windowSize is the size Chrome's window opens at
+func (e Emulation) windowSize() (int, int) {
+	if e.Width > 0 && e.Height > 0 {
+		return e.Width, e.Height
+	}
+	return defaultWindowWidth, defaultWindowHeight
+}
+
+// overridesMetrics reports whether a viewport is set. The window size alone
+// would include the browser's own toolbars in headful mode.
+func (e Emulation) overridesMetrics() bool {
+	return e.Width > 0 && e.Height > 0
+}
+
+// SetEmulation shows the page as the given device. The viewport, scale factor
+// and mobile flag are set together so responsive breakpoints and srcset pick
+// what the real device would; a scale factor of 0 keeps the screen's own.
+func (m *ChromeDPManager) SetEmulation(e Emulation) error {
+	if err := m.emulate(m.tabCtx(), e); err != nil {
+		return err
+	}
+	m.emulation = e
+	return nil
+}
+
+// emulate applies e to the tab of tabCtx
+func (m *ChromeDPManager) emulate(tabCtx context.Context, e Emulation) error {
+	ctx, cancel := context.WithTimeout(tabCtx, m.timeouts.Action)
+	defer cancel()
+
+	var actions []chromedp.Action
+	if e.overridesMetrics() {
+		actions = append(actions, emulation.SetDeviceMetricsOverride(int64(e.Width), int64(e.Height), e.DeviceScaleFactor, e.Mobile))
+	}
+	if e.Touch {
+		actions = append(actions, emulation.SetTouchEmulationEnabled(true).WithMaxTouchPoints(mobileMaxTouchPoints))
+	}
+	if e.UserAgent != "" {
+		actions = append(actions, emulation.SetUserAgentOverride(e.UserAgent))
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	if err := m.run(ctx, actions...); err != nil {
+		return fmt.Errorf("failed to emulate device: %w", err)
+	}
+	return nil
+}
+
+// Emulation returns the device the page is emulated as
+func (m *ChromeDPManager) Emulation() Emulation {
+	return m.emulation
+}
@@ -0,0 +1,91 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+const (
+	// highlightOverlayID is the id of the overlay element HighlightElement adds
+	highlightOverlayID = "__tod_highlight__"
+
+	// actionHighlightDuration is how long the target of a click or fill stays
+	// outlined in headful mode
+	actionHighlightDuration = 800
+
+	// actionHighlightLead is how long the outline shows before the action runs
+	actionHighlightLead = 200 * time.Millisecond
+)
+
+// HighlightElement outlines the element matching selector for durationMs,
+// then removes the outline. The outline is a separate overlay that ignores
+// pointer events, so it never takes a click meant for the element.
+func (m *ChromeDPManager) HighlightElement(selector string, durationMs int) error {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	script := fmt.Sprintf(`
+		(() => {
+			const el = document.querySelector(%s);
+			if (!el) return false;
+			const id = %s;
+			document.getElementById(id)?.remove();
+
+			// Page coordinates, so the outline stays put if the page scrolls
+			const rect = el.getBoundingClientRect();
+			const overlay = document.createElement('div');
+			overlay.id = id;
+			overlay.setAttribute('aria-hidden', 'true');
+			Object.assign(overlay.style, {
+				position: 'absolute',
+				left: (rect.left + window.scrollX - 3) + 'px',
+				top: (rect.top + window.scrollY - 3) + 'px',
+				width: (rect.width + 6) + 'px',
+				height: (rect.height + 6) + 'px',
+				border: '3px solid #ff3d7f',
+				borderRadius: '4px',
+				boxSizing: 'border-box',
+				background: 'rgba(255, 61, 127, 0.12)',
+				pointerEvents: 'none',
+				zIndex: '2147483647',
+			});
+			document.documentElement.appendChild(overlay);
+			setTimeout(() => overlay.remove(), %d);
+			return true;
+		})()
+	`, QuoteJS(selector), QuoteJS(highlightOverlayID), durationMs)
+
+	var found bool
+	if err := m.run(ctx, chromedp.Evaluate(script, &found)); err != nil {
+		return fmt.Errorf("failed to highlight %s: %w", selector, err)
+	}
+	if !found {
+		return fmt.Errorf("element not found: %s", selector)
+	}
+	return nil
+}
+
+// removeHighlight removes a highlight overlay before it times out
+func (m *ChromeDPManager) removeHighlight(ctx context.Context) {
+	script := fmt.Sprintf(`document.getElementById(%s)?.remove()`, QuoteJS(highlightOverlayID))
+	if err := m.run(ctx, chromedp.Evaluate(script, nil)); err != nil {
+		logging.Debug("Failed to remove highlight: %v", err)
+	}
+}
+
+// highlightTarget briefly outlines the element an action is about to use
+// when Chrome is visible. Failing to highlight doesn't stop the action.
+func (m *ChromeDPManager) highlightTarget(selector string) {
+	if m.isHeadless {
+		return
+	}
+	if err := m.HighlightElement(selector, actionHighlightDuration); err != nil {
+		logging.Debug("Highlight skipped: %v", err)
+		return
+	}
+	time.Sleep(actionHighlightLead)
+}
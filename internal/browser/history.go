@@ -0,0 +1,104 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrNoPreviousPage is returned by Back when there's no earlier history entry
+var ErrNoPreviousPage = errors.New("no previous page")
+
+// ErrNoNextPage is returned by Forward when there's no later history entry
+var ErrNoNextPage = errors.New("no next page")
+
+// historyPosition returns the current index into the browser history and its
+// entries
+func (m *ChromeDPManager) historyPosition() (current int64, entries []*page.NavigationEntry, err error) {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	err = m.run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		current, entries, err = page.GetNavigationHistory().Do(ctx)
+		return err
+	}))
+	return current, entries, err
+}
+
+// Back goes to the previous page in the browser history and waits for it to load
+func (m *ChromeDPManager) Back() error {
+	current, entries, err := m.historyPosition()
+	if err != nil {
+		return fmt.Errorf("failed to read browser history: %w", err)
+	}
+	if current <= 0 {
+		return ErrNoPreviousPage
+	}
+	if err := m.checkNavigation(entries[current-1].URL); err != nil {
+		return err
+	}
+	if m.skipInDryRun("go back") {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Navigation)
+	defer cancel()
+	if err := m.run(ctx, chromedp.NavigateBack()); err != nil {
+		return fmt.Errorf("failed to navigate back: %w", err)
+	}
+
+	return m.waitForNewPage()
+}
+
+// Forward goes to the next page in the browser history and waits for it to load
+func (m *ChromeDPManager) Forward() error {
+	current, entries, err := m.historyPosition()
+	if err != nil {
+		return fmt.Errorf("failed to read browser history: %w", err)
+	}
+	if int(current) >= len(entries)-1 {
+		return ErrNoNextPage
+	}
+	if err := m.checkNavigation(entries[current+1].URL); err != nil {
+		return err
+	}
+	if m.skipInDryRun("go forward") {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Navigation)
+	defer cancel()
+	if err := m.run(ctx, chromedp.NavigateForward()); err != nil {
+		return fmt.Errorf("failed to navigate forward: %w", err)
+	}
+
+	return m.waitForNewPage()
+}
+
+// Reload reloads the current page and waits for it to load
+func (m *ChromeDPManager) Reload() error {
+	if m.skipInDryRun("reload the page") {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Navigation)
+	defer cancel()
+	if err := m.run(ctx, chromedp.Reload()); err != nil {
+		return fmt.Errorf("failed to reload page: %w", err)
+	}
+
+	return m.waitForNewPage()
+}
+
+// waitForNewPage waits for the page a history navigation or reload loaded
+// and sets it up like Navigate does
+func (m *ChromeDPManager) waitForNewPage() error {
+	if err := m.WaitForPageLoad(m.timeouts.Navigation); err != nil {
+		return err
+	}
+	m.reinjectInspect()
+	return nil
+}
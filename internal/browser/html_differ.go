@@ -4,6 +4,7 @@ import (
 	"crypto/md5"
 	"fmt"
 	"strings"
+	"sync"
 )
 
 // HTMLSnapshot represents a snapshot of HTML content at a specific time
@@ -13,8 +14,9 @@ type HTMLSnapshot struct {
 	Timestamp int64
 }
 
-// HTMLDiffer tracks HTML changes over time
+// HTMLDiffer tracks HTML changes over time. It's safe for concurrent use.
 type HTMLDiffer struct {
+	mu           sync.Mutex // guards lastSnapshot
 	lastSnapshot *HTMLSnapshot
 }
 
@@ -34,6 +36,9 @@ func (d *HTMLDiffer) HasChanged(html string, timestamp int64) (bool, *HTMLSnapsh
 		Timestamp: timestamp,
 	}
 	
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	// If this is the first snapshot, consider it changed
 	if d.lastSnapshot == nil {
 		d.lastSnapshot = snapshot
@@ -50,6 +55,24 @@ func (d *HTMLDiffer) HasChanged(html string, timestamp int64) (bool, *HTMLSnapsh
 	return changed, snapshot
 }
 
+// HasSnapshot reports whether a snapshot has been taken since the differ was
+// created or reset
+func (d *HTMLDiffer) HasSnapshot() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastSnapshot != nil
+}
+
+// maxDiffCells bounds the LCS table, about 8MB; bigger diffs fall back to
+// comparing which lines were added or removed regardless of order
+const maxDiffCells = 1_000_000
+
+// DiffLine is a line of HTML that was added or removed between two snapshots
+type DiffLine struct {
+	Added bool
+	Text  string
+}
+
 // GetChangedSections extracts sections of HTML that have changed
 func (d *HTMLDiffer) GetChangedSections(oldHTML, newHTML string) string {
 	// Simple implementation: if content length changed significantly,
@@ -70,6 +93,105 @@ func (d *HTMLDiffer) GetChangedSections(oldHTML, newHTML string) string {
 	return newHTML
 }
 
+// Diff compares two snapshots line by line, where each tag and each run of
+// text is a line, and returns the lines that were removed or added in page
+// order. It's quadratic in the changed lines, so it's meant for one-off diffs
+// like the diff command rather than every poll.
+func (d *HTMLDiffer) Diff(oldHTML, newHTML string) []DiffLine {
+	a, b := splitHTMLLines(oldHTML), splitHTMLLines(newHTML)
+
+	// Lines shared at the start and end never need the LCS table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	if len(a)*len(b) > maxDiffCells {
+		return diffUnordered(a, b)
+	}
+	return diffLCS(a, b)
+}
+
+// splitHTMLLines breaks HTML into one line per tag or text run, dropping blanks
+func splitHTMLLines(html string) []string {
+	var lines []string
+	for _, chunk := range strings.SplitAfter(strings.ReplaceAll(html, "<", "\n<"), ">") {
+		for _, line := range strings.Split(chunk, "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+// diffLCS diffs two line slices using their longest common subsequence
+func diffLCS(a, b []string) []DiffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []DiffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, DiffLine{Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Added: true, Text: b[j]})
+			j++
+		}
+	}
+	return diff
+}
+
+// diffUnordered reports lines that appear more often in one slice than the
+// other, for diffs too large to align
+func diffUnordered(a, b []string) []DiffLine {
+	counts := make(map[string]int)
+	for _, line := range a {
+		counts[line]++
+	}
+	for _, line := range b {
+		counts[line]--
+	}
+
+	var diff []DiffLine
+	for _, line := range a {
+		if counts[line] > 0 {
+			diff = append(diff, DiffLine{Text: line})
+			counts[line]--
+		}
+	}
+	for _, line := range b {
+		if counts[line] < 0 {
+			diff = append(diff, DiffLine{Added: true, Text: line})
+			counts[line]++
+		}
+	}
+	return diff
+}
+
 // hashHTML creates a hash of HTML content for comparison
 func (d *HTMLDiffer) hashHTML(html string) string {
 	// Normalize whitespace and create hash
@@ -121,7 +243,7 @@ func (d *HTMLDiffer) extractNewContent(oldHTML, newHTML string) string {
 	}
 	
 	// Fallback: return a portion of the new content
-	if len(newHTML) > 1000 {
+	if len(newHTML)-len(oldHTML) > 1000 {
 		return newHTML[len(oldHTML):len(oldHTML)+1000] + "..."
 	}
 	
@@ -130,5 +252,7 @@ func (d *HTMLDiffer) extractNewContent(oldHTML, newHTML string) string {
 
 // Reset resets the differ state
 func (d *HTMLDiffer) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	d.lastSnapshot = nil
 }
\ No newline at end of file
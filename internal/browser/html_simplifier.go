@@ -372,15 +372,21 @@ type InteractiveElement struct {
 	ID         string
 	Class      string
 	TestID     string
+	TodID      string // Value of data-tod, which marks an element as testable
 	Type       string
+	Placeholder string // Placeholder of text inputs
 	Text       string
 	AriaLabel  string
 	Href       string
 	Selector   string
 	FullUrl    string // Full resolved URL for navigation
 	Title      string // Title attribute
+	Role           string // ARIA role, explicit or implied by the tag; empty if it has none
+	AccessibleName string // Computed accessible name, as used by getByRole
 	IsNavigation bool // True if this is a navigation link
 	IsButton   bool   // True if this is a button or button-like element
+	IsDisabled bool   // True if disabled or aria-disabled
+	InShadow   bool   // True if inside a shadow root; Selector then uses ShadowPiercer
 }
 
 // extractElements recursively extracts interactive elements
@@ -401,18 +407,33 @@ func extractElements(n *html.Node, elements *[]InteractiveElement) {
 					elem.Class = attr.Val
 				case "data-testid", "data-test", "data-cy":
 					elem.TestID = attr.Val
+				case "data-tod":
+					elem.TodID = attr.Val
 				case "type":
 					elem.Type = attr.Val
 				case "aria-label":
 					elem.AriaLabel = attr.Val
 				case "href":
 					elem.Href = attr.Val
+				case "title":
+					elem.Title = attr.Val
+				case "placeholder":
+					elem.Placeholder = attr.Val
+				case "role":
+					if fields := strings.Fields(attr.Val); len(fields) > 0 {
+						elem.Role = fields[0]
+					}
 				}
 			}
 
 			// Extract text content
 			elem.Text = extractText(n)
 
+			if elem.Role == "" {
+				elem.Role = implicitRole(elem)
+			}
+			elem.AccessibleName = accessibleName(elem)
+
 			// Build selector
 			elem.Selector = buildSelector(elem)
 
@@ -428,6 +449,12 @@ func extractElements(n *html.Node, elements *[]InteractiveElement) {
 
 // isInteractiveElement checks if a node is an interactive element
 func isInteractiveElement(n *html.Node) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "data-tod" {
+			return true
+		}
+	}
+
 	switch n.DataAtom {
 	case atom.Button, atom.A, atom.Input, atom.Select, atom.Textarea:
 		return true
@@ -464,8 +491,11 @@ func extractTextHelper(n *html.Node, text *strings.Builder) {
 
 // buildSelector builds a CSS selector for an element
 func buildSelector(elem InteractiveElement) string {
+	if elem.TodID != "" {
+		return fmt.Sprintf("[data-tod=%s]", QuoteJS(elem.TodID))
+	}
 	if elem.TestID != "" {
-		return fmt.Sprintf("[data-testid='%s']", elem.TestID)
+		return fmt.Sprintf("[data-testid=%s]", QuoteJS(elem.TestID))
 	}
 	if elem.ID != "" {
 		return fmt.Sprintf("#%s", elem.ID)
@@ -0,0 +1,223 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// inspectBinding is the function the inspect script calls to report a
+// picked element back over CDP
+const inspectBinding = "__todInspectPicked"
+
+// InspectedElement is an element picked in the browser in inspect mode
+type InspectedElement struct {
+	Selector string `json:"selector"`
+	Tag      string `json:"tag"`
+	Text     string `json:"text"`
+}
+
+// inspectSession is a running inspect mode
+type inspectSession struct {
+	mu     sync.Mutex         // guards picks against a pick arriving as it closes
+	cancel context.CancelFunc // stops listening for picks in the current tab
+	picks  chan InspectedElement
+	closed bool
+}
+
+// pick reports a picked element, replacing one not read yet
+func (s *inspectSession) pick(picked InspectedElement) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case <-s.picks:
+	default:
+	}
+	s.picks <- picked
+}
+
+// listen replaces the listener the session's picks come from
+func (s *inspectSession) listen(cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.cancel = cancel
+}
+
+// close stops the session's picks
+func (s *inspectSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if !s.closed {
+		s.closed = true
+		close(s.picks)
+	}
+}
+
+// StartInspect turns on inspect mode: hovering an element in the browser
+// outlines it, and clicking it reports its selector on the returned channel
+// instead of clicking it. Clicks, key presses and form submissions are kept
+// from the page until StopInspect, which closes the channel.
+func (m *ChromeDPManager) StartInspect() (<-chan InspectedElement, error) {
+	if m.inspect != nil {
+		return nil, fmt.Errorf("inspect mode is already on")
+	}
+
+	session := &inspectSession{picks: make(chan InspectedElement, 1)}
+	if err := m.attachInspect(session); err != nil {
+		return nil, fmt.Errorf("failed to start inspect mode: %w", err)
+	}
+	m.inspect = session
+	return session.picks, nil
+}
+
+// attachInspect adds the inspect binding and overlay to the active tab and
+// listens there for session's picks
+func (m *ChromeDPManager) attachInspect(session *inspectSession) error {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	if err := m.run(ctx, runtime.AddBinding(inspectBinding)); err != nil {
+		return err
+	}
+
+	listenCtx, stopListening := context.WithCancel(m.tabCtx())
+	chromedp.ListenTarget(listenCtx, func(ev interface{}) {
+		called, ok := ev.(*runtime.EventBindingCalled)
+		if !ok || called.Name != inspectBinding {
+			return
+		}
+		var picked InspectedElement
+		if err := json.Unmarshal([]byte(called.Payload), &picked); err != nil {
+			logging.Debug("Ignoring inspect payload %q: %v", called.Payload, err)
+			return
+		}
+		session.pick(picked)
+	})
+
+	if err := m.run(ctx, chromedp.Evaluate(inspectScript(true), nil)); err != nil {
+		stopListening()
+		return err
+	}
+	session.listen(stopListening)
+	return nil
+}
+
+// reinjectInspect turns the overlay back on after a navigation replaced the
+// page it was in. The binding added by attachInspect survives navigations.
+func (m *ChromeDPManager) reinjectInspect() {
+	if m.inspect == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+	if err := m.run(ctx, chromedp.Evaluate(inspectScript(true), nil)); err != nil {
+		logging.Warn("Failed to restore inspect mode after navigation: %v", err)
+	}
+}
+
+// StopInspect turns inspect mode off and gives the page its clicks back
+func (m *ChromeDPManager) StopInspect() error {
+	session := m.inspect
+	if session == nil {
+		return nil
+	}
+	m.inspect = nil
+	session.close()
+
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+	if err := m.run(ctx, chromedp.Evaluate(inspectScript(false), nil)); err != nil {
+		return fmt.Errorf("failed to stop inspect mode: %w", err)
+	}
+	if err := m.run(ctx, runtime.RemoveBinding(inspectBinding)); err != nil {
+		logging.Debug("Failed to remove inspect binding: %v", err)
+	}
+	return nil
+}
+
+// IsInspecting reports whether inspect mode is on
+func (m *ChromeDPManager) IsInspecting() bool {
+	return m.inspect != nil
+}
+
+// inspectScript returns the script that turns the page's inspect overlay on
+// or off. Listeners are added in the capture phase on window so they run
+// before the page's own and can stop the event reaching it.
+func inspectScript(on bool) string {
+	return fmt.Sprintf(`
+		(() => {
+			if (window.__todInspect) {
+				window.__todInspect.stop();
+				delete window.__todInspect;
+			}
+			if (!%t) return;
+
+			%s
+
+			const box = document.createElement('div');
+			box.style.cssText = 'position:fixed;pointer-events:none;z-index:2147483647;' +
+				'border:2px solid #ff6b35;background:rgba(255,107,53,0.15);display:none;';
+			const badge = document.createElement('div');
+			badge.textContent = 'Tod inspect mode: click an element to pick its selector';
+			badge.style.cssText = 'position:fixed;top:8px;right:8px;z-index:2147483647;pointer-events:none;' +
+				'background:#ff6b35;color:#fff;font:12px sans-serif;padding:4px 8px;border-radius:4px;';
+			document.documentElement.append(box, badge);
+
+			const outline = el => {
+				const rect = el.getBoundingClientRect();
+				Object.assign(box.style, {
+					display: 'block',
+					top: rect.top + 'px', left: rect.left + 'px',
+					width: rect.width + 'px', height: rect.height + 'px',
+				});
+			};
+			const onMove = e => {
+				if (e.target instanceof Element) outline(e.target);
+			};
+			const block = e => {
+				e.preventDefault();
+				e.stopImmediatePropagation();
+			};
+			const onClick = e => {
+				block(e);
+				const el = e.target;
+				if (!(el instanceof Element)) return;
+				window.%s(JSON.stringify({
+					selector: generateSelector(el),
+					tag: el.tagName.toLowerCase(),
+					text: (el.innerText || el.value || '').replace(/\s+/g, ' ').trim().slice(0, 80),
+				}));
+			};
+
+			const blocked = ['mousedown', 'mouseup', 'pointerdown', 'pointerup', 'dblclick',
+				'contextmenu', 'submit', 'keydown', 'keypress', 'keyup'];
+			window.addEventListener('mousemove', onMove, true);
+			window.addEventListener('click', onClick, true);
+			blocked.forEach(type => window.addEventListener(type, block, true));
+
+			window.__todInspect = {
+				stop() {
+					window.removeEventListener('mousemove', onMove, true);
+					window.removeEventListener('click', onClick, true);
+					blocked.forEach(type => window.removeEventListener(type, block, true));
+					box.remove();
+					badge.remove();
+				},
+			};
+		})()
+	`, on, generateSelectorJS, inspectBinding)
+}
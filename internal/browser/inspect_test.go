@@ -0,0 +1,47 @@
+package browser
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInspectSurvivesNavigation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, `<html><body><a id="next" href="/next">Next</a></body></html>`)
+	}))
+	t.Cleanup(server.Close)
+
+	manager := newTestManager(t, server.URL)
+	if err := manager.Navigate(server.URL); err != nil {
+		t.Fatalf("Navigate failed: %v", err)
+	}
+	if _, err := manager.StartInspect(); err != nil {
+		t.Fatalf("StartInspect failed: %v", err)
+	}
+	t.Cleanup(func() { manager.StopInspect() })
+
+	navigations := []struct {
+		name     string
+		navigate func() error
+	}{
+		{"navigate", func() error { return manager.Navigate(server.URL + "/next") }},
+		{"back", manager.Back},
+		{"forward", manager.Forward},
+		{"reload", manager.Reload},
+	}
+	for _, nav := range navigations {
+		if err := nav.navigate(); err != nil {
+			t.Fatalf("%s failed: %v", nav.name, err)
+		}
+		var on bool
+		if err := manager.ExecuteScript(`!!window.__todInspect`, &on); err != nil {
+			t.Fatalf("ExecuteScript failed: %v", err)
+		}
+		if !on {
+			t.Errorf("inspect overlay missing after %s", nav.name)
+		}
+	}
+}
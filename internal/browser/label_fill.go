@@ -0,0 +1,108 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// labelMatch is the field the label lookup script found
+type labelMatch struct {
+	Selector string `json:"selector"`
+	Via      string `json:"via"` // how the label was associated, for the log
+}
+
+// FillFieldByLabel fills the field a person would call label, whatever its
+// name or id. The field is found, in order, by a <label> naming it (with
+// for/id or by containing it), aria-labelledby, aria-label, its placeholder
+// and finally text just before it on the page. Exact matches win over
+// partial ones, and visible fields over hidden ones.
+func (m *ChromeDPManager) FillFieldByLabel(label, value string) error {
+	selector, err := m.FindFieldByLabel(label)
+	if err != nil {
+		return err
+	}
+	return m.FillFormField(selector, value)
+}
+
+// FindFieldByLabel returns a selector for the field labelled label, found as
+// FillFieldByLabel describes
+func (m *ChromeDPManager) FindFieldByLabel(label string) (string, error) {
+	if strings.TrimSpace(label) == "" {
+		return "", fmt.Errorf("no field label given")
+	}
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Element)
+	defer cancel()
+
+	var match *labelMatch
+	if err := m.run(ctx, chromedp.Evaluate(fieldByLabelScript(label), &match)); err != nil {
+		return "", fmt.Errorf("failed to look up field %q: %w", label, err)
+	}
+	if match == nil || match.Selector == "" {
+		return "", fmt.Errorf("no field labelled %q on this page", label)
+	}
+	logging.Debug("Field %q found by %s: %s", label, match.Via, match.Selector)
+	return match.Selector, nil
+}
+
+// fieldByLabelScript returns the script finding the field labelled label
+func fieldByLabelScript(label string) string {
+	return fmt.Sprintf(`
+		(() => {
+			%s
+
+			const normalize = text => (text || '').replace(/[*:]/g, '').replace(/\s+/g, ' ').trim().toLowerCase();
+			const wanted = normalize(%s);
+			const fillable = el => {
+				if (!el || el.disabled || el.readOnly) return false;
+				const tag = el.tagName.toLowerCase();
+				if (tag === 'textarea' || el.isContentEditable) return true;
+				if (tag !== 'input') return false;
+				const type = (el.getAttribute('type') || 'text').toLowerCase();
+				return !['hidden', 'submit', 'button', 'reset', 'image', 'checkbox', 'radio', 'file'].includes(type);
+			};
+			const visible = el => el.getClientRects().length > 0 && getComputedStyle(el).visibility !== 'hidden';
+			const fields = Array.from(document.querySelectorAll('input, textarea, [contenteditable=""], [contenteditable=true]')).filter(fillable);
+
+			// Each strategy maps a field to the text labelling it that way
+			const strategies = [
+				['label', el => Array.from(el.labels || []).map(l => l.innerText)],
+				['aria-labelledby', el => (el.getAttribute('aria-labelledby') || '').split(/\s+/)
+					.map(id => document.getElementById(id)).filter(Boolean).map(l => l.innerText)],
+				['aria-label', el => [el.getAttribute('aria-label')]],
+				['placeholder', el => [el.getAttribute('placeholder')]],
+				['nearby text', el => [textBefore(el)]],
+			];
+
+			// textBefore finds the text closest before a field within its
+			// nearest few containers, for labels that aren't <label>s
+			function textBefore(el) {
+				let node = el;
+				for (let depth = 0; depth < 3 && node.parentElement; depth++) {
+					let sibling = node.previousElementSibling;
+					while (sibling) {
+						if (!sibling.matches('input, textarea, select, button')) {
+							const text = sibling.innerText;
+							if (text && text.trim()) return text;
+						}
+						sibling = sibling.previousElementSibling;
+					}
+					node = node.parentElement;
+				}
+				return '';
+			}
+
+			for (const matches of [t => t === wanted, t => t !== '' && t.includes(wanted)]) {
+				for (const [via, texts] of strategies) {
+					const candidates = fields.filter(el => texts(el).some(t => matches(normalize(t))));
+					const el = candidates.find(visible) || candidates[0];
+					if (el) return { selector: generateSelector(el), via };
+				}
+			}
+			return null;
+		})()
+	`, generateSelectorJS, QuoteJS(label))
+}
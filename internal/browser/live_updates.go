@@ -0,0 +1,109 @@
+package browser
+
+import (
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// Default bounds for waiting on WebSocket/EventSource traffic to settle
+const (
+	DefaultLiveUpdateQuietPeriod = 500 * time.Millisecond
+	DefaultLiveUpdateMaxWait     = 3 * time.Second
+)
+
+// liveActivityTracker records WebSocket and EventSource traffic so that
+// post-action waits can account for pages that update without new requests
+type liveActivityTracker struct {
+	mu           sync.Mutex
+	openSockets  map[network.RequestID]string
+	lastActivity time.Time
+}
+
+// newLiveActivityTracker creates an empty tracker
+func newLiveActivityTracker() *liveActivityTracker {
+	return &liveActivityTracker{
+		openSockets: make(map[network.RequestID]string),
+	}
+}
+
+// handleEvent updates the tracker from a network domain event
+func (t *liveActivityTracker) handleEvent(ev interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch e := ev.(type) {
+	case *network.EventWebSocketCreated:
+		t.openSockets[e.RequestID] = e.URL
+		t.lastActivity = time.Now()
+	case *network.EventWebSocketFrameReceived:
+		t.lastActivity = time.Now()
+	case *network.EventWebSocketClosed:
+		delete(t.openSockets, e.RequestID)
+	case *network.EventEventSourceMessageReceived:
+		t.lastActivity = time.Now()
+	}
+}
+
+// snapshot returns the number of open sockets and the last activity time
+func (t *liveActivityTracker) snapshot() (int, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.openSockets), t.lastActivity
+}
+
+// startLiveActivityTracking tracks WebSocket and EventSource events;
+// listenTab attaches it to each tab. chromedp enables the network domain
+// when it attaches to a target.
+func (m *ChromeDPManager) startLiveActivityTracking() {
+	m.liveActivity = newLiveActivityTracker()
+}
+
+// HasLiveConnections reports whether the page has open WebSocket connections
+// or has received EventSource messages recently
+func (m *ChromeDPManager) HasLiveConnections() bool {
+	if m.liveActivity == nil {
+		return false
+	}
+	open, last := m.liveActivity.snapshot()
+	return open > 0 || time.Since(last) < DefaultLiveUpdateMaxWait
+}
+
+// WaitForLiveUpdatesToSettle waits until no WebSocket frames or EventSource
+// messages have arrived for the quiet period, bounded by maxWait. It returns
+// true if live update traffic was observed during the wait.
+func (m *ChromeDPManager) WaitForLiveUpdatesToSettle(quiet, maxWait time.Duration) bool {
+	if m.liveActivity == nil {
+		return false
+	}
+	if quiet <= 0 {
+		quiet = DefaultLiveUpdateQuietPeriod
+	}
+	if maxWait <= 0 {
+		maxWait = DefaultLiveUpdateMaxWait
+	}
+
+	deadline := time.Now().Add(maxWait)
+	detected := false
+
+	for {
+		_, last := m.liveActivity.snapshot()
+		if last.IsZero() || time.Since(last) >= quiet {
+			return detected
+		}
+		detected = true
+
+		if time.Now().After(deadline) {
+			logging.Debug("Live updates still streaming after %v, continuing", maxWait)
+			return detected
+		}
+
+		select {
+		case <-m.tabCtx().Done():
+			return detected
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package browser
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// Limits of CaptureNetworkDuring: how much of each request and response body
+// is kept, how long the network must be quiet before the capture ends, and
+// the longest it waits for requests still in flight
+const (
+	NetworkBodyLimit      = 64 * 1024
+	networkQuietPeriod    = 300 * time.Millisecond
+	networkCaptureMaxWait = 5 * time.Second
+)
+
+// staticResourceTypes are requests for page assets rather than API calls
+var staticResourceTypes = map[network.ResourceType]bool{
+	network.ResourceTypeImage:      true,
+	network.ResourceTypeMedia:      true,
+	network.ResourceTypeFont:       true,
+	network.ResourceTypeStylesheet: true,
+	network.ResourceTypeScript:     true,
+	network.ResourceTypeManifest:   true,
+	network.ResourceTypeTextTrack:  true,
+}
+
+// staticExtensions are asset file types, for requests without a resource type
+var staticExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".map": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".ico": true, ".avif": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".mp4": true, ".webm": true, ".mp3": true,
+}
+
+// NetworkRequest is a request an action triggered and how it was answered
+type NetworkRequest struct {
+	Method        string        `json:"method"`
+	URL           string        `json:"url"`
+	ResourceType  string        `json:"resource_type"` // XHR, Fetch, Document, ...
+	Status        int           `json:"status"`        // 0 if no response arrived
+	MIMEType      string        `json:"mime_type,omitempty"`
+	RequestBody   string        `json:"request_body,omitempty"`
+	ResponseBody  string        `json:"response_body,omitempty"`
+	BodyTruncated bool          `json:"body_truncated,omitempty"` // a body was cut at NetworkBodyLimit
+	Failed        string        `json:"failed,omitempty"`         // why the request failed, if it did
+	Duration      time.Duration `json:"duration"`
+
+	id       network.RequestID
+	started  time.Time
+	finished bool
+}
+
+// Path returns the request's URL path, e.g. "/api/login"
+func (r NetworkRequest) Path() string {
+	if u, err := url.Parse(r.URL); err == nil && u.Path != "" {
+		return u.Path
+	}
+	return r.URL
+}
+
+// networkCapture collects the requests sent while it listens
+type networkCapture struct {
+	mu       sync.Mutex
+	requests []*NetworkRequest
+	byID     map[network.RequestID]*NetworkRequest
+	lastSeen time.Time // last request sent or finished
+}
+
+func (c *networkCapture) handleEvent(ev interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch e := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		if e.Request == nil || isStaticRequest(e.Type, e.Request.URL) {
+			return
+		}
+		// A redirect reuses the request ID; the capture follows it to the end
+		if existing, ok := c.byID[e.RequestID]; ok {
+			existing.URL = e.Request.URL
+			existing.Method = e.Request.Method
+			c.lastSeen = time.Now()
+			return
+		}
+		request := &NetworkRequest{
+			Method:       e.Request.Method,
+			URL:          e.Request.URL,
+			ResourceType: string(e.Type),
+			id:           e.RequestID,
+			started:      time.Now(),
+		}
+		request.RequestBody, request.BodyTruncated = truncateBody(decodePostData(e.Request.PostDataEntries))
+		c.requests = append(c.requests, request)
+		c.byID[e.RequestID] = request
+		c.lastSeen = time.Now()
+
+	case *network.EventResponseReceived:
+		if request, ok := c.byID[e.RequestID]; ok && e.Response != nil {
+			request.Status = int(e.Response.Status)
+			request.MIMEType = e.Response.MimeType
+		}
+
+	case *network.EventLoadingFinished:
+		if request, ok := c.byID[e.RequestID]; ok {
+			request.finished = true
+			request.Duration = time.Since(request.started)
+			c.lastSeen = time.Now()
+		}
+
+	case *network.EventLoadingFailed:
+		if request, ok := c.byID[e.RequestID]; ok {
+			request.finished = true
+			request.Failed = e.ErrorText
+			request.Duration = time.Since(request.started)
+			c.lastSeen = time.Now()
+		}
+	}
+}
+
+// settled reports whether no request is in flight and none has started or
+// finished for networkQuietPeriod
+func (c *networkCapture) settled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, request := range c.requests {
+		if !request.finished {
+			return false
+		}
+	}
+	return time.Since(c.lastSeen) >= networkQuietPeriod
+}
+
+// CaptureNetworkDuring runs action and returns the requests it triggered,
+// such as the API call a submit button makes. Requests for static assets
+// are left out. After action returns, the capture waits for the requests
+// still in flight, up to a few seconds, and then reads the text response
+// bodies. Bodies are cut at NetworkBodyLimit. The requests are the ones the
+// request capture sees, from every tab.
+func (m *ChromeDPManager) CaptureNetworkDuring(action func() error) ([]NetworkRequest, error) {
+	capture := &networkCapture{byID: make(map[network.RequestID]*NetworkRequest), lastSeen: time.Now()}
+	stopListening := m.requestCapture.watch(capture)
+
+	err := action()
+
+	// Requests a click sets off often start a moment after it
+	capture.mu.Lock()
+	capture.lastSeen = time.Now()
+	capture.mu.Unlock()
+
+	deadline := time.Now().Add(networkCaptureMaxWait)
+	for !capture.settled() && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	stopListening()
+
+	capture.mu.Lock()
+	requests := make([]NetworkRequest, 0, len(capture.requests))
+	for _, request := range capture.requests {
+		requests = append(requests, *request)
+	}
+	capture.mu.Unlock()
+
+	for i := range requests {
+		if requests[i].finished && requests[i].Failed == "" && isTextMIME(requests[i].MIMEType) {
+			m.readResponseBody(&requests[i])
+		}
+	}
+	return requests, err
+}
+
+// readResponseBody fills in a finished request's response body, cut at
+// NetworkBodyLimit
+func (m *ChromeDPManager) readResponseBody(request *NetworkRequest) {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	var body []byte
+	err := m.run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		body, err = network.GetResponseBody(request.id).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		// Chrome drops bodies of some responses, like redirects, right away
+		logging.Debug("No response body for %s %s: %v", request.Method, request.URL, err)
+		return
+	}
+	var truncated bool
+	request.ResponseBody, truncated = truncateBody(string(body))
+	request.BodyTruncated = request.BodyTruncated || truncated
+}
+
+// isStaticRequest reports whether a request is for a page asset rather than
+// an API call or page load
+func isStaticRequest(resourceType network.ResourceType, rawURL string) bool {
+	if staticResourceTypes[resourceType] {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "data" || u.Scheme == "blob" {
+		return true
+	}
+	return staticExtensions[strings.ToLower(path.Ext(u.Path))]
+}
+
+// isTextMIME reports whether a response body is worth reading as text
+func isTextMIME(mimeType string) bool {
+	mimeType = strings.ToLower(mimeType)
+	return strings.HasPrefix(mimeType, "text/") ||
+		strings.Contains(mimeType, "json") ||
+		strings.Contains(mimeType, "xml") ||
+		strings.Contains(mimeType, "x-www-form-urlencoded")
+}
+
+// truncateBody cuts a body at NetworkBodyLimit, reporting whether it did
+func truncateBody(body string) (string, bool) {
+	if len(body) <= NetworkBodyLimit {
+		return body, false
+	}
+	return body[:NetworkBodyLimit], true
+}
@@ -0,0 +1,86 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// NetworkConditions describes emulated network conditions for the page
+type NetworkConditions struct {
+	Offline            bool
+	Latency            time.Duration
+	DownloadThroughput float64 // bytes per second, -1 for unlimited
+	UploadThroughput   float64 // bytes per second, -1 for unlimited
+}
+
+// NoThrottling is the browser's default, unthrottled network
+var NoThrottling = NetworkConditions{DownloadThroughput: -1, UploadThroughput: -1}
+
+// SetNetworkConditions emulates the given network conditions
+func (m *ChromeDPManager) SetNetworkConditions(conditions NetworkConditions) error {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	latency := float64(conditions.Latency.Milliseconds())
+	if err := m.run(ctx, network.EmulateNetworkConditions(
+		conditions.Offline,
+		latency,
+		conditions.DownloadThroughput,
+		conditions.UploadThroughput,
+	)); err != nil {
+		return fmt.Errorf("failed to set network conditions: %w", err)
+	}
+
+	m.networkConditions = conditions
+	return nil
+}
+
+// NetworkConditions returns the network conditions currently emulated
+func (m *ChromeDPManager) NetworkConditions() NetworkConditions {
+	return m.networkConditions
+}
+
+// IsOffline reports whether the network is emulated as offline
+func (m *ChromeDPManager) IsOffline() bool {
+	return m.networkConditions.Offline
+}
+
+// GoOffline cuts the page's network access, remembering the current
+// conditions so GoOnline can restore them
+func (m *ChromeDPManager) GoOffline() error {
+	if m.networkConditions.Offline {
+		return nil
+	}
+
+	previous := m.networkConditions
+	offline := previous
+	offline.Offline = true
+	if err := m.SetNetworkConditions(offline); err != nil {
+		return err
+	}
+
+	m.onlineConditions = &previous
+	return nil
+}
+
+// GoOnline restores the network conditions that were active before GoOffline
+func (m *ChromeDPManager) GoOnline() error {
+	if !m.networkConditions.Offline {
+		return nil
+	}
+
+	restore := NoThrottling
+	if m.onlineConditions != nil {
+		restore = *m.onlineConditions
+	}
+	restore.Offline = false
+	if err := m.SetNetworkConditions(restore); err != nil {
+		return err
+	}
+
+	m.onlineConditions = nil
+	return nil
+}
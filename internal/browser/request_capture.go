@@ -0,0 +1,246 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// maxCapturedRequests bounds the in-memory request history
+const maxCapturedRequests = 100
+
+// RequestRecord is a captured network request that can be replayed
+type RequestRecord struct {
+	Method       string            `json:"method"`
+	URL          string            `json:"url"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	PostData     string            `json:"post_data,omitempty"`
+	ResourceType string            `json:"resource_type"` // XHR, Fetch, Document, ...
+	Timestamp    time.Time         `json:"timestamp"`
+
+	// Omit browser cookies when replaying; set by StripSessionTokens
+	OmitCredentials bool `json:"omit_credentials,omitempty"`
+}
+
+// sessionHeaders carry credentials and are dropped by StripSessionTokens
+var sessionHeaders = []string{"cookie", "authorization", "x-csrf-token", "x-xsrf-token", "x-auth-token"}
+
+// forbiddenReplayHeaders can't be set from fetch() and are managed by the browser
+var forbiddenReplayHeaders = []string{"cookie", "host", "content-length", "origin", "referer", "connection", "accept-encoding"}
+
+// StripSessionTokens returns a copy of the record without credential headers,
+// useful for checking that an endpoint rejects unauthenticated calls
+func (r RequestRecord) StripSessionTokens() RequestRecord {
+	stripped := r
+	stripped.OmitCredentials = true
+	stripped.Headers = make(map[string]string, len(r.Headers))
+	for name, value := range r.Headers {
+		if !containsHeader(sessionHeaders, name) {
+			stripped.Headers[name] = value
+		}
+	}
+	return stripped
+}
+
+// containsHeader reports whether name is in list, ignoring case
+func containsHeader(list []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, h := range list {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+// requestCapture keeps a bounded history of outgoing requests, and passes
+// the network events of every tab on to the network captures watching it
+type requestCapture struct {
+	mu       sync.Mutex
+	requests []RequestRecord
+	watchers map[*networkCapture]bool
+}
+
+// handleEvent records outgoing requests from network domain events
+func (c *requestCapture) handleEvent(ev interface{}) {
+	if e, ok := ev.(*network.EventRequestWillBeSent); ok && e.Request != nil {
+		c.record(e)
+	}
+
+	c.mu.Lock()
+	watchers := make([]*networkCapture, 0, len(c.watchers))
+	for watcher := range c.watchers {
+		watchers = append(watchers, watcher)
+	}
+	c.mu.Unlock()
+	for _, watcher := range watchers {
+		watcher.handleEvent(ev)
+	}
+}
+
+// watch passes network events to capture until stop is called
+func (c *requestCapture) watch(capture *networkCapture) (stop func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.watchers == nil {
+		c.watchers = make(map[*networkCapture]bool)
+	}
+	c.watchers[capture] = true
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.watchers, capture)
+	}
+}
+
+// record adds an outgoing request to the history
+func (c *requestCapture) record(e *network.EventRequestWillBeSent) {
+
+	headers := make(map[string]string, len(e.Request.Headers))
+	for name, value := range e.Request.Headers {
+		headers[name] = fmt.Sprint(value)
+	}
+
+	record := RequestRecord{
+		Method:       e.Request.Method,
+		URL:          e.Request.URL,
+		Headers:      headers,
+		PostData:     decodePostData(e.Request.PostDataEntries),
+		ResourceType: string(e.Type),
+		Timestamp:    time.Now(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, record)
+	if len(c.requests) > maxCapturedRequests {
+		c.requests = c.requests[len(c.requests)-maxCapturedRequests:]
+	}
+}
+
+// decodePostData joins a request's body from its post data entries, which
+// the protocol sends base64 encoded
+func decodePostData(entries []*network.PostDataEntry) string {
+	var postData strings.Builder
+	for _, entry := range entries {
+		decoded, err := base64.StdEncoding.DecodeString(entry.Bytes)
+		if err != nil {
+			// Keep what we were given rather than lose the body
+			postData.WriteString(entry.Bytes)
+			continue
+		}
+		postData.Write(decoded)
+	}
+	return postData.String()
+}
+
+// startRequestCapture records outgoing requests; listenTab attaches it to
+// each tab
+func (m *ChromeDPManager) startRequestCapture() {
+	m.requestCapture = &requestCapture{}
+}
+
+// RequestsSince returns captured requests sent at or after the given time
+func (m *ChromeDPManager) RequestsSince(since time.Time) []RequestRecord {
+	if m.requestCapture == nil {
+		return nil
+	}
+
+	m.requestCapture.mu.Lock()
+	defer m.requestCapture.mu.Unlock()
+
+	var records []RequestRecord
+	for _, r := range m.requestCapture.requests {
+		if !r.Timestamp.Before(since) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// FindSubmitRequest returns the first state-changing request sent since the
+// given time, which is usually the one a form submission triggered
+func (m *ChromeDPManager) FindSubmitRequest(since time.Time) *RequestRecord {
+	for _, r := range m.RequestsSince(since) {
+		if r.Method != "GET" && r.Method != "OPTIONS" {
+			record := r
+			return &record
+		}
+	}
+	return nil
+}
+
+// ReplayRequest re-issues a captured request from the page using fetch, so the
+// browser's cookies still apply unless the record's tokens were stripped.
+// It returns the response status and body.
+func (m *ChromeDPManager) ReplayRequest(record RequestRecord) (status int, body string, err error) {
+	if m.skipInDryRun("replay %s %s", record.Method, redactURL(record.URL)) {
+		return 0, "", nil
+	}
+	headers := make(map[string]string, len(record.Headers))
+	for name, value := range record.Headers {
+		if !containsHeader(forbiddenReplayHeaders, name) && !strings.HasPrefix(name, ":") {
+			headers[name] = value
+		}
+	}
+
+	credentials := "include"
+	if record.OmitCredentials {
+		credentials = "omit"
+	}
+
+	init := map[string]interface{}{
+		"method":      record.Method,
+		"headers":     headers,
+		"credentials": credentials,
+	}
+	if record.PostData != "" && record.Method != "GET" && record.Method != "HEAD" {
+		init["body"] = record.PostData
+	}
+
+	urlJSON, err := json.Marshal(record.URL)
+	if err != nil {
+		return 0, "", err
+	}
+	initJSON, err := json.Marshal(init)
+	if err != nil {
+		return 0, "", err
+	}
+
+	script := fmt.Sprintf(`
+		(async () => {
+			const response = await fetch(%s, %s);
+			return { status: response.status, body: await response.text() };
+		})()
+	`, urlJSON, initJSON)
+
+	var result struct {
+		Status int    `json:"status"`
+		Body   string `json:"body"`
+	}
+	if err := m.ExecuteScriptAsync(script, &result); err != nil {
+		return 0, "", fmt.Errorf("failed to replay %s %s: %w", record.Method, record.URL, err)
+	}
+
+	return result.Status, result.Body, nil
+}
+
+// ExecuteScriptAsync executes JavaScript and waits for the returned promise
+func (m *ChromeDPManager) ExecuteScriptAsync(script string, result interface{}) error {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Capture)
+	defer cancel()
+
+	return m.run(ctx,
+		chromedp.Evaluate(script, result, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		}),
+	)
+}
@@ -0,0 +1,129 @@
+package browser
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+func TestHandleEventDecodesPostData(t *testing.T) {
+	body := `{"name":"Ada","admin":false}`
+	capture := &requestCapture{}
+	capture.handleEvent(&network.EventRequestWillBeSent{
+		Type: network.ResourceTypeFetch,
+		Request: &network.Request{
+			Method: "POST",
+			URL:    "http://localhost/api/users",
+			PostDataEntries: []*network.PostDataEntry{
+				{Bytes: base64.StdEncoding.EncodeToString([]byte(body[:10]))},
+				{Bytes: base64.StdEncoding.EncodeToString([]byte(body[10:]))},
+			},
+		},
+	})
+
+	if len(capture.requests) != 1 {
+		t.Fatalf("captured %d requests, want 1", len(capture.requests))
+	}
+	if got := capture.requests[0].PostData; got != body {
+		t.Errorf("PostData = %q, want %q", got, body)
+	}
+}
+
+func TestReplayRequestSendsJSONBody(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Content-Type", "text/html")
+			io.WriteString(w, "<html><body>ok</body></html>")
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, "created")
+	}))
+	defer server.Close()
+
+	manager := newTestManager(t, server.URL)
+
+	const body = `{"name":"Ada","tags":["a","b"]}`
+	since := time.Now()
+	var status int
+	err := manager.ExecuteScriptAsync(`fetch("/api/users", {
+		method: "POST",
+		headers: {"Content-Type": "application/json"},
+		body: JSON.stringify({name: "Ada", tags: ["a", "b"]}),
+	}).then(r => r.status)`, &status)
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	var record *RequestRecord
+	for deadline := time.Now().Add(5 * time.Second); record == nil && time.Now().Before(deadline); {
+		if record = manager.FindSubmitRequest(since); record == nil {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	if record == nil {
+		t.Fatal("POST request was not captured")
+	}
+	if record.PostData != body {
+		t.Fatalf("captured PostData = %q, want %q", record.PostData, body)
+	}
+
+	replayStatus, _, err := manager.ReplayRequest(*record)
+	if err != nil {
+		t.Fatalf("ReplayRequest failed: %v", err)
+	}
+	if replayStatus != http.StatusCreated {
+		t.Errorf("replay status = %d, want %d", replayStatus, http.StatusCreated)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 || bodies[1] != body {
+		t.Errorf("server received bodies %q, want the replay to send %q", bodies, body)
+	}
+}
+
+func TestNetworkCaptureWatchesRequestCapture(t *testing.T) {
+	capture := &requestCapture{}
+	watcher := &networkCapture{byID: make(map[network.RequestID]*NetworkRequest)}
+	stop := capture.watch(watcher)
+
+	send := func(id network.RequestID, body string) {
+		capture.handleEvent(&network.EventRequestWillBeSent{
+			RequestID: id,
+			Type:      network.ResourceTypeXHR,
+			Request: &network.Request{
+				Method:          "POST",
+				URL:             "http://localhost/api/login",
+				PostDataEntries: []*network.PostDataEntry{{Bytes: base64.StdEncoding.EncodeToString([]byte(body))}},
+			},
+		})
+	}
+	send("1", "user=ada")
+	stop()
+	send("2", "user=grace")
+
+	if len(watcher.requests) != 1 {
+		t.Fatalf("watcher saw %d requests, want 1 before it stopped", len(watcher.requests))
+	}
+	if got := watcher.requests[0].RequestBody; got != "user=ada" {
+		t.Errorf("RequestBody = %q, want %q", got, "user=ada")
+	}
+	if len(capture.requests) != 2 {
+		t.Errorf("request capture recorded %d requests, want 2", len(capture.requests))
+	}
+}
@@ -0,0 +1,122 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// richTextSettleDelay gives an editor time to re-render before the value is
+// read back
+const richTextSettleDelay = 150 * time.Millisecond
+
+// richTextResult is what the FillRichText script reports back
+type richTextResult struct {
+	Found bool   `json:"found"`
+	Text  string `json:"text"`
+}
+
+// FillRichText replaces the content of a contenteditable element, such as a
+// Slate, ProseMirror or Lexical editor. The content is selected and replaced
+// with execCommand, which fires trusted beforeinput and input events so the
+// editor updates its own state rather than just the DOM; where that isn't
+// supported the events are dispatched by hand. The value is read back after
+// the editor re-renders to check it stuck.
+func (m *ChromeDPManager) FillRichText(selector, value string) error {
+	if m.skipInDryRun("fill rich text %s with %q", selector, value) {
+		return nil
+	}
+	return m.fillRichText(selector, value)
+}
+
+func (m *ChromeDPManager) fillRichText(selector, value string) error {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Element)
+	defer cancel()
+
+	script := fmt.Sprintf(`
+		(() => {
+			const el = %s;
+			if (!el) return { found: false, text: '' };
+			const value = %s;
+
+			// Edit the editor root, not a paragraph inside it
+			let target = el;
+			while (target.parentElement && target.parentElement.isContentEditable) {
+				target = target.parentElement;
+			}
+			target.focus();
+
+			const range = document.createRange();
+			range.selectNodeContents(target);
+			const selection = window.getSelection();
+			selection.removeAllRanges();
+			selection.addRange(range);
+
+			const handled = value === ''
+				? document.execCommand('delete')
+				: document.execCommand('insertText', false, value);
+			if (!handled) {
+				const before = new InputEvent('beforeinput', {
+					inputType: 'insertReplacementText', data: value, bubbles: true, cancelable: true
+				});
+				// An editor that cancels beforeinput applies the change itself
+				if (target.dispatchEvent(before)) {
+					range.deleteContents();
+					range.insertNode(document.createTextNode(value));
+				}
+				target.dispatchEvent(new InputEvent('input', {
+					inputType: 'insertReplacementText', data: value, bubbles: true
+				}));
+			}
+			return { found: true, text: target.innerText };
+		})()
+	`, queryElementJS(selector), QuoteJS(value))
+
+	var result richTextResult
+	if err := m.run(ctx, chromedp.Evaluate(script, &result)); err != nil {
+		return fmt.Errorf("failed to fill rich text %s: %w", selector, err)
+	}
+	if !result.Found {
+		return fmt.Errorf("rich text element not found: %s", selector)
+	}
+
+	// Editors that keep their own state re-render from it, so a value only
+	// written to the DOM would be gone by now
+	time.Sleep(richTextSettleDelay)
+	var text string
+	readBack := fmt.Sprintf(`
+		(() => {
+			let el = %s;
+			if (!el) return '';
+			while (el.parentElement && el.parentElement.isContentEditable) el = el.parentElement;
+			return el.innerText;
+		})()
+	`, queryElementJS(selector))
+	if err := m.run(ctx, chromedp.Evaluate(readBack, &text)); err != nil {
+		return fmt.Errorf("failed to read back rich text %s: %w", selector, err)
+	}
+	if normalizeSpace(text) != normalizeSpace(value) {
+		return fmt.Errorf("rich text editor %s shows %q after filling in %q", selector, normalizeSpace(text), value)
+	}
+	return nil
+}
+
+// isContentEditable reports whether the element matching selector is edited
+// as rich text rather than as a form control
+func (m *ChromeDPManager) isContentEditable(ctx context.Context, selector string) bool {
+	var editable bool
+	script := fmt.Sprintf(`(() => { const el = %s; return !!el && el.isContentEditable; })()`, queryElementJS(selector))
+	if err := m.run(ctx, chromedp.Evaluate(script, &editable)); err != nil {
+		return false
+	}
+	return editable
+}
+
+// normalizeSpace collapses runs of whitespace, including the non-breaking
+// spaces editors use, into single spaces
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
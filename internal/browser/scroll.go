@@ -0,0 +1,53 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Infinite-scroll pages load more content after scrolling; this is how long
+// to watch for it
+const (
+	scrollLoadTimeout  = 2 * time.Second
+	scrollLoadInterval = 250 * time.Millisecond
+)
+
+// ScrollIntoView scrolls the page until the element matching selector is visible
+func (m *ChromeDPManager) ScrollIntoView(selector string) error {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	var scroll chromedp.Action = chromedp.ScrollIntoView(selector, chromedp.ByQuery)
+	if IsShadowSelector(selector) {
+		scroll = chromedp.Evaluate(fmt.Sprintf(`(() => { const el = %s; if (el) el.scrollIntoView({ block: 'center', inline: 'center' }); })()`, queryElementJS(selector)), nil)
+	}
+	if err := m.run(ctx, scroll); err != nil {
+		return fmt.Errorf("failed to scroll to %s: %w", selector, err)
+	}
+	return nil
+}
+
+// ScrollToTop scrolls to the top of the page
+func (m *ChromeDPManager) ScrollToTop() error {
+	return m.ExecuteScript(`window.scrollTo(0, 0)`, nil)
+}
+
+// ScrollToBottom scrolls to the bottom of the page and reports whether new
+// content loaded in response, as on infinite-scroll pages
+func (m *ChromeDPManager) ScrollToBottom() (bool, error) {
+	changes := m.PollForChanges(scrollLoadTimeout, scrollLoadInterval, 0)
+
+	// Let the poller take its initial snapshot before scrolling
+	if first, ok := <-changes; !ok || !first.IsInitial {
+		return false, fmt.Errorf("failed to snapshot the page before scrolling")
+	}
+	if err := m.ExecuteScript(`window.scrollTo(0, document.documentElement.scrollHeight)`, nil); err != nil {
+		return false, fmt.Errorf("failed to scroll to the bottom: %w", err)
+	}
+
+	_, loaded := <-changes
+	return loaded, nil
+}
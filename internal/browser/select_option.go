@@ -0,0 +1,99 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// SelectOptionInfo describes one option of a <select> element
+type SelectOptionInfo struct {
+	Value    string `json:"value"`
+	Text     string `json:"text"`
+	Selected bool   `json:"selected"`
+}
+
+// GetSelectOptions returns the options of a <select> element
+func (m *ChromeDPManager) GetSelectOptions(selector string) ([]SelectOptionInfo, error) {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	script := fmt.Sprintf(`
+		(() => {
+			const el = document.querySelector(%s);
+			if (!el || el.tagName.toLowerCase() !== 'select') return null;
+			return Array.from(el.options).map(o => ({
+				value: o.value,
+				text: o.text.trim(),
+				selected: o.selected
+			}));
+		})()
+	`, QuoteJS(selector))
+
+	var options []SelectOptionInfo
+	if err := m.run(ctx, chromedp.Evaluate(script, &options)); err != nil {
+		return nil, fmt.Errorf("failed to read options of %s: %w", selector, err)
+	}
+	if options == nil {
+		return nil, fmt.Errorf("not a select element: %s", selector)
+	}
+
+	return options, nil
+}
+
+// SelectOption chooses an option in a <select> element and fires the input and
+// change events. value matches an option's value attribute first, then its
+// visible text (case-insensitive), since the two often differ.
+func (m *ChromeDPManager) SelectOption(selector, value string) error {
+	if err := m.checkActionable(selector); err != nil {
+		return err
+	}
+	if m.skipInDryRun("select %q in %s", value, selector) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Action)
+	defer cancel()
+
+	if err := m.run(ctx, chromedp.WaitReady(selector, chromedp.ByQuery)); err != nil {
+		return fmt.Errorf("select element not found: %w", err)
+	}
+
+	script := fmt.Sprintf(`
+		(() => {
+			const el = document.querySelector(%s);
+			if (!el || el.tagName.toLowerCase() !== 'select') return 'not-select';
+			const wanted = %s;
+			const options = Array.from(el.options);
+			const option = options.find(o => o.value === wanted) ||
+				options.find(o => o.text.trim().toLowerCase() === wanted.trim().toLowerCase());
+			if (!option) return 'no-option';
+			el.value = option.value;
+			el.dispatchEvent(new Event('input', { bubbles: true }));
+			el.dispatchEvent(new Event('change', { bubbles: true }));
+			return 'ok';
+		})()
+	`, QuoteJS(selector), QuoteJS(value))
+
+	var result string
+	if err := m.run(ctx, chromedp.Evaluate(script, &result)); err != nil {
+		return fmt.Errorf("failed to select %q: %w", value, err)
+	}
+
+	switch result {
+	case "ok":
+		return nil
+	case "not-select":
+		return fmt.Errorf("not a select element: %s", selector)
+	default:
+		var available []string
+		if options, err := m.GetSelectOptions(selector); err == nil {
+			for _, o := range options {
+				available = append(available, o.Text)
+			}
+		}
+		return fmt.Errorf("no option %q (available: %s)", value, strings.Join(available, ", "))
+	}
+}
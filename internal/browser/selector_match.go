@@ -0,0 +1,80 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSelector is returned for a CSS selector or XPath the page can't parse
+var ErrInvalidSelector = errors.New("invalid selector")
+
+// SelectorMatches is what a selector matched on the current page
+type SelectorMatches struct {
+	Count int      `json:"count"`
+	Texts []string `json:"texts"` // text of the first matches, or their tag if they have none
+	Error string   `json:"error"` // parse error reported by the page
+}
+
+// IsXPath reports whether selector is an XPath expression rather than CSS.
+// CSS selectors can't start with "/" or "(", so those are taken as XPath.
+func IsXPath(selector string) bool {
+	selector = strings.TrimSpace(selector)
+	return strings.HasPrefix(selector, "/") || strings.HasPrefix(selector, "(")
+}
+
+// CountMatches returns how many elements on the page match a CSS selector or
+// an XPath expression (one starting with "//"). A selector that doesn't parse
+// returns ErrInvalidSelector rather than 0 matches.
+func (m *ChromeDPManager) CountMatches(selector string) (int, error) {
+	matches, err := m.MatchSelector(selector, 0)
+	if err != nil {
+		return 0, err
+	}
+	return matches.Count, nil
+}
+
+// MatchSelector is CountMatches also returning the text of the first limit
+// matches
+func (m *ChromeDPManager) MatchSelector(selector string, limit int) (*SelectorMatches, error) {
+	kind := "CSS selector"
+	if IsXPath(selector) {
+		kind = "XPath"
+	}
+
+	script := fmt.Sprintf(`
+		(() => {
+			const selector = %s;
+			const limit = %d;
+			let nodes = [];
+			try {
+				if (%t) {
+					const result = document.evaluate(selector, document, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+					for (let i = 0; i < result.snapshotLength; i++) nodes.push(result.snapshotItem(i));
+				} else {
+					nodes = Array.from(document.querySelectorAll(selector));
+				}
+			} catch (e) {
+				return { count: 0, texts: [], error: e.message || String(e) };
+			}
+			const describe = node => {
+				if (node.nodeType !== Node.ELEMENT_NODE) {
+					return (node.nodeValue || node.textContent || '').replace(/\s+/g, ' ').trim();
+				}
+				const text = (node.innerText || node.value || node.getAttribute('aria-label') || node.textContent || '')
+					.replace(/\s+/g, ' ').trim();
+				return text || '<' + node.tagName.toLowerCase() + '>';
+			};
+			return { count: nodes.length, texts: nodes.slice(0, limit).map(describe), error: '' };
+		})()
+	`, QuoteJS(selector), limit, kind == "XPath")
+
+	var matches SelectorMatches
+	if err := m.ExecuteScript(script, &matches); err != nil {
+		return nil, fmt.Errorf("failed to match %s %q: %w", kind, selector, err)
+	}
+	if matches.Error != "" {
+		return nil, fmt.Errorf("%w: %s %q: %s", ErrInvalidSelector, kind, selector, matches.Error)
+	}
+	return &matches, nil
+}
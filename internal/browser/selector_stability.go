@@ -0,0 +1,144 @@
+package browser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultFragileSelectorThreshold is the stability score below which a
+// selector is reported as fragile
+const DefaultFragileSelectorThreshold = 0.5
+
+// Penalties ScoreSelectorStability takes off a selector's score
+const (
+	hashedClassPenalty  = 0.6
+	generatedIDPenalty  = 0.6
+	positionalPenalty   = 0.25
+	textContainsPenalty = 0.2
+	deepChainPenalty    = 0.1
+)
+
+// maxStableDepth is how many combinators a selector has before its dependence
+// on the page structure counts against it
+const maxStableDepth = 3
+
+var (
+	// Classes from CSS-in-JS and CSS modules, like .css-1a2b3c, .sc-bdVaJa,
+	// .jss42 and .Button_primary__3xKq1
+	hashedClassPattern = regexp.MustCompile(`\.((?:css|sc|jss|emotion|styled|makeStyles)-?[A-Za-z0-9_-]*\d[A-Za-z0-9_-]*|sc-[A-Za-z]{5,}|[A-Za-z][\w-]*__[A-Za-z0-9]{5,})`)
+
+	// IDs frameworks generate, like #react-select-3-input, #:r1:, #ember123,
+	// #mui-42 and uuids
+	generatedIDPattern = regexp.MustCompile(`#((?:react|mui|headlessui|radix|ember|ext-gen|yui|downshift)[\w-]*\d[\w-]*|\\?:r[0-9a-z]+\\?:|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|[\w-]*\d{4,}[\w-]*)`)
+
+	positionalPattern = regexp.MustCompile(`:nth-(?:child|of-type|last-child|last-of-type)\([^)]*\)`)
+
+	// Attributes meant for tests and accessibility, which survive restyling
+	stableAttributePattern = regexp.MustCompile(`\[(?:data-(?:testid|test-id|test|cy|qa|tod)|aria-label|role|name)[~|^$*]?=`)
+)
+
+// ScoreSelectorStability rates how likely a CSS selector is to survive
+// changes to the page, from 0 (fragile) to 1 (stable), with a reason for
+// each penalty. Hashed class names, generated ids, positional pseudo-classes
+// and long descendant chains are penalized; test ids and ARIA attributes
+// are not.
+func ScoreSelectorStability(selector string) (float64, []string) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return 0, []string{"empty selector"}
+	}
+	if IsXPath(selector) {
+		if strings.Contains(selector, "[") && !strings.Contains(selector, "@") {
+			return 1 - positionalPenalty, []string{"positional XPath index"}
+		}
+		return 1, nil
+	}
+
+	score := 1.0
+	var reasons []string
+	for _, class := range hashedClassPattern.FindAllString(selector, -1) {
+		score -= hashedClassPenalty
+		reasons = append(reasons, fmt.Sprintf("hashed class %s", class))
+	}
+	for _, id := range generatedIDPattern.FindAllString(selector, -1) {
+		score -= generatedIDPenalty
+		reasons = append(reasons, fmt.Sprintf("generated id %s", id))
+	}
+	if positions := positionalPattern.FindAllString(selector, -1); len(positions) > 0 {
+		score -= positionalPenalty * float64(len(positions))
+		if len(positions) > 1 {
+			reasons = append(reasons, fmt.Sprintf("chain of %d positional selectors", len(positions)))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("positional %s", positions[0]))
+		}
+	}
+	if strings.Contains(selector, ":contains(") {
+		score -= textContainsPenalty
+		reasons = append(reasons, "matches on text, which changes with copy and translations")
+	}
+	if depth := selectorDepth(selector); depth > maxStableDepth {
+		score -= deepChainPenalty * float64(depth-maxStableDepth)
+		reasons = append(reasons, fmt.Sprintf("depends on %d levels of page structure", depth+1))
+	}
+
+	// A test id or ARIA attribute keeps the selector usable even if the
+	// rest of it goes stale
+	if stableAttributePattern.MatchString(selector) {
+		score = max(score, DefaultFragileSelectorThreshold)
+	}
+	return max(0, score), reasons
+}
+
+// IsFragileSelector reports whether a selector scores below threshold, or
+// DefaultFragileSelectorThreshold if threshold is 0
+func IsFragileSelector(selector string, threshold float64) bool {
+	if threshold <= 0 {
+		threshold = DefaultFragileSelectorThreshold
+	}
+	score, _ := ScoreSelectorStability(selector)
+	return score < threshold
+}
+
+// selectorDepth counts the combinators in a selector, outside attribute
+// values and parentheses
+func selectorDepth(selector string) int {
+	depth, nesting := 0, 0
+	var quote rune
+	space, combined := false, false
+	for _, r := range selector {
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
+		if nesting == 0 {
+			switch r {
+			case ' ':
+				space = true
+				continue
+			case '>', '+', '~':
+				depth++
+				space, combined = false, true
+				continue
+			}
+		}
+		// Whitespace alone between two compound selectors is a descendant
+		// combinator
+		if space && !combined {
+			depth++
+		}
+		space, combined = false, false
+
+		switch r {
+		case '\'', '"':
+			quote = r
+		case '[', '(':
+			nesting++
+		case ']', ')':
+			nesting--
+		}
+	}
+	return depth
+}
@@ -0,0 +1,115 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ShadowPiercer separates the steps of a selector that crosses shadow DOM
+// boundaries: each step before it selects a shadow host, and the next step
+// is looked up in that host's shadow root, e.g. "my-app >>> #login". CSS
+// can't cross the boundary, so these selectors are resolved in JS.
+const ShadowPiercer = " >>> "
+
+// IsShadowSelector reports whether selector crosses a shadow DOM boundary
+func IsShadowSelector(selector string) bool {
+	return strings.Contains(selector, ShadowPiercer)
+}
+
+// resolveShadowJS defines resolveShadowSelector(path), which walks a
+// ShadowPiercer selector through each shadow root and returns the element it
+// ends on, or null. Steps can use the tag:contains('text') form
+// generateSelector gives buttons and links without better attributes.
+const resolveShadowJS = `
+	function resolveShadowSelector(path) {
+		const query = (root, step) => {
+			const contains = step.match(/^([a-z0-9-]+):contains\('(.*)'\)$/i);
+			if (!contains) return root.querySelector(step);
+			const text = contains[2].replace(/\\'/g, "'");
+			return Array.from(root.querySelectorAll(contains[1]))
+				.find(el => el.textContent.trim() === text) || null;
+		};
+		const steps = path.split(' >>> ');
+		let root = document;
+		for (let i = 0; i < steps.length; i++) {
+			const el = query(root, steps[i]);
+			if (!el || i === steps.length - 1) return el;
+			root = el.shadowRoot;
+			if (!root) return null;
+		}
+		return null;
+	}
+`
+
+// queryElementJS is a JS expression for the element selector matches, or
+// null, resolving ShadowPiercer selectors through their shadow roots and
+// the :contains('text') steps querySelector can't
+func queryElementJS(selector string) string {
+	if IsShadowSelector(selector) || strings.Contains(selector, ":contains(") {
+		return fmt.Sprintf("(() => { %s return resolveShadowSelector(%s); })()", resolveShadowJS, QuoteJS(selector))
+	}
+	return fmt.Sprintf("document.querySelector(%s)", QuoteJS(selector))
+}
+
+// fillShadow fills a form control inside a shadow root. The control is
+// focused and cleared from JS and the value typed with key events, which
+// reach the focused element wherever it is.
+func (m *ChromeDPManager) fillShadow(ctx context.Context, selector, value string) error {
+	focus := fmt.Sprintf(`
+		(() => {
+			const el = %s;
+			if (!el) return false;
+			el.scrollIntoView({ block: 'center', inline: 'center' });
+			el.focus();
+			if ('value' in el) el.value = '';
+			return true;
+		})()
+	`, queryElementJS(selector))
+	notify := fmt.Sprintf(`
+		(() => {
+			const el = %s;
+			if (el) {
+				el.dispatchEvent(new Event('input', { bubbles: true }));
+				el.dispatchEvent(new Event('change', { bubbles: true }));
+			}
+		})()
+	`, queryElementJS(selector))
+
+	var found bool
+	if err := m.run(ctx, chromedp.Evaluate(focus, &found)); err != nil {
+		return fmt.Errorf("failed to fill %s: %w", selector, err)
+	}
+	if !found {
+		return fmt.Errorf("element not found in shadow DOM: %s", selector)
+	}
+	return m.run(ctx,
+		chromedp.KeyEvent(value),
+		chromedp.Evaluate(notify, nil),
+	)
+}
+
+// clickShadow clicks an element inside a shadow root by resolving its
+// ShadowPiercer selector in JS
+func (m *ChromeDPManager) clickShadow(ctx context.Context, selector string) error {
+	script := fmt.Sprintf(`
+		(() => {
+			const el = %s;
+			if (!el) return false;
+			el.scrollIntoView({ block: 'center', inline: 'center' });
+			el.click();
+			return true;
+		})()
+	`, queryElementJS(selector))
+
+	var clicked bool
+	if err := m.run(ctx, chromedp.Evaluate(script, &clicked)); err != nil {
+		return fmt.Errorf("failed to click %s: %w", selector, err)
+	}
+	if !clicked {
+		return fmt.Errorf("element not found in shadow DOM: %s", selector)
+	}
+	return nil
+}
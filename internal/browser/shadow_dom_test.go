@@ -0,0 +1,114 @@
+package browser
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// shadowPage defines <login-form>, whose button and input live in an open
+// shadow root. Clicking the button records that it was clicked.
+const shadowPage = `<html><body>
+<login-form></login-form>
+<script>
+customElements.define('login-form', class extends HTMLElement {
+	constructor() {
+		super();
+		const root = this.attachShadow({ mode: 'open' });
+		root.innerHTML = '<input id="email"><button id="submit">Sign in</button>';
+		root.querySelector('#submit').addEventListener('click', () => { window.clicked = true; });
+	}
+});
+</script>
+</body></html>`
+
+func newShadowManager(t *testing.T) *ChromeDPManager {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, shadowPage)
+	}))
+	t.Cleanup(server.Close)
+
+	manager := newTestManager(t, server.URL)
+	if err := manager.Navigate(server.URL); err != nil {
+		t.Fatalf("Navigate failed: %v", err)
+	}
+	return manager
+}
+
+func TestClickInsideShadowRoot(t *testing.T) {
+	manager := newShadowManager(t)
+	selector := "login-form" + ShadowPiercer + "#submit"
+
+	if err := manager.WaitForElement(selector); err != nil {
+		t.Fatalf("WaitForElement failed: %v", err)
+	}
+	state, err := manager.GetElementState(selector)
+	if err != nil {
+		t.Fatalf("GetElementState failed: %v", err)
+	}
+	if !state.Exists || !state.Visible || state.Disabled {
+		t.Errorf("GetElementState = %+v, want an existing, visible, enabled element", state)
+	}
+
+	if err := manager.Click(selector); err != nil {
+		t.Fatalf("Click failed: %v", err)
+	}
+	var clicked bool
+	if err := manager.ExecuteScript(`window.clicked === true`, &clicked); err != nil {
+		t.Fatalf("ExecuteScript failed: %v", err)
+	}
+	if !clicked {
+		t.Error("button inside the shadow root wasn't clicked")
+	}
+
+	// SmartClick keeps trying strategies while the URL stays the same, but
+	// each one has to reach the button
+	if err := manager.ExecuteScript(`window.clicked = false`, nil); err != nil {
+		t.Fatalf("ExecuteScript failed: %v", err)
+	}
+	if _, err := manager.SmartClick(selector, "Sign in"); err != nil {
+		t.Fatalf("SmartClick failed: %v", err)
+	}
+	if err := manager.ExecuteScript(`window.clicked === true`, &clicked); err != nil {
+		t.Fatalf("ExecuteScript failed: %v", err)
+	}
+	if !clicked {
+		t.Error("SmartClick didn't click the button inside the shadow root")
+	}
+}
+
+func TestFillInsideShadowRoot(t *testing.T) {
+	manager := newShadowManager(t)
+	selector := "login-form" + ShadowPiercer + "#email"
+
+	if err := manager.FillFormField(selector, "ada@example.com"); err != nil {
+		t.Fatalf("FillFormField failed: %v", err)
+	}
+	var value string
+	script := `document.querySelector('login-form').shadowRoot.querySelector('#email').value`
+	if err := manager.ExecuteScript(script, &value); err != nil {
+		t.Fatalf("ExecuteScript failed: %v", err)
+	}
+	if value != "ada@example.com" {
+		t.Errorf("field value = %q, want %q", value, "ada@example.com")
+	}
+}
+
+func TestQueryElementJSQuotesSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{`#email`, `document.querySelector('#email')`},
+		{`[data-testid='email']`, `document.querySelector('[data-testid=\'email\']')`},
+		{`input[name="a\b"]`, `document.querySelector('input[name="a\\b"]')`},
+	}
+	for _, tt := range tests {
+		if got := queryElementJS(tt.selector); got != tt.want {
+			t.Errorf("queryElementJS(%q) = %s, want %s", tt.selector, got, tt.want)
+		}
+	}
+}
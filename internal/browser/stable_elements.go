@@ -0,0 +1,47 @@
+package browser
+
+import (
+	"time"
+
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// Default polling for ExtractStableInteractiveElements
+const (
+	DefaultStableElementsInterval = 300 * time.Millisecond
+	DefaultStableElementsTimeout  = 3 * time.Second
+)
+
+// ExtractStableInteractiveElements polls ExtractInteractiveElements until the
+// element count is unchanged across two consecutive polls or the timeout hits,
+// so client-rendered pages are extracted after they finish rendering.
+// Zero interval or timeout use the defaults.
+func (m *ChromeDPManager) ExtractStableInteractiveElements(interval, timeout time.Duration) ([]InteractiveElement, error) {
+	if interval <= 0 {
+		interval = DefaultStableElementsInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultStableElementsTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	previousCount := -1
+	for polls := 1; ; polls++ {
+		elements, err := m.ExtractInteractiveElements()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(elements) == previousCount {
+			logging.Debug("Element count stable at %d after %d polls", len(elements), polls)
+			return elements, nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			logging.Debug("Element count still changing after %v, using %d elements", timeout, len(elements))
+			return elements, nil
+		}
+
+		previousCount = len(elements)
+		time.Sleep(interval)
+	}
+}
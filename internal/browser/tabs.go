@@ -0,0 +1,224 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// TargetInfo describes an open browser tab
+type TargetInfo struct {
+	ID     string
+	Title  string
+	URL    string
+	Active bool // actions run against this tab
+}
+
+// tab is a tab the manager has attached to
+type tab struct {
+	ctx    context.Context
+	cancel context.CancelFunc // nil for the original tab, which owns the browser
+}
+
+// initTabs registers the original tab as the active one and attaches the
+// event listeners to it
+func (m *ChromeDPManager) initTabs() {
+	rootCtx := m.browserCtx()
+	var root target.ID
+	if c := chromedp.FromContext(rootCtx); c != nil && c.Target != nil {
+		root = c.Target.TargetID
+	}
+
+	m.ctxMu.Lock()
+	m.tabs = map[target.ID]*tab{root: {ctx: rootCtx}}
+	m.knownTargets = map[target.ID]bool{root: true}
+	m.rootTarget = root
+	m.activeTarget = root
+	m.ctxMu.Unlock()
+
+	m.listenTab(rootCtx)
+}
+
+// attachedTabs returns the tabs the manager has attached to
+func (m *ChromeDPManager) attachedTabs() []*tab {
+	m.ctxMu.RLock()
+	defer m.ctxMu.RUnlock()
+	tabs := make([]*tab, 0, len(m.tabs))
+	for _, t := range m.tabs {
+		tabs = append(tabs, t)
+	}
+	return tabs
+}
+
+// listenTab attaches the manager's event listeners to a tab. Events from
+// every attached tab are collected, not just the active one's.
+func (m *ChromeDPManager) listenTab(ctx context.Context) {
+	chromedp.ListenTarget(ctx, m.liveActivity.handleEvent)
+	chromedp.ListenTarget(ctx, m.requestCapture.handleEvent)
+	chromedp.ListenTarget(ctx, m.consoleErrors.handleEvent)
+	chromedp.ListenTarget(ctx, m.dialogs.handleEvent(ctx))
+}
+
+// ListTargets returns the open page tabs
+func (m *ChromeDPManager) ListTargets() ([]TargetInfo, error) {
+	ctx, cancel := context.WithTimeout(m.browserCtx(), m.timeouts.Action)
+	defer cancel()
+
+	infos, err := chromedp.Targets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tabs: %w", err)
+	}
+
+	m.ctxMu.Lock()
+	defer m.ctxMu.Unlock()
+
+	var targets []TargetInfo
+	for _, info := range infos {
+		if info.Type != "page" {
+			continue
+		}
+		m.knownTargets[info.TargetID] = true
+		targets = append(targets, TargetInfo{
+			ID:     string(info.TargetID),
+			Title:  info.Title,
+			URL:    info.URL,
+			Active: info.TargetID == m.activeTarget,
+		})
+	}
+	return targets, nil
+}
+
+// NewTargets returns the tabs opened since the last call to NewTargets or
+// ListTargets, e.g. by a target="_blank" link
+func (m *ChromeDPManager) NewTargets() ([]TargetInfo, error) {
+	m.ctxMu.RLock()
+	known := make(map[target.ID]bool, len(m.knownTargets))
+	for id := range m.knownTargets {
+		known[id] = true
+	}
+	m.ctxMu.RUnlock()
+
+	targets, err := m.ListTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	var opened []TargetInfo
+	for _, t := range targets {
+		if !known[target.ID(t.ID)] {
+			opened = append(opened, t)
+		}
+	}
+	return opened, nil
+}
+
+// SwitchToTarget makes the tab with the given ID the active one, so every
+// later action, GetPageInfo and Navigate included, runs against it
+func (m *ChromeDPManager) SwitchToTarget(id string) error {
+	targetID := target.ID(id)
+	m.ctxMu.RLock()
+	active := m.activeTarget
+	t, ok := m.tabs[targetID]
+	m.ctxMu.RUnlock()
+	if targetID == active {
+		return nil
+	}
+
+	if !ok {
+		var err error
+		if t, err = m.adoptTab(targetID); err != nil {
+			return err
+		}
+	}
+
+	if err := m.run(t.ctx, page.BringToFront()); err != nil {
+		logging.Debug("Failed to bring tab %s to front: %v", id, err)
+	}
+
+	m.ctxMu.Lock()
+	m.ctx = t.ctx
+	m.activeTarget = targetID
+	m.knownTargets[targetID] = true
+	m.ctxMu.Unlock()
+	return nil
+}
+
+// adoptTab attaches to a tab the manager didn't open, setting it up like the
+// original one: event listeners, basic auth and the emulated device
+func (m *ChromeDPManager) adoptTab(targetID target.ID) (*tab, error) {
+	ctx, cancel := chromedp.NewContext(m.browserCtx(), chromedp.WithTargetID(targetID))
+	m.listenTab(ctx)
+	if err := m.run(ctx); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to attach to tab %s: %w", targetID, err)
+	}
+	if m.basicAuth != nil {
+		if err := m.basicAuth.attach(ctx); err != nil {
+			logging.Warn("Failed to set up basic auth in tab %s: %v", targetID, err)
+		}
+	}
+	if err := m.emulate(ctx, m.emulation); err != nil {
+		logging.Warn("Failed to emulate device in tab %s: %v", targetID, err)
+	}
+
+	m.ctxMu.Lock()
+	defer m.ctxMu.Unlock()
+	if existing, ok := m.tabs[targetID]; ok {
+		// Adopted meanwhile. Cancelling ctx would close the tab, so it's
+		// left to end with the browser.
+		return existing, nil
+	}
+	t := &tab{ctx: ctx, cancel: cancel}
+	m.tabs[targetID] = t
+	return t, nil
+}
+
+// CloseTarget closes a tab. Closing the active tab switches back to the
+// original one, which can't be closed since it owns the browser.
+func (m *ChromeDPManager) CloseTarget(id string) error {
+	targetID := target.ID(id)
+	m.ctxMu.RLock()
+	root, active := m.rootTarget, m.activeTarget
+	m.ctxMu.RUnlock()
+	if targetID == root {
+		return fmt.Errorf("can't close the original tab")
+	}
+
+	if targetID == active {
+		if err := m.SwitchToTarget(string(root)); err != nil {
+			return err
+		}
+	}
+
+	m.ctxMu.Lock()
+	t, ok := m.tabs[targetID]
+	delete(m.tabs, targetID)
+	m.ctxMu.Unlock()
+
+	if ok {
+		// Cancelling a tab's context closes the tab
+		t.cancel()
+	} else {
+		ctx, cancel := context.WithTimeout(m.browserCtx(), m.timeouts.Action)
+		defer cancel()
+		if err := m.run(ctx, target.CloseTarget(targetID)); err != nil {
+			return fmt.Errorf("failed to close tab %s: %w", id, err)
+		}
+	}
+
+	m.ctxMu.Lock()
+	delete(m.knownTargets, targetID)
+	m.ctxMu.Unlock()
+	return nil
+}
+
+// ActiveTargetID returns the ID of the tab actions run against
+func (m *ChromeDPManager) ActiveTargetID() string {
+	m.ctxMu.RLock()
+	defer m.ctxMu.RUnlock()
+	return string(m.activeTarget)
+}
@@ -0,0 +1,60 @@
+package browser
+
+import "time"
+
+// Timeouts bound how long each kind of browser operation may take. Zero
+// fields use the defaults from DefaultTimeouts.
+type Timeouts struct {
+	Action     time.Duration // clicks, typing, scripts and other quick page interactions
+	Element    time.Duration // waiting for elements, filling forms and reading the page
+	Navigation time.Duration // going back, forward or reloading, and the page load after
+	Capture    time.Duration // replaying captured requests
+
+	// Scale multiplies every timeout, so one setting can slow them all down
+	// for a slow machine or network. 0 means 1.
+	Scale float64
+}
+
+// DefaultTimeouts returns the timeouts used when none are configured
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Action:     5 * time.Second,
+		Element:    10 * time.Second,
+		Navigation: 10 * time.Second,
+		Capture:    30 * time.Second,
+		Scale:      1,
+	}
+}
+
+// resolve fills unset fields from the defaults and applies Scale, returning
+// the timeouts methods use directly
+func (t Timeouts) resolve() Timeouts {
+	defaults := DefaultTimeouts()
+	scale := t.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	pick := func(d, fallback time.Duration) time.Duration {
+		if d <= 0 {
+			d = fallback
+		}
+		return time.Duration(float64(d) * scale)
+	}
+	return Timeouts{
+		Action:     pick(t.Action, defaults.Action),
+		Element:    pick(t.Element, defaults.Element),
+		Navigation: pick(t.Navigation, defaults.Navigation),
+		Capture:    pick(t.Capture, defaults.Capture),
+		Scale:      1,
+	}
+}
+
+// Timeouts returns the timeouts in effect, already scaled
+func (m *ChromeDPManager) Timeouts() Timeouts {
+	return m.timeouts
+}
+
+// SetTimeouts replaces the timeouts used by later operations
+func (m *ChromeDPManager) SetTimeouts(timeouts Timeouts) {
+	m.timeouts = timeouts.resolve()
+}
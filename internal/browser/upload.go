@@ -0,0 +1,100 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// fileInputInfo is what the UploadFile check reports about the target
+type fileInputInfo struct {
+	Found    bool `json:"found"`
+	IsFile   bool `json:"isFile"`
+	Multiple bool `json:"multiple"`
+}
+
+// UploadFile sets the files of an <input type="file">. filePath may list
+// several files separated by commas for inputs that accept multiple files.
+// Paths are resolved against the working directory, and "~" against the home
+// directory; a path that doesn't exist is an error.
+func (m *ChromeDPManager) UploadFile(selector, filePath string) error {
+	paths, err := resolveUploadPaths(filePath)
+	if err != nil {
+		return err
+	}
+	if m.skipInDryRun("upload %s to %s", strings.Join(paths, ", "), selector) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(m.tabCtx(), m.timeouts.Element)
+	defer cancel()
+
+	var info fileInputInfo
+	script := fmt.Sprintf(`
+		(() => {
+			const el = document.querySelector(%s);
+			if (!el) return { found: false, isFile: false, multiple: false };
+			return { found: true, isFile: el.tagName === 'INPUT' && el.type === 'file', multiple: el.multiple };
+		})()
+	`, QuoteJS(selector))
+	if err := m.run(ctx, chromedp.Evaluate(script, &info)); err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", selector, err)
+	}
+	switch {
+	case !info.Found:
+		return fmt.Errorf("file input not found: %s", selector)
+	case !info.IsFile:
+		return fmt.Errorf("%s is not a file input", selector)
+	case len(paths) > 1 && !info.Multiple:
+		return fmt.Errorf("%s accepts a single file, got %d", selector, len(paths))
+	}
+
+	m.highlightTarget(selector)
+	if err := m.run(ctx, chromedp.SetUploadFiles(selector, paths, chromedp.ByQuery)); err != nil {
+		return fmt.Errorf("failed to upload to %s: %w", selector, err)
+	}
+	return nil
+}
+
+// resolveUploadPaths splits a comma-separated list of files into absolute
+// paths, checking each is an existing file
+func resolveUploadPaths(filePath string) ([]string, error) {
+	var paths []string
+	for _, path := range strings.Split(filePath, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if path == "~" || strings.HasPrefix(path, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+			}
+			path = filepath.Join(home, path[1:])
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+		}
+
+		info, err := os.Stat(abs)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file to upload not found: %s", abs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", abs, err)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is a directory, not a file", abs)
+		}
+		paths = append(paths, abs)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no file path given to upload")
+	}
+	return paths, nil
+}
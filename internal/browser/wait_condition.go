@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ErrConditionTimeout is returned by WaitForCondition when the condition
+// never became true
+var ErrConditionTimeout = errors.New("timed out waiting for condition")
+
+// conditionPollInterval is how often WaitForCondition re-evaluates
+const conditionPollInterval = 100 * time.Millisecond
+
+// WaitForCondition evaluates jsExpr until it is truthy or timeout passes, e.g.
+// "document.querySelector('.toast') !== null". It returns true once the
+// condition holds, ErrConditionTimeout if it never did, or the error if the
+// expression couldn't be evaluated.
+func (m *ChromeDPManager) WaitForCondition(jsExpr string, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(m.tabCtx(), timeout)
+	defer cancel()
+
+	script := fmt.Sprintf(`(() => { try { return !!(%s); } catch (e) { return false; } })()`, jsExpr)
+	for {
+		var met bool
+		if err := m.run(ctx, chromedp.Evaluate(script, &met)); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return false, fmt.Errorf("%w after %v: %s", ErrConditionTimeout, timeout, jsExpr)
+			}
+			return false, fmt.Errorf("failed to evaluate condition: %w", err)
+		}
+		if met {
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("%w after %v: %s", ErrConditionTimeout, timeout, jsExpr)
+		case <-time.After(conditionPollInterval):
+		}
+	}
+}
@@ -1,18 +1,33 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 )
 
 // Config represents the complete Tod configuration
 type Config struct {
-	AI      AIConfig               `yaml:"ai"`
-	Testing TestingConfig          `yaml:"testing"`
-	Envs    map[string]EnvConfig   `yaml:"environments"`
-	Current string                 `yaml:"current_env"`
-	Email   map[string]interface{} `yaml:"email,omitempty"`
-	Browser BrowserConfig          `yaml:"browser,omitempty"`
-	Meta    MetaConfig             `yaml:"meta"`
+	AI       AIConfig               `yaml:"ai"`
+	Testing  TestingConfig          `yaml:"testing"`
+	Envs     map[string]EnvConfig   `yaml:"environments"`
+	Current  string                 `yaml:"current_env"`
+	Email    map[string]interface{} `yaml:"email,omitempty"`
+	Browser  BrowserConfig          `yaml:"browser,omitempty"`
+	Matching MatchingConfig         `yaml:"matching,omitempty"`
+	Hooks    HooksConfig            `yaml:"hooks,omitempty"`
+	Session  SessionConfig          `yaml:"session,omitempty"`
+	Safety   SafetyConfig           `yaml:"safety,omitempty"`
+	Meta     MetaConfig             `yaml:"meta"`
+
+	// PlanOnly is set by --plan-only for the current run and never saved.
+	// Pages are analyzed but no action is executed.
+	PlanOnly bool `yaml:"-"`
+
+	// DryRun is set by --dry-run for the current run and never saved. Actions
+	// are simulated as with PlanOnly, and the browser also refuses to act.
+	DryRun bool `yaml:"-"`
 }
 
 // AIConfig holds AI provider configuration
@@ -22,6 +37,60 @@ type AIConfig struct {
 	Model    string                 `yaml:"model"`
 	Endpoint string                 `yaml:"endpoint,omitempty"` // for custom providers
 	Settings map[string]interface{} `yaml:"settings,omitempty"`
+
+	// AllowUnknownModels skips model validation for models newer than Tod's list
+	AllowUnknownModels bool `yaml:"allow_unknown_models,omitempty"`
+
+	// RequestsPerMinute caps LLM calls made with the same provider and API
+	// key, whichever client makes them (0 = unlimited)
+	RequestsPerMinute int `yaml:"requests_per_minute,omitempty"`
+
+	// MaxRetries is how many times an LLM call is retried after a rate limit,
+	// 5xx or network error, with exponential backoff (0 = no retries)
+	MaxRetries int `yaml:"max_retries,omitempty"`
+
+	// DiscoveryRetries is how many times page analysis is retried after a
+	// transient failure (0 = no retries)
+	DiscoveryRetries int `yaml:"discovery_retries"`
+
+	// An interpreted command runs when its confidence is at least
+	// ConfidenceThreshold. Between ConfidenceAskThreshold and that, the user
+	// is asked "Did you mean ...?" instead; below it the command is rejected.
+	// Default to 0.5 and 0.3.
+	ConfidenceThreshold    float64 `yaml:"confidence_threshold,omitempty"`
+	ConfidenceAskThreshold float64 `yaml:"confidence_ask_threshold,omitempty"`
+}
+
+// DefaultDiscoveryRetries is used when a config doesn't set ai.discovery_retries
+const DefaultDiscoveryRetries = 2
+
+// Default confidence bands for interpreted commands
+const (
+	DefaultConfidenceThreshold    = 0.5
+	DefaultConfidenceAskThreshold = 0.3
+)
+
+// ConfidenceBands returns the confidence needed to ask about an interpreted
+// command and to run it, falling back to the defaults when unset
+func (a AIConfig) ConfidenceBands() (ask, execute float64) {
+	ask, execute = a.ConfidenceAskThreshold, a.ConfidenceThreshold
+	if execute == 0 {
+		execute = DefaultConfidenceThreshold
+	}
+	if ask == 0 {
+		ask = min(DefaultConfidenceAskThreshold, execute)
+	}
+	return ask, execute
+}
+
+// ClientOptions returns the options passed to llm.NewClient for this config
+func (a AIConfig) ClientOptions() map[string]interface{} {
+	return map[string]interface{}{
+		"model":                a.Model,
+		"allow_unknown_models": a.AllowUnknownModels,
+		"requests_per_minute":  a.RequestsPerMinute,
+		"max_retries":          a.MaxRetries,
+	}
 }
 
 // TestingConfig holds E2E testing framework configuration
@@ -33,15 +102,42 @@ type TestingConfig struct {
 	Command   string `yaml:"command"`          // how to run tests (e.g., "npm test")
 	Template  string `yaml:"template,omitempty"` // optional: custom test template
 	Pattern   string `yaml:"pattern"`          // test file pattern (e.g., "*.spec.ts")
+	Style     string `yaml:"style,omitempty"`  // inline (default) or pageobject
+
+	// FragileSelectorThreshold is the selector stability score, from 0 to
+	// 1, below which discovered actions are flagged as fragile; 0 uses 0.5
+	FragileSelectorThreshold float64 `yaml:"fragile_selector_threshold,omitempty"`
 }
 
 // EnvConfig holds environment-specific configuration
 type EnvConfig struct {
 	Name     string            `yaml:"name"`
 	BaseURL  string            `yaml:"base_url"`
+	BasePath string            `yaml:"base_path,omitempty"` // app path prefix, e.g. "/app"
 	Headers  map[string]string `yaml:"headers,omitempty"`
 	Auth     *AuthConfig       `yaml:"auth,omitempty"`
 	Cookies  []Cookie          `yaml:"cookies,omitempty"`
+
+	// BasicAuth answers the HTTP Basic Auth prompt of staging environments
+	BasicAuth *BasicAuthConfig `yaml:"basic_auth,omitempty"`
+
+	// Device, Viewport and UserAgent emulate another device, e.g. a phone
+	// for mobile layouts. Viewport and UserAgent override the device's.
+	Device    string          `yaml:"device,omitempty"` // preset: iphone-13, pixel-7
+	Viewport  *ViewportConfig `yaml:"viewport,omitempty"`
+	UserAgent string          `yaml:"user_agent,omitempty"`
+}
+
+// ViewportConfig is the size of the page's viewport in CSS pixels
+type ViewportConfig struct {
+	Width  int `yaml:"width"`
+	Height int `yaml:"height"`
+}
+
+// BasicAuthConfig holds HTTP Basic Auth credentials
+type BasicAuthConfig struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
 }
 
 // AuthConfig holds authentication configuration
@@ -86,6 +182,66 @@ type Cookie struct {
 type BrowserConfig struct {
 	Headless bool           `yaml:"headless"`
 	Location *LocationConfig `yaml:"location,omitempty"`
+
+	// DebugPort is Chrome's remote-debugging port. Defaults to 9222; if the
+	// port is taken a free one is picked instead.
+	DebugPort int `yaml:"debug_port,omitempty"`
+
+	// LiveUpdateMaxWait bounds how long to wait for WebSocket/SSE traffic to
+	// settle after an action (e.g. "3s"). Defaults to 3s when unset.
+	LiveUpdateMaxWait time.Duration `yaml:"live_update_max_wait,omitempty"`
+
+	// Before extracting elements, poll every StableElementsInterval until the
+	// element count stops changing or StableElementsTimeout passes, so
+	// client-rendered routes are fully rendered. Default to 300ms and 3s.
+	StableElementsInterval time.Duration `yaml:"stable_elements_interval,omitempty"`
+	StableElementsTimeout  time.Duration `yaml:"stable_elements_timeout,omitempty"`
+
+	// After a capture, the page is polled every PollInterval for PollDuration,
+	// starting after PollInitialDelay, to find actions in content that loads
+	// late. Default to 500ms, 5s and no delay. Polling stops early once
+	// PollQuietIntervals polls in a row see no change; 0 never stops early.
+	PollDuration       time.Duration `yaml:"poll_duration,omitempty"`
+	PollInterval       time.Duration `yaml:"poll_interval,omitempty"`
+	PollInitialDelay   time.Duration `yaml:"poll_initial_delay,omitempty"`
+	PollQuietIntervals int           `yaml:"poll_quiet_intervals,omitempty"`
+
+	// ActionWaitCondition is a JS expression waited for after clicks and
+	// submissions instead of a fixed delay, e.g. "!document.querySelector('.spinner')"
+	ActionWaitCondition string `yaml:"action_wait_condition,omitempty"`
+
+	// DismissDialogs cancels alert/confirm/prompt dialogs instead of
+	// accepting them. Dialogs are always answered so they can't hang the page.
+	DismissDialogs bool `yaml:"dismiss_dialogs,omitempty"`
+
+	// AutoDismissConsent clicks away cookie consent banners, in the page or
+	// an iframe, after every navigation. ConsentSelectors and ConsentTexts
+	// (button labels like "Accept all", only clicked inside a consent banner
+	// or dialog) are tried before the built-in ones for OneTrust, Cookiebot
+	// and other common banners.
+	AutoDismissConsent bool     `yaml:"auto_dismiss_consent,omitempty"`
+	ConsentSelectors   []string `yaml:"consent_selectors,omitempty"`
+	ConsentTexts       []string `yaml:"consent_texts,omitempty"`
+
+	// InteractiveSelectors are extra CSS selectors for elements to discover,
+	// e.g. "div[role=menuitem]" for custom component libraries. They add to
+	// the built-in selectors; elements with a data-tod attribute are always found.
+	InteractiveSelectors []string `yaml:"interactive_selectors,omitempty"`
+
+	// Timeouts override how long browser operations may take
+	Timeouts BrowserTimeouts `yaml:"timeouts,omitempty"`
+}
+
+// BrowserTimeouts bound each kind of browser operation. Unset fields keep
+// their defaults: action 5s, element 10s, navigation 10s and capture 30s.
+// Scale multiplies all of them, e.g. 2 doubles every timeout for a slow
+// environment.
+type BrowserTimeouts struct {
+	Action     time.Duration `yaml:"action,omitempty"`     // clicks, typing and scripts
+	Element    time.Duration `yaml:"element,omitempty"`    // waiting for elements, filling forms, reading the page
+	Navigation time.Duration `yaml:"navigation,omitempty"` // back, forward, reload and the page load after
+	Capture    time.Duration `yaml:"capture,omitempty"`    // replaying captured requests
+	Scale      float64       `yaml:"scale,omitempty"`
 }
 
 // LocationConfig holds geolocation settings for browser
@@ -95,6 +251,173 @@ type LocationConfig struct {
 	Accuracy  float64 `yaml:"accuracy,omitempty"`
 }
 
+// MatchingConfig holds settings for matching user input to page elements
+type MatchingConfig struct {
+	Weights MatchingWeights `yaml:"weights"`
+
+	// NavMinConfidence is the LLM ranking confidence required before auto-clicking
+	NavMinConfidence float64 `yaml:"nav_min_confidence"`
+	// NavSuggestConfidence is the confidence required to offer an element as a choice
+	NavSuggestConfidence float64 `yaml:"nav_suggest_confidence"`
+}
+
+// MatchingWeights controls how input is scored against navigable elements.
+// The match-kind scores rank how the input matches a piece of text, while the
+// source weights scale how much element text, descriptions and navigation
+// history contribute to the final ranking.
+type MatchingWeights struct {
+	ExactMatch    float64 `yaml:"exact_match"`    // input equals the text
+	PrefixMatch   float64 `yaml:"prefix_match"`   // text starts with the input
+	ContainsMatch float64 `yaml:"contains_match"` // base score when text contains the input
+	WordMatch     float64 `yaml:"word_match"`     // base score when input words match text words
+	CharMatch     float64 `yaml:"char_match"`     // max score for character overlap (typos)
+
+	Text        float64 `yaml:"text"`        // weight of the element's visible text
+	Description float64 `yaml:"description"` // weight of the element's description
+	History     float64 `yaml:"history"`     // weight of recently visited URLs
+
+	// TypeBonus adds a fixed bonus per element type (link, button, form, action, input)
+	TypeBonus map[string]float64 `yaml:"type_bonus,omitempty"`
+}
+
+// DefaultMatchingConfig returns the built-in matching weights
+func DefaultMatchingConfig() MatchingConfig {
+	return MatchingConfig{
+		Weights: MatchingWeights{
+			ExactMatch:    1.0,
+			PrefixMatch:   0.9,
+			ContainsMatch: 0.6,
+			WordMatch:     0.3,
+			CharMatch:     0.2,
+			Text:          1.0,
+			Description:   0.0,
+			History:       1.0,
+		},
+		NavMinConfidence:     0.3,
+		NavSuggestConfidence: 0.1,
+	}
+}
+
+// HooksConfig holds JavaScript snippets run in the page around each action
+type HooksConfig struct {
+	BeforeAction string `yaml:"before_action,omitempty"`
+	AfterAction  string `yaml:"after_action,omitempty"`
+	Fatal        bool   `yaml:"fatal,omitempty"` // a failing hook fails the action
+
+	// Domains overrides the hooks for specific hosts (e.g. "app.example.com")
+	Domains map[string]DomainHooks `yaml:"domains,omitempty"`
+}
+
+// DomainHooks holds per-domain hook overrides
+type DomainHooks struct {
+	BeforeAction string `yaml:"before_action,omitempty"`
+	AfterAction  string `yaml:"after_action,omitempty"`
+}
+
+// ForHost returns the before/after hooks that apply to the given host,
+// preferring a per-domain override over the global hooks
+func (h HooksConfig) ForHost(host string) (before, after string) {
+	before, after = h.BeforeAction, h.AfterAction
+	if override, ok := h.Domains[host]; ok {
+		if override.BeforeAction != "" {
+			before = override.BeforeAction
+		}
+		if override.AfterAction != "" {
+			after = override.AfterAction
+		}
+	}
+	return before, after
+}
+
+// SessionConfig holds limits for interactive sessions
+type SessionConfig struct {
+	// MaxDuration ends the session after this long, saving it first (0 = unlimited)
+	MaxDuration time.Duration `yaml:"max_duration,omitempty"`
+
+	// HistoryLogMaxSize is the size in bytes at which .tod/navigation.log
+	// rotates to navigation.log.1 (default 1MB)
+	HistoryLogMaxSize int64 `yaml:"history_log_max_size,omitempty"`
+}
+
+// DefaultDestructiveWords flag actions that are hard to undo when their text
+// or selector contains one, see SafetyConfig.ConfirmDestructive
+var DefaultDestructiveWords = []string{
+	"delete",
+	"remove",
+	"destroy",
+	"erase",
+	"wipe",
+	"purge",
+	"deactivate",
+	"terminate",
+	"revoke",
+	"unsubscribe",
+	"cancel subscription",
+	"cancel plan",
+	"close account",
+	"discard",
+}
+
+// SafetyConfig keeps the browser on the app under test
+type SafetyConfig struct {
+	// RestrictDomains limits navigation to the environment's host and its
+	// subdomains, plus AllowedDomains. Setting AllowedDomains also enables it.
+	RestrictDomains bool     `yaml:"restrict_domains,omitempty"`
+	AllowedDomains  []string `yaml:"allowed_domains,omitempty"`
+
+	// ConfirmDestructive asks before running an action that looks
+	// destructive, like clicking "Delete account". DestructiveWords replaces
+	// DefaultDestructiveWords as the words that flag one.
+	ConfirmDestructive bool     `yaml:"confirm_destructive,omitempty"`
+	DestructiveWords   []string `yaml:"destructive_words,omitempty"`
+}
+
+// RestrictsDomains reports whether navigation is limited to allowed hosts
+func (s SafetyConfig) RestrictsDomains() bool {
+	return s.RestrictDomains || len(s.AllowedDomains) > 0
+}
+
+// DestructiveWord returns the destructive word one of texts contains, such
+// as an element's text or selector, or "" if none does. Matching ignores
+// case, and - and _ count as spaces so "#cancel-subscription" matches.
+func (s SafetyConfig) DestructiveWord(texts ...string) string {
+	words := s.DestructiveWords
+	if len(words) == 0 {
+		words = DefaultDestructiveWords
+	}
+	for _, text := range texts {
+		text = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(text))
+		for _, word := range words {
+			if word = strings.ToLower(strings.TrimSpace(word)); word != "" && strings.Contains(text, word) {
+				return word
+			}
+		}
+	}
+	return ""
+}
+
+// AllowsHost reports whether the browser may navigate to host. The host of
+// baseURL and its subdomains are always allowed.
+func (s SafetyConfig) AllowsHost(host, baseURL string) bool {
+	if !s.RestrictsDomains() {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	allowed := s.AllowedDomains
+	if parsed, err := url.Parse(baseURL); err == nil && parsed.Hostname() != "" {
+		allowed = append([]string{parsed.Hostname()}, allowed...)
+	}
+
+	for _, domain := range allowed {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*.")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
 // UsageConfig holds LLM usage tracking and cost data
 type UsageConfig struct {
 	Session SessionUsage            `json:"session"`
@@ -169,8 +492,9 @@ func DefaultConfig() *Config {
 	now := time.Now()
 	return &Config{
 		AI: AIConfig{
-			Provider: "openai",
-			Model:    "gpt-4-turbo",
+			Provider:         "openai",
+			Model:            "gpt-4-turbo",
+			DiscoveryRetries: DefaultDiscoveryRetries,
 		},
 		Testing: TestingConfig{
 			Language: "typescript",
@@ -184,7 +508,8 @@ func DefaultConfig() *Config {
 				BaseURL: "http://localhost:3000",
 			},
 		},
-		Current: "development",
+		Current:  "development",
+		Matching: DefaultMatchingConfig(),
 		Meta: MetaConfig{
 			Version:   "1.0.0",
 			CreatedAt: now,
@@ -203,10 +528,59 @@ func (c *Config) Validate() error {
 		return NewValidationError("ai.api_key is required for provider: " + c.AI.Provider)
 	}
 	
+	if ask, execute := c.AI.ConfidenceBands(); ask < 0 || execute > 1 || ask > execute {
+		return NewValidationError("ai.confidence_ask_threshold and ai.confidence_threshold must be between 0 and 1, with the ask threshold not above the other")
+	}
+	
+	if c.AI.DiscoveryRetries < 0 {
+		return NewValidationError("ai.discovery_retries must not be negative")
+	}
+	
+	if c.AI.MaxRetries < 0 {
+		return NewValidationError("ai.max_retries must not be negative")
+	}
+	
+	if c.AI.RequestsPerMinute < 0 {
+		return NewValidationError("ai.requests_per_minute must not be negative")
+	}
+	
+	if c.Browser.DebugPort < 0 || c.Browser.DebugPort > 65535 {
+		return NewValidationError("browser.debug_port must be between 0 and 65535")
+	}
+	
+	if c.Session.MaxDuration < 0 {
+		return NewValidationError("session.max_duration must not be negative")
+	}
+	
+	if c.Session.HistoryLogMaxSize < 0 {
+		return NewValidationError("session.history_log_max_size must not be negative")
+	}
+	
+	if _, err := c.MagicLinkTimeout(); err != nil {
+		return NewValidationError(err.Error())
+	}
+	
 	if c.Testing.Framework == "" {
 		return NewValidationError("testing.framework is required")
 	}
 	
+	if c.Testing.FragileSelectorThreshold < 0 || c.Testing.FragileSelectorThreshold > 1 {
+		return NewValidationError("testing.fragile_selector_threshold must be between 0 and 1")
+	}
+	
+	if c.Matching.NavSuggestConfidence > c.Matching.NavMinConfidence {
+		return NewValidationError("matching.nav_suggest_confidence must not exceed matching.nav_min_confidence")
+	}
+	
+	for name, env := range c.Envs {
+		if env.BasePath != "" && !strings.HasPrefix(env.BasePath, "/") {
+			return NewValidationError("environments." + name + ".base_path must start with '/'")
+		}
+		if env.Viewport != nil && (env.Viewport.Width <= 0 || env.Viewport.Height <= 0) {
+			return NewValidationError("environments." + name + ".viewport needs a positive width and height")
+		}
+	}
+	
 	if c.Current != "" {
 		if _, exists := c.Envs[c.Current]; !exists {
 			return NewValidationError("current_env references non-existent environment: " + c.Current)
@@ -230,6 +604,43 @@ func (c *Config) GetCurrentEnv() *EnvConfig {
 	return &env
 }
 
+// DefaultMagicLinkTimeout is used when a config doesn't set email.magic_link_timeout
+const DefaultMagicLinkTimeout = 2 * time.Minute
+
+// MagicLinkTimeout returns how long to wait for a magic link email, from
+// email.magic_link_timeout as a duration ("90s") or a number of seconds
+func (c *Config) MagicLinkTimeout() (time.Duration, error) {
+	var timeout time.Duration
+	switch value := c.Email["magic_link_timeout"].(type) {
+	case nil:
+		return DefaultMagicLinkTimeout, nil
+	case int:
+		timeout = time.Duration(value) * time.Second
+	case float64:
+		timeout = time.Duration(value * float64(time.Second))
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("email.magic_link_timeout must be a duration like \"90s\": %w", err)
+		}
+		timeout = parsed
+	default:
+		return 0, fmt.Errorf("email.magic_link_timeout must be a duration like \"90s\"")
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("email.magic_link_timeout must be positive")
+	}
+	return timeout, nil
+}
+
+// HomeURL returns the app's home page: BaseURL with BasePath appended
+func (e *EnvConfig) HomeURL() string {
+	if e.BasePath == "" {
+		return e.BaseURL
+	}
+	return strings.TrimRight(e.BaseURL, "/") + "/" + strings.Trim(e.BasePath, "/")
+}
+
 // ValidationError represents a configuration validation error
 type ValidationError struct {
 	Message string
@@ -89,7 +89,11 @@ func (l *Loader) loadFromFile(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 	
-	var config Config
+	// Start from defaults for sections that older configs may not define
+	config := Config{
+		AI:       AIConfig{DiscoveryRetries: DefaultDiscoveryRetries},
+		Matching: DefaultMatchingConfig(),
+	}
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
@@ -82,6 +82,13 @@ var ModelRegistry = []ModelInfo{
 		ModelName:   "claude-3-opus-20240229",
 		Description: "High-intelligence model",
 	},
+	{
+		ID:          "claude-3-haiku",
+		DisplayName: "Claude 3 Haiku (Anthropic)",
+		Provider:    "anthropic",
+		ModelName:   "claude-3-haiku-20240307",
+		Description: "Fast, low-cost model",
+	},
 
 	// Google Gemini Models (2025)
 	{
@@ -1,15 +1,28 @@
 package database
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// DefaultFileName is the name of the project database inside .tod
+const DefaultFileName = "tod.db"
+
+// ProjectPath returns the path of the database for a project directory
+func ProjectPath(projectDir string) string {
+	return filepath.Join(projectDir, ".tod", DefaultFileName)
+}
+
 // DB represents the database connection
 type DB struct {
 	conn *sql.DB
@@ -63,6 +76,7 @@ func (db *DB) InitSchema() error {
 		captured_at TIMESTAMP NOT NULL,
 		chrome_port INTEGER,
 		websocket_url TEXT,
+		screenshot_file TEXT NOT NULL DEFAULT '',
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
 
@@ -104,6 +118,19 @@ func (db *DB) InitSchema() error {
 		FOREIGN KEY (capture_id) REFERENCES page_captures(id) ON DELETE CASCADE
 	);
 
+	CREATE TABLE IF NOT EXISTS usage_records (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider TEXT NOT NULL,
+		model TEXT NOT NULL,
+		input_tokens INTEGER NOT NULL DEFAULT 0,
+		output_tokens INTEGER NOT NULL DEFAULT 0,
+		total_tokens INTEGER NOT NULL DEFAULT 0,
+		cost REAL NOT NULL DEFAULT 0,
+		request_count INTEGER NOT NULL DEFAULT 0,
+		started_at TIMESTAMP NOT NULL,
+		ended_at TIMESTAMP NOT NULL
+	);
+
 	CREATE INDEX IF NOT EXISTS idx_captures_url ON page_captures(url);
 	CREATE INDEX IF NOT EXISTS idx_captures_captured_at ON page_captures(captured_at);
 	CREATE INDEX IF NOT EXISTS idx_actions_capture_id ON discovered_actions(capture_id);
@@ -111,17 +138,73 @@ func (db *DB) InitSchema() error {
 	CREATE INDEX IF NOT EXISTS idx_generations_capture_id ON test_generations(capture_id);
 	CREATE INDEX IF NOT EXISTS idx_llm_capture_id ON llm_interactions(capture_id);
 	CREATE INDEX IF NOT EXISTS idx_llm_type ON llm_interactions(interaction_type);
+	CREATE INDEX IF NOT EXISTS idx_llm_created_at ON llm_interactions(created_at);
+	CREATE INDEX IF NOT EXISTS idx_usage_started_at ON usage_records(started_at);
 	`
 
-	_, err := db.conn.Exec(schema)
+	if _, err := db.conn.Exec(schema); err != nil {
+		return err
+	}
+
+	// Columns added after the table was first created
+	if err := db.addColumnIfMissing("page_captures", "screenshot_file", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("discovered_actions", "dedup_key", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("discovered_actions", "expected_result", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("llm_interactions", "latency_ms", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Rows saved before dedup keys existed keep an empty key
+	_, err := db.conn.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_actions_dedup_key ON discovered_actions(dedup_key) WHERE dedup_key != ''`)
 	return err
 }
 
+// addColumnIfMissing adds a column to a table created by an older version
+func (db *DB) addColumnIfMissing(table, column, definition string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := db.conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
 // SavePageCapture saves a page capture to the database
 func (db *DB) SavePageCapture(capture *PageCapture) (int64, error) {
 	query := `
-		INSERT INTO page_captures (url, title, html_file, html_length, captured_at, chrome_port, websocket_url)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO page_captures (url, title, html_file, html_length, captured_at, chrome_port, websocket_url, screenshot_file)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := db.conn.Exec(query, 
@@ -132,6 +215,7 @@ func (db *DB) SavePageCapture(capture *PageCapture) (int64, error) {
 		capture.CapturedAt,
 		capture.ChromePort,
 		capture.WebSocketURL,
+		capture.ScreenshotFile,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to save page capture: %w", err)
@@ -154,8 +238,8 @@ func (db *DB) SaveDiscoveredActions(captureID int64, actions []DiscoveredAction)
 	defer tx.Rollback()
 
 	query := `
-		INSERT INTO discovered_actions (capture_id, description, element, selector, action, is_tested, priority)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO discovered_actions (capture_id, description, element, selector, action, is_tested, priority, expected_result)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	stmt, err := tx.Prepare(query)
@@ -173,6 +257,7 @@ func (db *DB) SaveDiscoveredActions(captureID int64, actions []DiscoveredAction)
 			action.Action,
 			action.IsTested,
 			action.Priority,
+			action.ExpectedResult,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to save action: %w", err)
@@ -186,6 +271,85 @@ func (db *DB) SaveDiscoveredActions(captureID int64, actions []DiscoveredAction)
 	return nil
 }
 
+// ActionDedupKey identifies the same action on the same page across captures
+func ActionDedupKey(pageURL, selector, action string) string {
+	if i := strings.Index(pageURL, "#"); i >= 0 {
+		pageURL = pageURL[:i]
+	}
+	sum := sha256.Sum256([]byte(pageURL + "\x00" + selector + "\x00" + action))
+	return hex.EncodeToString(sum[:])
+}
+
+// UpsertDiscoveredActions saves actions like SaveDiscoveredActions, but an
+// action already discovered on the same URL with the same selector and action
+// is merged into its existing row: the row moves to the new capture, takes the
+// new priority, stays tested once it has been tested and keeps its expected
+// result unless a new one was observed.
+func (db *DB) UpsertDiscoveredActions(captureID int64, actions []DiscoveredAction) error {
+	var pageURL string
+	if err := db.conn.QueryRow(`SELECT url FROM page_captures WHERE id = ?`, captureID).Scan(&pageURL); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("capture not found")
+		}
+		return fmt.Errorf("failed to get capture: %w", err)
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO discovered_actions (capture_id, description, element, selector, action, is_tested, priority, expected_result, dedup_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(dedup_key) WHERE dedup_key != '' DO UPDATE SET
+			capture_id      = excluded.capture_id,
+			description     = excluded.description,
+			element         = excluded.element,
+			is_tested       = is_tested OR excluded.is_tested,
+			priority        = excluded.priority,
+			expected_result = CASE WHEN excluded.expected_result != '' THEN excluded.expected_result ELSE expected_result END
+	`
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, action := range actions {
+		_, err := stmt.Exec(
+			captureID,
+			action.Description,
+			action.Element,
+			action.Selector,
+			action.Action,
+			action.IsTested,
+			action.Priority,
+			action.ExpectedResult,
+			ActionDedupKey(pageURL, action.Selector, action.Action),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save action: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// uniqueActionsQuery selects each distinct action once, counting rows saved
+// before dedup keys existed by their page, selector and action
+const uniqueActionsQuery = `
+	SELECT DISTINCT c.url, a.selector, a.action
+	FROM discovered_actions a
+	JOIN page_captures c ON c.id = a.capture_id
+`
+
 // SaveTestGeneration saves a test generation record
 func (db *DB) SaveTestGeneration(gen *TestGeneration) (int64, error) {
 	query := `
@@ -211,13 +375,19 @@ func (db *DB) SaveLLMInteraction(interaction *LLMInteraction) (int64, error) {
 	query := `
 		INSERT INTO llm_interactions (
 			capture_id, interaction_type, provider, model, 
-			prompt, response, tokens_used, cost, error
+			prompt, response, tokens_used, cost, latency_ms, error
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
+	// Interactions outside a capture have no capture to reference
+	var captureID sql.NullInt64
+	if interaction.CaptureID != 0 {
+		captureID = sql.NullInt64{Int64: interaction.CaptureID, Valid: true}
+	}
+
 	result, err := db.conn.Exec(query,
-		interaction.CaptureID,
+		captureID,
 		interaction.InteractionType,
 		interaction.Provider,
 		interaction.Model,
@@ -225,6 +395,7 @@ func (db *DB) SaveLLMInteraction(interaction *LLMInteraction) (int64, error) {
 		interaction.Response,
 		interaction.TokensUsed,
 		interaction.Cost,
+		interaction.LatencyMs,
 		interaction.Error,
 	)
 	if err != nil {
@@ -237,7 +408,7 @@ func (db *DB) SaveLLMInteraction(interaction *LLMInteraction) (int64, error) {
 // GetPageCapture retrieves a page capture by ID
 func (db *DB) GetPageCapture(id int64) (*PageCapture, error) {
 	query := `
-		SELECT id, url, title, html_file, html_length, captured_at, chrome_port, websocket_url
+		SELECT id, url, title, html_file, html_length, captured_at, chrome_port, websocket_url, screenshot_file
 		FROM page_captures
 		WHERE id = ?
 	`
@@ -252,6 +423,7 @@ func (db *DB) GetPageCapture(id int64) (*PageCapture, error) {
 		&capture.CapturedAt,
 		&capture.ChromePort,
 		&capture.WebSocketURL,
+		&capture.ScreenshotFile,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -263,10 +435,24 @@ func (db *DB) GetPageCapture(id int64) (*PageCapture, error) {
 	return &capture, nil
 }
 
+// GetScreenshotPath returns the screenshot saved with a capture, or "" if
+// taking it failed
+func (db *DB) GetScreenshotPath(captureID int64) (string, error) {
+	var path string
+	err := db.conn.QueryRow(`SELECT screenshot_file FROM page_captures WHERE id = ?`, captureID).Scan(&path)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("capture not found")
+		}
+		return "", fmt.Errorf("failed to get screenshot: %w", err)
+	}
+	return path, nil
+}
+
 // GetDiscoveredActions retrieves all actions for a capture
 func (db *DB) GetDiscoveredActions(captureID int64) ([]DiscoveredAction, error) {
 	query := `
-		SELECT id, capture_id, description, element, selector, action, is_tested, priority, created_at
+		SELECT id, capture_id, description, element, selector, action, is_tested, priority, created_at, expected_result
 		FROM discovered_actions
 		WHERE capture_id = ?
 		ORDER BY priority DESC, id ASC
@@ -291,6 +477,7 @@ func (db *DB) GetDiscoveredActions(captureID int64) ([]DiscoveredAction, error)
 			&action.IsTested,
 			&action.Priority,
 			&action.CreatedAt,
+			&action.ExpectedResult,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan action: %w", err)
@@ -304,7 +491,7 @@ func (db *DB) GetDiscoveredActions(captureID int64) ([]DiscoveredAction, error)
 // GetRecentCaptures retrieves the most recent page captures
 func (db *DB) GetRecentCaptures(limit int) ([]PageCapture, error) {
 	query := `
-		SELECT id, url, title, html_file, html_length, captured_at, chrome_port, websocket_url
+		SELECT id, url, title, html_file, html_length, captured_at, chrome_port, websocket_url, screenshot_file
 		FROM page_captures
 		ORDER BY captured_at DESC
 		LIMIT ?
@@ -328,6 +515,7 @@ func (db *DB) GetRecentCaptures(limit int) ([]PageCapture, error) {
 			&capture.CapturedAt,
 			&capture.ChromePort,
 			&capture.WebSocketURL,
+			&capture.ScreenshotFile,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan capture: %w", err)
@@ -338,10 +526,10 @@ func (db *DB) GetRecentCaptures(limit int) ([]PageCapture, error) {
 	return captures, nil
 }
 
-// GetUntestedActionCount returns the count of untested actions
+// GetUntestedActionCount returns the number of unique untested actions
 func (db *DB) GetUntestedActionCount() (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM discovered_actions WHERE is_tested = 0`
+	query := `SELECT COUNT(*) FROM (` + uniqueActionsQuery + ` WHERE a.is_tested = 0)`
 	err := db.conn.QueryRow(query).Scan(&count)
 	return count, err
 }
@@ -358,17 +546,16 @@ func (db *DB) GetStatistics() (map[string]interface{}, error) {
 	}
 	stats["total_captures"] = totalCaptures
 
-	// Total actions
+	// Unique actions
 	var totalActions int
-	err = db.conn.QueryRow("SELECT COUNT(*) FROM discovered_actions").Scan(&totalActions)
+	err = db.conn.QueryRow("SELECT COUNT(*) FROM (" + uniqueActionsQuery + ")").Scan(&totalActions)
 	if err != nil {
 		return nil, err
 	}
 	stats["total_actions"] = totalActions
 
 	// Untested actions
-	var untestedActions int
-	err = db.conn.QueryRow("SELECT COUNT(*) FROM discovered_actions WHERE is_tested = 0").Scan(&untestedActions)
+	untestedActions, err := db.GetUntestedActionCount()
 	if err != nil {
 		return nil, err
 	}
@@ -403,11 +590,115 @@ func (db *DB) GetStatistics() (map[string]interface{}, error) {
 	return stats, nil
 }
 
+// GetStatisticsByPeriod counts the captures, discovered actions and LLM
+// interactions between from and to, grouped by day and by the domain of the
+// captured page. LLM interactions not tied to a capture are grouped under an
+// empty domain. Rows are sorted by day, then domain.
+func (db *DB) GetStatisticsByPeriod(from, to time.Time) ([]PeriodStats, error) {
+	type key struct{ day, domain string }
+	groups := make(map[key]*PeriodStats)
+	group := func(at time.Time, domain string) *PeriodStats {
+		k := key{at.Local().Format("2006-01-02"), domain}
+		stats, ok := groups[k]
+		if !ok {
+			stats = &PeriodStats{Day: k.day, Domain: domain}
+			groups[k] = stats
+		}
+		return stats
+	}
+
+	// The range filter uses idx_captures_captured_at and the counts use the
+	// capture_id indexes
+	rows, err := db.conn.Query(`
+		SELECT c.captured_at, c.url,
+		       (SELECT COUNT(*) FROM discovered_actions a WHERE a.capture_id = c.id),
+		       (SELECT COUNT(*) FROM llm_interactions l WHERE l.capture_id = c.id),
+		       (SELECT COALESCE(SUM(l.cost), 0) FROM llm_interactions l WHERE l.capture_id = c.id)
+		FROM page_captures c
+		WHERE c.captured_at >= ? AND c.captured_at < ?
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query captures: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			capturedAt time.Time
+			pageURL    string
+			actions    int
+			llmCount   int
+			llmCost    float64
+		)
+		if err := rows.Scan(&capturedAt, &pageURL, &actions, &llmCount, &llmCost); err != nil {
+			return nil, fmt.Errorf("failed to scan capture: %w", err)
+		}
+		stats := group(capturedAt, urlDomain(pageURL))
+		stats.Captures++
+		stats.Actions += actions
+		stats.LLMInteractions += llmCount
+		stats.LLMCost += llmCost
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query captures: %w", err)
+	}
+	rows.Close()
+
+	// created_at defaults to CURRENT_TIMESTAMP, which is UTC without an offset
+	const sqliteTimestamp = "2006-01-02 15:04:05"
+	llmRows, err := db.conn.Query(`
+		SELECT created_at, COALESCE(cost, 0)
+		FROM llm_interactions
+		WHERE created_at >= ? AND created_at < ? AND capture_id IS NULL
+	`, from.UTC().Format(sqliteTimestamp), to.UTC().Format(sqliteTimestamp))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query LLM interactions: %w", err)
+	}
+	defer llmRows.Close()
+
+	for llmRows.Next() {
+		var (
+			createdAt time.Time
+			cost      float64
+		)
+		if err := llmRows.Scan(&createdAt, &cost); err != nil {
+			return nil, fmt.Errorf("failed to scan LLM interaction: %w", err)
+		}
+		stats := group(createdAt, "")
+		stats.LLMInteractions++
+		stats.LLMCost += cost
+	}
+	if err := llmRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query LLM interactions: %w", err)
+	}
+
+	periods := make([]PeriodStats, 0, len(groups))
+	for _, stats := range groups {
+		periods = append(periods, *stats)
+	}
+	sort.Slice(periods, func(i, j int) bool {
+		if periods[i].Day != periods[j].Day {
+			return periods[i].Day < periods[j].Day
+		}
+		return periods[i].Domain < periods[j].Domain
+	})
+	return periods, nil
+}
+
+// urlDomain returns the host of a captured page's URL
+func urlDomain(pageURL string) string {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || parsed.Hostname() == "" {
+		return pageURL
+	}
+	return parsed.Hostname()
+}
+
 // GetLLMInteractions retrieves LLM interactions for a capture
 func (db *DB) GetLLMInteractions(captureID int64) ([]LLMInteraction, error) {
 	query := `
 		SELECT id, capture_id, interaction_type, provider, model, 
-		       prompt, response, tokens_used, cost, error, created_at
+		       prompt, response, tokens_used, cost, latency_ms, error, created_at
 		FROM llm_interactions
 		WHERE capture_id = ?
 		ORDER BY created_at DESC
@@ -419,26 +710,61 @@ func (db *DB) GetLLMInteractions(captureID int64) ([]LLMInteraction, error) {
 	}
 	defer rows.Close()
 
+	return scanLLMInteractions(rows)
+}
+
+// GetRecentLLMInteractions retrieves the most recent LLM interactions across
+// all captures, newest first
+func (db *DB) GetRecentLLMInteractions(limit int) ([]LLMInteraction, error) {
+	query := `
+		SELECT id, capture_id, interaction_type, provider, model, 
+		       prompt, response, tokens_used, cost, latency_ms, error, created_at
+		FROM llm_interactions
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := db.conn.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query LLM interactions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLLMInteractions(rows)
+}
+
+// scanLLMInteractions reads the rows of an llm_interactions query
+func scanLLMInteractions(rows *sql.Rows) ([]LLMInteraction, error) {
 	var interactions []LLMInteraction
 	for rows.Next() {
 		var interaction LLMInteraction
-		var errorStr sql.NullString
+		var captureID sql.NullInt64
+		var provider, model, response, errorStr sql.NullString
+		var tokensUsed sql.NullInt64
+		var cost sql.NullFloat64
 		err := rows.Scan(
 			&interaction.ID,
-			&interaction.CaptureID,
+			&captureID,
 			&interaction.InteractionType,
-			&interaction.Provider,
-			&interaction.Model,
+			&provider,
+			&model,
 			&interaction.Prompt,
-			&interaction.Response,
-			&interaction.TokensUsed,
-			&interaction.Cost,
+			&response,
+			&tokensUsed,
+			&cost,
+			&interaction.LatencyMs,
 			&errorStr,
 			&interaction.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan LLM interaction: %w", err)
 		}
+		interaction.CaptureID = captureID.Int64
+		interaction.Provider = provider.String
+		interaction.Model = model.String
+		interaction.Response = response.String
+		interaction.TokensUsed = int(tokensUsed.Int64)
+		interaction.Cost = cost.Float64
 		if errorStr.Valid {
 			interaction.Error = errorStr.String
 		}
@@ -446,4 +772,88 @@ func (db *DB) GetLLMInteractions(captureID int64) ([]LLMInteraction, error) {
 	}
 
 	return interactions, nil
-}
\ No newline at end of file
+}
+// SaveUsageRecord saves the LLM usage of a session
+func (db *DB) SaveUsageRecord(record *UsageRecord) (int64, error) {
+	query := `
+		INSERT INTO usage_records (
+			provider, model, input_tokens, output_tokens, total_tokens,
+			cost, request_count, started_at, ended_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.conn.Exec(query,
+		record.Provider,
+		record.Model,
+		record.InputTokens,
+		record.OutputTokens,
+		record.TotalTokens,
+		record.Cost,
+		record.RequestCount,
+		record.StartedAt,
+		record.EndedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save usage record: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetTotalUsage returns the usage recorded across all sessions
+func (db *DB) GetTotalUsage() (*UsageTotal, error) {
+	query := `
+		SELECT COUNT(DISTINCT started_at), COALESCE(SUM(total_tokens), 0),
+		       COALESCE(SUM(cost), 0), COALESCE(SUM(request_count), 0)
+		FROM usage_records
+	`
+
+	total := UsageTotal{Key: "total"}
+	err := db.conn.QueryRow(query).Scan(&total.Sessions, &total.TotalTokens, &total.Cost, &total.RequestCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total usage: %w", err)
+	}
+
+	return &total, nil
+}
+
+// GetUsageByDay returns usage grouped by the day sessions started, newest first
+func (db *DB) GetUsageByDay() ([]UsageTotal, error) {
+	return db.queryUsageTotals(`
+		SELECT substr(started_at, 1, 10), COUNT(DISTINCT started_at), SUM(total_tokens), SUM(cost), SUM(request_count)
+		FROM usage_records
+		GROUP BY substr(started_at, 1, 10)
+		ORDER BY substr(started_at, 1, 10) DESC
+	`)
+}
+
+// GetUsageByModel returns usage grouped by provider and model, costliest first
+func (db *DB) GetUsageByModel() ([]UsageTotal, error) {
+	return db.queryUsageTotals(`
+		SELECT provider || '/' || model, COUNT(DISTINCT started_at), SUM(total_tokens), SUM(cost), SUM(request_count)
+		FROM usage_records
+		GROUP BY provider, model
+		ORDER BY SUM(cost) DESC
+	`)
+}
+
+// queryUsageTotals runs a grouped usage query
+func (db *DB) queryUsageTotals(query string) ([]UsageTotal, error) {
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []UsageTotal
+	for rows.Next() {
+		var total UsageTotal
+		if err := rows.Scan(&total.Key, &total.Sessions, &total.TotalTokens, &total.Cost, &total.RequestCount); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, nil
+}
@@ -6,14 +6,15 @@ import (
 
 // PageCapture represents a captured web page
 type PageCapture struct {
-	ID           int64     `db:"id"`
-	URL          string    `db:"url"`
-	Title        string    `db:"title"`
-	HTMLFile     string    `db:"html_file"`
-	HTMLLength   int       `db:"html_length"`
-	CapturedAt   time.Time `db:"captured_at"`
-	ChromePort   int       `db:"chrome_port"`
-	WebSocketURL string    `db:"websocket_url"`
+	ID             int64     `db:"id"`
+	URL            string    `db:"url"`
+	Title          string    `db:"title"`
+	HTMLFile       string    `db:"html_file"`
+	HTMLLength     int       `db:"html_length"`
+	CapturedAt     time.Time `db:"captured_at"`
+	ChromePort     int       `db:"chrome_port"`
+	WebSocketURL   string    `db:"websocket_url"`
+	ScreenshotFile string    `db:"screenshot_file"` // empty if the screenshot failed
 }
 
 // DiscoveredAction represents an action found on a page
@@ -27,6 +28,10 @@ type DiscoveredAction struct {
 	IsTested    bool      `db:"is_tested"`
 	Priority    string    `db:"priority"`
 	CreatedAt   time.Time `db:"created_at"`
+
+	// ExpectedResult is what the page was seen to do after the action ran,
+	// used as the assertion in generated tests
+	ExpectedResult string `db:"expected_result"`
 }
 
 // TestGeneration represents a test generation session
@@ -50,6 +55,39 @@ type LLMInteraction struct {
 	Response     string    `db:"response"`
 	TokensUsed   int       `db:"tokens_used"`
 	Cost         float64   `db:"cost"`
+	LatencyMs    int64     `db:"latency_ms"`
 	Error        string    `db:"error"`
 	CreatedAt    time.Time `db:"created_at"`
-}
\ No newline at end of file
+}
+// UsageRecord is the LLM usage of one session for one provider and model
+type UsageRecord struct {
+	ID           int64     `db:"id"`
+	Provider     string    `db:"provider"`
+	Model        string    `db:"model"`
+	InputTokens  int64     `db:"input_tokens"`
+	OutputTokens int64     `db:"output_tokens"`
+	TotalTokens  int64     `db:"total_tokens"`
+	Cost         float64   `db:"cost"`
+	RequestCount int       `db:"request_count"`
+	StartedAt    time.Time `db:"started_at"`
+	EndedAt      time.Time `db:"ended_at"`
+}
+
+// UsageTotal summarizes usage for a group such as a day or a provider/model
+type UsageTotal struct {
+	Key          string
+	Sessions     int
+	TotalTokens  int64
+	Cost         float64
+	RequestCount int
+}
+
+// PeriodStats counts the activity recorded on one day for one domain
+type PeriodStats struct {
+	Day             string // YYYY-MM-DD in local time
+	Domain          string // empty for LLM interactions without a capture
+	Captures        int
+	Actions         int
+	LLMInteractions int
+	LLMCost         float64
+}
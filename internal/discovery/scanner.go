@@ -723,11 +723,7 @@ func (s *Scanner) createLLMClient() (llm.Client, error) {
 		return nil, fmt.Errorf("%s API key not configured - run 'tod init' to set up AI provider or use 'local' provider for free analysis", s.config.AI.Provider)
 	}
 	
-	options := map[string]interface{}{
-		"model": s.config.AI.Model,
-	}
-	
-	return llm.NewClient(provider, apiKey, options)
+	return llm.NewClient(provider, apiKey, s.config.AI.ClientOptions())
 }
 
 // convertAnalysisToActions converts LLM analysis results to TestActions
@@ -1,9 +1,9 @@
 package email
 
 import (
+	stdcontext "context"
 	"fmt"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/lance13c/tod/internal/llm"
@@ -12,6 +12,7 @@ import (
 // ExtractorService handles LLM-powered extraction of authentication data from emails
 type ExtractorService struct {
 	llmClient llm.Client
+	linkRules LinkRuleSet
 }
 
 // ExtractionResult contains the result of LLM-based email content extraction
@@ -41,6 +42,11 @@ func NewExtractorService(llmClient llm.Client) *ExtractorService {
 	}
 }
 
+// SetLinkRules sets the per-domain rules used to pick magic links
+func (e *ExtractorService) SetLinkRules(rules LinkRuleSet) {
+	e.linkRules = rules
+}
+
 // ExtractAuthData extracts authentication data from recent emails using LLM
 func (e *ExtractorService) ExtractAuthData(emails []*Email, authType AuthType, context string) (*ExtractionResult, error) {
 	if len(emails) == 0 {
@@ -104,48 +110,28 @@ func (e *ExtractorService) tryFallbackExtraction(email *Email, authType AuthType
 	}
 }
 
-// extractMagicLink finds URLs in email content that look like magic links
+// extractMagicLink picks the most likely magic link among the email's links,
+// using the link rules configured for the sender's domain
 func (e *ExtractorService) extractMagicLink(email *Email) *ExtractionResult {
-	// Look for URLs in the email body
-	urlRegex := regexp.MustCompile(`https?://[^\s<>"']+(?:verify|auth|login|confirm|activate|magic)[^\s<>"']*`)
-	
-	content := email.Body + " " + email.Snippet
-	matches := urlRegex.FindAllString(content, -1)
-	
-	if len(matches) > 0 {
-		// Return the first URL found
+	candidates := scoreAuthLinks(email.Body+"\n"+email.Snippet, e.linkRules.For(senderDomain(email.From)))
+	if len(candidates) == 0 {
 		return &ExtractionResult{
-			Success:    true,
-			Type:       string(AuthTypeMagicLink),
-			Value:      matches[0],
-			Confidence: 0.8, // High confidence for URL pattern match
+			Success: false,
+			Type:    string(AuthTypeMagicLink),
+			Error:   "no magic link URLs found",
 		}
 	}
 
-	// Also look for any HTTPS URL as potential magic link
-	generalURLRegex := regexp.MustCompile(`https://[^\s<>"']+`)
-	generalMatches := generalURLRegex.FindAllString(content, -1)
-	
-	if len(generalMatches) > 0 {
-		// Filter out common non-auth URLs
-		for _, url := range generalMatches {
-			if !strings.Contains(strings.ToLower(url), "unsubscribe") &&
-			   !strings.Contains(strings.ToLower(url), "privacy") &&
-			   !strings.Contains(strings.ToLower(url), "terms") {
-				return &ExtractionResult{
-					Success:    true,
-					Type:       string(AuthTypeMagicLink),
-					Value:      url,
-					Confidence: 0.6, // Lower confidence for general URL
-				}
-			}
-		}
+	// Lower confidence when the link only won on position
+	confidence := 0.6
+	if candidates[0].Score >= 3 {
+		confidence = 0.8
 	}
-
 	return &ExtractionResult{
-		Success: false,
-		Type:    string(AuthTypeMagicLink),
-		Error:   "no magic link URLs found",
+		Success:    true,
+		Type:       string(AuthTypeMagicLink),
+		Value:      candidates[0].URL,
+		Confidence: confidence,
 	}
 }
 
@@ -256,6 +242,13 @@ func (e *ExtractorService) WaitForAuthEmail(client *Client, authType AuthType, c
 
 // WaitForAuthEmailContinuous polls for authentication emails continuously with configurable interval
 func (e *ExtractorService) WaitForAuthEmailContinuous(client *Client, authType AuthType, context string, checkInterval time.Duration, maxTimeout time.Duration) (*ExtractionResult, error) {
+	return e.WaitForAuthEmailContext(stdcontext.Background(), client, authType, context, checkInterval, maxTimeout, nil)
+}
+
+// WaitForAuthEmailContext polls for authentication emails like
+// WaitForAuthEmailContinuous until ctx is done, calling progress, if set,
+// before each check so callers can show the wait isn't stuck
+func (e *ExtractorService) WaitForAuthEmailContext(ctx stdcontext.Context, client *Client, authType AuthType, emailContext string, checkInterval time.Duration, maxTimeout time.Duration, progress func(attempt int, elapsed time.Duration)) (*ExtractionResult, error) {
 	startTime := time.Now()
 	
 	// Default check interval to 5 seconds if not specified
@@ -268,26 +261,26 @@ func (e *ExtractorService) WaitForAuthEmailContinuous(client *Client, authType A
 		maxTimeout = 2 * time.Minute
 	}
 
-	for time.Since(startTime) < maxTimeout {
-		// Get emails from the last 2 minutes (wider window for continuous scanning)
-		emails, err := client.GetRecentEmails(2 * time.Minute)
-		if err != nil {
-			time.Sleep(checkInterval)
-			continue
+	for attempt := 1; time.Since(startTime) < maxTimeout; attempt++ {
+		if progress != nil {
+			progress(attempt, time.Since(startTime))
 		}
 
-		// Try to extract auth data
-		result, err := e.ExtractAuthData(emails, authType, context)
-		if err != nil {
-			time.Sleep(checkInterval)
-			continue
+		// Get emails from the last 2 minutes (wider window for continuous scanning)
+		emails, err := client.GetRecentEmails(2 * time.Minute)
+		if err == nil {
+			// Try to extract auth data
+			result, err := e.ExtractAuthData(emails, authType, emailContext)
+			if err == nil && result.Success {
+				return result, nil
+			}
 		}
 
-		if result.Success {
-			return result, nil
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(checkInterval):
 		}
-
-		time.Sleep(checkInterval)
 	}
 
 	return &ExtractionResult{
@@ -7,7 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,10 +27,21 @@ type IMAPMonitor struct {
 	password string
 	useTLS   bool
 	
+	// Filters messages must match to be checked for links
+	from    string
+	subject string
+	
+	// Rules for picking the magic link among a message's links
+	linkRules LinkRuleSet
+	
 	// Monitoring state
-	client        *client.Client
-	lastMessageID uint32
-	pollInterval  time.Duration
+	client       *client.Client
+	startedAt    time.Time
+	pollInterval time.Duration
+	
+	// UIDs already checked, valid while the mailbox keeps its UIDVALIDITY
+	seenUIDs    map[uint32]bool
+	uidValidity uint32
 	
 	// Callbacks
 	onMagicLink func(url string) error
@@ -41,11 +52,18 @@ type IMAPConfig struct {
 	Host         string
 	Port         string
 	Username     string
-	Password     string
-	UseTLS       bool
+	Password     string // the account password or an app password
+	UseTLS       bool   // implicit TLS; otherwise STARTTLS is used when offered
 	PollInterval time.Duration
+	From         string // only check messages from this sender, if set
+	Subject      string // only check messages with this in the subject, if set
+	LinkRules    LinkRuleSet
 }
 
+// recentMessageWindow is how old a message may be when monitoring starts and
+// still have its link followed
+const recentMessageWindow = 10 * time.Minute
+
 // GetUsername returns the configured username
 func (m *IMAPMonitor) GetUsername() string {
 	return m.username
@@ -63,7 +81,11 @@ func NewIMAPMonitor(config *IMAPConfig) (*IMAPMonitor, error) {
 		username:     config.Username,
 		password:     config.Password,
 		useTLS:       config.UseTLS,
+		from:         config.From,
+		subject:      config.Subject,
+		linkRules:    config.LinkRules,
 		pollInterval: config.PollInterval,
+		seenUIDs:     make(map[uint32]bool),
 	}
 	
 	return monitor, nil
@@ -75,10 +97,26 @@ func LoadIMAPConfigFromEnv() *IMAPConfig {
 		Host:         getEnvOrDefault("IMAP_HOST", "imap.fastmail.com"),
 		Port:         getEnvOrDefault("IMAP_PORT", "993"), // IMAP SSL port
 		Username:     os.Getenv("IMAP_USER"),
-		Password:     os.Getenv("IMAP_PASS"),
+		Password:     imapPasswordFromEnv(),
 		UseTLS:       os.Getenv("IMAP_SECURE") != "false", // Default to true
 		PollInterval: 5 * time.Second,
+		From:         os.Getenv("IMAP_FROM"),
+		Subject:      os.Getenv("IMAP_SUBJECT"),
+	}
+}
+
+// imapPasswordFromEnv returns IMAP_APP_PASSWORD, falling back to IMAP_PASS
+func imapPasswordFromEnv() string {
+	if appPassword := os.Getenv("IMAP_APP_PASSWORD"); appPassword != "" {
+		return normalizeAppPassword(appPassword)
 	}
+	return os.Getenv("IMAP_PASS")
+}
+
+// normalizeAppPassword removes the spaces providers like Gmail show app
+// passwords with ("abcd efgh ijkl mnop")
+func normalizeAppPassword(password string) string {
+	return strings.ReplaceAll(password, " ", "")
 }
 
 // LoadIMAPConfig loads IMAP configuration from the project's config file
@@ -121,9 +159,19 @@ func LoadIMAPConfigFromFile(configData map[string]interface{}) *IMAPConfig {
 		if pass, ok := emailConfig["imap_pass"].(string); ok {
 			config.Password = pass
 		}
+		if appPassword, ok := emailConfig["imap_app_password"].(string); ok && appPassword != "" {
+			config.Password = normalizeAppPassword(appPassword)
+		}
 		if secure, ok := emailConfig["imap_secure"].(bool); ok {
 			config.UseTLS = secure
 		}
+		if from, ok := emailConfig["imap_from"].(string); ok {
+			config.From = from
+		}
+		if subject, ok := emailConfig["imap_subject"].(string); ok {
+			config.Subject = subject
+		}
+		config.LinkRules = linkRulesFromConfig(configData)
 		
 		// Fall back to old SMTP config names for compatibility
 		if config.Host == "" {
@@ -154,7 +202,7 @@ func LoadIMAPConfigFromFile(configData map[string]interface{}) *IMAPConfig {
 		config.Username = os.Getenv("IMAP_USER")
 	}
 	if config.Password == "" {
-		config.Password = os.Getenv("IMAP_PASS")
+		config.Password = imapPasswordFromEnv()
 	}
 	
 	return config
@@ -167,14 +215,27 @@ func (m *IMAPMonitor) Connect() error {
 	var c *client.Client
 	var err error
 	
+	tlsConfig := &tls.Config{
+		ServerName: m.host,
+		MinVersion: tls.VersionTLS12,
+	}
+	
 	if m.useTLS || m.port == "993" {
 		// Connect with TLS
-		c, err = client.DialTLS(address, &tls.Config{
-			ServerName: m.host,
-		})
+		c, err = client.DialTLS(address, tlsConfig)
 	} else {
-		// Connect without TLS
+		// Connect without TLS, upgrading with STARTTLS when the server offers it
 		c, err = client.Dial(address)
+		if err == nil {
+			if ok, _ := c.SupportStartTLS(); ok {
+				if err := c.StartTLS(tlsConfig); err != nil {
+					c.Logout()
+					return fmt.Errorf("failed to start TLS: %w", err)
+				}
+			} else {
+				logging.Warn("[EMAIL MONITOR] %s doesn't support STARTTLS, the password is sent unencrypted", m.host)
+			}
+		}
 	}
 	
 	if err != nil {
@@ -190,33 +251,92 @@ func (m *IMAPMonitor) Connect() error {
 	m.client = c
 	
 	// Select INBOX
-	_, err = c.Select("INBOX", false)
+	mailbox, err := c.Select("INBOX", false)
 	if err != nil {
 		return fmt.Errorf("failed to select INBOX: %w", err)
 	}
 	
-	// Don't update lastMessageID on initial connect - we want to check recent emails
-	// Only set it if it's the very first connection
-	if m.lastMessageID == 0 {
-		// Check last 10 messages on initial connect to catch recently sent magic links
-		status, err := m.client.Status("INBOX", []imap.StatusItem{imap.StatusMessages})
-		if err == nil && status.Messages > 0 {
-			// Start checking from 10 messages back (or from beginning if less than 10)
-			if status.Messages > 10 {
-				m.lastMessageID = status.Messages - 10
-			} else {
-				m.lastMessageID = 0
-			}
-			logging.Info("[EMAIL MONITOR] Will check last %d messages for magic links", status.Messages - m.lastMessageID)
+	// UIDs only identify the same messages while UIDVALIDITY is unchanged
+	if mailbox.UidValidity != m.uidValidity {
+		if m.uidValidity != 0 {
+			logging.Info("[EMAIL MONITOR] INBOX UIDVALIDITY changed, rechecking recent messages")
 		}
+		m.uidValidity = mailbox.UidValidity
+		m.seenUIDs = make(map[uint32]bool)
+	}
+	
+	// Messages received shortly before the first connection are checked too,
+	// to catch a magic link sent just before monitoring started
+	if m.startedAt.IsZero() {
+		m.startedAt = time.Now()
 	} else {
-		// On reconnect, keep the existing lastMessageID
-		logging.Debug("[EMAIL MONITOR] Reconnected, continuing from message ID: %d", m.lastMessageID)
+		logging.Debug("[EMAIL MONITOR] Reconnected, %d messages already checked", len(m.seenUIDs))
 	}
 	
 	return nil
 }
 
+// searchCriteria matches messages received since the given time that pass
+// the sender and subject filters. IMAP only compares dates, so callers
+// still need to check InternalDate.
+func (m *IMAPMonitor) searchCriteria(since time.Time) *imap.SearchCriteria {
+	criteria := imap.NewSearchCriteria()
+	criteria.Since = since
+	if m.from != "" {
+		criteria.Header.Add("From", m.from)
+	}
+	if m.subject != "" {
+		criteria.Header.Add("Subject", m.subject)
+	}
+	return criteria
+}
+
+// fetchUnseen fetches the full messages matching criteria whose UIDs
+// haven't been checked yet, oldest first
+func (m *IMAPMonitor) fetchUnseen(criteria *imap.SearchCriteria) ([]*imap.Message, error) {
+	uids, err := m.client.UidSearch(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search INBOX: %w", err)
+	}
+	
+	seqset := new(imap.SeqSet)
+	for _, uid := range uids {
+		if !m.seenUIDs[uid] {
+			seqset.AddNum(uid)
+		}
+	}
+	if seqset.Empty() {
+		return nil, nil
+	}
+	
+	// Fetch BODY[] for the actual content, not just BODY
+	messages := make(chan *imap.Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		section := &imap.BodySectionName{}
+		items := []imap.FetchItem{
+			imap.FetchUid,
+			imap.FetchEnvelope,
+			imap.FetchInternalDate,
+			section.FetchItem(),
+		}
+		done <- m.client.UidFetch(seqset, items, messages)
+	}()
+	
+	var fetched []*imap.Message
+	for msg := range messages {
+		if msg != nil {
+			fetched = append(fetched, msg)
+		}
+	}
+	if err := <-done; err != nil {
+		return nil, err
+	}
+	
+	sort.Slice(fetched, func(i, j int) bool { return fetched[i].Uid < fetched[j].Uid })
+	return fetched, nil
+}
+
 // Disconnect closes the connection to the IMAP server
 func (m *IMAPMonitor) Disconnect() error {
 	if m.client != nil {
@@ -300,94 +420,37 @@ func (m *IMAPMonitor) StartMonitoringBackground(onMagicLink func(url string) err
 	return stopChan, nil
 }
 
-// checkNewEmails checks for new emails and extracts magic links
+// checkNewEmails checks messages not seen before for magic links
 func (m *IMAPMonitor) checkNewEmails() error {
 	logging.Debug("[EMAIL CHECK] Starting email check...")
 	
-	// Get current mailbox status
-	status, err := m.client.Status("INBOX", []imap.StatusItem{imap.StatusMessages})
+	since := m.startedAt.Add(-recentMessageWindow)
+	messages, err := m.fetchUnseen(m.searchCriteria(since))
 	if err != nil {
 		return err
 	}
 	
-	logging.Debug("[EMAIL CHECK] Inbox has %d total messages", status.Messages)
-	
-	if status.Messages == 0 {
-		logging.Debug("[EMAIL CHECK] Inbox is empty, nothing to check")
-		return nil
-	}
-	
-	// Check for new messages OR recent messages if we just started
-	from := m.lastMessageID + 1
-	to := status.Messages
-	
-	// If this is our first check (lastMessageID could be 0 or set to messages-10)
-	// make sure we check recent messages
-	if from == 1 || m.lastMessageID == 0 {
-		// Check last 10 messages on first scan
-		if status.Messages > 10 {
-			from = status.Messages - 9  // -9 because we want 10 messages total
-		} else {
-			from = 1
-		}
-		logging.Info("[EMAIL CHECK] First scan - checking messages from %d to %d", from, to)
-	} else {
-		logging.Debug("[EMAIL CHECK] Checking messages from %d to %d (last checked: %d)", 
-			from, to, m.lastMessageID)
-	}
-	
-	if from > to {
+	if len(messages) == 0 {
 		logging.Debug("[EMAIL CHECK] No new messages since last check")
 		return nil
 	}
 	
-	newMessageCount := to - from + 1
-	logging.Debug("[EMAIL CHECK] Found %d new message(s) to process", newMessageCount)
-	
-	seqset := new(imap.SeqSet)
-	seqset.AddRange(from, to)
-	
-	// Fetch the messages - CRITICAL: Use BODY[] not just BODY
-	messages := make(chan *imap.Message, 10)
-	done := make(chan error, 1)
-	
-	go func() {
-		// This is the critical fix - fetch BODY[] for actual content
-		section := &imap.BodySectionName{}
-		items := []imap.FetchItem{
-			imap.FetchEnvelope,
-			imap.FetchFlags,
-			imap.FetchInternalDate,
-			section.FetchItem(),  // This fetches BODY[] - the full content
-		}
-		done <- m.client.Fetch(seqset, items, messages)
-	}()
+	logging.Debug("[EMAIL CHECK] Found %d new message(s) to process", len(messages))
 	
-	// Process messages
-	processed := 0
-	for msg := range messages {
-		if msg == nil {
+	for _, msg := range messages {
+		m.seenUIDs[msg.Uid] = true
+		
+		if msg.InternalDate.Before(since) {
 			continue
 		}
 		
-		processed++
-		
 		// Process the message body
 		if err := m.processMessage(msg); err != nil {
 			logging.Error("[EMAIL PARSE] Error processing message: %v", err)
 		}
-		
-		// Update last message ID
-		if msg.SeqNum > m.lastMessageID {
-			m.lastMessageID = msg.SeqNum
-		}
 	}
 	
-	if err := <-done; err != nil {
-		return err
-	}
-	
-	logging.Debug("[EMAIL CHECK] Processed %d messages", processed)
+	logging.Debug("[EMAIL CHECK] Processed %d messages", len(messages))
 	return nil
 }
 
@@ -399,6 +462,38 @@ func (m *IMAPMonitor) processMessage(msg *imap.Message) error {
 			msg.Envelope.From, msg.Envelope.Subject)
 	}
 	
+	content, err := readMessageContent(msg)
+	if err != nil {
+		return err
+	}
+	logging.Debug("[EMAIL PARSE] Email content length: %d bytes", len(content))
+	
+	// Log first 500 chars of content for debugging (sanitized)
+	if len(content) > 0 {
+		preview := content
+		if len(preview) > 500 {
+			preview = preview[:500] + "..."
+		}
+		logging.Debug("[EMAIL PARSE] Content preview: %s", preview)
+	}
+	
+	link := m.extractLink(msg, content)
+	if link != "" {
+		logging.Info("[EMAIL PARSE] Found magic link: %s", link)
+		if m.onMagicLink != nil {
+			if err := m.onMagicLink(link); err != nil {
+				logging.Error("[EMAIL PARSE] Error in callback: %v", err)
+			}
+		}
+	} else {
+		logging.Debug("[EMAIL PARSE] No magic link found in this message")
+	}
+	
+	return nil
+}
+
+// readMessageContent returns the text of a fetched message's inline parts
+func readMessageContent(msg *imap.Message) (string, error) {
 	// Get the email body - with fallback approaches
 	var body io.Reader
 	
@@ -417,13 +512,13 @@ func (m *IMAPMonitor) processMessage(msg *imap.Message) error {
 	}
 	
 	if body == nil {
-		return fmt.Errorf("no body sections available")
+		return "", fmt.Errorf("no body sections available")
 	}
 	
 	// Parse the message
 	mr, err := mail.CreateReader(body)
 	if err != nil {
-		return fmt.Errorf("failed to parse message: %w", err)
+		return "", fmt.Errorf("failed to parse message: %w", err)
 	}
 	
 	// Extract text from all parts
@@ -451,44 +546,12 @@ func (m *IMAPMonitor) processMessage(msg *imap.Message) error {
 		}
 	}
 	
-	// Extract magic link from email content
-	content := emailContent.String()
-	logging.Debug("[EMAIL PARSE] Email content length: %d bytes", len(content))
-	
-	// Log first 500 chars of content for debugging (sanitized)
-	if len(content) > 0 {
-		preview := content
-		if len(preview) > 500 {
-			preview = preview[:500] + "..."
-		}
-		logging.Debug("[EMAIL PARSE] Content preview: %s", preview)
-	}
-	
-	link := extractMagicLinkFromContent(content)
-	if link != "" {
-		logging.Info("[EMAIL PARSE] Found magic link: %s", link)
-		if m.onMagicLink != nil {
-			if err := m.onMagicLink(link); err != nil {
-				logging.Error("[EMAIL PARSE] Error in callback: %v", err)
-			}
-		}
-	} else {
-		logging.Debug("[EMAIL PARSE] No magic link found in this message")
-	}
-	
-	return nil
-}
-
-// updateLastMessageID updates the last message ID to the current latest
-func (m *IMAPMonitor) updateLastMessageID() {
-	status, err := m.client.Status("INBOX", []imap.StatusItem{imap.StatusMessages})
-	if err == nil && status.Messages > 0 {
-		m.lastMessageID = status.Messages
-		logging.Debug("[EMAIL MONITOR] Updated last message ID to: %d", m.lastMessageID)
-	}
+	return emailContent.String(), nil
 }
 
-// CheckRecentEmails checks emails from the last N minutes for magic links
+// CheckRecentEmails checks emails from the last N minutes that match the
+// sender and subject filters for magic links, returning the link in the
+// newest one. Each message is only checked once.
 func (m *IMAPMonitor) CheckRecentEmails(minutes int) (string, error) {
 	if m.client == nil {
 		if err := m.Connect(); err != nil {
@@ -498,49 +561,20 @@ func (m *IMAPMonitor) CheckRecentEmails(minutes int) (string, error) {
 	
 	logging.Info("[EMAIL CHECK] Checking emails from last %d minutes for magic links...", minutes)
 	
-	// Get current mailbox status
-	status, err := m.client.Status("INBOX", []imap.StatusItem{imap.StatusMessages})
+	cutoffTime := time.Now().Add(-time.Duration(minutes) * time.Minute)
+	messages, err := m.fetchUnseen(m.searchCriteria(cutoffTime))
 	if err != nil {
 		return "", err
 	}
 	
-	if status.Messages == 0 {
-		logging.Debug("[EMAIL CHECK] Inbox is empty")
-		return "", nil
-	}
-	
-	// Check last 20 messages (should cover recent emails)
-	from := uint32(1)
-	to := status.Messages
-	
-	if status.Messages > 20 {
-		from = status.Messages - 19
+	// Older messages are superseded by a newer link, so all count as checked
+	for _, msg := range messages {
+		m.seenUIDs[msg.Uid] = true
 	}
 	
-	logging.Debug("[EMAIL CHECK] Scanning messages %d to %d for recent magic links", from, to)
-	
-	seqset := new(imap.SeqSet)
-	seqset.AddRange(from, to)
-	
-	messages := make(chan *imap.Message, 10)
-	done := make(chan error, 1)
-	
-	go func() {
-		section := &imap.BodySectionName{}
-		items := []imap.FetchItem{
-			imap.FetchEnvelope,
-			imap.FetchInternalDate,
-			section.FetchItem(),
-		}
-		done <- m.client.Fetch(seqset, items, messages)
-	}()
-	
 	// Check messages from most recent first
-	cutoffTime := time.Now().Add(-time.Duration(minutes) * time.Minute)
-	var foundLink string
-	
-	for msg := range messages {
-		// Check if message is recent enough
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
 		if msg.InternalDate.Before(cutoffTime) {
 			continue
 		}
@@ -548,143 +582,34 @@ func (m *IMAPMonitor) CheckRecentEmails(minutes int) (string, error) {
 		logging.Debug("[EMAIL CHECK] Processing message from %s (Subject: %s)", 
 			msg.InternalDate.Format("15:04:05"), msg.Envelope.Subject)
 		
-		// Extract body
-		section := &imap.BodySectionName{}
-		body := msg.GetBody(section)
-		if body != nil {
-			content, err := ioutil.ReadAll(body)
-			if err == nil {
-				// Look for magic links
-				link := extractMagicLinkFromContent(string(content))
-				if link != "" {
-					logging.Info("[EMAIL CHECK] Found magic link in email from %s", msg.InternalDate.Format("15:04:05"))
-					foundLink = link
-					break
-				}
-			}
+		content, err := readMessageContent(msg)
+		if err != nil {
+			logging.Debug("[EMAIL CHECK] Failed to read message: %v", err)
+			continue
 		}
-	}
-	
-	if err := <-done; err != nil {
-		return "", err
-	}
-	
-	return foundLink, nil
-}
-
-// extractMagicLinkFromContent extracts magic link URLs from email content
-func extractMagicLinkFromContent(content string) string {
-	logging.Debug("[REGEX EXTRACT] Starting regex extraction on %d bytes", len(content))
-	
-	// First, handle potential line breaks and whitespace in URLs
-	// Emails often wrap long URLs across lines
-	// Replace common line break patterns that might be in the middle of URLs
-	cleanedContent := content
-	// Remove soft line breaks (=\r\n or =\n) which are common in quoted-printable encoding
-	cleanedContent = regexp.MustCompile(`=[\r\n]+`).ReplaceAllString(cleanedContent, "")
-	// Remove line breaks that might be inserted in the middle of URLs
-	cleanedContent = regexp.MustCompile(`(https?://[^\s]*?)[\r\n]+([^\s]*)`).ReplaceAllString(cleanedContent, "$1$2")
-	
-	// Look for URLs containing magic-link/verify or similar auth patterns
-	// Updated pattern to better handle URL parameters with = signs and encoded characters
-	// The key change: use [^\s]+ to match until whitespace, allowing = and & in URLs
-	authURLRegex := regexp.MustCompile(
-		`https?://[^\s]+/[^\s]*(?:magic-link|auth|signin|verify)[^\s]*\?[^\s]+`,
-	)
-	
-	// Also try to find URLs that might be wrapped in angle brackets
-	bracketURLRegex := regexp.MustCompile(
-		`<(https?://[^>]+/[^>]*(?:magic-link|auth|signin|verify)[^>]*\?[^>]+)>`,
-	)
-	
-	// Try bracket-wrapped URLs first (more specific)
-	matches := bracketURLRegex.FindAllStringSubmatch(cleanedContent, -1)
-	if len(matches) > 0 && len(matches[0]) > 1 {
-		fullURL := matches[0][1] // Get the captured group (URL without brackets)
-		logging.Debug("[REGEX EXTRACT] Found bracket-wrapped URL: %s", fullURL)
-		logging.Info("[REGEX EXTRACT] Extracted magic link: %s", fullURL)
-		return fullURL
-	}
-	
-	// Try regular URL pattern
-	matches2 := authURLRegex.FindAllString(cleanedContent, -1)
-	logging.Debug("[REGEX EXTRACT] Auth regex found %d matches", len(matches2))
-	
-	if len(matches2) > 0 {
-		fullURL := matches2[0]
-		logging.Debug("[REGEX EXTRACT] Raw URL found: %s", fullURL)
 		
-		// Clean up the URL more carefully
-		// Remove trailing punctuation that's definitely not part of the URL
-		fullURL = strings.TrimRight(fullURL, " \t\n\r")
-		
-		// Handle common email artifacts at the end
-		// But be careful not to remove valid URL characters
-		for {
-			if len(fullURL) == 0 {
-				break
-			}
-			lastChar := fullURL[len(fullURL)-1:]
-			// Only remove these if they're truly at the end and not part of encoding
-			if lastChar == "." || lastChar == "," || lastChar == "!" || lastChar == ";" || 
-			   lastChar == ":" || lastChar == ")" || lastChar == "]" || lastChar == "}" ||
-			   lastChar == ">" || lastChar == "'" || lastChar == "\"" {
-				// But don't remove ) if there's a matching ( in the URL
-				if lastChar == ")" && strings.Count(fullURL, "(") == strings.Count(fullURL, ")") {
-					break
-				}
-				fullURL = fullURL[:len(fullURL)-1]
-			} else {
-				break
-			}
+		// Look for magic links
+		if link := m.extractLink(msg, content); link != "" {
+			logging.Info("[EMAIL CHECK] Found magic link in email from %s", msg.InternalDate.Format("15:04:05"))
+			return link, nil
 		}
-		
-		logging.Info("[REGEX EXTRACT] Extracted magic link: %s", fullURL)
-		return fullURL
 	}
 	
-	// Second try: Look for any URL with query parameters (likely to be magic links)
-	// Updated to better handle = signs in query parameters
-	generalURLRegex := regexp.MustCompile(`https?://[^\s<>"]+\?[^\s<>"]+`)
-	matches2 = generalURLRegex.FindAllString(cleanedContent, -1)
-	logging.Debug("[REGEX EXTRACT] General regex found %d URLs with params", len(matches2))
-	
-	for _, url := range matches2 {
-		lowerURL := strings.ToLower(url)
-		
-		// Skip common non-auth links
-		if !strings.Contains(lowerURL, "unsubscribe") &&
-		   !strings.Contains(lowerURL, "privacy") &&
-		   !strings.Contains(lowerURL, "terms") &&
-		   !strings.Contains(lowerURL, "preferences") &&
-		   !strings.Contains(lowerURL, "email-settings") &&
-		   !strings.Contains(lowerURL, "support") &&
-		   !strings.Contains(lowerURL, "help") {
-			// More careful cleanup
-			cleanURL := strings.TrimRight(url, " \t\n\r")
-			for {
-				if len(cleanURL) == 0 {
-					break
-				}
-				lastChar := cleanURL[len(cleanURL)-1:]
-				if lastChar == "." || lastChar == "," || lastChar == "!" || lastChar == ";" ||
-				   lastChar == ":" || lastChar == ")" || lastChar == "]" || lastChar == "}" ||
-				   lastChar == ">" || lastChar == "'" || lastChar == "\"" {
-					if lastChar == ")" && strings.Count(cleanURL, "(") == strings.Count(cleanURL, ")") {
-						break
-					}
-					cleanURL = cleanURL[:len(cleanURL)-1]
-				} else {
-					break
-				}
-			}
-			logging.Debug("[REGEX EXTRACT] Selected URL: %s", cleanURL)
-			return cleanURL
-		}
+	return "", nil
+}
+
+// extractLink picks the magic link in a message's content, using the link
+// rules configured for the sender's domain
+func (m *IMAPMonitor) extractLink(msg *imap.Message, content string) string {
+	domain := ""
+	if msg.Envelope != nil && len(msg.Envelope.From) > 0 {
+		domain = msg.Envelope.From[0].HostName
+	}
+	link, err := ExtractAuthLink(content, m.linkRules.For(domain))
+	if err != nil {
+		return ""
 	}
-	
-	logging.Debug("[REGEX EXTRACT] No suitable URL found")
-	return ""
+	return link
 }
 
 // getEnvOrDefault gets environment variable or returns default value
@@ -0,0 +1,260 @@
+package email
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"gopkg.in/yaml.v3"
+)
+
+// LinkMatchRules tunes how ExtractAuthLink scores the links in an email
+type LinkMatchRules struct {
+	HrefPatterns    []string `yaml:"href_patterns"`    // URL substrings of auth links, e.g. "/auth", "token="
+	AnchorPatterns  []string `yaml:"anchor_patterns"`  // anchor text of auth links, e.g. "sign in"
+	ExcludePatterns []string `yaml:"exclude_patterns"` // URL path, query or anchor substrings never chosen, e.g. "unsubscribe"
+	RedirectParams  []string `yaml:"redirect_params"`  // query parameters tracking redirects keep the real URL in
+}
+
+// LinkRuleSet holds the link rules configured per sender domain in
+// email.link_rules
+type LinkRuleSet map[string]LinkMatchRules
+
+// DefaultLinkMatchRules returns the rules used for every email
+func DefaultLinkMatchRules() LinkMatchRules {
+	return LinkMatchRules{
+		HrefPatterns: []string{
+			"magic-link", "magic_link", "magiclink", "/auth", "/verify", "/confirm",
+			"/login", "/signin", "/sign-in", "/callback", "/activate",
+			"token=", "code=", "otp=", "key=",
+		},
+		AnchorPatterns: []string{
+			"sign in", "sign-in", "log in", "login", "confirm", "verify",
+			"magic link", "continue", "activate", "get started", "access your account",
+		},
+		ExcludePatterns: []string{
+			"unsubscribe", "privacy", "terms", "preferences", "email-settings",
+			"support", "help", "mailto:", "view in browser", "view this email",
+		},
+		RedirectParams: []string{"url", "u", "q", "redirect", "redirect_url", "target", "link", "destination"},
+	}
+}
+
+// For returns the default rules extended with those configured for domain or
+// one of its parent domains
+func (s LinkRuleSet) For(domain string) LinkMatchRules {
+	rules := DefaultLinkMatchRules()
+	domain = strings.ToLower(domain)
+	for configured, extra := range s {
+		configured = strings.ToLower(configured)
+		if domain == configured || strings.HasSuffix(domain, "."+configured) {
+			rules.HrefPatterns = append(rules.HrefPatterns, extra.HrefPatterns...)
+			rules.AnchorPatterns = append(rules.AnchorPatterns, extra.AnchorPatterns...)
+			rules.ExcludePatterns = append(rules.ExcludePatterns, extra.ExcludePatterns...)
+			rules.RedirectParams = append(rules.RedirectParams, extra.RedirectParams...)
+		}
+	}
+	return rules
+}
+
+// LoadLinkRules loads email.link_rules from the project's config file
+func LoadLinkRules(projectDir string) LinkRuleSet {
+	configData, err := loadConfigFile(filepath.Join(projectDir, ".tod", "config.yaml"))
+	if err != nil {
+		return nil
+	}
+	return linkRulesFromConfig(configData)
+}
+
+// linkRulesFromConfig reads email.link_rules from parsed config data
+func linkRulesFromConfig(configData map[string]interface{}) LinkRuleSet {
+	emailConfig, ok := configData["email"].(map[string]interface{})
+	if !ok || emailConfig["link_rules"] == nil {
+		return nil
+	}
+
+	// Round-trip through YAML to decode the generic map into the rules
+	data, err := yaml.Marshal(emailConfig["link_rules"])
+	if err != nil {
+		return nil
+	}
+	var rules LinkRuleSet
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil
+	}
+	return rules
+}
+
+// senderDomain returns the domain of an address like "App <noreply@app.com>"
+func senderDomain(from string) string {
+	from = strings.TrimSpace(from)
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		from = strings.TrimSuffix(from[i+1:], ">")
+	}
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		return strings.ToLower(from[i+1:])
+	}
+	return ""
+}
+
+// linkCandidate is a link found in an email
+type linkCandidate struct {
+	URL    string
+	Anchor string // anchor text, empty for plaintext links
+	Index  int    // order of appearance
+	Score  float64
+}
+
+var plainURLPattern = regexp.MustCompile(`https?://[^\s<>"'\[\]]+`)
+
+// ExtractAuthLink finds the authentication link in an email body. Links in
+// the HTML and plaintext parts are scored by their URL, anchor text and
+// position, tracking redirects are unwrapped, and the best is returned.
+func ExtractAuthLink(body string, rules LinkMatchRules) (string, error) {
+	candidates := scoreAuthLinks(body, rules)
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no authentication link found")
+	}
+	return candidates[0].URL, nil
+}
+
+// scoreAuthLinks returns the links in body that aren't excluded, best first
+func scoreAuthLinks(body string, rules LinkMatchRules) []linkCandidate {
+	found := findLinks(body)
+
+	var candidates []linkCandidate
+	for _, c := range found {
+		best, ok := scoreLink(c, c.URL, body, len(found), rules)
+		// A magic link can carry where to go after signing in, so the URL a
+		// redirect points to only replaces it when it scores at least as well
+		if target := unwrapRedirect(c.URL, rules.RedirectParams); target != c.URL {
+			if unwrapped, unwrappedOK := scoreLink(c, target, body, len(found), rules); unwrappedOK && (!ok || unwrapped.Score >= best.Score) {
+				best, ok = unwrapped, true
+			}
+		}
+		if ok {
+			candidates = append(candidates, best)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// scoreLink scores c as a link to link among total links in body. It
+// reports false if the link is excluded.
+func scoreLink(c linkCandidate, link, body string, total int, rules LinkMatchRules) (linkCandidate, bool) {
+	c.URL = link
+	if isExcluded(link, rules.ExcludePatterns) || matchesAny(c.Anchor, rules.ExcludePatterns) {
+		return c, false
+	}
+
+	for _, pattern := range rules.HrefPatterns {
+		if strings.Contains(strings.ToLower(link), strings.ToLower(pattern)) {
+			c.Score += 3
+		}
+	}
+	if matchesAny(c.Anchor, rules.AnchorPatterns) {
+		c.Score += 4
+	}
+	if strings.HasPrefix(link, "https://") {
+		c.Score += 0.5
+	}
+	// Auth links carry a long one-time token
+	if parsed, err := url.Parse(link); err == nil && len(parsed.RawQuery)+len(parsed.Path) > 40 {
+		c.Score += 1
+	}
+	// Links without text are mostly logos and images
+	if c.Anchor == "" && strings.Contains(body, "<a") {
+		c.Score -= 1
+	}
+	// Auth links tend to come first, before footers
+	c.Score += 1 - float64(c.Index)/float64(total)
+	return c, true
+}
+
+// isExcluded reports whether the path, query or fragment of link contains
+// one of patterns. The host isn't matched, so apps on domains like
+// help.example.com still get their links picked.
+func isExcluded(link string, patterns []string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return matchesAny(link, patterns)
+	}
+	return matchesAny(parsed.Path+"?"+parsed.RawQuery+"#"+parsed.Fragment, patterns)
+}
+
+// findLinks collects the links of both the HTML anchors and the plaintext in
+// body, each URL once, in order of appearance
+func findLinks(body string) []linkCandidate {
+	var links []linkCandidate
+	seen := make(map[string]bool)
+	add := func(link, anchor string) {
+		link = strings.TrimRight(strings.ReplaceAll(link, "&amp;", "&"), ".,;:!)]}>'\"")
+		if link == "" || seen[link] {
+			return
+		}
+		seen[link] = true
+		links = append(links, linkCandidate{URL: link, Anchor: strings.Join(strings.Fields(anchor), " "), Index: len(links)})
+	}
+
+	if strings.Contains(body, "<a") {
+		if doc, err := goquery.NewDocumentFromReader(strings.NewReader(body)); err == nil {
+			doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+				href, _ := a.Attr("href")
+				if strings.HasPrefix(href, "http") {
+					add(href, a.Text())
+				}
+			})
+		}
+	}
+
+	for _, link := range plainURLPattern.FindAllString(body, -1) {
+		add(link, "")
+	}
+	return links
+}
+
+// unwrapRedirect returns the URL a tracking redirect points to, following
+// nested redirects a few levels deep
+func unwrapRedirect(link string, params []string) string {
+	for range 3 {
+		parsed, err := url.Parse(link)
+		if err != nil {
+			return link
+		}
+		query := parsed.Query()
+		unwrapped := ""
+		for _, param := range params {
+			if target := query.Get(param); strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+				unwrapped = target
+				break
+			}
+		}
+		if unwrapped == "" {
+			return link
+		}
+		link = unwrapped
+	}
+	return link
+}
+
+// matchesAny reports whether s contains one of patterns, ignoring case
+func matchesAny(s string, patterns []string) bool {
+	if s == "" {
+		return false
+	}
+	s = strings.ToLower(s)
+	for _, pattern := range patterns {
+		if strings.Contains(s, strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
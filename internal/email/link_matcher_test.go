@@ -0,0 +1,61 @@
+package email
+
+import "testing"
+
+func TestExtractAuthLink(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "magic link carrying its post-login target",
+			body: `<p>Hi Ada,</p>
+<a href="https://app.example.com/auth/verify?token=3f9a1c7e5b2d4f6a8c0e&redirect=https://app.example.com/dashboard">Sign in</a>
+<a href="https://app.example.com/help">Help</a>`,
+			want: "https://app.example.com/auth/verify?token=3f9a1c7e5b2d4f6a8c0e&redirect=https://app.example.com/dashboard",
+		},
+		{
+			name: "tracking redirect",
+			body: `<a href="https://click.mailer.net/c/8f2?url=https%3A%2F%2Fapp.example.com%2Fauth%2Fverify%3Ftoken%3D3f9a1c7e5b2d4f6a8c0e">Sign in</a>`,
+			want: "https://app.example.com/auth/verify?token=3f9a1c7e5b2d4f6a8c0e",
+		},
+		{
+			name: "app on a help domain",
+			body: "Sign in: https://login.helpdesk.io/magic?token=3f9a1c7e5b2d4f6a8c0e",
+			want: "https://login.helpdesk.io/magic?token=3f9a1c7e5b2d4f6a8c0e",
+		},
+		{
+			name: "app on a support subdomain",
+			body: `<a href="https://support.example.com/auth/verify?token=3f9a1c7e5b2d4f6a8c0e">Log in</a>
+<a href="https://support.example.com/unsubscribe?id=42">Unsubscribe</a>`,
+			want: "https://support.example.com/auth/verify?token=3f9a1c7e5b2d4f6a8c0e",
+		},
+		{
+			name: "footer links excluded by path",
+			body: `<a href="https://app.example.com/login/callback?code=91b2">Continue</a>
+<a href="https://app.example.com/support/login-help">Trouble signing in?</a>`,
+			want: "https://app.example.com/login/callback?code=91b2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractAuthLink(tt.body, DefaultLinkMatchRules())
+			if err != nil {
+				t.Fatalf("ExtractAuthLink failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractAuthLink = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractAuthLinkOnlyExcluded(t *testing.T) {
+	body := `<a href="https://app.example.com/help/articles">Help center</a>
+<a href="https://app.example.com/unsubscribe?id=42">Unsubscribe</a>`
+	if link, err := ExtractAuthLink(body, DefaultLinkMatchRules()); err == nil {
+		t.Errorf("ExtractAuthLink = %q, want an error", link)
+	}
+}
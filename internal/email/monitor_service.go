@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/lance13c/tod/internal/browser"
@@ -11,9 +13,21 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// Email providers, set with email.provider
+const (
+	ProviderIMAP = "imap" // poll a real inbox over IMAP (default)
+	ProviderSMTP = "smtp" // poll the mailbox of an SMTP capture service
+)
+
+// magicLinkMonitor polls a mailbox for magic links in the background
+type magicLinkMonitor interface {
+	StartMonitoringBackground(onMagicLink func(url string) error) (chan struct{}, error)
+	GetUsername() string
+}
+
 // MonitorService manages background email monitoring
 type MonitorService struct {
-	monitor      *IMAPMonitor
+	monitor      magicLinkMonitor
 	stopChan     chan struct{}
 	wsURL        string
 	mu           sync.Mutex
@@ -68,16 +82,14 @@ func (m *MonitorService) StartBackgroundMonitoring() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 	
-	// Load IMAP config from file
-	imapConfig := LoadIMAPConfigFromFile(configData)
-	
-	// Validate configuration
-	if imapConfig.Username == "" || imapConfig.Password == "" {
-		return fmt.Errorf("Email (IMAP) credentials not configured in .tod/config.yaml")
+	monitor, err := newMagicLinkMonitor(configData)
+	if err != nil {
+		return err
 	}
 	
 	// Try to connect to Chrome
-	wsURL, err := browser.GetChromeWebSocketURL("localhost", "9222")
+	debugPort := strconv.Itoa(browser.RunningDebugPort(configuredDebugPort(configData)))
+	wsURL, err := browser.GetChromeWebSocketURL("localhost", debugPort)
 	if err != nil {
 		logging.Warn("Chrome DevTools not available. Magic links will be logged but not navigated: %v", err)
 		m.wsURL = ""
@@ -86,12 +98,6 @@ func (m *MonitorService) StartBackgroundMonitoring() error {
 		logging.Info("Connected to Chrome DevTools")
 	}
 	
-	// Create IMAP monitor
-	monitor, err := NewIMAPMonitor(imapConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create email monitor: %w", err)
-	}
-	
 	// Start monitoring in background
 	stopChan, err := monitor.StartMonitoringBackground(func(magicLink string) error {
 		logging.Info("[MONITOR SERVICE] Magic link detected: %s", magicLink)
@@ -116,7 +122,7 @@ func (m *MonitorService) StartBackgroundMonitoring() error {
 			if err := browser.NavigateToURLDirect(m.wsURL, magicLink); err != nil {
 				logging.Error("[MONITOR SERVICE] Failed to navigate Chrome: %v", err)
 				// Try to reconnect to Chrome
-				if newWSURL, err := browser.GetChromeWebSocketURL("localhost", "9222"); err == nil {
+				if newWSURL, err := browser.GetChromeWebSocketURL("localhost", debugPort); err == nil {
 					m.wsURL = newWSURL
 					// Retry navigation
 					if err := browser.NavigateToURLDirect(m.wsURL, magicLink); err == nil {
@@ -139,10 +145,60 @@ func (m *MonitorService) StartBackgroundMonitoring() error {
 	m.stopChan = stopChan
 	m.isRunning = true
 	
-	logging.Info("[MONITOR SERVICE] Email monitoring started in background (user: %s)", imapConfig.Username)
+	logging.Info("[MONITOR SERVICE] Email monitoring started in background (user: %s)", monitor.GetUsername())
 	return nil
 }
 
+// monitorProvider returns the configured email.provider, defaulting to IMAP
+func monitorProvider(configData map[string]interface{}) string {
+	if emailConfig, ok := configData["email"].(map[string]interface{}); ok {
+		if provider, ok := emailConfig["provider"].(string); ok && provider != "" {
+			return strings.ToLower(provider)
+		}
+	}
+	return ProviderIMAP
+}
+
+// configuredDebugPort returns browser.debug_port, 0 if it's not set
+func configuredDebugPort(configData map[string]interface{}) int {
+	if browserConfig, ok := configData["browser"].(map[string]interface{}); ok {
+		if port, ok := browserConfig["debug_port"].(int); ok {
+			return port
+		}
+	}
+	return 0
+}
+
+// newMagicLinkMonitor creates the monitor for the configured email.provider
+func newMagicLinkMonitor(configData map[string]interface{}) (magicLinkMonitor, error) {
+	switch provider := monitorProvider(configData); provider {
+	case ProviderIMAP:
+		imapConfig := LoadIMAPConfigFromFile(configData)
+		if imapConfig.Username == "" || imapConfig.Password == "" {
+			return nil, fmt.Errorf("Email (IMAP) credentials not configured in .tod/config.yaml")
+		}
+		monitor, err := NewIMAPMonitor(imapConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email monitor: %w", err)
+		}
+		return monitor, nil
+		
+	case ProviderSMTP:
+		smtpConfig := LoadSMTPConfigFromFile(configData)
+		if smtpConfig.Username == "" || smtpConfig.Password == "" {
+			return nil, fmt.Errorf("Email (SMTP) credentials not configured in .tod/config.yaml")
+		}
+		monitor, err := NewSMTPMonitor(smtpConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email monitor: %w", err)
+		}
+		return monitor, nil
+		
+	default:
+		return nil, fmt.Errorf("unknown email.provider %q (expected %q or %q)", provider, ProviderIMAP, ProviderSMTP)
+	}
+}
+
 // StopMonitoring stops the background email monitoring
 func (m *MonitorService) StopMonitoring() {
 	m.mu.Lock()
@@ -200,7 +256,7 @@ func AutoStartMonitoring(projectDir string) {
 	if emailConfig, ok := configData["email"].(map[string]interface{}); ok {
 		// Check for IMAP config first, then fall back to SMTP names
 		hasConfig := false
-		if user, ok := emailConfig["imap_user"].(string); ok && user != "" {
+		if user, ok := emailConfig["imap_user"].(string); ok && user != "" && monitorProvider(configData) == ProviderIMAP {
 			hasConfig = true
 		} else if user, ok := emailConfig["smtp_user"].(string); ok && user != "" {
 			hasConfig = true
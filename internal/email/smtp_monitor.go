@@ -4,7 +4,6 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"strings"
 	"time"
@@ -12,6 +11,7 @@ import (
 	"github.com/emersion/go-imap"
 	"github.com/emersion/go-imap/client"
 	"github.com/emersion/go-message/mail"
+	"github.com/lance13c/tod/internal/logging"
 )
 
 // SMTPMonitor monitors IMAP server for incoming emails with magic links
@@ -194,7 +194,7 @@ func (m *SMTPMonitor) StartMonitoring(onMagicLink func(url string) error) error
 		}
 	}
 	
-	log.Println("Starting email monitoring for magic links...")
+	logging.Info("Starting email monitoring for magic links...")
 	
 	ticker := time.NewTicker(m.pollInterval)
 	defer ticker.Stop()
@@ -203,10 +203,10 @@ func (m *SMTPMonitor) StartMonitoring(onMagicLink func(url string) error) error
 		select {
 		case <-ticker.C:
 			if err := m.checkNewEmails(); err != nil {
-				log.Printf("Error checking emails: %v", err)
+				logging.Warn("Error checking emails: %v", err)
 				// Try to reconnect
 				if err := m.Connect(); err != nil {
-					log.Printf("Failed to reconnect: %v", err)
+					logging.Warn("Failed to reconnect: %v", err)
 				}
 			}
 		}
@@ -223,7 +223,7 @@ func (m *SMTPMonitor) StartMonitoringBackground(onMagicLink func(url string) err
 		}
 	}
 	
-	log.Println("Starting background email monitoring for magic links...")
+	logging.Info("Starting background email monitoring for magic links...")
 	
 	stopChan := make(chan struct{})
 	
@@ -235,14 +235,14 @@ func (m *SMTPMonitor) StartMonitoringBackground(onMagicLink func(url string) err
 		for {
 			select {
 			case <-stopChan:
-				log.Println("Stopping email monitoring...")
+				logging.Info("Stopping email monitoring...")
 				return
 			case <-ticker.C:
 				if err := m.checkNewEmails(); err != nil {
-					log.Printf("Error checking emails: %v", err)
+					logging.Warn("Error checking emails: %v", err)
 					// Try to reconnect
 					if err := m.Connect(); err != nil {
-						log.Printf("Failed to reconnect: %v", err)
+						logging.Warn("Failed to reconnect: %v", err)
 					}
 				}
 			}
@@ -299,7 +299,7 @@ func (m *SMTPMonitor) checkNewEmails() error {
 		// Parse the message
 		mr, err := mail.CreateReader(body)
 		if err != nil {
-			log.Printf("Failed to parse message: %v", err)
+			logging.Warn("Failed to parse message: %v", err)
 			continue
 		}
 		
@@ -311,7 +311,7 @@ func (m *SMTPMonitor) checkNewEmails() error {
 				break
 			}
 			if err != nil {
-				log.Printf("Failed to read part: %v", err)
+				logging.Warn("Failed to read part: %v", err)
 				continue
 			}
 			
@@ -328,10 +328,10 @@ func (m *SMTPMonitor) checkNewEmails() error {
 		
 		// Extract magic link from email content
 		content := emailContent.String()
-		// Note: Using extractMagicLinkFromContent from imap_monitor.go
+		// Note: IMAPMonitor picks links with ExtractAuthLink
 		// This file is kept for backward compatibility only
 		// TODO: Remove this file and update all references to use IMAPMonitor
-		log.Printf("[SMTP_MONITOR] Found content, processing for magic links...")
+		logging.Debug("Found content, processing for magic links...")
 		// For now, let's just log the content length
 		if len(content) > 0 && m.onMagicLink != nil {
 			// Simple check for any https URL
@@ -344,9 +344,9 @@ func (m *SMTPMonitor) checkNewEmails() error {
 				}
 				if end > start {
 					link := content[start:end]
-					log.Printf("Found potential magic link: %s", link)
+					logging.Info("Found potential magic link: %s", link)
 					if err := m.onMagicLink(link); err != nil {
-						log.Printf("Error handling magic link: %v", err)
+						logging.Warn("Error handling magic link: %v", err)
 					}
 				}
 			}
@@ -1,18 +1,28 @@
 package llm
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 
 	"github.com/lance13c/tod/internal/types"
 )
 
+// anthropicBaseURL is the Anthropic API, used for health checks
+const anthropicBaseURL = "https://api.anthropic.com/v1"
+
 // anthropicClientSimple is a simplified implementation that delegates to mock
 type anthropicClientSimple struct {
-	apiKey   string
-	model    string
-	mock     *mockClient
-	costCalc *CostCalculator
+	apiKey    string
+	model     string
+	baseURL   string
+	mock      *mockClient
+	costCalc  *CostCalculator
+	lastUsage *UsageStats // of the last StreamAnswer
 }
 
 // newAnthropicClient creates a new simplified Anthropic client
@@ -26,21 +36,130 @@ func newAnthropicClient(apiKey string, options map[string]interface{}) (Client,
 		model = m
 	}
 
+	baseURL := anthropicBaseURL
+	if url, ok := options["base_url"].(string); ok && url != "" {
+		baseURL = url
+	}
+
 	mockClient := &mockClient{}
 
 	return &anthropicClientSimple{
 		apiKey:   apiKey,
 		model:    model,
+		baseURL:  baseURL,
 		mock:     mockClient,
 		costCalc: NewCostCalculator(),
 	}, nil
 }
 
+// Ping looks up the configured model, which needs a valid API key
+func (c *anthropicClientSimple) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models/"+c.model, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	return ping(pingHTTPClient, req, "Anthropic", c.model)
+}
+
+// ListModels lists the models the API key can use. Anthropic doesn't report
+// context windows or prices, so only the built-in prices are filled in.
+func (c *anthropicClientSimple) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models?limit=1000", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	var response struct {
+		Data []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := fetchModelList(pingHTTPClient, req, "Anthropic", &response); err != nil {
+		return nil, err
+	}
+
+	models := make([]ModelInfo, 0, len(response.Data))
+	for _, model := range response.Data {
+		models = append(models, ModelInfo{ID: model.ID, Name: model.DisplayName})
+	}
+	return withKnownPricing(models, "anthropic", c.costCalc), nil
+}
+
 // AnalyzeCode delegates to mock implementation
 func (c *anthropicClientSimple) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
 	return c.mock.AnalyzeCode(ctx, code, filePath)
 }
 
+// StreamAnalyzeCode implements the Client interface without streaming
+func (c *anthropicClientSimple) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	return streamAnalysisFallback(ctx, c, code, filePath)
+}
+
+// StreamAnswer asks the Messages API and sends the whole answer as one chunk
+func (c *anthropicClientSimple) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"model":      c.model,
+		"max_tokens": answerMaxTokens,
+		"system":     answerSystemPrompt,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := answerHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Anthropic API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int64 `json:"input_tokens"`
+			OutputTokens int64 `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var answer strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			answer.WriteString(block.Text)
+		}
+	}
+	c.lastUsage = c.costCalc.CalculateCost("anthropic", c.model, response.Usage.InputTokens, response.Usage.OutputTokens)
+	return streamText(ctx, answer.String()), nil
+}
+
 // GenerateFlow delegates to mock implementation
 func (c *anthropicClientSimple) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
 	return c.mock.GenerateFlow(ctx, actions)
@@ -87,6 +206,9 @@ func (c *anthropicClientSimple) InterpretCommand(ctx context.Context, command st
 
 // GetLastUsage implements the Client interface
 func (c *anthropicClientSimple) GetLastUsage() *UsageStats {
+	if c.lastUsage != nil {
+		return c.lastUsage
+	}
 	return c.mock.GetLastUsage()
 }
 
@@ -112,6 +234,16 @@ func (c *anthropicClientSimple) InterpretCommandWithContext(ctx context.Context,
 	return interpretation, nil
 }
 
+// InterpretCommands implements the Client interface using the mock client
+func (c *anthropicClientSimple) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	return c.mock.InterpretCommands(ctx, commands, availableActions, conversation)
+}
+
+// EstimateTokenCost implements the Client interface
+func (c *anthropicClientSimple) EstimateTokenCost(promptTokens, completionTokens int) float64 {
+	return c.costCalc.TokenCost("anthropic", c.model, promptTokens, completionTokens)
+}
+
 // EstimateCost implements the Client interface
 func (c *anthropicClientSimple) EstimateCost(operation string, inputSize int) *UsageStats {
 	// Get token estimates from mock (for token calculation logic)
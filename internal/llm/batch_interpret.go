@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lance13c/tod/internal/types"
+)
+
+// Command types a batch interpretation sorts commands into
+const (
+	CommandNavigate = "navigate"
+	CommandClick    = "click"
+	CommandFill     = "fill"
+	CommandSelect   = "select"
+	CommandOther    = "command" // one of Tod's own commands, like "go back"
+	CommandUnknown  = "unknown"
+)
+
+// batchInterpretPrompt asks for a list of commands to be interpreted in one
+// response, against the elements of the page they start on
+const batchInterpretPrompt = `You are interpreting commands a tester typed to drive a web app in a browser. Interpret each numbered command on its own, in order.
+
+Elements on the current page:
+%s
+
+Commands:
+%s
+
+Respond with ONLY a JSON array holding one object per command, in the same order:
+[
+  {
+    "index": 1,
+    "intent": "what the command is asking for",
+    "command_type": "navigate|click|fill|select|command|unknown",
+    "action_id": "ID of the page element it acts on, or empty",
+    "parameters": {"target": "text of the element or page", "url": "URL or path to open", "field": "label of the field", "value": "value to fill or option to select"},
+    "confidence": 0.0,
+    "suggestions": ["clearer phrasings, if the command is ambiguous"]
+  }
+]
+
+Use "command" for requests about the browser or the tool itself, such as going back or taking a screenshot. Leave out parameters that don't apply.`
+
+// batchInterpretPromptFor builds the batch prompt for commands
+func batchInterpretPromptFor(commands []string, availableActions []types.CodeAction) string {
+	var numbered strings.Builder
+	for i, command := range commands {
+		fmt.Fprintf(&numbered, "%d. %s\n", i+1, command)
+	}
+	return fmt.Sprintf(batchInterpretPrompt, formatActionsForLLM(availableActions), numbered.String())
+}
+
+// batchInterpretMaxTokens is the response budget for interpreting n commands
+func batchInterpretMaxTokens(n int) int {
+	return 200 + 150*n
+}
+
+// parseBatchInterpretations reads the model's answer to the batch prompt
+// into one interpretation per command, nil for commands it left out. usage
+// is the cost of the whole batch and is attached to the first
+// interpretation only, so adding up every interpretation's usage is right.
+func parseBatchInterpretations(content string, count int, usage *UsageStats) ([]*CommandInterpretation, error) {
+	start, end := strings.Index(content, "["), strings.LastIndex(content, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in batch interpretation response")
+	}
+
+	var parsed []struct {
+		Index int `json:"index"`
+		CommandInterpretation
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse batch interpretation response: %w", err)
+	}
+
+	interpretations := make([]*CommandInterpretation, count)
+	for i, entry := range parsed {
+		position := entry.Index - 1
+		if position < 0 || position >= count || interpretations[position] != nil {
+			position = i
+		}
+		if position >= count {
+			continue
+		}
+		interpretation := entry.CommandInterpretation
+		if interpretation.Parameters == nil {
+			interpretation.Parameters = make(map[string]string)
+		}
+		interpretations[position] = &interpretation
+	}
+
+	for _, interpretation := range interpretations {
+		if interpretation != nil {
+			interpretation.Usage = usage
+			break
+		}
+	}
+	return interpretations, nil
+}
@@ -23,15 +23,35 @@ const (
 // Client interface for LLM operations
 type Client interface {
 	AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error)
+	// StreamAnalyzeCode is AnalyzeCode returning the response text as it is
+	// generated. The channel is closed when the response ends or ctx is cancelled.
+	StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error)
+	// StreamAnswer sends prompt as a plain question, without the analysis
+	// instructions, and returns the answer text as it is generated. The
+	// channel is closed when the answer ends or ctx is cancelled.
+	StreamAnswer(ctx context.Context, prompt string) (<-chan string, error)
 	GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error)
 	ExtractActions(ctx context.Context, code, framework, language string) ([]types.CodeAction, error)
 	ResearchFramework(ctx context.Context, frameworkName, version string) (*FrameworkResearch, error)
 	InterpretCommand(ctx context.Context, command string, availableActions []types.CodeAction) (*CommandInterpretation, error)
 	InterpretCommandWithContext(ctx context.Context, command string, availableActions []types.CodeAction, conversation *ConversationContext) (*CommandInterpretation, error)
+	InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error)
 	AnalyzeScreenshot(ctx context.Context, screenshot []byte, prompt string) (*ScreenshotAnalysis, error)
 	RankNavigationElements(ctx context.Context, userInput string, elements []NavigationElement) (*NavigationRanking, error)
 	GetLastUsage() *UsageStats
 	EstimateCost(operation string, inputSize int) *UsageStats
+
+	// EstimateTokenCost prices a request with the given token counts using
+	// the provider's pricing for the configured model
+	EstimateTokenCost(promptTokens, completionTokens int) float64
+
+	// Ping makes a minimal, free call checking the API key is accepted and
+	// the configured model is available
+	Ping(ctx context.Context) error
+
+	// ListModels returns the models the provider offers, with context window
+	// and pricing where the provider or the built-in pricing knows them
+	ListModels(ctx context.Context) ([]ModelInfo, error)
 }
 
 // CodeAnalysis represents the result of LLM code analysis
@@ -176,22 +196,62 @@ type Bounds struct {
 
 // NewClient creates a new LLM client based on provider
 func NewClient(provider Provider, apiKey string, options map[string]interface{}) (Client, error) {
+	// Catch misconfigured model names before the first API call
+	if allow, _ := options["allow_unknown_models"].(bool); !allow {
+		model, _ := options["model"].(string)
+		if err := ValidateModel(provider, model); err != nil {
+			return nil, err
+		}
+	}
+
+	var client Client
+	var err error
 	switch provider {
 	case OpenAI:
-		return newOpenAIClient(apiKey, options)
+		client, err = newOpenAIClient(apiKey, options)
 	case Anthropic:
-		return newAnthropicClient(apiKey, options)
+		client, err = newAnthropicClient(apiKey, options)
 	case OpenRouter:
-		return newOpenRouterClient(apiKey, options)
+		client, err = newOpenRouterClient(apiKey, options)
 	case Google:
-		return newGoogleClient(apiKey, options)
+		client, err = newGoogleClient(apiKey, options)
 	case Local:
-		return newLocalClient(options)
+		client, err = newLocalClient(options)
 	case Mock:
-		return newMockClient(options)
+		client, err = newMockClient(options)
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", provider)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Smooth bursts so we don't trip provider rate limits
+	if rpm, ok := options["requests_per_minute"].(int); ok && rpm > 0 {
+		client = NewRateLimitedClient(client, provider, apiKey, rpm)
+	}
+
+	// Retry transient failures; wrapped last so each retry also waits for
+	// the rate limiter
+	if retries, ok := options["max_retries"].(int); ok && retries > 0 {
+		client = NewRetryingClient(client, retries, DefaultRetryBaseDelay)
+	}
+
+	return client, nil
+}
+
+// interpretCommandsSequentially interprets each command with its own call for
+// clients that cannot batch. Results are returned in the same order as commands.
+func interpretCommandsSequentially(ctx context.Context, client Client, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	interpretations := make([]*CommandInterpretation, len(commands))
+	for i, command := range commands {
+		interpretation, err := client.InterpretCommandWithContext(ctx, command, availableActions, conversation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to interpret command %d (%q): %w", i+1, command, err)
+		}
+		interpretations[i] = interpretation
+	}
+	return interpretations, nil
 }
 
 // AnalyzeCodeWithLLM is a convenience function for code analysis
@@ -104,6 +104,55 @@ func (c *CostCalculator) loadModelPricing() {
 		LastUpdated: now,
 	}
 
+	c.pricing["anthropic/claude-3-5-haiku"] = ModelPricing{
+		Provider:    "anthropic",
+		Model:       "claude-3-5-haiku",
+		InputCost:   0.80, // $0.80 per 1M input tokens
+		OutputCost:  4.00, // $4 per 1M output tokens
+		LastUpdated: now,
+	}
+
+	c.pricing["anthropic/claude-sonnet-4"] = ModelPricing{
+		Provider:    "anthropic",
+		Model:       "claude-sonnet-4",
+		InputCost:   3.00,  // $3 per 1M input tokens
+		OutputCost:  15.00, // $15 per 1M output tokens
+		LastUpdated: now,
+	}
+
+	// Google Gemini Models (as of 2025, prompts up to 200K tokens)
+	c.pricing["google/gemini-2.5-pro"] = ModelPricing{
+		Provider:    "google",
+		Model:       "gemini-2.5-pro",
+		InputCost:   1.25,  // $1.25 per 1M input tokens
+		OutputCost:  10.00, // $10 per 1M output tokens
+		LastUpdated: now,
+	}
+
+	c.pricing["google/gemini-2.5-flash"] = ModelPricing{
+		Provider:    "google",
+		Model:       "gemini-2.5-flash",
+		InputCost:   0.30, // $0.30 per 1M input tokens
+		OutputCost:  2.50, // $2.50 per 1M output tokens
+		LastUpdated: now,
+	}
+
+	c.pricing["google/gemini-1.5-pro"] = ModelPricing{
+		Provider:    "google",
+		Model:       "gemini-1.5-pro",
+		InputCost:   1.25, // $1.25 per 1M input tokens
+		OutputCost:  5.00, // $5 per 1M output tokens
+		LastUpdated: now,
+	}
+
+	c.pricing["google/gemini-1.5-flash"] = ModelPricing{
+		Provider:    "google",
+		Model:       "gemini-1.5-flash",
+		InputCost:   0.075, // $0.075 per 1M input tokens
+		OutputCost:  0.30,  // $0.30 per 1M output tokens
+		LastUpdated: now,
+	}
+
 	// OpenRouter models use their own routing
 	// Default to GPT-4o pricing for unknown OpenRouter models
 	c.pricing["openrouter/default"] = ModelPricing{
@@ -142,11 +191,32 @@ func (c *CostCalculator) EstimateCost(provider, model string, estimatedInputToke
 	return c.CalculateCost(provider, model, estimatedInputTokens, estimatedOutputTokens)
 }
 
-// getPricingForModel gets pricing for a specific model
-func (c *CostCalculator) getPricingForModel(provider, model string) ModelPricing {
+// knownPricing returns the pricing table entry for a model, without falling
+// back to an estimate for models that aren't in it
+func (c *CostCalculator) knownPricing(provider, model string) (ModelPricing, bool) {
 	// Try exact match first
 	key := fmt.Sprintf("%s/%s", provider, model)
 	if pricing, exists := c.pricing[key]; exists {
+		return pricing, true
+	}
+
+	// Then the longest known model the name starts with, so dated versions
+	// like "gpt-4o-mini-2024-07-18" get their family's price, not gpt-4o's
+	bestKey := ""
+	for k := range c.pricing {
+		if strings.HasPrefix(key, k) && len(k) > len(bestKey) {
+			bestKey = k
+		}
+	}
+	if bestKey != "" {
+		return c.pricing[bestKey], true
+	}
+	return ModelPricing{}, false
+}
+
+// getPricingForModel gets pricing for a specific model
+func (c *CostCalculator) getPricingForModel(provider, model string) ModelPricing {
+	if pricing, ok := c.knownPricing(provider, model); ok {
 		return pricing
 	}
 
@@ -162,6 +232,11 @@ func (c *CostCalculator) getPricingForModel(provider, model string) ModelPricing
 		if pricing, exists := c.pricing["anthropic/claude-3-5-sonnet"]; exists {
 			return pricing
 		}
+	case "google":
+		// Default to the priciest Gemini for unknown Google models
+		if pricing, exists := c.pricing["google/gemini-2.5-pro"]; exists {
+			return pricing
+		}
 	case "openrouter":
 		// Check if it's a known model through OpenRouter
 		modelKey := strings.ToLower(model)
@@ -185,6 +260,12 @@ func (c *CostCalculator) getPricingForModel(provider, model string) ModelPricing
 	}
 }
 
+// TokenCost returns the cost in USD of a request with the given token counts.
+// Unknown models are priced conservatively rather than as free.
+func (c *CostCalculator) TokenCost(provider, model string, promptTokens, completionTokens int) float64 {
+	return c.CalculateCost(provider, model, int64(promptTokens), int64(completionTokens)).TotalCost
+}
+
 // GetModelPricing returns pricing information for a model
 func (c *CostCalculator) GetModelPricing(provider, model string) ModelPricing {
 	return c.getPricingForModel(provider, model)
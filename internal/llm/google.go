@@ -1,18 +1,30 @@
 package llm
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/lance13c/tod/internal/types"
 )
 
+// googleBaseURL is the Gemini API, used for health checks
+const googleBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
 // googleClientSimple is a simplified implementation that delegates to mock
 type googleClientSimple struct {
-	apiKey   string
-	model    string
-	mock     *mockClient
-	costCalc *CostCalculator
+	apiKey    string
+	model     string
+	baseURL   string
+	mock      *mockClient
+	costCalc  *CostCalculator
+	lastUsage *UsageStats // of the last StreamAnswer
 }
 
 // newGoogleClient creates a new simplified Google client
@@ -26,21 +38,141 @@ func newGoogleClient(apiKey string, options map[string]interface{}) (Client, err
 		model = m
 	}
 
+	baseURL := googleBaseURL
+	if u, ok := options["base_url"].(string); ok && u != "" {
+		baseURL = u
+	}
+
 	mockClient := &mockClient{}
 
 	return &googleClientSimple{
 		apiKey:   apiKey,
 		model:    model,
+		baseURL:  baseURL,
 		mock:     mockClient,
 		costCalc: NewCostCalculator(),
 	}, nil
 }
 
+// Ping looks up the configured model, which needs a valid API key
+func (c *googleClientSimple) Ping(ctx context.Context) error {
+	endpoint := c.baseURL + "/models/" + url.PathEscape(c.model) + "?key=" + url.QueryEscape(c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	return ping(pingHTTPClient, req, "Google AI", c.model)
+}
+
+// ListModels lists the Gemini models that can generate content, with their
+// input token limits. Google doesn't report prices, so only the built-in
+// prices are filled in.
+func (c *googleClientSimple) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	endpoint := c.baseURL + "/models?pageSize=1000&key=" + url.QueryEscape(c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var response struct {
+		Models []struct {
+			Name                       string   `json:"name"`
+			DisplayName                string   `json:"displayName"`
+			InputTokenLimit            int      `json:"inputTokenLimit"`
+			SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := fetchModelList(pingHTTPClient, req, "Google AI", &response); err != nil {
+		return nil, err
+	}
+
+	var models []ModelInfo
+	for _, model := range response.Models {
+		if !slices.Contains(model.SupportedGenerationMethods, "generateContent") {
+			continue
+		}
+		models = append(models, ModelInfo{
+			ID:            strings.TrimPrefix(model.Name, "models/"),
+			Name:          model.DisplayName,
+			ContextWindow: model.InputTokenLimit,
+		})
+	}
+	return withKnownPricing(models, "google", c.costCalc), nil
+}
+
 // AnalyzeCode delegates to mock implementation
 func (c *googleClientSimple) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
 	return c.mock.AnalyzeCode(ctx, code, filePath)
 }
 
+// StreamAnalyzeCode implements the Client interface without streaming
+func (c *googleClientSimple) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	return streamAnalysisFallback(ctx, c, code, filePath)
+}
+
+// StreamAnswer asks generateContent and sends the whole answer as one chunk
+func (c *googleClientSimple) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Role  string `json:"role,omitempty"`
+		Parts []part `json:"parts"`
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"systemInstruction": content{Parts: []part{{Text: answerSystemPrompt}}},
+		"contents":          []content{{Role: "user", Parts: []part{{Text: prompt}}}},
+		"generationConfig":  map[string]int{"maxOutputTokens": answerMaxTokens},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/models/" + url.PathEscape(c.model) + ":generateContent?key=" + url.QueryEscape(c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := answerHTTPClient.Do(req)
+	if err != nil {
+		// The URL holds the API key, so don't let it into the error
+		return nil, fmt.Errorf("failed to make request to Google AI")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Google AI request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Candidates []struct {
+			Content content `json:"content"`
+		} `json:"candidates"`
+		UsageMetadata struct {
+			PromptTokenCount     int64 `json:"promptTokenCount"`
+			CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+		} `json:"usageMetadata"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var answer strings.Builder
+	if len(response.Candidates) > 0 {
+		for _, p := range response.Candidates[0].Content.Parts {
+			answer.WriteString(p.Text)
+		}
+	}
+	c.lastUsage = c.costCalc.CalculateCost("google", c.model, response.UsageMetadata.PromptTokenCount, response.UsageMetadata.CandidatesTokenCount)
+	return streamText(ctx, answer.String()), nil
+}
+
 // GenerateFlow delegates to mock implementation
 func (c *googleClientSimple) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
 	return c.mock.GenerateFlow(ctx, actions)
@@ -92,6 +224,9 @@ func (c *googleClientSimple) AnalyzeScreenshot(ctx context.Context, screenshot [
 
 // GetLastUsage implements the Client interface
 func (c *googleClientSimple) GetLastUsage() *UsageStats {
+	if c.lastUsage != nil {
+		return c.lastUsage
+	}
 	return c.mock.GetLastUsage()
 }
 
@@ -101,6 +236,16 @@ func (c *googleClientSimple) InterpretCommandWithContext(ctx context.Context, co
 	return c.InterpretCommand(ctx, command, availableActions)
 }
 
+// InterpretCommands implements the Client interface
+func (c *googleClientSimple) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	return c.mock.InterpretCommands(ctx, commands, availableActions, conversation)
+}
+
+// EstimateTokenCost implements the Client interface
+func (c *googleClientSimple) EstimateTokenCost(promptTokens, completionTokens int) float64 {
+	return c.costCalc.TokenCost("google", c.model, promptTokens, completionTokens)
+}
+
 // EstimateCost implements the Client interface
 func (c *googleClientSimple) EstimateCost(operation string, inputSize int) *UsageStats {
 	// Get token estimates from mock (for token calculation logic)
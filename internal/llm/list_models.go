@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ModelInfo describes a model a provider offers. Fields the provider doesn't
+// report, and that aren't in the built-in pricing, are left at zero.
+type ModelInfo struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name,omitempty"`
+	ContextWindow int     `json:"context_window,omitempty"` // tokens
+	InputCost     float64 `json:"input_cost,omitempty"`     // USD per 1M input tokens
+	OutputCost    float64 `json:"output_cost,omitempty"`    // USD per 1M output tokens
+}
+
+// fetchModelList sends a model listing request and decodes the JSON response
+// into out
+func fetchModelList(client *http.Client, req *http.Request, provider string, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s is unreachable: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden ||
+			resp.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(string(body)), "api key") {
+			return fmt.Errorf("%s rejected the API key (status %d), check ai.api_key", provider, resp.StatusCode)
+		}
+		return fmt.Errorf("failed to list %s models, status %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s model list: %w", provider, err)
+	}
+	return nil
+}
+
+// withKnownPricing fills in prices from the built-in pricing table for models
+// the provider didn't price, and sorts the list by ID
+func withKnownPricing(models []ModelInfo, provider string, costCalc *CostCalculator) []ModelInfo {
+	if costCalc == nil {
+		costCalc = NewCostCalculator()
+	}
+	for i, model := range models {
+		if model.InputCost != 0 || model.OutputCost != 0 {
+			continue
+		}
+		if pricing, ok := costCalc.knownPricing(provider, model.ID); ok {
+			models[i].InputCost = pricing.InputCost
+			models[i].OutputCost = pricing.OutputCost
+		}
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models
+}
+
+// perTokenToPerMillion converts a per-token price in USD, as OpenRouter
+// reports it, to the per-1M-token price used elsewhere
+func perTokenToPerMillion(price string) float64 {
+	value, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return 0
+	}
+	return value * 1_000_000
+}
@@ -3,6 +3,7 @@ package llm
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/lance13c/tod/internal/types"
@@ -21,6 +22,34 @@ func newLocalClient(options map[string]interface{}) (Client, error) {
 	}, nil
 }
 
+// Ping checks a configured local model server responds. Without one the
+// analysis is pattern matching, which always works.
+func (c *localClient) Ping(ctx context.Context) error {
+	endpoint, _ := c.options["endpoint"].(string)
+	if endpoint == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("invalid local endpoint %q: %w", endpoint, err)
+	}
+	resp, err := pingHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("local model server at %s is unreachable: %w", endpoint, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("local model server at %s returned status %d", endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+// ListModels isn't supported; local analysis doesn't use a model
+func (c *localClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return nil, fmt.Errorf("the local provider has no models to list")
+}
+
 // AnalyzeCode performs local code analysis without LLM
 func (c *localClient) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
 	// Perform pattern-based analysis without external LLM
@@ -45,6 +74,16 @@ func (c *localClient) AnalyzeCode(ctx context.Context, code, filePath string) (*
 	return analysis, nil
 }
 
+// StreamAnalyzeCode implements the Client interface without streaming
+func (c *localClient) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	return streamAnalysisFallback(ctx, c, code, filePath)
+}
+
+// StreamAnswer can't be done without a model
+func (c *localClient) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	return nil, fmt.Errorf("local analysis can't answer questions, configure an AI provider")
+}
+
 // GenerateFlow generates a basic flow using local logic
 func (c *localClient) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
 	if len(actions) == 0 {
@@ -627,6 +666,11 @@ func (c *localClient) InterpretCommandWithContext(ctx context.Context, command s
 	return c.InterpretCommand(ctx, command, availableActions)
 }
 
+// InterpretCommands implements the Client interface
+func (c *localClient) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	return interpretCommandsSequentially(ctx, c, commands, availableActions, conversation)
+}
+
 // RankNavigationElements implements the Client interface using local ranking
 func (c *localClient) RankNavigationElements(ctx context.Context, userInput string, elements []NavigationElement) (*NavigationRanking, error) {
 	// Simple local ranking based on text similarity
@@ -724,6 +768,11 @@ func (c *localClient) calculateSimpleScore(userInput, elementText, elementType s
 	return 0.0
 }
 
+// EstimateTokenCost implements the Client interface; local analysis is free
+func (c *localClient) EstimateTokenCost(promptTokens, completionTokens int) float64 {
+	return 0
+}
+
 // EstimateCost implements the Client interface
 func (c *localClient) EstimateCost(operation string, inputSize int) *UsageStats {
 	// Local analysis is free
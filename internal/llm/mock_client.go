@@ -23,6 +23,32 @@ func newMockClient(options map[string]interface{}) (Client, error) {
 	}, nil
 }
 
+// Ping always succeeds; the mock needs no credentials
+func (m *mockClient) Ping(ctx context.Context) error {
+	return nil
+}
+
+// ListModels returns the models Tod knows for each real provider, named as
+// OpenRouter names them, so the list is useful without an API key
+func (m *mockClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	var models []ModelInfo
+	for provider, names := range KnownModels {
+		for _, name := range names {
+			models = append(models, ModelInfo{ID: string(provider) + "/" + name})
+		}
+	}
+	costCalc := NewCostCalculator()
+	for i, model := range models {
+		provider, name, _ := strings.Cut(model.ID, "/")
+		if pricing, ok := costCalc.knownPricing(provider, name); ok {
+			models[i].InputCost = pricing.InputCost
+			models[i].OutputCost = pricing.OutputCost
+		}
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
+
 // AnalyzeCode implements the Client interface with mock responses
 func (m *mockClient) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
 	// Log the API call
@@ -121,6 +147,16 @@ Click the Get Started button | low`
 	return analysis, nil
 }
 
+// StreamAnalyzeCode implements the Client interface for mock without streaming
+func (m *mockClient) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	return streamAnalysisFallback(ctx, m, code, filePath)
+}
+
+// StreamAnswer implements the Client interface for mock with a fixed answer
+func (m *mockClient) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	return streamText(ctx, "This is a mock answer; configure an AI provider for real answers about the page."), nil
+}
+
 // GenerateFlow implements the Client interface with mock responses
 func (m *mockClient) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
 	flow := &FlowSuggestion{
@@ -348,6 +384,21 @@ func (m *mockClient) InterpretCommand(ctx context.Context, command string, avail
 		interpretation.Suggestions = []string{"try 'navigate to homepage'", "try 'sign in'", "try 'click button'"}
 	}
 
+	// Point at the available action sharing the most words with the command
+	if interpretation.ActionID == "" {
+		bestScore := 0.3
+		for _, action := range availableActions {
+			if score := calculateBasicSimilarity(command, strings.ToLower(action.Name)); score > bestScore {
+				bestScore = score
+				interpretation.ActionID = action.ID
+			}
+		}
+		if interpretation.ActionID != "" && interpretation.CommandType == "unknown" {
+			interpretation.CommandType = "interaction"
+			interpretation.Confidence = bestScore
+		}
+	}
+
 	return interpretation, nil
 }
 
@@ -405,6 +456,11 @@ func (m *mockClient) InterpretCommandWithContext(ctx context.Context, command st
 	return interpretation, nil
 }
 
+// InterpretCommands implements the Client interface by interpreting each command in order
+func (m *mockClient) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	return interpretCommandsSequentially(ctx, m, commands, availableActions, conversation)
+}
+
 // GetLastUsage implements the Client interface for mock
 func (m *mockClient) GetLastUsage() *UsageStats {
 	// Return mock usage stats
@@ -477,6 +533,11 @@ func (m *mockClient) AnalyzeScreenshot(ctx context.Context, screenshot []byte, p
 	return analysis, nil
 }
 
+// EstimateTokenCost implements the Client interface for mock; mock calls are free
+func (m *mockClient) EstimateTokenCost(promptTokens, completionTokens int) float64 {
+	return 0
+}
+
 // EstimateCost implements the Client interface for mock
 func (m *mockClient) EstimateCost(operation string, inputSize int) *UsageStats {
 	// Return mock cost estimates
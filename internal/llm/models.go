@@ -0,0 +1,44 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lance13c/tod/internal/config"
+)
+
+// validatedProviders are the providers whose models ValidateModel checks.
+// The rest (OpenRouter, local, mock) accept any model.
+var validatedProviders = []Provider{OpenAI, Anthropic, Google}
+
+// KnownModels lists the model names each validated provider is known to
+// accept, as listed in config.ModelRegistry
+var KnownModels = knownModels()
+
+func knownModels() map[Provider][]string {
+	known := make(map[Provider][]string, len(validatedProviders))
+	for _, provider := range validatedProviders {
+		for _, model := range config.FilterModelsByProvider(string(provider)) {
+			known[provider] = append(known[provider], model.ModelName)
+		}
+	}
+	return known
+}
+
+// ValidateModel checks that a model is known for the provider. An empty model
+// is always valid since each client falls back to its own default.
+func ValidateModel(provider Provider, model string) error {
+	known, ok := KnownModels[provider]
+	if !ok || model == "" {
+		return nil
+	}
+
+	for _, name := range known {
+		if name == model {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown %s model %q (valid models: %s); set ai.allow_unknown_models to use it anyway",
+		provider, model, strings.Join(known, ", "))
+}
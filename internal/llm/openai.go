@@ -2,7 +2,9 @@ package llm
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 
 	"github.com/lance13c/tod/internal/types"
@@ -24,6 +26,45 @@ func newOpenAIClient(apiKey string, options map[string]interface{}) (Client, err
 	return newRealOpenAIClient(apiKey, options)
 }
 
+// Ping looks up the configured model, which needs a valid API key
+func (c *openAIClientSimple) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.openai.com/v1/models/"+c.model, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	return ping(pingHTTPClient, req, "OpenAI", c.model)
+}
+
+// ListModels lists the models the API key can use
+func (c *openAIClientSimple) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return listOpenAIModels(ctx, pingHTTPClient, "https://api.openai.com/v1", c.apiKey, c.costCalc)
+}
+
+// listOpenAIModels lists models from an OpenAI-compatible models endpoint
+func listOpenAIModels(ctx context.Context, client *http.Client, baseURL, apiKey string, costCalc *CostCalculator) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	var response struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := fetchModelList(client, req, "OpenAI", &response); err != nil {
+		return nil, err
+	}
+
+	models := make([]ModelInfo, 0, len(response.Data))
+	for _, model := range response.Data {
+		models = append(models, ModelInfo{ID: model.ID})
+	}
+	return withKnownPricing(models, "openai", costCalc), nil
+}
+
 // AnalyzeCode delegates to mock implementation
 func (c *openAIClientSimple) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
 	// Log the API call
@@ -52,6 +93,20 @@ func (c *openAIClientSimple) AnalyzeCode(ctx context.Context, code, filePath str
 	return result, err
 }
 
+// StreamAnalyzeCode implements the Client interface without streaming
+func (c *openAIClientSimple) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	return streamAnalysisFallback(ctx, c, code, filePath)
+}
+
+// StreamAnswer asks the real OpenAI client, since answers can't be mocked
+func (c *openAIClientSimple) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	client, err := newRealOpenAIClient(c.apiKey, map[string]interface{}{"model": c.model})
+	if err != nil {
+		return nil, err
+	}
+	return client.StreamAnswer(ctx, prompt)
+}
+
 // GenerateFlow delegates to mock implementation
 func (c *openAIClientSimple) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
 	return c.mock.GenerateFlow(ctx, actions)
@@ -112,6 +167,16 @@ func (c *openAIClientSimple) InterpretCommandWithContext(ctx context.Context, co
 	return c.InterpretCommand(ctx, command, availableActions)
 }
 
+// InterpretCommands implements the Client interface
+func (c *openAIClientSimple) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	return c.mock.InterpretCommands(ctx, commands, availableActions, conversation)
+}
+
+// EstimateTokenCost implements the Client interface
+func (c *openAIClientSimple) EstimateTokenCost(promptTokens, completionTokens int) float64 {
+	return c.costCalc.TokenCost("openai", c.model, promptTokens, completionTokens)
+}
+
 // EstimateCost implements the Client interface
 func (c *openAIClientSimple) EstimateCost(operation string, inputSize int) *UsageStats {
 	// Get token estimates from mock (for token calculation logic)
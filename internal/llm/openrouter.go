@@ -9,12 +9,14 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/lance13c/tod/internal/logging"
 	"github.com/lance13c/tod/internal/types"
 )
 
 const (
 	openRouterBaseURL        = "https://openrouter.ai/api/v1"
 	openRouterModelsEndpoint = "/models"
+	openRouterKeyEndpoint    = "/key"
 )
 
 // OpenRouterModel represents a model from OpenRouter API
@@ -124,27 +126,57 @@ func (c *OpenRouterClient) FetchModels(ctx context.Context) ([]OpenRouterModel,
 	return modelsResponse.Data, nil
 }
 
-// makeAPIRequest performs an API request to OpenRouter
-func (c *OpenRouterClient) makeAPIRequest(ctx context.Context, endpoint string, payload interface{}) ([]byte, error) {
-	var body io.Reader
-	if payload != nil {
-		jsonData, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+// Ping checks the API key against the key endpoint, since listing models
+// doesn't need one, then that the configured model is listed
+func (c *OpenRouterClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+openRouterKeyEndpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if err := ping(c.httpClient, req, "OpenRouter", c.model); err != nil {
+		return err
+	}
+
+	models, err := c.FetchModels(ctx)
+	if err != nil {
+		return fmt.Errorf("OpenRouter health check failed: %w", err)
+	}
+	for _, model := range models {
+		if model.ID == c.model {
+			return nil
 		}
-		body = bytes.NewBuffer(jsonData)
 	}
+	return fmt.Errorf("model %q is not available from OpenRouter, check ai.model", c.model)
+}
 
-	url := c.baseURL + endpoint
-	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+// ListModels lists the models OpenRouter offers with their context windows
+// and prices
+func (c *OpenRouterClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	openRouterModels, err := c.FetchModels(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("HTTP-Referer", "https://github.com/lance13c/tod")
-	req.Header.Set("X-Title", "Tod - Text-adventure Interface Framework")
+	models := make([]ModelInfo, 0, len(openRouterModels))
+	for _, model := range openRouterModels {
+		models = append(models, ModelInfo{
+			ID:            model.ID,
+			Name:          model.Name,
+			ContextWindow: model.Context,
+			InputCost:     perTokenToPerMillion(model.Pricing.Prompt),
+			OutputCost:    perTokenToPerMillion(model.Pricing.Completion),
+		})
+	}
+	return withKnownPricing(models, "openrouter", c.costCalc), nil
+}
+
+// makeAPIRequest performs an API request to OpenRouter
+func (c *OpenRouterClient) makeAPIRequest(ctx context.Context, endpoint string, payload interface{}) ([]byte, error) {
+	req, err := c.newAPIRequest(ctx, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -164,6 +196,30 @@ func (c *OpenRouterClient) makeAPIRequest(ctx context.Context, endpoint string,
 	return respBody, nil
 }
 
+// newAPIRequest builds an authenticated POST request to an API endpoint
+func (c *OpenRouterClient) newAPIRequest(ctx context.Context, endpoint string, payload interface{}) (*http.Request, error) {
+	var body io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		body = bytes.NewBuffer(jsonData)
+	}
+
+	url := c.baseURL + endpoint
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("HTTP-Referer", "https://github.com/lance13c/tod")
+	req.Header.Set("X-Title", "Tod - Text-adventure Interface Framework")
+	return req, nil
+}
+
 // AnalyzeCode implements the Client interface
 func (c *OpenRouterClient) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
 	// Estimate token usage and cost
@@ -261,6 +317,62 @@ func (c *OpenRouterClient) AnalyzeCode(ctx context.Context, code, filePath strin
 	return &analysis, nil
 }
 
+// StreamAnalyzeCode implements the Client interface without streaming
+func (c *OpenRouterClient) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	return streamAnalysisFallback(ctx, c, code, filePath)
+}
+
+// StreamAnswer implements the Client interface, streaming the answer as
+// server-sent events. Errors before the stream starts are returned.
+func (c *OpenRouterClient) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	payload := map[string]interface{}{
+		"model": c.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": answerSystemPrompt},
+			{"role": "user", "content": prompt},
+		},
+		"temperature":    0.3,
+		"max_tokens":     answerMaxTokens,
+		"stream":         true,
+		"stream_options": map[string]bool{"include_usage": true},
+	}
+
+	req, err := c.newAPIRequest(ctx, "/chat/completions", payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The client timeout would cut long streams off; ctx bounds the stream instead
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	chunks := make(chan string)
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		usage, err := readOpenAIStream(ctx, resp.Body, chunks)
+		if err != nil && ctx.Err() == nil {
+			logging.Warn("OpenRouter stream ended early: %v", err)
+		}
+		if usage != nil && usage.TotalTokens > 0 {
+			c.lastUsage = c.costCalc.CalculateCost("openrouter", c.model, int64(usage.PromptTokens), int64(usage.CompletionTokens))
+		}
+	}()
+	return chunks, nil
+}
+
 // GenerateFlow implements the Client interface
 func (c *OpenRouterClient) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
 	actionsJSON, err := json.Marshal(actions)
@@ -554,6 +666,55 @@ func (c *OpenRouterClient) InterpretCommandWithContext(ctx context.Context, comm
 	return interpretation, nil
 }
 
+// InterpretCommands interprets every command in a single request
+func (c *OpenRouterClient) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	if len(commands) == 0 {
+		return nil, nil
+	}
+
+	payload := map[string]interface{}{
+		"model": c.model,
+		"messages": []map[string]string{
+			{
+				"role":    "user",
+				"content": batchInterpretPromptFor(commands, availableActions),
+			},
+		},
+		"temperature": 0.1,
+		"max_tokens":  batchInterpretMaxTokens(len(commands)),
+	}
+
+	respBody, err := c.makeAPIRequest(ctx, "/chat/completions", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if inputTokens, outputTokens, err := TokenUsageFromResponse("openrouter", respBody); err == nil {
+		c.lastUsage = c.costCalc.CalculateCost("openrouter", c.model, inputTokens, outputTokens)
+	}
+
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from model")
+	}
+
+	return parseBatchInterpretations(response.Choices[0].Message.Content, len(commands), c.lastUsage)
+}
+
+// EstimateTokenCost implements the Client interface
+func (c *OpenRouterClient) EstimateTokenCost(promptTokens, completionTokens int) float64 {
+	return c.costCalc.TokenCost("openrouter", c.model, promptTokens, completionTokens)
+}
+
 // EstimateCost implements the Client interface
 func (c *OpenRouterClient) EstimateCost(operation string, inputSize int) *UsageStats {
 	var inputTokens, outputTokens int64
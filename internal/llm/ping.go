@@ -0,0 +1,36 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// pingHTTPClient is used by clients that don't keep an HTTP client of their own
+var pingHTTPClient = &http.Client{}
+
+// ping sends a health check request and turns a failed response into an
+// error saying what is wrong: the API key, the model or the provider
+func ping(client *http.Client, req *http.Request, provider, model string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s is unreachable: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden,
+		resp.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(string(body)), "api key"):
+		return fmt.Errorf("%s rejected the API key (status %d), check ai.api_key", provider, resp.StatusCode)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("model %q is not available from %s, check ai.model", model, provider)
+	default:
+		return fmt.Errorf("%s health check failed with status %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
@@ -0,0 +1,218 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lance13c/tod/internal/logging"
+	"github.com/lance13c/tod/internal/types"
+)
+
+// throttledCalls counts calls currently waiting for a rate limit slot
+var throttledCalls int32
+
+// ThrottledCalls returns how many LLM calls are waiting on the rate limiter
+func ThrottledCalls() int {
+	return int(atomic.LoadInt32(&throttledCalls))
+}
+
+// rateLimiter is a token bucket that allows short bursts up to its capacity
+// and refills at a steady rate
+type rateLimiter struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	perSec   float64
+	last     time.Time
+}
+
+// newRateLimiter creates a limiter allowing requestsPerMinute calls per minute
+func newRateLimiter(requestsPerMinute int) *rateLimiter {
+	r := &rateLimiter{last: time.Now()}
+	r.setRate(requestsPerMinute)
+	r.tokens = r.capacity
+	return r
+}
+
+// setRate changes the limit to requestsPerMinute, keeping the tokens
+// already available up to the new capacity
+func (r *rateLimiter) setRate(requestsPerMinute int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.capacity = float64(requestsPerMinute) / 6 // allow ~10s worth of burst
+	if r.capacity < 1 {
+		r.capacity = 1
+	}
+	r.perSec = float64(requestsPerMinute) / 60
+	if r.tokens > r.capacity {
+		r.tokens = r.capacity
+	}
+}
+
+// sharedLimiters holds one limiter per provider and API key, so every client
+// calling with the same account draws on the same budget
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = make(map[string]*rateLimiter)
+)
+
+// sharedRateLimiter returns the limiter of provider and apiKey, creating it
+// on first use. Asking for a different requestsPerMinute changes the limit
+// for every client sharing it.
+func sharedRateLimiter(provider Provider, apiKey string, requestsPerMinute int) *rateLimiter {
+	sum := sha256.Sum256([]byte(apiKey))
+	key := string(provider) + "/" + hex.EncodeToString(sum[:8])
+
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+	if limiter, ok := sharedLimiters[key]; ok {
+		limiter.setRate(requestsPerMinute)
+		return limiter
+	}
+	limiter := newRateLimiter(requestsPerMinute)
+	sharedLimiters[key] = limiter
+	return limiter
+}
+
+// reserve takes a token if one is available, otherwise returns how long to wait
+func (r *rateLimiter) reserve() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.perSec
+	if r.tokens > r.capacity {
+		r.tokens = r.capacity
+	}
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+	return time.Duration((1 - r.tokens) / r.perSec * float64(time.Second))
+}
+
+// Wait blocks until a token is available or the context is done
+func (r *rateLimiter) Wait(ctx context.Context) error {
+	wait := r.reserve()
+	if wait == 0 {
+		return nil
+	}
+
+	atomic.AddInt32(&throttledCalls, 1)
+	defer atomic.AddInt32(&throttledCalls, -1)
+
+	for wait > 0 {
+		logging.Info("LLM call throttled, waiting %v for a rate limit slot", wait.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait = r.reserve()
+	}
+	return nil
+}
+
+// rateLimitedClient passes every API call through a shared rate limiter
+type rateLimitedClient struct {
+	Client
+	limiter *rateLimiter
+}
+
+// NewRateLimitedClient wraps a client so at most requestsPerMinute API calls
+// per minute are made with provider and apiKey, queueing calls that would
+// exceed the limit. Every client wrapped for the same provider and key
+// shares the limit.
+func NewRateLimitedClient(client Client, provider Provider, apiKey string, requestsPerMinute int) Client {
+	if requestsPerMinute <= 0 {
+		return client
+	}
+	return &rateLimitedClient{
+		Client:  client,
+		limiter: sharedRateLimiter(provider, apiKey, requestsPerMinute),
+	}
+}
+
+func (c *rateLimitedClient) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.AnalyzeCode(ctx, code, filePath)
+}
+
+func (c *rateLimitedClient) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.StreamAnalyzeCode(ctx, code, filePath)
+}
+
+func (c *rateLimitedClient) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.StreamAnswer(ctx, prompt)
+}
+
+func (c *rateLimitedClient) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.GenerateFlow(ctx, actions)
+}
+
+func (c *rateLimitedClient) ExtractActions(ctx context.Context, code, framework, language string) ([]types.CodeAction, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.ExtractActions(ctx, code, framework, language)
+}
+
+func (c *rateLimitedClient) ResearchFramework(ctx context.Context, frameworkName, version string) (*FrameworkResearch, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.ResearchFramework(ctx, frameworkName, version)
+}
+
+func (c *rateLimitedClient) InterpretCommand(ctx context.Context, command string, availableActions []types.CodeAction) (*CommandInterpretation, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.InterpretCommand(ctx, command, availableActions)
+}
+
+func (c *rateLimitedClient) InterpretCommandWithContext(ctx context.Context, command string, availableActions []types.CodeAction, conversation *ConversationContext) (*CommandInterpretation, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.InterpretCommandWithContext(ctx, command, availableActions, conversation)
+}
+
+func (c *rateLimitedClient) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.InterpretCommands(ctx, commands, availableActions, conversation)
+}
+
+func (c *rateLimitedClient) AnalyzeScreenshot(ctx context.Context, screenshot []byte, prompt string) (*ScreenshotAnalysis, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.AnalyzeScreenshot(ctx, screenshot, prompt)
+}
+
+func (c *rateLimitedClient) RankNavigationElements(ctx context.Context, userInput string, elements []NavigationElement) (*NavigationRanking, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.RankNavigationElements(ctx, userInput, elements)
+}
@@ -0,0 +1,225 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lance13c/tod/internal/types"
+)
+
+// Interaction is one LLM call as seen by the caller: what was asked, what
+// came back and what it cost
+type Interaction struct {
+	Type     string // the Client method, e.g. "interpret_command"
+	Provider Provider
+	Model    string
+	Prompt   string
+	Response string
+	Usage    *UsageStats // nil if the provider reported none
+	Latency  time.Duration
+	Err      error
+}
+
+// InteractionRecorder is called after every LLM call a recording client makes.
+// It may be called from several goroutines at once.
+type InteractionRecorder func(Interaction)
+
+// recordingClient reports every API call of the client it wraps to a recorder
+type recordingClient struct {
+	Client
+	provider Provider
+	model    string
+	record   InteractionRecorder
+}
+
+// NewRecordingClient wraps a client so every call it makes to the provider is
+// passed to record, with the request's inputs as the prompt and the result
+// as the response
+func NewRecordingClient(client Client, provider Provider, model string, record InteractionRecorder) Client {
+	if record == nil {
+		return client
+	}
+	return &recordingClient{
+		Client:   client,
+		provider: provider,
+		model:    model,
+		record:   record,
+	}
+}
+
+// recordCall runs call and records it as an interaction of kind
+func recordCall[T any](c *recordingClient, kind, prompt string, call func() (T, error)) (T, error) {
+	start := time.Now()
+	result, err := call()
+	interaction := Interaction{
+		Type:     kind,
+		Provider: c.provider,
+		Model:    c.model,
+		Prompt:   prompt,
+		Latency:  time.Since(start),
+		Err:      err,
+	}
+	if err == nil {
+		if response, marshalErr := json.MarshalIndent(result, "", "  "); marshalErr == nil {
+			interaction.Response = string(response)
+		}
+		interaction.Usage = c.usageOf(result)
+	}
+	c.record(interaction)
+	return result, err
+}
+
+// usageOf returns the usage a call's result reports, falling back to the
+// client's last usage for results that don't carry it
+func (c *recordingClient) usageOf(result any) *UsageStats {
+	var usage *UsageStats
+	switch result := result.(type) {
+	case *CodeAnalysis:
+		usage = result.Usage
+	case *FlowSuggestion:
+		usage = result.Usage
+	case *CommandInterpretation:
+		usage = result.Usage
+	case *ScreenshotAnalysis:
+		usage = result.Usage
+	case []*CommandInterpretation:
+		for _, interpretation := range result {
+			if interpretation != nil && interpretation.Usage != nil {
+				usage = interpretation.Usage
+				break
+			}
+		}
+	}
+	if usage == nil {
+		usage = c.Client.GetLastUsage()
+	}
+	return usage
+}
+
+func (c *recordingClient) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
+	return recordCall(c, "analyze_code", fmt.Sprintf("File: %s\n\n%s", filePath, code), func() (*CodeAnalysis, error) {
+		return c.Client.AnalyzeCode(ctx, code, filePath)
+	})
+}
+
+// StreamAnalyzeCode records the streamed response once the stream ends
+func (c *recordingClient) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	return c.recordStream(ctx, "stream_analyze_code", fmt.Sprintf("File: %s\n\n%s", filePath, code), func() (<-chan string, error) {
+		return c.Client.StreamAnalyzeCode(ctx, code, filePath)
+	})
+}
+
+// StreamAnswer records the streamed answer once the stream ends
+func (c *recordingClient) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	return c.recordStream(ctx, "answer", prompt, func() (<-chan string, error) {
+		return c.Client.StreamAnswer(ctx, prompt)
+	})
+}
+
+// recordStream starts a stream and relays it, recording the whole response
+// as an interaction of kind once the stream ends
+func (c *recordingClient) recordStream(ctx context.Context, kind, prompt string, stream func() (<-chan string, error)) (<-chan string, error) {
+	start := time.Now()
+	interaction := Interaction{
+		Type:     kind,
+		Provider: c.provider,
+		Model:    c.model,
+		Prompt:   prompt,
+	}
+
+	chunks, err := stream()
+	if err != nil {
+		interaction.Latency = time.Since(start)
+		interaction.Err = err
+		c.record(interaction)
+		return nil, err
+	}
+
+	relayed := make(chan string)
+	go func() {
+		defer close(relayed)
+
+		var response strings.Builder
+		defer func() {
+			interaction.Response = response.String()
+			interaction.Usage = c.Client.GetLastUsage()
+			interaction.Latency = time.Since(start)
+			interaction.Err = ctx.Err()
+			c.record(interaction)
+		}()
+
+		for chunk := range chunks {
+			response.WriteString(chunk)
+			select {
+			case relayed <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return relayed, nil
+}
+
+func (c *recordingClient) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
+	return recordCall(c, "generate_flow", "Actions:\n"+formatActionsForLLM(actions), func() (*FlowSuggestion, error) {
+		return c.Client.GenerateFlow(ctx, actions)
+	})
+}
+
+func (c *recordingClient) ExtractActions(ctx context.Context, code, framework, language string) ([]types.CodeAction, error) {
+	prompt := fmt.Sprintf("Framework: %s\nLanguage: %s\n\n%s", framework, language, code)
+	return recordCall(c, "extract_actions", prompt, func() ([]types.CodeAction, error) {
+		return c.Client.ExtractActions(ctx, code, framework, language)
+	})
+}
+
+func (c *recordingClient) ResearchFramework(ctx context.Context, frameworkName, version string) (*FrameworkResearch, error) {
+	prompt := fmt.Sprintf("Framework: %s %s", frameworkName, version)
+	return recordCall(c, "research_framework", prompt, func() (*FrameworkResearch, error) {
+		return c.Client.ResearchFramework(ctx, frameworkName, version)
+	})
+}
+
+func (c *recordingClient) InterpretCommand(ctx context.Context, command string, availableActions []types.CodeAction) (*CommandInterpretation, error) {
+	return recordCall(c, "interpret_command", commandPrompt(command, availableActions), func() (*CommandInterpretation, error) {
+		return c.Client.InterpretCommand(ctx, command, availableActions)
+	})
+}
+
+func (c *recordingClient) InterpretCommandWithContext(ctx context.Context, command string, availableActions []types.CodeAction, conversation *ConversationContext) (*CommandInterpretation, error) {
+	return recordCall(c, "interpret_command", commandPrompt(command, availableActions), func() (*CommandInterpretation, error) {
+		return c.Client.InterpretCommandWithContext(ctx, command, availableActions, conversation)
+	})
+}
+
+func (c *recordingClient) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	return recordCall(c, "interpret_commands", batchInterpretPromptFor(commands, availableActions), func() ([]*CommandInterpretation, error) {
+		return c.Client.InterpretCommands(ctx, commands, availableActions, conversation)
+	})
+}
+
+func (c *recordingClient) AnalyzeScreenshot(ctx context.Context, screenshot []byte, prompt string) (*ScreenshotAnalysis, error) {
+	recorded := fmt.Sprintf("%s\n\n[screenshot, %d bytes]", prompt, len(screenshot))
+	return recordCall(c, "analyze_screenshot", recorded, func() (*ScreenshotAnalysis, error) {
+		return c.Client.AnalyzeScreenshot(ctx, screenshot, prompt)
+	})
+}
+
+func (c *recordingClient) RankNavigationElements(ctx context.Context, userInput string, elements []NavigationElement) (*NavigationRanking, error) {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Input: %s\n\nElements:\n", userInput)
+	for _, element := range elements {
+		fmt.Fprintf(&prompt, "- [%s] %s (%s)\n", element.Type, element.Text, element.Selector)
+	}
+	return recordCall(c, "rank_navigation", prompt.String(), func() (*NavigationRanking, error) {
+		return c.Client.RankNavigationElements(ctx, userInput, elements)
+	})
+}
+
+// commandPrompt describes a single command interpretation request
+func commandPrompt(command string, availableActions []types.CodeAction) string {
+	return fmt.Sprintf("Command: %s\n\nAvailable actions:\n%s", command, formatActionsForLLM(availableActions))
+}
@@ -0,0 +1,228 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lance13c/tod/internal/logging"
+	"github.com/lance13c/tod/internal/types"
+)
+
+// DefaultRetryBaseDelay is the delay before the first retry; each further
+// retry doubles it
+const DefaultRetryBaseDelay = 1 * time.Second
+
+// maxRetryDelay caps the backoff between retries
+const maxRetryDelay = 30 * time.Second
+
+// retryableStatusPattern matches the HTTP status in provider errors such as
+// "API request failed with status 503: ..."
+var retryableStatusPattern = regexp.MustCompile(`status(?: code)?:? (429|5\d\d)\b`)
+
+// clientErrorStatusPattern matches a 4xx status in provider errors
+var clientErrorStatusPattern = regexp.MustCompile(`status(?: code)?:? (4\d\d)\b`)
+
+// permanentMessages are fragments of errors for a rejected API key or account
+var permanentMessages = []string{
+	"api key",
+	"api_key",
+	"api-key",
+	"unauthorized",
+	"authentication",
+	"permission denied",
+	"insufficient_quota",
+}
+
+// retryableMessages are fragments of transient provider and network errors
+var retryableMessages = []string{
+	"rate limit",
+	"rate_limit",
+	"too many requests",
+	"overloaded",
+	"temporarily unavailable",
+	"service unavailable",
+	"bad gateway",
+	"gateway timeout",
+	"connection reset",
+	"connection refused",
+	"i/o timeout",
+	"tls handshake timeout",
+}
+
+// IsRetryableError reports whether an LLM call failed for a transient reason
+// (rate limiting, a 5xx response or a network hiccup) and may succeed if
+// retried. Errors such as an invalid API key are not retryable.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	if retryableStatusPattern.MatchString(msg) {
+		return true
+	}
+	for _, fragment := range retryableMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPermanentError reports whether an LLM call failed in a way retrying can't
+// fix: the API key or account was rejected, or the provider refused the
+// request itself with a 4xx status other than 408 and 429
+func IsPermanentError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	if match := clientErrorStatusPattern.FindStringSubmatch(msg); match != nil {
+		return match[1] != "408" && match[1] != "429"
+	}
+	for _, fragment := range permanentMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryingClient retries API calls that fail with retryable errors
+type retryingClient struct {
+	Client
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+// NewRetryingClient wraps a client so calls failing with retryable errors are
+// retried up to maxRetries times with exponential backoff and jitter
+func NewRetryingClient(client Client, maxRetries int, baseDelay time.Duration) Client {
+	if maxRetries <= 0 {
+		return client
+	}
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryBaseDelay
+	}
+	return &retryingClient{
+		Client:     client,
+		maxRetries: maxRetries,
+		baseDelay:  baseDelay,
+	}
+}
+
+// backoff returns the delay before the given retry (1-based): the base delay
+// doubled per attempt, capped, with up to 50% jitter so parallel calls spread out
+func (c *retryingClient) backoff(retry int) time.Duration {
+	delay := c.baseDelay << (retry - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// withRetry calls fn until it succeeds, fails with a non-retryable error, the
+// retries run out or the context is done
+func withRetry[T any](ctx context.Context, c *retryingClient, op string, fn func() (T, error)) (T, error) {
+	result, err := fn()
+	for retry := 1; retry <= c.maxRetries && IsRetryableError(err); retry++ {
+		delay := c.backoff(retry)
+		logging.Warn("LLM %s failed (%v), retry %d/%d in %v", op, err, retry, c.maxRetries, delay.Round(time.Millisecond))
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		result, err = fn()
+	}
+	return result, err
+}
+
+func (c *retryingClient) AnalyzeCode(ctx context.Context, code, filePath string) (*CodeAnalysis, error) {
+	return withRetry(ctx, c, "AnalyzeCode", func() (*CodeAnalysis, error) {
+		return c.Client.AnalyzeCode(ctx, code, filePath)
+	})
+}
+
+func (c *retryingClient) StreamAnalyzeCode(ctx context.Context, code, filePath string) (<-chan string, error) {
+	// Only starting the stream is retried; text already sent can't be taken back
+	return withRetry(ctx, c, "StreamAnalyzeCode", func() (<-chan string, error) {
+		return c.Client.StreamAnalyzeCode(ctx, code, filePath)
+	})
+}
+
+func (c *retryingClient) StreamAnswer(ctx context.Context, prompt string) (<-chan string, error) {
+	// Only starting the stream is retried, as for StreamAnalyzeCode
+	return withRetry(ctx, c, "StreamAnswer", func() (<-chan string, error) {
+		return c.Client.StreamAnswer(ctx, prompt)
+	})
+}
+
+func (c *retryingClient) GenerateFlow(ctx context.Context, actions []types.CodeAction) (*FlowSuggestion, error) {
+	return withRetry(ctx, c, "GenerateFlow", func() (*FlowSuggestion, error) {
+		return c.Client.GenerateFlow(ctx, actions)
+	})
+}
+
+func (c *retryingClient) ExtractActions(ctx context.Context, code, framework, language string) ([]types.CodeAction, error) {
+	return withRetry(ctx, c, "ExtractActions", func() ([]types.CodeAction, error) {
+		return c.Client.ExtractActions(ctx, code, framework, language)
+	})
+}
+
+func (c *retryingClient) ResearchFramework(ctx context.Context, frameworkName, version string) (*FrameworkResearch, error) {
+	return withRetry(ctx, c, "ResearchFramework", func() (*FrameworkResearch, error) {
+		return c.Client.ResearchFramework(ctx, frameworkName, version)
+	})
+}
+
+func (c *retryingClient) InterpretCommand(ctx context.Context, command string, availableActions []types.CodeAction) (*CommandInterpretation, error) {
+	return withRetry(ctx, c, "InterpretCommand", func() (*CommandInterpretation, error) {
+		return c.Client.InterpretCommand(ctx, command, availableActions)
+	})
+}
+
+func (c *retryingClient) InterpretCommandWithContext(ctx context.Context, command string, availableActions []types.CodeAction, conversation *ConversationContext) (*CommandInterpretation, error) {
+	return withRetry(ctx, c, "InterpretCommandWithContext", func() (*CommandInterpretation, error) {
+		return c.Client.InterpretCommandWithContext(ctx, command, availableActions, conversation)
+	})
+}
+
+func (c *retryingClient) InterpretCommands(ctx context.Context, commands []string, availableActions []types.CodeAction, conversation *ConversationContext) ([]*CommandInterpretation, error) {
+	return withRetry(ctx, c, "InterpretCommands", func() ([]*CommandInterpretation, error) {
+		return c.Client.InterpretCommands(ctx, commands, availableActions, conversation)
+	})
+}
+
+func (c *retryingClient) AnalyzeScreenshot(ctx context.Context, screenshot []byte, prompt string) (*ScreenshotAnalysis, error) {
+	return withRetry(ctx, c, "AnalyzeScreenshot", func() (*ScreenshotAnalysis, error) {
+		return c.Client.AnalyzeScreenshot(ctx, screenshot, prompt)
+	})
+}
+
+func (c *retryingClient) Ping(ctx context.Context) error {
+	_, err := withRetry(ctx, c, "Ping", func() (struct{}, error) {
+		return struct{}{}, c.Client.Ping(ctx)
+	})
+	return err
+}
+
+func (c *retryingClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return withRetry(ctx, c, "ListModels", func() ([]ModelInfo, error) {
+		return c.Client.ListModels(ctx)
+	})
+}
+
+func (c *retryingClient) RankNavigationElements(ctx context.Context, userInput string, elements []NavigationElement) (*NavigationRanking, error) {
+	return withRetry(ctx, c, "RankNavigationElements", func() (*NavigationRanking, error) {
+		return c.Client.RankNavigationElements(ctx, userInput, elements)
+	})
+}
@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// streamAnalysisFallback implements StreamAnalyzeCode for clients that can't
+// stream: it runs AnalyzeCode and sends the whole response as one chunk. The
+// channel is closed early if ctx is cancelled.
+func streamAnalysisFallback(ctx context.Context, client Client, code, filePath string) (<-chan string, error) {
+	chunks := make(chan string)
+	go func() {
+		defer close(chunks)
+
+		analysis, err := client.AnalyzeCode(ctx, code, filePath)
+		if err != nil {
+			logging.Warn("Analysis failed: %v", err)
+			return
+		}
+
+		select {
+		case chunks <- analysis.Notes:
+		case <-ctx.Done():
+		}
+	}()
+	return chunks, nil
+}
+
+// answerSystemPrompt frames the questions StreamAnswer sends
+const answerSystemPrompt = "You are an expert QA engineer exploring a web application. Answer concisely in plain text."
+
+// answerMaxTokens bounds answers from providers that need a limit
+const answerMaxTokens = 1024
+
+// answerHTTPClient sends StreamAnswer requests; their context bounds them
+var answerHTTPClient = &http.Client{}
+
+// streamText sends text as the whole of a stream, for providers that answer
+// in one piece. The channel is closed early if ctx is cancelled.
+func streamText(ctx context.Context, text string) <-chan string {
+	chunks := make(chan string)
+	go func() {
+		defer close(chunks)
+		select {
+		case chunks <- text:
+		case <-ctx.Done():
+		}
+	}()
+	return chunks
+}
+
+// openAIStreamChunk is one server-sent event of a streamed chat completion
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *OpenAIUsage `json:"usage,omitempty"`
+}
+
+// readOpenAIStream sends the content deltas of a streamed chat completion to
+// chunks until the stream ends or ctx is cancelled, and returns the usage
+// reported in the final event, if any
+func readOpenAIStream(ctx context.Context, body io.Reader, chunks chan<- string) (*OpenAIUsage, error) {
+	var usage *OpenAIUsage
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return usage, nil
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return usage, fmt.Errorf("failed to parse stream event: %w", err)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			select {
+			case chunks <- choice.Delta.Content:
+			case <-ctx.Done():
+				return usage, ctx.Err()
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return usage, ctx.Err()
+		}
+		return usage, fmt.Errorf("failed to read stream: %w", err)
+	}
+	return usage, nil
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -290,6 +291,27 @@ func Fatal(format string, v ...interface{}) {
 	GetLogger().Fatal(format, v...)
 }
 
+// SetLevel sets the level below which the global logger drops messages
+func SetLevel(level int) {
+	GetLogger().SetLevel(level)
+}
+
+// ParseLevel returns the level named by "debug", "info", "warn" or "error"
+func ParseLevel(name string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return DEBUG, nil
+	case "info":
+		return INFO, nil
+	case "warn", "warning":
+		return WARN, nil
+	case "error":
+		return ERROR, nil
+	default:
+		return INFO, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+	}
+}
+
 // Printf is a compatibility function that logs at INFO level
 func Printf(format string, v ...interface{}) {
 	GetLogger().Info(format, v...)
@@ -297,7 +319,7 @@ func Printf(format string, v ...interface{}) {
 
 // Println is a compatibility function that logs at INFO level
 func Println(v ...interface{}) {
-	GetLogger().Info(fmt.Sprint(v...))
+	GetLogger().Info("%s", fmt.Sprint(v...))
 }
 
 // Writer returns an io.Writer for the logger (useful for redirecting standard log)
@@ -311,7 +333,7 @@ type logWriter struct {
 }
 
 func (w *logWriter) Write(p []byte) (n int, err error) {
-	w.logger.Info(string(p))
+	w.logger.Info("%s", string(p))
 	return len(p), nil
 }
 
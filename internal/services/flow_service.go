@@ -48,7 +48,7 @@ func NewFlowService(cfg *config.Config, projectRoot string) (*FlowService, error
 	// Create flow context
 	flowContext := &core.FlowContext{
 		Environment: cfg.Current,
-		BaseURL:     cfg.GetCurrentEnv().BaseURL,
+		BaseURL:     cfg.GetCurrentEnv().HomeURL(),
 		Config:      cfg,
 		UserConfig:  userConfig,
 		Variables:   make(map[string]string),
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/lance13c/tod/internal/browser"
@@ -15,6 +16,21 @@ import (
 type ActionDiscovery struct {
 	llmClient   llm.Client
 	projectRoot string
+
+	// FragileThreshold is the selector stability score below which
+	// generation prefers another locator, see browser.ScoreSelectorStability
+	FragileThreshold float64
+
+	// Browser, Concurrency, OnPage and Follow are used by CrawlAndDiscover
+	Browser     *browser.ChromeDPManager
+	Concurrency int                          // pages analyzed at once, defaults to 3
+	OnPage      func(PageActions)            // called as each crawled page completes
+	Follow      func(text, link string) bool // whether to visit a link, all are when nil
+
+	// Retries is how many times DiscoverActionsFromURL runs again after a
+	// retryable failure, and OnRetry, if set, is told before each retry
+	Retries int
+	OnRetry func(retry, retries int, err error)
 }
 
 // NewActionDiscovery creates a new action discovery instance
@@ -36,6 +52,14 @@ type DiscoveredAction struct {
 	Priority     string `json:"priority"` // high, medium, low
 	JavaScript   string `json:"javascript"` // Executable JavaScript code
 	UserInput    string `json:"user_input"` // The exact user input for this action
+
+	// ExpectedResult is what the page was seen to do after the action ran,
+	// see DescribeExpectedResult
+	ExpectedResult string `json:"expected_result,omitempty"`
+
+	// Unverified actions were found in saved HTML, so their selectors were
+	// never checked against a live page
+	Unverified bool `json:"unverified,omitempty"`
 }
 
 // DiscoverActionsFromHTML analyzes HTML and finds untested user actions
@@ -107,7 +131,7 @@ func (ad *ActionDiscovery) buildDiscoveryPromptWithContext(simplifiedHTML string
 			break
 		}
 		if elem.Text != "" || elem.Href != "" {
-			prompt.WriteString(fmt.Sprintf("- %s: \"%s\"\n", elem.Tag, elem.Text))
+			prompt.WriteString(fmt.Sprintf("- %s: \"%s\" (%s)\n", elem.Tag, elem.Text, elem.PlaywrightLocator()))
 			count++
 		}
 	}
@@ -212,41 +236,19 @@ func (ad *ActionDiscovery) cleanDescription(desc string) string {
 	return desc
 }
 
-// isActionTested checks if an action is already covered by existing tests
+// isActionTested checks if an action is fully covered by existing tests, as
+// scored by CoverageReport. Partially covered actions aren't tested.
 func (ad *ActionDiscovery) isActionTested(action DiscoveredAction, existingTests []string) bool {
-	// Simple heuristic: check if selector or key terms appear in test files
-	searchTerms := []string{
-		action.Selector,
-		strings.ToLower(action.Description),
-	}
-
-	// Extract key terms from selector
-	if strings.Contains(action.Selector, "data-testid=") {
-		start := strings.Index(action.Selector, "'")
-		if start >= 0 {
-			end := strings.Index(action.Selector[start+1:], "'")
-			if end >= 0 {
-				testId := action.Selector[start+1 : start+1+end]
-				searchTerms = append(searchTerms, testId)
-			}
-		}
-	}
-
-	// Check each test file content
-	for _, testContent := range existingTests {
-		testLower := strings.ToLower(testContent)
-		for _, term := range searchTerms {
-			if term != "" && strings.Contains(testLower, strings.ToLower(term)) {
-				return true
-			}
-		}
+	if len(existingTests) == 0 {
+		return false
 	}
-
-	return false
+	return actionCoverage(action, parseTests(existingTests)).Status == CoverageCovered
 }
 
-// GenerateTestSuggestions creates test code suggestions for discovered actions
-func (ad *ActionDiscovery) GenerateTestSuggestions(ctx context.Context, actions []DiscoveredAction, framework string) (string, error) {
+// GenerateTestSuggestions creates test code suggestions for discovered actions.
+// style is TestStyleInline, the default, or TestStylePageObject to group the
+// elements into a Page Object class that the spec uses.
+func (ad *ActionDiscovery) GenerateTestSuggestions(ctx context.Context, actions []DiscoveredAction, framework, style string) (string, error) {
 	// Setup logging
 	logFile, err := os.OpenFile(".tod/api_calls.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err == nil {
@@ -276,8 +278,13 @@ func (ad *ActionDiscovery) GenerateTestSuggestions(ctx context.Context, actions
 		return "", nil
 	}
 
+	// Fall back to the framework the project already uses
+	if framework == "" {
+		framework = ad.detectFramework()
+	}
+
 	// Build prompt for test generation
-	prompt := ad.buildTestGenerationPrompt(untestedActions, framework)
+	prompt := ad.buildTestGenerationPrompt(untestedActions, framework, style)
 
 	if logFile != nil {
 		logger := log.New(logFile, "[ACTION_DISCOVERY] ", log.LstdFlags|log.Lmicroseconds)
@@ -301,7 +308,30 @@ func (ad *ActionDiscovery) GenerateTestSuggestions(ctx context.Context, actions
 		logger.Printf("Response: %s", analysis.Notes)
 	}
 
-	return analysis.Notes, nil
+	// Ask once more rather than hand back tests that only click
+	code := analysis.Notes
+	if countAssertions(code) < len(untestedActions) {
+		retryPrompt := prompt + fmt.Sprintf("\nA previous attempt had only %d assertions for %d actions. Every action needs at least one assertion.\n",
+			countAssertions(code), len(untestedActions))
+		if retry, err := ad.llmClient.AnalyzeCode(ctx, retryPrompt, "test-generation.txt"); err == nil && countAssertions(retry.Notes) > countAssertions(code) {
+			code = retry.Notes
+		}
+	}
+
+	return code, nil
+}
+
+// assertionPattern matches the start of one assertion in each framework
+// tests are generated for: expect(), expect.soft() and TestCafe's t.expect()
+// in Playwright, Puppeteer, WebdriverIO and TestCafe; .should() and .and() in
+// Cypress; assert(), assert.x(), assertX() and Python's assert statement in
+// Selenium; browser.assert.x(), .verify.x() and expect.element() in Nightwatch
+var assertionPattern = regexp.MustCompile(`(?m)\bexpect(?:\.\w+)?\(|\.should\(|\.and\(|\b(?:assert(?:\.\w+)?|verify\.\w+)\(|\bassert[A-Z]\w*\(|^\s*assert\s`)
+
+// countAssertions counts the assertions in generated test code across the
+// assertion styles of the frameworks in assertionPattern
+func countAssertions(code string) int {
+	return len(assertionPattern.FindAllStringIndex(code, -1))
 }
 
 // GenerateActionCode generates executable JavaScript for a specific action
@@ -705,48 +735,189 @@ func (ad *ActionDiscovery) actionsAreSimilar(desc1, desc2 string) bool {
 	return false
 }
 
-// MergeActions merges new actions with existing ones, maintaining priority order
-func (ad *ActionDiscovery) MergeActions(existing []DiscoveredAction, newActions []DiscoveredAction) []DiscoveredAction {
-	// Deduplicate new actions against existing ones
-	filtered := ad.deduplicateActions(newActions, existing)
+// Key identifies an action across discoveries: the element it targets and
+// what is done to it. Actions without a selector have no key.
+func (a DiscoveredAction) Key() string {
+	selector := strings.TrimSpace(a.Selector)
+	if selector == "" {
+		return ""
+	}
+	return selector + "|" + strings.ToLower(strings.TrimSpace(a.Action))
+}
 
-	// Combine all actions
-	combined := append(existing, filtered...)
+// priorityRank orders priorities from low to high
+var priorityRank = map[string]int{"low": 1, "medium": 2, "high": 3}
 
-	// Sort by priority: high -> medium -> low
-	var high, medium, low []DiscoveredAction
-	for _, action := range combined {
-		switch strings.ToLower(action.Priority) {
-		case "high":
-			high = append(high, action)
-		case "medium":
-			medium = append(medium, action)
-		default:
-			low = append(low, action)
+// MergeActions merges new actions into existing ones. An action rediscovered
+// with the same Key is merged into the existing one, keeping the higher
+// priority and both descriptions, rather than added twice. Actions without
+// a selector are matched by description instead. Existing actions keep
+// their order and new ones follow in the order found, so the list stays
+// stable as content loads.
+func (ad *ActionDiscovery) MergeActions(existing []DiscoveredAction, newActions []DiscoveredAction) []DiscoveredAction {
+	merged := append([]DiscoveredAction{}, existing...)
+	index := make(map[string]int, len(merged))
+	for i, action := range merged {
+		if key := action.Key(); key != "" {
+			if _, seen := index[key]; !seen {
+				index[key] = i
+			}
 		}
 	}
 
-	// Combine in priority order
-	result := append(high, medium...)
-	result = append(result, low...)
+	var unkeyed []DiscoveredAction
+	for _, action := range newActions {
+		key := action.Key()
+		if key == "" {
+			unkeyed = append(unkeyed, action)
+			continue
+		}
+		if i, seen := index[key]; seen {
+			merged[i] = mergeAction(merged[i], action)
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, action)
+	}
+
+	// Without a selector the only thing to go on is the description
+	return append(merged, ad.deduplicateActions(unkeyed, merged)...)
+}
+
+// mergeAction combines two discoveries of the same action
+func mergeAction(existing, found DiscoveredAction) DiscoveredAction {
+	merged := existing
+	if priorityRank[strings.ToLower(found.Priority)] > priorityRank[strings.ToLower(existing.Priority)] {
+		merged.Priority = found.Priority
+	}
+	merged.Description = unionDescriptions(existing.Description, found.Description)
+	merged.IsTested = existing.IsTested || found.IsTested
+	merged.Unverified = existing.Unverified && found.Unverified
+
+	// Fill in whatever the first discovery left out
+	if merged.Element == "" {
+		merged.Element = found.Element
+	}
+	if merged.TestScenario == "" {
+		merged.TestScenario = found.TestScenario
+	}
+	if merged.JavaScript == "" {
+		merged.JavaScript = found.JavaScript
+	}
+	if merged.UserInput == "" {
+		merged.UserInput = found.UserInput
+	}
+	if merged.ExpectedResult == "" {
+		merged.ExpectedResult = found.ExpectedResult
+	}
+	return merged
+}
+
+// unionDescriptions joins two descriptions of the same action, leaving out
+// the second when the first already says it
+func unionDescriptions(first, second string) string {
+	a, b := strings.ToLower(strings.TrimSpace(first)), strings.ToLower(strings.TrimSpace(second))
+	switch {
+	case b == "" || strings.Contains(a, b):
+		return first
+	case a == "" || strings.Contains(b, a):
+		return second
+	default:
+		return first + " / " + second
+	}
+}
 
-	return result
+// detectFramework returns the E2E framework configured in the project, or
+// playwright if none is found
+func (ad *ActionDiscovery) detectFramework() string {
+	if framework, err := NewFrameworkDetector(ad.projectRoot).DetectFramework(); err == nil {
+		return framework.Name
+	}
+	return "playwright"
 }
 
-// buildTestGenerationPrompt creates a prompt for generating test code
-func (ad *ActionDiscovery) buildTestGenerationPrompt(actions []DiscoveredAction, framework string) string {
+// buildTestGenerationPrompt creates a prompt for generating test code, using
+// the framework's template when there is one
+func (ad *ActionDiscovery) buildTestGenerationPrompt(actions []DiscoveredAction, framework, style string) string {
 	var prompt strings.Builder
 
+	template, ok := testPromptTemplates[normalizeFramework(framework)]
+	if ok {
+		framework = template.DisplayName
+	}
+
 	prompt.WriteString(fmt.Sprintf("Generate %s test code for the following untested user actions:\n\n", framework))
 
+	pageObject := strings.EqualFold(style, TestStylePageObject)
+	var methodNames []string
+	if pageObject {
+		methodNames = pageObjectMethodNames(actions)
+	}
+
+	anyFragile := false
 	for i, action := range actions {
 		prompt.WriteString(fmt.Sprintf("%d. %s\n", i+1, action.Description))
+		if pageObject {
+			prompt.WriteString(fmt.Sprintf("   Method: %s\n", methodNames[i]))
+		}
 		prompt.WriteString(fmt.Sprintf("   Selector: %s\n", action.Selector))
+		if ok && action.Selector != "" {
+			prompt.WriteString(fmt.Sprintf("   Locator: %s\n", translateSelector(framework, action.Selector)))
+		}
+		if action.Selector != "" && browser.IsFragileSelector(action.Selector, ad.FragileThreshold) {
+			_, reasons := browser.ScoreSelectorStability(action.Selector)
+			prompt.WriteString(fmt.Sprintf("   Fragile selector: %s\n", strings.Join(reasons, "; ")))
+			anyFragile = true
+		}
 		prompt.WriteString(fmt.Sprintf("   Action: %s\n", action.Action))
 		prompt.WriteString(fmt.Sprintf("   Scenario: %s\n", action.TestScenario))
+		if action.ExpectedResult != "" {
+			prompt.WriteString(fmt.Sprintf("   Expected result: %s\n", action.ExpectedResult))
+		}
 		prompt.WriteString(fmt.Sprintf("   Priority: %s\n\n", action.Priority))
 	}
 
+	if ok {
+		prompt.WriteString(fmt.Sprintf("%s CONVENTIONS:\n", strings.ToUpper(template.DisplayName)))
+		for _, convention := range template.Conventions {
+			prompt.WriteString(fmt.Sprintf("- %s\n", convention))
+		}
+		prompt.WriteString("- Use the Locator given for each action rather than inventing a new one, unless it is marked fragile\n\n")
+
+		prompt.WriteString("EXAMPLE:\n")
+		prompt.WriteString(template.Example)
+		prompt.WriteString("\n\n")
+	}
+
+	if pageObject {
+		prompt.WriteString("PAGE OBJECT:\n")
+		prompt.WriteString("- First output a Page Object class named after the page, e.g. LoginPage, that keeps every locator\n")
+		prompt.WriteString("- Give it one method per action, using exactly the Method name given for the action\n")
+		prompt.WriteString("- Then output the spec file, which imports the class and calls its methods; it must not contain raw selectors\n")
+		prompt.WriteString("- Keep assertions in the spec, not in the Page Object\n\n")
+	}
+
+	for _, action := range actions {
+		if action.Unverified {
+			prompt.WriteString("UNVERIFIED SELECTORS:\n")
+			prompt.WriteString("- The selectors come from a saved HTML file and were not checked against a live page\n")
+			prompt.WriteString("- Use them as given, relative to the page; don't anchor them to the document root or to absolute URLs\n")
+			prompt.WriteString("- Start the file with a comment saying the selectors are unverified and need a run against the real page\n\n")
+			break
+		}
+	}
+
+	if anyFragile {
+		prompt.WriteString("SELECTORS:\n")
+		prompt.WriteString("- Where an action has a Fragile selector, locate the element by data-testid, or by role and accessible name, instead\n")
+		prompt.WriteString("- Only fall back to the fragile selector if the element has neither\n\n")
+	}
+
+	prompt.WriteString("ASSERTIONS:\n")
+	prompt.WriteString("- Follow every action with at least one assertion; a test that only clicks is not acceptable\n")
+	prompt.WriteString("- Where an action has an Expected result, assert each condition in it: URL changes, visible text, elements that appear\n")
+	prompt.WriteString("- Otherwise assert the most likely visible outcome, or at least that the element acted on is visible\n\n")
+
 	prompt.WriteString("Generate concise, well-structured test cases.\n")
 	prompt.WriteString("Use proper assertions and follow testing best practices.\n")
 	prompt.WriteString("Include both positive and negative test cases where appropriate.\n")
@@ -0,0 +1,95 @@
+package testing
+
+import "testing"
+
+func TestMergeActions(t *testing.T) {
+	existing := []DiscoveredAction{
+		{Description: "Sign in", Selector: "#login", Action: "click", Priority: "medium"},
+		{Description: "Search products", Selector: "#search", Action: "fill", Priority: "high", IsTested: true},
+	}
+	found := []DiscoveredAction{
+		// Same element and action: priority escalates and descriptions join
+		{Description: "Log in to the account", Selector: " #login ", Action: "Click", Priority: "high", ExpectedResult: "URL changes to /dashboard"},
+		// Same element and action at a lower priority: priority is kept
+		{Description: "Search products", Selector: "#search", Action: "fill", Priority: "low"},
+		// Same element, another action: a separate action
+		{Description: "Hover sign in", Selector: "#login", Action: "hover", Priority: "low"},
+		// New element
+		{Description: "Open cart", Selector: "#cart", Action: "click", Priority: "medium"},
+		// No selector: deduplicated by description
+		{Description: "sign in", Priority: "high"},
+		{Description: "Read the FAQ", Priority: "low"},
+	}
+
+	merged := NewActionDiscovery(nil, ".").MergeActions(existing, found)
+
+	wantKeys := []string{"#login|click", "#search|fill", "#login|hover", "#cart|click", ""}
+	if len(merged) != len(wantKeys) {
+		t.Fatalf("merged %d actions, want %d: %+v", len(merged), len(wantKeys), merged)
+	}
+	for i, key := range wantKeys {
+		if got := merged[i].Key(); got != key {
+			t.Errorf("merged[%d].Key() = %q, want %q", i, got, key)
+		}
+	}
+
+	login := merged[0]
+	if login.Priority != "high" {
+		t.Errorf("login priority = %q, want it escalated to high", login.Priority)
+	}
+	if want := "Sign in / Log in to the account"; login.Description != want {
+		t.Errorf("login description = %q, want %q", login.Description, want)
+	}
+	if login.ExpectedResult != "URL changes to /dashboard" {
+		t.Errorf("login expected result = %q, want it filled in from the rediscovery", login.ExpectedResult)
+	}
+
+	search := merged[1]
+	if search.Priority != "high" {
+		t.Errorf("search priority = %q, want high kept", search.Priority)
+	}
+	if search.Description != "Search products" {
+		t.Errorf("search description = %q, want it not repeated", search.Description)
+	}
+	if !search.IsTested {
+		t.Error("search lost IsTested in the merge")
+	}
+
+	if merged[4].Description != "Read the FAQ" {
+		t.Errorf("unkeyed action = %q, want only the one not already found", merged[4].Description)
+	}
+}
+
+func TestCountAssertions(t *testing.T) {
+	tests := []struct {
+		framework string
+		code      string
+		want      int
+	}{
+		{"playwright", `await page.getByText('Pricing').click();
+await expect(page).toHaveURL(/pricing/);
+await expect.soft(page.getByRole('heading')).toBeVisible();`, 2},
+		{"cypress", `cy.contains('a', 'Pricing').click();
+cy.url().should('include', '/pricing').and('not.include', '/login');
+cy.get('h1').then($h1 => expect($h1).to.contain('Pricing'));`, 3},
+		{"testcafe", `await t.click(Selector('a').withText('Pricing'));
+await t.expect(getURL()).contains('/pricing');`, 1},
+		{"selenium", `await driver.findElement(By.css('#pricing')).click();
+assert.strictEqual(await driver.getCurrentUrl(), 'http://localhost:3000/pricing');
+assert(await driver.findElement(By.css('h1')).isDisplayed());
+assertEquals("Pricing", driver.getTitle());`, 3},
+		{"selenium python", `driver.find_element(By.ID, "pricing").click()
+assert "/pricing" in driver.current_url`, 1},
+		{"nightwatch", `browser.click('#pricing');
+browser.assert.urlContains('/pricing');
+browser.verify.visible('h1');
+browser.expect.element('h1').text.to.contain('Pricing');`, 3},
+		{"clicks only", `await page.click('#pricing');`, 0},
+	}
+
+	for _, tt := range tests {
+		if got := countAssertions(tt.code); got != tt.want {
+			t.Errorf("%s: countAssertions = %d, want %d", tt.framework, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package testing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Coverage statuses of an action
+const (
+	CoverageCovered  = "covered"
+	CoveragePartial  = "partial"
+	CoverageUntested = "untested"
+)
+
+// Weights of the coverage score. An action is covered when a test finds its
+// element, performs the action on it and asserts its result afterwards.
+const (
+	coverageSelectorWeight  = 0.5
+	coverageTextWeight      = 0.4
+	coverageActionWeight    = 0.3
+	coverageAssertWeight    = 0.2
+	coverageAnyAssertWeight = 0.05 // an assertion that doesn't check the expected result
+
+	// assertionWindow is how many lines after an interaction an assertion
+	// is taken to check its result
+	assertionWindow = 6
+
+	// interactionWindow is how many lines after finding an element a test
+	// is taken to act on it, in a chained call or through a variable
+	interactionWindow = 4
+)
+
+// quotedArg matches a quoted string argument, in any of JavaScript's quotes
+const quotedArg = `\(\s*(?:'([^']*)'|"([^"]*)"|` + "`([^`]*)`)"
+
+var (
+	// testSelectorPattern matches calls taking a CSS selector
+	testSelectorPattern = regexp.MustCompile(`\b(?:locator|get|find|querySelector|querySelectorAll|waitForSelector|\$|\$\$|click|dblclick|fill|type|check|uncheck|hover|selectOption|setInputFiles)` + quotedArg)
+
+	// testIDPattern matches getByTestId calls
+	testIDPattern = regexp.MustCompile(`\bgetByTestId` + quotedArg)
+
+	// testTextPattern matches calls finding an element by what it says
+	testTextPattern = regexp.MustCompile(`\b(?:getByText|getByLabel|getByPlaceholder|getByTitle|getByAltText|contains)` + quotedArg + `|\bname:\s*(?:'([^']*)'|"([^"]*)"|` + "`([^`]*)`)")
+
+	// testInteractionPattern matches the interactions a test performs
+	testInteractionPattern = regexp.MustCompile(`\.(click|dblclick|tap|check|uncheck|press|submit|fill|type|clear|selectOption|select|setInputFiles|selectFile|hover|trigger)\(`)
+
+	// testAssertionPattern matches assertions in Playwright, Cypress, Jest and Selenium tests
+	testAssertionPattern = regexp.MustCompile(`\bexpect\(|\.should\(|\bassert\w*\(|\.to(?:Be|Have|Contain|Equal)\w*\(`)
+
+	// testBindingPattern matches a line assigning a variable, as tests do
+	// with the elements they find
+	testBindingPattern = regexp.MustCompile(`^\s*(?:(?:const|let|var|final)\s+)?(?:\w+\s+)?(\w+)\s*=[^=]`)
+
+	// expectedQuotePattern matches the quoted texts of an expected result
+	expectedQuotePattern = regexp.MustCompile(`"([^"]+)"`)
+
+	// expectedURLPattern matches the path of an expected URL change
+	expectedURLPattern = regexp.MustCompile(`URL changes to (\S+)`)
+)
+
+// actionVerbs maps an action to the test interactions that perform it
+var actionVerbs = map[string][]string{
+	"click":  {"click", "dblclick", "tap", "check", "uncheck", "press", "submit", "trigger"},
+	"submit": {"click", "submit", "press"},
+	"fill":   {"fill", "type", "clear", "setInputFiles", "selectFile"},
+	"type":   {"fill", "type", "clear"},
+	"select": {"selectOption", "select"},
+	"hover":  {"hover", "trigger"},
+	"upload": {"setInputFiles", "selectFile"},
+}
+
+// ActionCoverage is how well existing tests cover one action
+type ActionCoverage struct {
+	Action DiscoveredAction `json:"action"`
+	Status string           `json:"status"` // CoverageCovered, CoveragePartial or CoverageUntested
+	Score  float64          `json:"score"`  // 0 to 1
+	Test   int              `json:"test"`   // index in tests of the best match, -1 if none
+	Line   int              `json:"line"`   // line of the best match in that test, 1-based
+	Reason string           `json:"reason"` // what matched, or what's missing
+}
+
+// CoverageReport is how well existing tests cover a page's actions
+type CoverageReport struct {
+	Actions  []ActionCoverage `json:"actions"`
+	Covered  int              `json:"covered"`
+	Partial  int              `json:"partial"`
+	Untested int              `json:"untested"`
+}
+
+// testLine is what one line of a test refers to and does
+type testLine struct {
+	selectors    []string
+	texts        []string
+	interactions []string
+	assertion    bool
+	lower        string
+	binding      string // variable the line assigns, lowercased
+	chained      bool   // the line continues a call chain, like ".click()"
+}
+
+// parsedTest is a test file broken into lines
+type parsedTest []testLine
+
+// CoverageReport matches each action against the contents of existing test
+// files. A test referencing the action's selector, or failing that its text,
+// partially covers it; one that also performs the action and asserts the
+// expected result right after covers it. A test that uses the selector but
+// asserts something other than the action's expected result is only partial.
+func (ad *ActionDiscovery) CoverageReport(actions []DiscoveredAction, tests []string) CoverageReport {
+	parsed := parseTests(tests)
+	report := CoverageReport{Actions: make([]ActionCoverage, 0, len(actions))}
+	for _, action := range actions {
+		coverage := actionCoverage(action, parsed)
+		switch coverage.Status {
+		case CoverageCovered:
+			report.Covered++
+		case CoveragePartial:
+			report.Partial++
+		default:
+			report.Untested++
+		}
+		report.Actions = append(report.Actions, coverage)
+	}
+	return report
+}
+
+// parseTests breaks test file contents into the selectors, texts,
+// interactions and assertions on each line
+func parseTests(tests []string) []parsedTest {
+	parsed := make([]parsedTest, 0, len(tests))
+	for _, content := range tests {
+		lines := strings.Split(content, "\n")
+		test := make(parsedTest, len(lines))
+		for i, line := range lines {
+			test[i] = parseTestLine(line)
+		}
+		parsed = append(parsed, test)
+	}
+	return parsed
+}
+
+func parseTestLine(line string) testLine {
+	parsed := testLine{
+		lower:     strings.ToLower(line),
+		assertion: testAssertionPattern.MatchString(line),
+		chained:   strings.HasPrefix(strings.TrimSpace(line), "."),
+	}
+	if match := testBindingPattern.FindStringSubmatch(line); match != nil {
+		parsed.binding = strings.ToLower(match[1])
+	}
+	for _, match := range testSelectorPattern.FindAllStringSubmatch(line, -1) {
+		parsed.selectors = append(parsed.selectors, normalizeTestSelector(firstGroup(match[1:])))
+	}
+	for _, match := range testIDPattern.FindAllStringSubmatch(line, -1) {
+		parsed.selectors = append(parsed.selectors, normalizeTestSelector(fmt.Sprintf(`[data-testid="%s"]`, firstGroup(match[1:]))))
+	}
+	for _, match := range testTextPattern.FindAllStringSubmatch(line, -1) {
+		parsed.texts = append(parsed.texts, normalizeText(firstGroup(match[1:])))
+	}
+	for _, match := range testInteractionPattern.FindAllStringSubmatch(line, -1) {
+		parsed.interactions = append(parsed.interactions, match[1])
+	}
+	return parsed
+}
+
+// actionCoverage scores an action against every line of every test and
+// keeps the best match
+func actionCoverage(action DiscoveredAction, tests []parsedTest) ActionCoverage {
+	best := ActionCoverage{Action: action, Status: CoverageUntested, Test: -1, Reason: "no test references its selector or text"}
+	selector := normalizeTestSelector(action.Selector)
+	text := normalizeText(action.Element)
+	expected := expectedTerms(action.ExpectedResult)
+
+	for t, test := range tests {
+		for l, line := range test {
+			var score float64
+			var reasons []string
+			status := CoveragePartial
+			switch {
+			case selector != "" && matchesSelector(selector, line.selectors):
+				score = coverageSelectorWeight
+				reasons = append(reasons, "selector referenced")
+			case text != "" && matchesText(text, line.texts):
+				score = coverageTextWeight
+				reasons = append(reasons, "text referenced")
+			default:
+				continue
+			}
+
+			if acted := actedOn(test, l, action.Action); acted >= 0 {
+				score += coverageActionWeight
+				reasons = append(reasons, "action performed")
+				switch checked, asserted := assertsAfter(test, acted, expected); {
+				case checked:
+					score += coverageAssertWeight
+					status = CoverageCovered
+					reasons = append(reasons, "result asserted")
+				case asserted:
+					score += coverageAnyAssertWeight
+					reasons = append(reasons, "asserts something other than the expected result")
+				default:
+					reasons = append(reasons, "no assertion after it")
+				}
+			} else {
+				reasons = append(reasons, "action not performed")
+			}
+
+			if score > best.Score {
+				best.Score, best.Status = score, status
+				best.Test, best.Line = t, l+1
+				best.Reason = strings.Join(reasons, ", ")
+			}
+		}
+	}
+	return best
+}
+
+// matchesSelector reports whether a test selector is the action's, or
+// contains it when it's distinctive, like an id or attribute
+func matchesSelector(selector string, refs []string) bool {
+	for _, ref := range refs {
+		if ref == selector {
+			return true
+		}
+		if len(selector) >= 4 && strings.ContainsAny(selector[:1], "#[") && strings.Contains(ref, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesText reports whether a test finds an element by the action's text
+func matchesText(text string, refs []string) bool {
+	for _, ref := range refs {
+		if ref == text || (len(ref) >= 3 && strings.Contains(text, ref)) {
+			return true
+		}
+	}
+	return false
+}
+
+// actedOn returns the line of a test that performs action on the element
+// found on line, or -1. That's the line itself, a call chained onto it on the
+// lines right after, or a later call on the variable it assigns the element to.
+func actedOn(test parsedTest, line int, action string) int {
+	if performsAction(action, test[line].interactions) {
+		return line
+	}
+	var uses *regexp.Regexp
+	if binding := test[line].binding; binding != "" {
+		uses = regexp.MustCompile(`\b` + regexp.QuoteMeta(binding) + `\s*\.`)
+	}
+	chained := true
+	for i := line + 1; i < len(test) && i <= line+interactionWindow; i++ {
+		chained = chained && test[i].chained
+		if !chained && (uses == nil || !uses.MatchString(test[i].lower)) {
+			continue
+		}
+		if performsAction(action, test[i].interactions) {
+			return i
+		}
+	}
+	return -1
+}
+
+// performsAction reports whether a line's interactions include the action.
+// Actions without a known verb accept any interaction.
+func performsAction(action string, interactions []string) bool {
+	if len(interactions) == 0 {
+		return false
+	}
+	verbs, known := actionVerbs[strings.ToLower(action)]
+	if !known {
+		return true
+	}
+	for _, interaction := range interactions {
+		for _, verb := range verbs {
+			if interaction == verb {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// assertsAfter looks for an assertion on the interaction's line or the few
+// after it, reporting whether one checks an expected term and whether there
+// is any. With no expected terms, any assertion checks the result.
+func assertsAfter(test parsedTest, line int, expected []string) (checked, asserted bool) {
+	for i := line; i < len(test) && i <= line+assertionWindow; i++ {
+		if !test[i].assertion {
+			continue
+		}
+		asserted = true
+		if len(expected) == 0 {
+			return true, true
+		}
+		for _, term := range expected {
+			if strings.Contains(test[i].lower, term) {
+				return true, true
+			}
+		}
+	}
+	return false, asserted
+}
+
+// expectedTerms returns the URL paths and texts an expected result, as
+// written by DescribeExpectedResult, says a test should assert
+func expectedTerms(expectedResult string) []string {
+	var terms []string
+	for _, match := range expectedURLPattern.FindAllStringSubmatch(expectedResult, -1) {
+		terms = append(terms, strings.ToLower(strings.TrimSuffix(match[1], ";")))
+	}
+	for _, match := range expectedQuotePattern.FindAllStringSubmatch(expectedResult, -1) {
+		terms = append(terms, normalizeText(strings.TrimSuffix(match[1], "...")))
+	}
+	return terms
+}
+
+// normalizeTestSelector makes selectors comparable regardless of quoting
+// and spacing
+func normalizeTestSelector(selector string) string {
+	selector = strings.ReplaceAll(selector, "'", `"`)
+	return strings.Join(strings.Fields(selector), " ")
+}
+
+// normalizeText lowercases text and collapses its whitespace
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// firstGroup returns the first non-empty submatch
+func firstGroup(groups []string) string {
+	for _, group := range groups {
+		if group != "" {
+			return group
+		}
+	}
+	return ""
+}
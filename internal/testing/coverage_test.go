@@ -0,0 +1,118 @@
+package testing
+
+import "testing"
+
+func TestCoverageReportScoresActions(t *testing.T) {
+	signIn := DiscoveredAction{
+		Element:        "Sign in",
+		Selector:       "#login",
+		Action:         "click",
+		ExpectedResult: "URL changes to /dashboard",
+	}
+
+	tests := []struct {
+		name   string
+		test   string
+		status string
+		score  float64
+	}{
+		{
+			name: "same line",
+			test: `await page.locator('#login').click();
+await expect(page).toHaveURL('/dashboard');`,
+			status: CoverageCovered,
+			score:  coverageSelectorWeight + coverageActionWeight + coverageAssertWeight,
+		},
+		{
+			name: "chained on the next line",
+			test: `cy.get("#login")
+  .click();
+cy.url().should('include', '/dashboard');`,
+			status: CoverageCovered,
+			score:  coverageSelectorWeight + coverageActionWeight + coverageAssertWeight,
+		},
+		{
+			name: "through a variable",
+			test: `const button = page.locator('#login');
+await button.click();
+await expect(page).toHaveURL(/dashboard/);`,
+			status: CoverageCovered,
+			score:  coverageSelectorWeight + coverageActionWeight + coverageAssertWeight,
+		},
+		{
+			name: "by text",
+			test: `await page.getByText('Sign in').click();
+await expect(page).toHaveURL('/dashboard');`,
+			status: CoverageCovered,
+			score:  coverageTextWeight + coverageActionWeight + coverageAssertWeight,
+		},
+		{
+			name: "asserts something else",
+			test: `await page.click('#login');
+await expect(page.locator('h1')).toHaveText('Welcome');`,
+			status: CoveragePartial,
+			score:  coverageSelectorWeight + coverageActionWeight + coverageAnyAssertWeight,
+		},
+		{
+			name:   "no assertion",
+			test:   `await page.click('#login');`,
+			status: CoveragePartial,
+			score:  coverageSelectorWeight + coverageActionWeight,
+		},
+		{
+			name:   "only referenced",
+			test:   `await expect(page.locator('#login')).toBeVisible();`,
+			status: CoveragePartial,
+			score:  coverageSelectorWeight,
+		},
+		{
+			name: "another element acted on",
+			test: `const button = page.locator('#login');
+const other = page.locator('#signup');
+await other.click();`,
+			status: CoveragePartial,
+			score:  coverageSelectorWeight,
+		},
+		{
+			name:   "not referenced",
+			test:   `await page.click('#signup');`,
+			status: CoverageUntested,
+		},
+	}
+
+	ad := NewActionDiscovery(nil, ".")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := ad.CoverageReport([]DiscoveredAction{signIn}, []string{tt.test})
+			got := report.Actions[0]
+			if got.Status != tt.status {
+				t.Errorf("status = %q, want %q (%s)", got.Status, tt.status, got.Reason)
+			}
+			if diff := got.Score - tt.score; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("score = %v, want %v (%s)", got.Score, tt.score, got.Reason)
+			}
+			if tested := ad.isActionTested(signIn, []string{tt.test}); tested != (tt.status == CoverageCovered) {
+				t.Errorf("isActionTested = %v, want %v", tested, tt.status == CoverageCovered)
+			}
+		})
+	}
+}
+
+func TestCoverageReportCounts(t *testing.T) {
+	actions := []DiscoveredAction{
+		{Element: "Sign in", Selector: "#login", Action: "click"},
+		{Element: "Email", Selector: "#email", Action: "fill"},
+		{Element: "Help", Selector: "#help", Action: "click"},
+	}
+	test := `await page.fill('#email', 'ada@example.com');
+await page.click('#login');
+await expect(page).toHaveURL('/dashboard');`
+
+	report := NewActionDiscovery(nil, ".").CoverageReport(actions, []string{test})
+	if report.Covered != 2 || report.Partial != 0 || report.Untested != 1 {
+		t.Errorf("covered, partial, untested = %d, %d, %d, want 2, 0, 1", report.Covered, report.Partial, report.Untested)
+	}
+	if got := report.Actions[1]; got.Test != 0 || got.Line != 1 {
+		t.Errorf("email matched test %d line %d, want test 0 line 1", got.Test, got.Line)
+	}
+}
@@ -0,0 +1,224 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// defaultCrawlConcurrency is how many pages CrawlAndDiscover analyzes at once
+// when Concurrency isn't set
+const defaultCrawlConcurrency = 3
+
+// PageActions are the actions discovered on one crawled page
+type PageActions struct {
+	URL     string
+	Title   string
+	Depth   int // links followed from the start page
+	Actions []DiscoveredAction
+	Error   error // why the page couldn't be loaded or analyzed
+}
+
+// crawlTarget is a page waiting to be crawled
+type crawlTarget struct {
+	url   string
+	depth int
+}
+
+// CrawlAndDiscover discovers actions on startURL and the same-origin pages
+// linked from it, breadth first, down to maxDepth links away and up to
+// maxPages pages. The browser has a single page, so pages are loaded one at
+// a time, but up to Concurrency of them are analyzed at once. OnPage is
+// called as each page completes. Cancelling ctx, or its deadline passing,
+// stops the crawl and returns the pages finished so far with ctx's error.
+func (ad *ActionDiscovery) CrawlAndDiscover(ctx context.Context, startURL string, maxDepth, maxPages int) ([]PageActions, error) {
+	if ad.Browser == nil {
+		return nil, fmt.Errorf("crawling needs a browser")
+	}
+	if maxPages <= 0 {
+		return nil, fmt.Errorf("maxPages must be positive, got %d", maxPages)
+	}
+	start, err := crawlURL(startURL)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := ad.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultCrawlConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make([]PageActions, 0, maxPages)
+	)
+	finish := func(i int, page PageActions) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = page
+		if ad.OnPage != nil {
+			ad.OnPage(page)
+		}
+	}
+
+	visited := map[string]bool{start.String(): true}
+	queue := []crawlTarget{{url: start.String()}}
+	for len(queue) > 0 && len(results) < maxPages && ctx.Err() == nil {
+		target := queue[0]
+		queue = queue[1:]
+
+		page, html := ad.loadCrawlPage(target)
+		mu.Lock()
+		results = append(results, page)
+		i := len(results) - 1
+		mu.Unlock()
+		if page.Error != nil {
+			finish(i, page)
+			continue
+		}
+
+		if target.depth < maxDepth {
+			for _, link := range crawlLinks(html, page.URL, start) {
+				if visited[link.url] {
+					continue
+				}
+				visited[link.url] = true
+				if ad.Follow != nil && !ad.Follow(link.text, link.url) {
+					continue
+				}
+				queue = append(queue, crawlTarget{url: link.url, depth: target.depth + 1})
+			}
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			finish(i, PageActions{URL: page.URL, Title: page.Title, Depth: page.Depth, Error: ctx.Err()})
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			page.Actions, page.Error = ad.discoverPage(ctx, html, nil)
+			finish(i, page)
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return results, fmt.Errorf("crawl stopped after %d pages: %w", len(results), err)
+	}
+	return results, nil
+}
+
+// loadCrawlPage navigates to target and reads the page it lands on
+func (ad *ActionDiscovery) loadCrawlPage(target crawlTarget) (PageActions, string) {
+	page := PageActions{URL: target.url, Depth: target.depth}
+	if err := ad.Browser.Navigate(target.url); err != nil {
+		page.Error = err
+		return page, ""
+	}
+	if err := ad.Browser.WaitForPageLoad(ad.Browser.Timeouts().Navigation); err != nil {
+		page.Error = err
+		return page, ""
+	}
+	html, err := ad.Browser.GetPageHTML()
+	if err != nil {
+		page.Error = fmt.Errorf("failed to read page: %w", err)
+		return page, ""
+	}
+	// Links resolve against where redirects ended up
+	if landed, title, err := ad.Browser.GetPageInfo(); err == nil {
+		page.URL, page.Title = landed, title
+	}
+	return page, html
+}
+
+// discoverPage finds the actions in a page's HTML, with the LLM when there is
+// one and from its interactive elements otherwise. Actions found in the
+// contents of existingTests are marked IsTested.
+func (ad *ActionDiscovery) discoverPage(ctx context.Context, html string, existingTests []string) ([]DiscoveredAction, error) {
+	if ad.llmClient != nil {
+		actions, _, _, err := ad.DiscoverActionsFromHTML(ctx, html, existingTests)
+		return actions, err
+	}
+
+	elements, err := browser.ExtractInteractiveElements(html)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract elements: %w", err)
+	}
+	var actions []DiscoveredAction
+	for _, elem := range elements {
+		text := strings.Join(strings.Fields(elem.Text), " ")
+		if text == "" {
+			continue
+		}
+		action := DiscoveredAction{
+			Description: text,
+			Element:     text,
+			Selector:    elem.Selector,
+			Action:      "click",
+			Priority:    "low",
+		}
+		action.IsTested = ad.isActionTested(action, existingTests)
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// crawlLink is a link found on a crawled page
+type crawlLink struct {
+	url  string
+	text string
+}
+
+// crawlLinks returns the links in html that share start's origin, resolved
+// against pageURL and without fragments
+func crawlLinks(html, pageURL string, start *url.URL) []crawlLink {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	elements, err := browser.ExtractInteractiveElements(html)
+	if err != nil {
+		return nil
+	}
+
+	var links []crawlLink
+	for _, elem := range elements {
+		if elem.Href == "" {
+			continue
+		}
+		ref, err := url.Parse(strings.TrimSpace(elem.Href))
+		if err != nil {
+			continue
+		}
+		link := base.ResolveReference(ref)
+		link.Fragment = ""
+		if link.Scheme != start.Scheme || !strings.EqualFold(link.Host, start.Host) {
+			continue
+		}
+		links = append(links, crawlLink{url: link.String(), text: strings.Join(strings.Fields(elem.Text), " ")})
+	}
+	return links
+}
+
+// crawlURL parses the URL a crawl starts from
+func crawlURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("start URL %q must be http or https", rawURL)
+	}
+	u.Fragment = ""
+	return u, nil
+}
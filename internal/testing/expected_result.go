@@ -0,0 +1,102 @@
+package testing
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// Limits on how much of a page change becomes an expected result
+const (
+	maxExpectedTexts    = 2
+	maxExpectedElements = 2
+	maxExpectedTextLen  = 60
+)
+
+// expectedElementTags are the elements worth asserting on when they appear
+var expectedElementTags = []string{"button", "a", "input", "select", "textarea", "form", "dialog", "h1", "h2", "h3"}
+
+// DescribeExpectedResult turns what an action was seen to do to the page into
+// the post-conditions a test should assert: a URL change, text that became
+// visible and elements that appeared. diff is the change to the simplified
+// HTML, as returned by browser.HTMLDiffer.Diff. It returns "" when nothing
+// observable changed.
+func DescribeExpectedResult(beforeURL, afterURL string, diff []browser.DiffLine) string {
+	var conditions []string
+	if afterURL != "" && !sameURL(beforeURL, afterURL) {
+		conditions = append(conditions, fmt.Sprintf("URL changes to %s", urlPath(afterURL)))
+	}
+
+	var texts, elements []string
+	for i, line := range diff {
+		if !line.Added {
+			continue
+		}
+		text := strings.TrimSpace(line.Text)
+		if !strings.HasPrefix(text, "<") {
+			if len(texts) < maxExpectedTexts && len(text) >= 3 {
+				texts = append(texts, fmt.Sprintf("text %q is visible", truncateExpected(text)))
+			}
+			continue
+		}
+
+		tag := openingTag(text)
+		if tag == "" || len(elements) >= maxExpectedElements || !slices.Contains(expectedElementTags, tag) {
+			continue
+		}
+		// Name the element by its text when the next added line holds it
+		if i+1 < len(diff) && diff[i+1].Added && !strings.HasPrefix(strings.TrimSpace(diff[i+1].Text), "<") {
+			elements = append(elements, fmt.Sprintf("%s %q appears", tag, truncateExpected(strings.TrimSpace(diff[i+1].Text))))
+		} else {
+			elements = append(elements, fmt.Sprintf("a %s appears", tag))
+		}
+	}
+
+	conditions = append(conditions, texts...)
+	conditions = append(conditions, elements...)
+	return strings.Join(conditions, "; ")
+}
+
+// openingTag returns the name of the tag an HTML line opens, or "" for a
+// closing tag
+func openingTag(line string) string {
+	if strings.HasPrefix(line, "</") {
+		return ""
+	}
+	name := strings.TrimPrefix(line, "<")
+	if end := strings.IndexAny(name, " />"); end >= 0 {
+		name = name[:end]
+	}
+	return strings.ToLower(name)
+}
+
+// sameURL reports whether two URLs differ only in their fragment
+func sameURL(a, b string) bool {
+	a, _, _ = strings.Cut(a, "#")
+	b, _, _ = strings.Cut(b, "#")
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+// urlPath returns the path and query of a URL, which is what a test asserts
+// on since the host changes between environments
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return rawURL
+	}
+	if u.RawQuery != "" {
+		return u.Path + "?" + u.RawQuery
+	}
+	return u.Path
+}
+
+func truncateExpected(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= maxExpectedTextLen {
+		return text
+	}
+	return strings.ToValidUTF8(text[:maxExpectedTextLen-3], "") + "..."
+}
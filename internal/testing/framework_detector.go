@@ -1,7 +1,6 @@
 package testing
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -40,18 +39,15 @@ func NewFrameworkDetector(projectRoot string) *FrameworkDetector {
 func (fd *FrameworkDetector) DetectFramework() (*E2EFramework, error) {
 	// Try different detection methods in order of reliability
 	
-	// 1. Check package.json for framework dependencies
-	if framework := fd.checkPackageJSON(); framework != nil {
-		fd.enhanceWithConfigFiles(framework)
-		return framework, nil
-	}
-	
-	// 2. Check for framework config files
-	if framework := fd.checkConfigFiles(); framework != nil {
-		return framework, nil
+	// 1. Check package.json dependencies, go.mod and config files across the
+	// project, monorepo packages included
+	if detected, err := DetectProjectFramework(fd.projectRoot); err == nil {
+		if candidate, ok := detected.E2E(); ok {
+			return fd.frameworkFromCandidate(candidate), nil
+		}
 	}
 	
-	// 3. Check test directories for framework patterns
+	// 2. Check test directories for framework patterns
 	if framework := fd.checkTestDirectories(); framework != nil {
 		return framework, nil
 	}
@@ -60,155 +56,25 @@ func (fd *FrameworkDetector) DetectFramework() (*E2EFramework, error) {
 	return nil, fmt.Errorf("could not auto-detect E2E testing framework")
 }
 
-// checkPackageJSON examines package.json for framework dependencies
-func (fd *FrameworkDetector) checkPackageJSON() *E2EFramework {
-	packageJSONPath := filepath.Join(fd.projectRoot, "package.json")
-	
-	data, err := os.ReadFile(packageJSONPath)
-	if err != nil {
-		return nil // No package.json found
+// frameworkFromCandidate fills in an E2EFramework for a detected candidate,
+// using the framework's defaults where detection found nothing
+func (fd *FrameworkDetector) frameworkFromCandidate(candidate FrameworkCandidate) *E2EFramework {
+	framework := fd.createFrameworkFromName(candidate.Name)
+	if framework == nil {
+		framework = &E2EFramework{Name: candidate.Name, DisplayName: candidate.DisplayName}
 	}
-	
-	var pkg struct {
-		Dependencies    map[string]string `json:"dependencies"`
-		DevDependencies map[string]string `json:"devDependencies"`
-		Scripts         map[string]string `json:"scripts"`
+	framework.Version = candidate.Version
+	framework.Language = candidate.Language
+	if candidate.ConfigFile != "" {
+		framework.ConfigFile = candidate.ConfigFile
 	}
-	
-	if err := json.Unmarshal(data, &pkg); err != nil {
-		return nil
-	}
-	
-	// Merge dependencies for checking
-	allDeps := make(map[string]string)
-	for name, version := range pkg.Dependencies {
-		allDeps[name] = version
+	if candidate.RunCommand != "" {
+		framework.RunCommand = candidate.RunCommand
 	}
-	for name, version := range pkg.DevDependencies {
-		allDeps[name] = version
+	if candidate.Dir != "." && framework.TestDir != "" {
+		framework.TestDir = filepath.ToSlash(filepath.Join(candidate.Dir, framework.TestDir))
 	}
-	
-	// Framework detection patterns
-	detectionMap := map[string]*E2EFramework{
-		"@playwright/test": {
-			Name:        "playwright",
-			DisplayName: "Playwright",
-			RunCommand:  "npx playwright test",
-			ConfigFile:  "playwright.config.ts",
-			TestDir:     "tests",
-			Extensions:  []string{".spec.ts", ".spec.js", ".test.ts", ".test.js"},
-		},
-		"cypress": {
-			Name:        "cypress",
-			DisplayName: "Cypress", 
-			RunCommand:  "npx cypress run",
-			ConfigFile:  "cypress.config.js",
-			TestDir:     "cypress/e2e",
-			Extensions:  []string{".cy.ts", ".cy.js", ".spec.ts", ".spec.js"},
-		},
-		"selenium-webdriver": {
-			Name:        "selenium",
-			DisplayName: "Selenium WebDriver",
-			RunCommand:  "npm test",
-			TestDir:     "test",
-			Extensions:  []string{".test.js", ".spec.js"},
-		},
-		"puppeteer": {
-			Name:        "puppeteer",
-			DisplayName: "Puppeteer",
-			RunCommand:  "npm test",
-			TestDir:     "test",
-			Extensions:  []string{".test.js", ".spec.js"},
-		},
-		"webdriverio": {
-			Name:        "webdriverio",
-			DisplayName: "WebdriverIO",
-			RunCommand:  "npx wdio run",
-			ConfigFile:  "wdio.conf.js",
-			TestDir:     "test/specs",
-			Extensions:  []string{".e2e.js", ".spec.js"},
-		},
-		"nightwatch": {
-			Name:        "nightwatch",
-			DisplayName: "Nightwatch.js",
-			RunCommand:  "npx nightwatch",
-			ConfigFile:  "nightwatch.conf.js",
-			TestDir:     "tests",
-			Extensions:  []string{".js"},
-		},
-		"testcafe": {
-			Name:        "testcafe",
-			DisplayName: "TestCafe",
-			RunCommand:  "npx testcafe",
-			TestDir:     "tests",
-			Extensions:  []string{".js", ".ts"},
-		},
-	}
-	
-	// Check for framework dependencies
-	for dep, framework := range detectionMap {
-		if version, exists := allDeps[dep]; exists {
-			framework.Version = cleanVersion(version)
-			
-			// Detect language from project structure
-			framework.Language = fd.detectLanguage()
-			
-			// Try to infer run command from scripts
-			if runCmd := fd.inferRunCommand(pkg.Scripts, framework.Name); runCmd != "" {
-				framework.RunCommand = runCmd
-			}
-			
-			return framework
-		}
-	}
-	
-	return nil
-}
-
-// checkConfigFiles looks for framework-specific configuration files
-func (fd *FrameworkDetector) checkConfigFiles() *E2EFramework {
-	configPatterns := map[string]*E2EFramework{
-		"playwright.config.*": {
-			Name:        "playwright",
-			DisplayName: "Playwright",
-			RunCommand:  "npx playwright test",
-		},
-		"cypress.config.*": {
-			Name:        "cypress", 
-			DisplayName: "Cypress",
-			RunCommand:  "npx cypress run",
-		},
-		"wdio.conf.*": {
-			Name:        "webdriverio",
-			DisplayName: "WebdriverIO", 
-			RunCommand:  "npx wdio run",
-		},
-		"nightwatch.conf.*": {
-			Name:        "nightwatch",
-			DisplayName: "Nightwatch.js",
-			RunCommand:  "npx nightwatch",
-		},
-		"testcafe.config.*": {
-			Name:        "testcafe",
-			DisplayName: "TestCafe",
-			RunCommand:  "npx testcafe",
-		},
-		"jest-puppeteer.config.*": {
-			Name:        "jest-puppeteer",
-			DisplayName: "Jest + Puppeteer",
-			RunCommand:  "npm test",
-		},
-	}
-	
-	for pattern, framework := range configPatterns {
-		if files := fd.globFiles(pattern); len(files) > 0 {
-			framework.ConfigFile = files[0]
-			framework.Language = fd.detectLanguage()
-			return framework
-		}
-	}
-	
-	return nil
+	return framework
 }
 
 // checkTestDirectories examines test directory structures for framework patterns
@@ -415,72 +281,60 @@ func (fd *FrameworkDetector) inferRunCommand(scripts map[string]string, framewor
 	return ""
 }
 
-func (fd *FrameworkDetector) enhanceWithConfigFiles(framework *E2EFramework) {
-	// Try to find actual config file
-	if framework.ConfigFile != "" {
-		configPath := filepath.Join(fd.projectRoot, framework.ConfigFile)
-		if _, err := os.Stat(configPath); err == nil {
-			// Config file exists, we're good
-			return
-		}
-	}
-	
-	// Try to find alternative config files
-	alternatives := fd.getConfigAlternatives(framework.Name)
-	for _, alt := range alternatives {
-		if _, err := os.Stat(filepath.Join(fd.projectRoot, alt)); err == nil {
-			framework.ConfigFile = alt
-			break
-		}
-	}
-}
-
-func (fd *FrameworkDetector) getConfigAlternatives(frameworkName string) []string {
-	alternatives := map[string][]string{
-		"playwright": {"playwright.config.js", "playwright.config.ts"},
-		"cypress":    {"cypress.config.js", "cypress.config.ts", "cypress.json"},
-		"webdriverio": {"wdio.conf.js", "wdio.conf.ts"},
-	}
-	
-	return alternatives[frameworkName]
-}
-
 func (fd *FrameworkDetector) createFrameworkFromName(name string) *E2EFramework {
 	frameworks := map[string]*E2EFramework{
 		"playwright": {
 			Name:        "playwright",
 			DisplayName: "Playwright",
 			RunCommand:  "npx playwright test",
+			ConfigFile:  "playwright.config.ts",
+			TestDir:     "tests",
+			Extensions:  []string{".spec.ts", ".spec.js", ".test.ts", ".test.js"},
 		},
 		"cypress": {
 			Name:        "cypress",
 			DisplayName: "Cypress",
 			RunCommand:  "npx cypress run",
+			ConfigFile:  "cypress.config.js",
+			TestDir:     "cypress/e2e",
+			Extensions:  []string{".cy.ts", ".cy.js", ".spec.ts", ".spec.js"},
 		},
 		"selenium": {
 			Name:        "selenium", 
 			DisplayName: "Selenium WebDriver",
 			RunCommand:  "npm test",
+			TestDir:     "test",
+			Extensions:  []string{".test.js", ".spec.js"},
 		},
 		"puppeteer": {
 			Name:        "puppeteer",
 			DisplayName: "Puppeteer",
 			RunCommand:  "npm test",
+			TestDir:     "test",
+			Extensions:  []string{".test.js", ".spec.js"},
 		},
 		"webdriverio": {
 			Name:        "webdriverio",
 			DisplayName: "WebdriverIO",
 			RunCommand:  "npx wdio run",
+			ConfigFile:  "wdio.conf.js",
+			TestDir:     "test/specs",
+			Extensions:  []string{".e2e.js", ".spec.js"},
 		},
 		"testcafe": {
 			Name:        "testcafe",
 			DisplayName: "TestCafe",
 			RunCommand:  "npx testcafe",
+			TestDir:     "tests",
+			Extensions:  []string{".js", ".ts"},
 		},
 		"nightwatch": {
 			Name:        "nightwatch",
 			DisplayName: "Nightwatch.js",
 			RunCommand:  "npx nightwatch",
+			ConfigFile:  "nightwatch.conf.js",
+			TestDir:     "tests",
+			Extensions:  []string{".js"},
 		},
 	}
 	
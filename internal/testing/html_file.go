@@ -0,0 +1,30 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DiscoverActionsFromFile discovers the actions in a saved HTML file without
+// a browser. Nothing can be checked against a live page, so the actions are
+// marked Unverified. Actions found in existingTests are marked IsTested.
+func (ad *ActionDiscovery) DiscoverActionsFromFile(ctx context.Context, path string, existingTests []string) ([]DiscoveredAction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML file: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, fmt.Errorf("HTML file %s is empty", path)
+	}
+
+	actions, err := ad.discoverPage(ctx, string(data), existingTests)
+	if err != nil {
+		return nil, err
+	}
+	for i := range actions {
+		actions[i].Unverified = true
+	}
+	return actions, nil
+}
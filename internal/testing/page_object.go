@@ -0,0 +1,97 @@
+package testing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Test output styles, set with testing.style
+const (
+	TestStyleInline     = "inline"     // selectors written into each test
+	TestStylePageObject = "pageobject" // a Page Object class, then a spec using it
+)
+
+// maxMethodNameWords keeps method names readable when an element's label is
+// a whole sentence
+const maxMethodNameWords = 4
+
+// selectorNamePattern picks a name out of selectors like #email,
+// [name="email"] or [aria-label="Email"]
+var selectorNamePattern = regexp.MustCompile(`(?:#|(?:name|aria-label|data-testid|id)=["']?)([\w-]+)`)
+
+// pageObjectMethodNames names a Page Object method for each action, from its
+// accessible name or label, e.g. clickSignIn or fillEmail. Actions that would
+// share a name get a numeric suffix: clickSave, clickSave2.
+func pageObjectMethodNames(actions []DiscoveredAction) []string {
+	names := make([]string, len(actions))
+	used := make(map[string]bool)
+	for i, action := range actions {
+		base := methodVerb(action.Action) + camelWords(accessibleName(action))
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// accessibleName returns what a user would call an element: its text or
+// label, then its description, then a name taken from its selector
+func accessibleName(action DiscoveredAction) string {
+	if name := strings.TrimSpace(action.Element); name != "" {
+		return name
+	}
+	if name := strings.TrimSpace(action.Description); name != "" {
+		return name
+	}
+	if match := selectorNamePattern.FindStringSubmatch(action.Selector); match != nil {
+		return match[1]
+	}
+	return "element"
+}
+
+// methodVerb starts a method name with what the method does to the element
+func methodVerb(action string) string {
+	switch strings.ToLower(action) {
+	case "type", "fill", "form_input":
+		return "fill"
+	case "select":
+		return "select"
+	case "navigate":
+		return "goTo"
+	case "submit", "form_submit":
+		return "submit"
+	default:
+		return "click"
+	}
+}
+
+// camelWords turns a name into the capitalized words of a method name,
+// dropping punctuation: "Sign in →" becomes "SignIn"
+func camelWords(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxMethodNameWords {
+		words = words[:maxMethodNameWords]
+	}
+
+	var b strings.Builder
+	for _, word := range words {
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	if b.Len() == 0 {
+		return "Element"
+	}
+	// Identifiers can't start with a digit
+	if unicode.IsDigit([]rune(b.String())[0]) {
+		return "Element" + b.String()
+	}
+	return b.String()
+}
@@ -0,0 +1,308 @@
+package testing
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Framework kinds
+const (
+	FrameworkKindE2E  = "e2e"
+	FrameworkKindUnit = "unit"
+)
+
+// maxDetectionDepth is how many directories below the root are searched for
+// monorepo packages
+const maxDetectionDepth = 3
+
+// skippedDetectionDirs are never searched for packages
+var skippedDetectionDirs = map[string]bool{
+	"node_modules": true,
+	".git":         true,
+	".tod":         true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	".next":        true,
+	"coverage":     true,
+}
+
+// Framework is what DetectProjectFramework found in a project
+type Framework struct {
+	// Candidates holds every framework detected, most likely first: E2E
+	// frameworks before unit test runners, then by confidence
+	Candidates []FrameworkCandidate
+}
+
+// FrameworkCandidate is a test framework detected in one package of a project
+type FrameworkCandidate struct {
+	Name        string   // "playwright", "cypress", "jest", ...
+	DisplayName string   // "Playwright"
+	Kind        string   // FrameworkKindE2E or FrameworkKindUnit
+	Version     string   // from package.json or go.mod, if listed there
+	Language    string   // "typescript", "javascript" or "go"
+	Dir         string   // package directory relative to the root, "." for the root
+	ConfigFile  string   // config file relative to the root, if one was found
+	RunCommand  string   // script that runs the tests, if package.json has one
+	Evidence    []string // what the detection is based on
+	Confidence  float64  // 0-1
+}
+
+// Primary returns the most likely framework
+func (f Framework) Primary() FrameworkCandidate {
+	return f.Candidates[0]
+}
+
+// E2E returns the most likely E2E framework
+func (f Framework) E2E() (FrameworkCandidate, bool) {
+	for _, c := range f.Candidates {
+		if c.Kind == FrameworkKindE2E {
+			return c, true
+		}
+	}
+	return FrameworkCandidate{}, false
+}
+
+// Names returns the distinct names of the detected frameworks, most likely first
+func (f Framework) Names() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, c := range f.Candidates {
+		if !seen[c.Name] {
+			seen[c.Name] = true
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// knownFramework describes how to recognize a JavaScript test framework
+type knownFramework struct {
+	Name        string
+	DisplayName string
+	Kind        string
+	Packages    []string // dependencies; a trailing "/*" matches a scope
+	ConfigFiles []string
+}
+
+// knownFrameworks are the JavaScript test frameworks DetectProjectFramework recognizes
+var knownFrameworks = []knownFramework{
+	{"playwright", "Playwright", FrameworkKindE2E, []string{"@playwright/test", "playwright"}, []string{"playwright.config.ts", "playwright.config.js", "playwright.config.mjs"}},
+	{"cypress", "Cypress", FrameworkKindE2E, []string{"cypress"}, []string{"cypress.config.ts", "cypress.config.js", "cypress.config.mjs", "cypress.json"}},
+	{"testcafe", "TestCafe", FrameworkKindE2E, []string{"testcafe"}, []string{".testcaferc.json", ".testcaferc.js"}},
+	{"webdriverio", "WebdriverIO", FrameworkKindE2E, []string{"webdriverio", "@wdio/cli"}, []string{"wdio.conf.js", "wdio.conf.ts"}},
+	{"nightwatch", "Nightwatch.js", FrameworkKindE2E, []string{"nightwatch"}, []string{"nightwatch.conf.js"}},
+	{"puppeteer", "Puppeteer", FrameworkKindE2E, []string{"puppeteer"}, []string{"jest-puppeteer.config.js"}},
+	{"selenium", "Selenium WebDriver", FrameworkKindE2E, []string{"selenium-webdriver"}, nil},
+	{"jest", "Jest", FrameworkKindUnit, []string{"jest"}, []string{"jest.config.js", "jest.config.ts", "jest.config.mjs"}},
+	{"vitest", "Vitest", FrameworkKindUnit, []string{"vitest"}, []string{"vitest.config.ts", "vitest.config.js", "vitest.config.mts"}},
+	{"testing-library", "Testing Library", FrameworkKindUnit, []string{"@testing-library/*"}, nil},
+}
+
+// goFrameworks are Go browser automation modules, by module path
+var goFrameworks = map[string]knownFramework{
+	"github.com/playwright-community/playwright-go": {Name: "playwright", DisplayName: "Playwright for Go", Kind: FrameworkKindE2E},
+	"github.com/chromedp/chromedp":                  {Name: "chromedp", DisplayName: "chromedp", Kind: FrameworkKindE2E},
+	"github.com/go-rod/rod":                         {Name: "rod", DisplayName: "Rod", Kind: FrameworkKindE2E},
+}
+
+// DetectProjectFramework finds the test frameworks used in the project at
+// root by inspecting package.json dependencies, go.mod and framework config
+// files. Monorepo packages up to a few directories deep are searched too, and
+// every framework found is returned as a candidate. An error is returned if
+// none is found.
+func DetectProjectFramework(root string) (Framework, error) {
+	var framework Framework
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		if rel != "." && (skippedDetectionDirs[d.Name()] || strings.Count(rel, string(filepath.Separator)) >= maxDetectionDepth) {
+			return filepath.SkipDir
+		}
+
+		framework.Candidates = append(framework.Candidates, detectPackageFrameworks(root, rel)...)
+		framework.Candidates = append(framework.Candidates, detectGoFrameworks(root, rel)...)
+		return nil
+	})
+	if err != nil {
+		return Framework{}, fmt.Errorf("failed to search %s: %w", root, err)
+	}
+	if len(framework.Candidates) == 0 {
+		return Framework{}, fmt.Errorf("no test framework found in %s", root)
+	}
+
+	sort.SliceStable(framework.Candidates, func(i, j int) bool {
+		a, b := framework.Candidates[i], framework.Candidates[j]
+		if (a.Kind == FrameworkKindE2E) != (b.Kind == FrameworkKindE2E) {
+			return a.Kind == FrameworkKindE2E
+		}
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		return strings.Count(a.Dir, "/") < strings.Count(b.Dir, "/")
+	})
+	return framework, nil
+}
+
+// detectPackageFrameworks detects JavaScript frameworks in the package at dir
+// from its package.json dependencies and config files
+func detectPackageFrameworks(root, dir string) []FrameworkCandidate {
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+		Scripts         map[string]string `json:"scripts"`
+	}
+	hasPackage := false
+	if data, err := os.ReadFile(filepath.Join(root, dir, "package.json")); err == nil {
+		hasPackage = json.Unmarshal(data, &pkg) == nil
+	}
+
+	deps := make(map[string]string)
+	for name, version := range pkg.Dependencies {
+		deps[name] = version
+	}
+	for name, version := range pkg.DevDependencies {
+		deps[name] = version
+	}
+
+	language := "javascript"
+	if fileExists(filepath.Join(root, dir, "tsconfig.json")) || fileExists(filepath.Join(root, "tsconfig.json")) {
+		language = "typescript"
+	}
+
+	var candidates []FrameworkCandidate
+	for _, known := range knownFrameworks {
+		candidate := FrameworkCandidate{
+			Name:        known.Name,
+			DisplayName: known.DisplayName,
+			Kind:        known.Kind,
+			Language:    language,
+			Dir:         filepath.ToSlash(dir),
+		}
+
+		for _, name := range known.Packages {
+			if dep, version, ok := findDependency(deps, name); ok {
+				candidate.Version = cleanVersion(version)
+				candidate.Evidence = append(candidate.Evidence, fmt.Sprintf("%s in %s", dep, filepath.ToSlash(filepath.Join(dir, "package.json"))))
+				candidate.Confidence = 0.8
+				break
+			}
+		}
+
+		for _, file := range known.ConfigFiles {
+			path := filepath.Join(dir, file)
+			if !fileExists(filepath.Join(root, path)) {
+				continue
+			}
+			candidate.ConfigFile = filepath.ToSlash(path)
+			candidate.Evidence = append(candidate.Evidence, candidate.ConfigFile)
+			if strings.HasSuffix(file, ".ts") {
+				candidate.Language = "typescript"
+			}
+			if candidate.Confidence == 0 {
+				candidate.Confidence = 0.6
+			} else {
+				candidate.Confidence = min(candidate.Confidence+0.15, 1)
+			}
+			break
+		}
+
+		if candidate.Confidence == 0 {
+			continue
+		}
+		if hasPackage {
+			if script := NewFrameworkDetector(root).inferRunCommand(pkg.Scripts, known.Name); script != "" {
+				candidate.RunCommand = script
+				if dir != "." {
+					candidate.RunCommand = strings.Replace(script, "npm run ", fmt.Sprintf("npm --prefix %s run ", candidate.Dir), 1)
+				}
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// findDependency looks a package up in deps. A name ending in "/*" matches
+// any package in that scope.
+func findDependency(deps map[string]string, name string) (string, string, bool) {
+	if scope, ok := strings.CutSuffix(name, "/*"); ok {
+		var matches []string
+		for dep := range deps {
+			if strings.HasPrefix(dep, scope+"/") {
+				matches = append(matches, dep)
+			}
+		}
+		if len(matches) == 0 {
+			return "", "", false
+		}
+		sort.Strings(matches)
+		return matches[0], deps[matches[0]], true
+	}
+	version, ok := deps[name]
+	return name, version, ok
+}
+
+// detectGoFrameworks detects Go browser automation modules and go test in
+// the module at dir
+func detectGoFrameworks(root, dir string) []FrameworkCandidate {
+	goMod := filepath.Join(dir, "go.mod")
+	file, err := os.Open(filepath.Join(root, goMod))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	evidence := filepath.ToSlash(goMod)
+	candidates := []FrameworkCandidate{{
+		Name:        "go-test",
+		DisplayName: "go test",
+		Kind:        FrameworkKindUnit,
+		Language:    "go",
+		Dir:         filepath.ToSlash(dir),
+		RunCommand:  "go test ./...",
+		Evidence:    []string{evidence},
+		Confidence:  0.4,
+	}}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "require "))
+		if len(fields) < 2 {
+			continue
+		}
+		known, ok := goFrameworks[fields[0]]
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, FrameworkCandidate{
+			Name:        known.Name,
+			DisplayName: known.DisplayName,
+			Kind:        known.Kind,
+			Version:     strings.TrimPrefix(fields[1], "v"),
+			Language:    "go",
+			Dir:         filepath.ToSlash(dir),
+			RunCommand:  "go test ./...",
+			Evidence:    []string{fmt.Sprintf("%s in %s", fields[0], evidence)},
+			Confidence:  0.6,
+		})
+	}
+	return candidates
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,159 @@
+package testing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// testPromptTemplate describes how to ask for idiomatic tests in a framework
+type testPromptTemplate struct {
+	DisplayName string
+	Conventions []string
+	Example     string
+}
+
+// testPromptTemplates are the frameworks with dedicated test generation prompts
+var testPromptTemplates = map[string]testPromptTemplate{
+	"playwright": {
+		DisplayName: "Playwright",
+		Conventions: []string{
+			"Import test and expect from '@playwright/test' and group tests with test.describe",
+			"Start each test with await page.goto(...) and await every page call",
+			"Prefer user-facing locators (page.getByRole, page.getByText) over raw CSS",
+			"Assert with web-first assertions such as await expect(locator).toBeVisible()",
+		},
+		Example: `import { test, expect } from '@playwright/test';
+
+test.describe('Pricing', () => {
+  test('opens the pricing page', async ({ page }) => {
+    await page.goto('/');
+    await page.getByText('Pricing').click();
+    await expect(page).toHaveURL(/pricing/);
+  });
+});`,
+	},
+	"cypress": {
+		DisplayName: "Cypress",
+		Conventions: []string{
+			"Group tests with describe and it; do not import anything or use async/await",
+			"Start each test with cy.visit(...)",
+			"Chain commands: cy.get(selector).click(), cy.get(selector).type('text')",
+			"Use cy.contains(tag, text) to find elements by their text",
+			"Assert with .should(...), e.g. cy.url().should('include', '/pricing')",
+			"XPath selectors need the cypress-xpath plugin: cy.xpath(...)",
+		},
+		Example: `describe('Pricing', () => {
+  it('opens the pricing page', () => {
+    cy.visit('/');
+    cy.contains('a', 'Pricing').click();
+    cy.url().should('include', '/pricing');
+  });
+});`,
+	},
+	"testcafe": {
+		DisplayName: "TestCafe",
+		Conventions: []string{
+			"Import { Selector } from 'testcafe' and declare a fixture with .page(...)",
+			"Write tests as test('name', async t => { ... }) and await every t action",
+			"Act through the test controller: await t.click(selector), await t.typeText(selector, 'text')",
+			"Find elements by text with Selector(tag).withText(text)",
+			"Assert with await t.expect(...).ok() or .eql(...)",
+		},
+		Example: `import { Selector, ClientFunction } from 'testcafe';
+
+fixture('Pricing').page('http://localhost:3000');
+
+const getURL = ClientFunction(() => window.location.href);
+
+test('opens the pricing page', async t => {
+  await t.click(Selector('a').withText('Pricing'));
+  await t.expect(getURL()).contains('/pricing');
+});`,
+	},
+}
+
+// frameworkAliases maps package and display names to framework names
+var frameworkAliases = map[string]string{
+	"@playwright/test": "playwright",
+	"playwright-test":  "playwright",
+	"cypress.io":       "cypress",
+	"test-cafe":        "testcafe",
+}
+
+// normalizeFramework returns the canonical name of a framework
+func normalizeFramework(framework string) string {
+	name := strings.ToLower(strings.TrimSpace(framework))
+	if alias, ok := frameworkAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// containsSelectorPattern matches jQuery-style selectors such as
+// button:contains('Sign In'), which discovery prompts produce
+var containsSelectorPattern = regexp.MustCompile(`^(.*?):contains\((['"]?)(.*?)['"]?\)$`)
+
+// translateSelector turns a selector from DiscoveredAction.Selector into the
+// framework's idiomatic locator expression. CSS, XPath, text= and
+// :contains() selectors are understood; other frameworks get the selector as is.
+func translateSelector(framework, selector string) string {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return ""
+	}
+
+	var base, text, xpath string
+	switch {
+	case strings.HasPrefix(selector, "xpath="):
+		xpath = strings.TrimPrefix(selector, "xpath=")
+	case strings.HasPrefix(selector, "/"), strings.HasPrefix(selector, "(/"):
+		xpath = selector
+	case strings.HasPrefix(selector, "text="):
+		text = strings.Trim(strings.TrimPrefix(selector, "text="), `'"`)
+	default:
+		if m := containsSelectorPattern.FindStringSubmatch(selector); m != nil {
+			base, text = strings.TrimSpace(m[1]), m[3]
+		}
+	}
+
+	switch normalizeFramework(framework) {
+	case "playwright":
+		switch {
+		case xpath != "":
+			return fmt.Sprintf("page.locator(%s)", browser.QuoteJS("xpath="+xpath))
+		case text != "" && base == "":
+			return fmt.Sprintf("page.getByText(%s)", browser.QuoteJS(text))
+		case text != "":
+			return fmt.Sprintf("page.locator(%s, { hasText: %s })", browser.QuoteJS(base), browser.QuoteJS(text))
+		}
+		return fmt.Sprintf("page.locator(%s)", browser.QuoteJS(selector))
+
+	case "cypress":
+		switch {
+		case xpath != "":
+			return fmt.Sprintf("cy.xpath(%s)", browser.QuoteJS(xpath))
+		case text != "" && base == "":
+			return fmt.Sprintf("cy.contains(%s)", browser.QuoteJS(text))
+		case text != "":
+			return fmt.Sprintf("cy.contains(%s, %s)", browser.QuoteJS(base), browser.QuoteJS(text))
+		}
+		return fmt.Sprintf("cy.get(%s)", browser.QuoteJS(selector))
+
+	case "testcafe":
+		switch {
+		case xpath != "":
+			// TestCafe has no XPath support, so evaluate it in the page
+			return fmt.Sprintf("Selector(() => document.evaluate(%s, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue)", browser.QuoteJS(xpath))
+		case text != "" && base == "":
+			return fmt.Sprintf("Selector('*').withExactText(%s)", browser.QuoteJS(text))
+		case text != "":
+			return fmt.Sprintf("Selector(%s).withText(%s)", browser.QuoteJS(base), browser.QuoteJS(text))
+		}
+		return fmt.Sprintf("Selector(%s)", browser.QuoteJS(selector))
+	}
+
+	return selector
+}
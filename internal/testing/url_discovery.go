@@ -0,0 +1,102 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lance13c/tod/internal/browser"
+	"github.com/lance13c/tod/internal/llm"
+)
+
+// discoveryRetryDelay is the wait before the first retry of a failed
+// discovery; each further retry doubles it
+const discoveryRetryDelay = 500 * time.Millisecond
+
+// DiscoverActionsFromURL loads pageURL in Browser and discovers the actions
+// on the page it lands on. Actions found in existingTests are marked IsTested.
+// A failure IsRetryableDiscoveryError accepts reloads the page and prompts
+// again, up to Retries times.
+func (ad *ActionDiscovery) DiscoverActionsFromURL(ctx context.Context, pageURL string, existingTests []string) ([]DiscoveredAction, error) {
+	if ad.Browser == nil {
+		return nil, fmt.Errorf("discovering actions from a URL needs a browser")
+	}
+
+	backoff := discoveryRetryDelay
+	for attempt := 0; ; attempt++ {
+		actions, err := ad.discoverURL(ctx, pageURL, existingTests)
+		if err == nil || attempt >= ad.Retries || !IsRetryableDiscoveryError(err) {
+			return actions, err
+		}
+
+		if ad.OnRetry != nil {
+			ad.OnRetry(attempt+1, ad.Retries, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// IsRetryableDiscoveryError reports whether discovery that failed with err
+// may succeed if run again. A crashed browser is left to crash recovery, and
+// a rejected API key or request fails the same way every time, see
+// llm.IsPermanentError.
+func IsRetryableDiscoveryError(err error) bool {
+	return err != nil &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, browser.ErrBrowserCrashed) &&
+		!llm.IsPermanentError(err)
+}
+
+// discoverURL loads pageURL and discovers its actions once
+func (ad *ActionDiscovery) discoverURL(ctx context.Context, pageURL string, existingTests []string) ([]DiscoveredAction, error) {
+	page, html := ad.loadCrawlPage(crawlTarget{url: pageURL})
+	if page.Error != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", pageURL, page.Error)
+	}
+	return ad.discoverPage(ctx, html, existingTests)
+}
+
+// LoadExistingTests reads the test files under dir whose names match
+// pattern, such as "*.spec.ts". A missing dir has no tests.
+func LoadExistingTests(dir, pattern string) ([]string, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+	var tests []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if matched, _ := filepath.Match(pattern, d.Name()); !matched {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tests = append(tests, string(data))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tests in %s: %w", dir, err)
+	}
+	return tests, nil
+}
@@ -0,0 +1,33 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+func TestIsRetryableDiscoveryError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("LLM analysis failed: API request failed with status 503: overloaded"), true},
+		{fmt.Errorf("LLM analysis failed: Anthropic API request failed with status 429: slow down"), true},
+		{fmt.Errorf("LLM analysis failed: OpenAI API error (status 408): request timeout"), true},
+		{fmt.Errorf("failed to load https://example.com: %w", context.DeadlineExceeded), true},
+		{fmt.Errorf("LLM analysis failed: OpenAI API error (status 401): Incorrect API key provided"), false},
+		{fmt.Errorf("LLM analysis failed: API request failed with status 400: max_tokens is too large"), false},
+		{fmt.Errorf("LLM analysis failed: invalid x-api-key"), false},
+		{fmt.Errorf("failed to load https://example.com: %w", browser.ErrBrowserCrashed), false},
+		{context.Canceled, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryableDiscoveryError(tt.err); got != tt.want {
+			t.Errorf("IsRetryableDiscoveryError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
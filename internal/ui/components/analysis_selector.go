@@ -317,10 +317,8 @@ func calculateCostEstimate(projectRoot string, directories []string, client llm.
 		return &CostEstimate{ErrorMsg: fmt.Sprintf("cost calculation error: %v", err)}
 	}
 	
-	// Calculate cost (rough OpenAI pricing)
-	inputCost := float64(inputTokens) * 0.00015 / 1000
-	outputCost := float64(outputTokens) * 0.0006 / 1000
-	totalCost := inputCost + outputCost
+	// Price with the client's provider and model
+	totalCost := client.EstimateTokenCost(int(inputTokens), int(outputTokens))
 	
 	return &CostEstimate{
 		FileCount:    len(allFiles),
@@ -363,11 +361,8 @@ func showCostEstimate(projectRoot string, directories []string, client llm.Clien
 		return true // Continue anyway
 	}
 	
-	// Calculate cost (this should use the actual client's cost calculator)
-	// For now, use rough OpenAI pricing: $0.15/1M input tokens, $0.60/1M output tokens
-	inputCost := float64(inputTokens) * 0.00015 / 1000
-	outputCost := float64(outputTokens) * 0.0006 / 1000
-	totalCost := inputCost + outputCost
+	// Price with the client's provider and model
+	totalCost := client.EstimateTokenCost(int(inputTokens), int(outputTokens))
 	
 	// Display the estimate
 	fmt.Printf("\n%s\n", llm.FormatBatchEstimate(inputTokens, outputTokens, totalCost, len(allFiles)))
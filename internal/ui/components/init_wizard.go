@@ -65,6 +65,10 @@ type InitWizardModel struct {
 	// Context
 	cwd string
 
+	// saveErr is why the last step couldn't be saved to disk, shown in the
+	// view since bubbletea owns the terminal
+	saveErr error
+
 	// Styles
 	titleStyle       lipgloss.Style
 	stepStyle        lipgloss.Style
@@ -205,10 +209,8 @@ func (m *InitWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Save config to disk after each step (except Complete)
 				if m.currentStep != StepComplete {
-					if err := m.upsertConfigStep(); err != nil {
-						// Log error but don't halt the wizard
-						fmt.Printf("⚠️ Warning: Could not save configuration: %v\n", err)
-					}
+					// Show the error but don't halt the wizard
+					m.saveErr = m.upsertConfigStep()
 				}
 
 				if m.currentStep == StepComplete {
@@ -324,6 +326,13 @@ func (m *InitWizardModel) View() string {
 		}
 	}
 
+	if m.saveErr != nil {
+		b.WriteString("\n")
+		warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD93D"))
+		b.WriteString(warningStyle.Render(fmt.Sprintf("⚠️ Warning: Could not save configuration: %v", m.saveErr)))
+		b.WriteString("\n")
+	}
+
 	// Help text
 	help := m.buildHelpText()
 	b.WriteString("\n")
@@ -594,7 +603,8 @@ func (m *InitWizardModel) GetConfig() *config.Config {
 		Envs: map[string]config.EnvConfig{
 			m.envConfig.Name: m.envConfig,
 		},
-		Current: m.envConfig.Name,
+		Current:  m.envConfig.Name,
+		Matching: config.DefaultMatchingConfig(),
 		Meta: config.MetaConfig{
 			Version:   "1.0.0",
 			CreatedAt: now,
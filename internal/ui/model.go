@@ -61,6 +61,15 @@ func (m *Model) ShouldRestartConfig() bool {
 	return m.requestRestart
 }
 
+// ExitSummary returns the summary the navigation view left when it ended the
+// session on its own, or "" if the user quit
+func (m *Model) ExitSummary() string {
+	if m.navigationView == nil {
+		return ""
+	}
+	return m.navigationView.ExitSummary()
+}
+
 // CleanupAllViews cleans up resources from all views
 func (m *Model) CleanupAllViews() {
 	// Clean up navigation view
@@ -0,0 +1,121 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxActionPanelLines caps how many executed steps the panel shows
+const maxActionPanelLines = 8
+
+// ExecutedStep records a single action taken in the browser
+type ExecutedStep struct {
+	Verb      string    `json:"verb"`               // navigate, click, submit, command, ...
+	Target    string    `json:"target"`             // element text, command or URL
+	Selector  string    `json:"selector,omitempty"` // CSS selector used, if any
+	URL       string    `json:"url,omitempty"`      // page URL after the action
+	Result    string    `json:"result"`             // short human-readable outcome
+	Success   bool      `json:"success"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Skippable steps depend on something outside the browser, like an
+	// email, and are skipped by "tod replay"
+	Skippable bool `json:"skippable,omitempty"`
+}
+
+// recordExecutedStep appends an executed action based on the message it produced.
+// Messages that don't represent a finished action are ignored.
+func (v *NavigationView) recordExecutedStep(verb, target, selector string, msg tea.Msg) {
+	// The action runs again once confirmed, and is recorded then
+	if _, ok := msg.(ConfirmDestructiveMsg); ok {
+		return
+	}
+
+	step := ExecutedStep{
+		Verb:      verb,
+		Target:    target,
+		Selector:  selector,
+		Timestamp: time.Now(),
+	}
+
+	switch m := msg.(type) {
+	case NavigationCompleteMsg:
+		step.URL = m.URL
+		step.Success = m.Error == nil
+		if m.Error != nil {
+			step.Result = m.Error.Error()
+		} else {
+			step.Result = "ok"
+			if m.URL != "" && v.currentURL != "" && !samePage(m.URL, v.currentURL) {
+				v.pushUndo(undoEntry{Kind: undoNavigation, URL: v.currentURL})
+			}
+		}
+	case PlannedActionMsg:
+		step.URL = v.currentURL
+		step.Success = true
+		step.Result = "simulated (plan only)"
+	case WaitResultMsg:
+		step.URL = v.currentURL
+		step.Success = m.Appeared
+		switch {
+		case m.Selector == "":
+			step.Result = fmt.Sprintf("waited %v", m.Waited)
+		case m.Appeared:
+			step.Result = "appeared"
+		default:
+			step.Result = fmt.Sprintf("timed out after %v", m.Timeout)
+		}
+	case MagicLinkWaitMsg:
+		step.URL = v.currentURL
+		step.Success = true
+		step.Result = "waited for magic link"
+		step.Skippable = true
+	case NavigationErrorMsg:
+		step.URL = v.currentURL
+		step.Result = "failed"
+		if m.Error != nil {
+			step.Result = m.Error.Error()
+		}
+	default:
+		return
+	}
+
+	v.executedActions = append(v.executedActions, step)
+}
+
+// ExecutedActions returns the actions executed during this session in order
+func (v *NavigationView) ExecutedActions() []ExecutedStep {
+	return v.executedActions
+}
+
+// renderActionPanel renders the collapsible list of executed actions
+func (v *NavigationView) renderActionPanel() string {
+	header := v.subtitleStyle.Render(fmt.Sprintf("Actions (%d) — %s to hide", len(v.executedActions), v.keys.Actions.Help().Key))
+	if len(v.executedActions) == 0 {
+		return header + "\n" + v.helpStyle.Render("  No actions yet")
+	}
+
+	start := max(0, len(v.executedActions)-maxActionPanelLines)
+	lines := []string{header}
+	for i, step := range v.executedActions[start:] {
+		icon := "✓"
+		if !step.Success {
+			icon = "✗"
+		}
+		line := fmt.Sprintf("  %d. %s %s \"%s\" → %s", start+i+1, icon, step.Verb, truncateText(step.Target, 30), truncateText(step.Result, 40))
+		lines = append(lines, line)
+	}
+
+	return v.borderStyle.Render(strings.Join(lines, "\n"))
+}
+
+// actionPanelHeight returns the number of lines the action panel occupies
+func (v *NavigationView) actionPanelHeight() int {
+	if !v.showActionPanel {
+		return 0
+	}
+	return min(len(v.executedActions), maxActionPanelLines) + 4 // header, border, spacing
+}
@@ -0,0 +1,34 @@
+package views
+
+import "fmt"
+
+// listActions writes the page's discovered actions to the history, grouped
+// like the suggestion list and numbered so a number picks one
+func (v *NavigationView) listActions() error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("no browser connected, so no actions have been discovered")
+	}
+	if len(v.pageElements) == 0 {
+		v.addHistory("No actions discovered on this page yet")
+		return nil
+	}
+
+	v.pendingChoices = nil
+	v.addHistory(fmt.Sprintf("⚡ %d actions on this page:", len(v.pageElements)))
+	for _, group := range v.groupElements(v.pageElements) {
+		if len(group.Elements) == 0 {
+			continue
+		}
+		v.addHistory(group.Title)
+		for _, elem := range group.Elements {
+			v.pendingChoices = append(v.pendingChoices, elem)
+			line := fmt.Sprintf("  %d. %s", len(v.pendingChoices), truncateText(elem.Text, 50))
+			if elem.Disabled {
+				line += " (disabled)"
+			}
+			v.addHistory(line)
+		}
+	}
+	v.addHistory("  Type a number to use one")
+	return nil
+}
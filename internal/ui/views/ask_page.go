@@ -0,0 +1,179 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lance13c/tod/internal/browser"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// maxAskHTMLLength bounds how much of the page is sent with a question,
+// about 3k tokens
+const maxAskHTMLLength = 12000
+
+// describePagePrompt is the question "describe" asks about the page
+const describePagePrompt = "Summarize the purpose of this page and the key actions a user can take on it, in a few sentences."
+
+// askTimeout bounds how long an answer may stream
+const askTimeout = 2 * time.Minute
+
+// parseAskQuestion returns the question of "ask <question>" input
+func parseAskQuestion(input string) (string, bool) {
+	if !strings.HasPrefix(strings.ToLower(input), "ask ") {
+		return "", false
+	}
+	question := strings.TrimSpace(input[len("ask "):])
+	return question, question != ""
+}
+
+// isDescribePage reports whether input asks for a summary of the page
+func isDescribePage(input string) bool {
+	input = strings.ToLower(input)
+	return input == "describe" || input == "describe page"
+}
+
+// askAboutPage asks the LLM a question about the current page and starts
+// streaming the answer
+func (v *NavigationView) askAboutPage(question string) tea.Cmd {
+	return v.streamPageAnswer(question, question)
+}
+
+// describePage asks the LLM to summarize the current page. It only needs the
+// page itself, so it works where no actions were discovered.
+func (v *NavigationView) describePage() tea.Cmd {
+	return v.streamPageAnswer("Describe this page", describePagePrompt)
+}
+
+// streamPageAnswer sends the current page's simplified HTML and title to the
+// LLM with a question and starts streaming the answer. label is what the
+// history shows for the question. It's called on the UI goroutine, which
+// owns answerCancel; the Cmd only reads the page and starts the stream.
+func (v *NavigationView) streamPageAnswer(label, question string) tea.Cmd {
+	fail := func(err error) tea.Cmd {
+		return func() tea.Msg { return NavigationErrorMsg{Error: err} }
+	}
+	if v.llmClient == nil || v.aiOffline {
+		return fail(fmt.Errorf("AI is offline (no API key set), so questions about the page can't be answered"))
+	}
+	if v.chromeDPManager == nil {
+		return fail(fmt.Errorf("Chrome not connected"))
+	}
+	if v.answerCancel != nil {
+		return fail(fmt.Errorf("still answering the last question, press Esc to stop it"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), askTimeout)
+	v.answerCancel = cancel
+	title, pageURL := v.currentTitle, v.currentURL
+
+	return func() tea.Msg {
+		pageHTML, err := v.chromeDPManager.GetPageHTML()
+		if err != nil {
+			return AnswerFailedMsg{Error: fmt.Errorf("failed to read page: %w", err)}
+		}
+		if simplified, err := browser.SimplifyHTML(pageHTML); err == nil {
+			pageHTML = simplified
+		}
+		if len(pageHTML) > maxAskHTMLLength {
+			// Cut on a rune boundary so the prompt stays valid UTF-8
+			pageHTML = strings.ToValidUTF8(pageHTML[:maxAskHTMLLength], "")
+		}
+
+		prompt := fmt.Sprintf("Page: %s\nURL: %s\n\nSimplified HTML:\n%s\n\nQuestion: %s",
+			title, pageURL, pageHTML, question)
+
+		chunks, err := v.llmClient.StreamAnswer(ctx, prompt)
+		if err != nil {
+			return AnswerFailedMsg{Error: fmt.Errorf("failed to ask about page: %w", err)}
+		}
+
+		return AnswerStreamMsg{Question: label, Chunks: chunks}
+	}
+}
+
+// waitForAnswerChunk reads the next piece of a streamed answer
+func waitForAnswerChunk(chunks <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		text, ok := <-chunks
+		if !ok {
+			return AnswerDoneMsg{}
+		}
+		return AnswerChunkMsg{Text: text}
+	}
+}
+
+// handleAnswerMsg updates the streamed answer as it arrives
+func (v *NavigationView) handleAnswerMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case AnswerStreamMsg:
+		v.addHistory(fmt.Sprintf("❓ %s", msg.Question))
+		v.answerChunks = msg.Chunks
+		v.answerText = ""
+		v.answerCancelled = false
+		return waitForAnswerChunk(msg.Chunks)
+
+	case AnswerChunkMsg:
+		v.answerText += msg.Text
+		return waitForAnswerChunk(v.answerChunks)
+
+	case AnswerFailedMsg:
+		v.isProcessing = false
+		if v.answerCancel != nil {
+			v.answerCancel()
+			v.answerCancel = nil
+		}
+		return func() tea.Msg { return NavigationErrorMsg{Error: msg.Error} }
+
+	case AnswerDoneMsg:
+		v.isProcessing = false
+		if v.answerCancel != nil {
+			v.answerCancel()
+			v.answerCancel = nil
+		}
+		v.answerChunks = nil
+
+		answer := strings.Join(strings.Fields(v.answerText), " ")
+		switch {
+		case v.answerCancelled:
+			v.addHistory(fmt.Sprintf("🤖 %s ⏹ (stopped)", answer))
+		case answer == "":
+			v.addHistory("🤖 No answer received")
+		default:
+			v.addHistory(fmt.Sprintf("🤖 %s", answer))
+			v.trackUsage(v.llmClient.GetLastUsage())
+		}
+		v.answerText = ""
+	}
+	return nil
+}
+
+// stopAnswer cancels a streaming answer, reporting whether one was streaming
+func (v *NavigationView) stopAnswer() bool {
+	if v.answerCancel == nil {
+		return false
+	}
+	logging.Info("Stopping streamed answer")
+	v.answerCancelled = true
+	v.answerCancel()
+	return true
+}
+
+// renderAnswer renders the tail of the answer being streamed
+func (v *NavigationView) renderAnswer() string {
+	if v.answerChunks == nil {
+		return ""
+	}
+
+	text := "🤖 " + strings.Join(strings.Fields(v.answerText), " ") + "▌"
+	wrapped := lipgloss.NewStyle().Width(max(v.width-4, 20)).Render(text)
+	lines := strings.Split(wrapped, "\n")
+	if len(lines) > 3 {
+		lines = lines[len(lines)-3:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,11 +2,11 @@ package views
 
 import (
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
 	"github.com/lance13c/tod/internal/config"
+	"github.com/lance13c/tod/internal/logging"
 )
 
 // AuthConfigManager manages saved user credentials for authentication
@@ -28,7 +28,7 @@ func (a *AuthConfigManager) GetSavedUsersForDomain(domain string) ([]config.Test
 	// Load test user configuration
 	testConfig, err := a.testUserLoader.Load()
 	if err != nil {
-		log.Printf("Warning: failed to load test user config: %v", err)
+		logging.Warn("Failed to load test user config: %v", err)
 		return []config.TestUser{}, nil // Return empty instead of error
 	}
 
@@ -77,7 +77,7 @@ func (a *AuthConfigManager) SaveUserForDomain(domain, email, password, name stri
 		
 		testConfig.Users[userID] = existingUser
 		
-		log.Printf("Updated saved user for %s: %s", domain, email)
+		logging.Info("Updated saved user for %s: %s", domain, email)
 	} else {
 		// Create new user
 		newUser := config.TestUser{
@@ -102,7 +102,7 @@ func (a *AuthConfigManager) SaveUserForDomain(domain, email, password, name stri
 
 		testConfig.AddUser(newUser)
 		
-		log.Printf("Created new saved user for %s: %s", domain, email)
+		logging.Info("Created new saved user for %s: %s", domain, email)
 	}
 
 	// Save the configuration
@@ -141,7 +141,7 @@ func (a *AuthConfigManager) SaveMagicLinkUserForDomain(domain, email, name strin
 		
 		testConfig.Users[userID] = existingUser
 		
-		log.Printf("Updated magic link user for %s: %s", domain, email)
+		logging.Info("Updated magic link user for %s: %s", domain, email)
 	} else {
 		// Create new magic link user
 		newUser := config.TestUser{
@@ -165,7 +165,7 @@ func (a *AuthConfigManager) SaveMagicLinkUserForDomain(domain, email, name strin
 
 		testConfig.AddUser(newUser)
 		
-		log.Printf("Created new magic link user for %s: %s", domain, email)
+		logging.Info("Created new magic link user for %s: %s", domain, email)
 	}
 
 	return a.testUserLoader.Save(testConfig)
@@ -199,7 +199,7 @@ func (a *AuthConfigManager) DeleteUserForDomain(domain, email string) error {
 	userID := a.generateUserID(domain, email)
 	
 	if testConfig.RemoveUser(userID) {
-		log.Printf("Deleted saved user for %s: %s", domain, email)
+		logging.Info("Deleted saved user for %s: %s", domain, email)
 		return a.testUserLoader.Save(testConfig)
 	}
 
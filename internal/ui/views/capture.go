@@ -0,0 +1,324 @@
+package views
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/browser"
+	"github.com/lance13c/tod/internal/database"
+	"github.com/lance13c/tod/internal/logging"
+	"github.com/lance13c/tod/internal/testing"
+)
+
+// pngSignature starts every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Capture polling defaults: how long the page is watched for content loaded
+// after the capture, and how often it is checked. browser.poll_duration and
+// browser.poll_interval override them.
+const (
+	captureWatchDuration = 5 * time.Second
+	captureWatchInterval = 500 * time.Millisecond
+)
+
+// pollForCapture starts polling the page with the configured polling window
+func (v *NavigationView) pollForCapture() <-chan browser.HTMLChange {
+	duration, interval := captureWatchDuration, captureWatchInterval
+	var initialDelay time.Duration
+	quietIntervals := 0
+	if v.config != nil {
+		if v.config.Browser.PollDuration > 0 {
+			duration = v.config.Browser.PollDuration
+		}
+		if v.config.Browser.PollInterval > 0 {
+			interval = v.config.Browser.PollInterval
+		}
+		initialDelay = v.config.Browser.PollInitialDelay
+		quietIntervals = v.config.Browser.PollQuietIntervals
+	}
+	return v.chromeDPManager.PollForChangesUntilQuiet(duration, interval, initialDelay, quietIntervals)
+}
+
+// capturePage saves the current page's HTML, a screenshot and its actions to
+// the project database for later review. The page is then watched for a few
+// seconds and actions in content that loads meanwhile are merged in, so the
+// capture finishes in the background.
+func (v *NavigationView) capturePage() error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+	if v.captureCancel != nil {
+		return fmt.Errorf("a capture is already in progress")
+	}
+
+	html, err := v.chromeDPManager.GetPageHTML()
+	if err != nil {
+		return fmt.Errorf("failed to read page: %w", err)
+	}
+
+	now := time.Now()
+	stamp := now.Format("20060102-150405")
+
+	htmlDir := filepath.Join(".tod", "captures")
+	if err := os.MkdirAll(htmlDir, 0755); err != nil {
+		return fmt.Errorf("failed to create captures directory: %w", err)
+	}
+	htmlFile := filepath.Join(htmlDir, fmt.Sprintf("capture-%s.html", stamp))
+	if err := os.WriteFile(htmlFile, []byte(html), 0644); err != nil {
+		return fmt.Errorf("failed to write capture: %w", err)
+	}
+
+	// A missing screenshot shouldn't cost the user the capture
+	screenshotFile, err := v.saveScreenshot(stamp)
+	if err != nil {
+		logging.Warn("Capturing without screenshot: %v", err)
+	}
+
+	db, err := database.New(database.ProjectPath("."))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	captureID, err := db.SavePageCapture(&database.PageCapture{
+		URL:            v.currentURL,
+		Title:          v.currentTitle,
+		HTMLFile:       htmlFile,
+		HTMLLength:     len(html),
+		CapturedAt:     now,
+		ChromePort:     v.chromeDPManager.Port(),
+		ScreenshotFile: screenshotFile,
+	})
+	if err != nil {
+		return err
+	}
+
+	var actions []testing.DiscoveredAction
+	for _, elem := range v.pageElements {
+		description := elem.Description
+		if description == "" {
+			description = elem.Text
+		}
+		actions = append(actions, testing.DiscoveredAction{
+			Description:    description,
+			Element:        elem.Text,
+			Selector:       elem.Selector,
+			Action:         elem.Method,
+			Priority:       "medium",
+			ExpectedResult: v.expectedResults[expectedResultKey(v.currentURL, elem.Selector)],
+		})
+	}
+
+	if screenshotFile != "" {
+		v.addHistory(fmt.Sprintf("📸 Capturing page #%d with %d actions, screenshot %s", captureID, len(actions), screenshotFile))
+	} else {
+		v.addHistory(fmt.Sprintf("📸 Capturing page #%d with %d actions (no screenshot)", captureID, len(actions)))
+	}
+
+	v.startedStream = v.watchCapture(captureID, actions)
+	return nil
+}
+
+// watchCapture polls the page for new content, discovers the actions in it
+// and merges them into actions, sending progress on the returned stream. The
+// merged set is saved to the capture when polling completes.
+func (v *NavigationView) watchCapture(captureID int64, actions []testing.DiscoveredAction) CaptureStreamMsg {
+	ctx, cancel := context.WithCancel(context.Background())
+	v.captureCancel = cancel
+	updates := make(chan tea.Msg)
+
+	discovery := testing.NewActionDiscovery(v.llmClient, ".")
+	useLLM := !v.aiOffline
+	changes := v.pollForCapture()
+
+	go func() {
+		defer close(updates)
+
+		merged := actions
+		for change := range changes {
+			// Keep draining so the poller can finish after a cancel
+			if ctx.Err() != nil || change.IsInitial || change.NewContent == "" {
+				continue
+			}
+
+			var found []testing.DiscoveredAction
+			if useLLM {
+				var err error
+				found, err = discovery.DiscoverIncrementalActions(ctx, change.NewContent, merged, nil)
+				if err != nil {
+					logging.Warn("Incremental action discovery failed: %v", err)
+					continue
+				}
+			} else {
+				found = v.extractCaptureActions()
+			}
+
+			previous := merged
+			merged = discovery.MergeActions(merged, found)
+			if len(merged) == len(previous) {
+				continue
+			}
+
+			// Rediscovered actions are merged in place, so the new ones are
+			// those past the end of the previous list
+			var added []string
+			for _, action := range merged[len(previous):] {
+				added = append(added, action.Description)
+			}
+
+			select {
+			case updates <- CaptureProgressMsg{Added: added, Total: len(merged)}:
+			case <-ctx.Done():
+			}
+		}
+
+		updates <- v.finishCapture(ctx, captureID, merged)
+	}()
+
+	return CaptureStreamMsg{Updates: updates}
+}
+
+// extractCaptureActions lists the page's interactive elements as actions,
+// for discovering new content without the LLM
+func (v *NavigationView) extractCaptureActions() []testing.DiscoveredAction {
+	elements, err := v.chromeDPManager.ExtractInteractiveElements()
+	if err != nil {
+		logging.Warn("Failed to extract elements during capture: %v", err)
+		return nil
+	}
+
+	var actions []testing.DiscoveredAction
+	for _, elem := range elements {
+		text := normalizeText(elem.Text)
+		if text == "" {
+			continue
+		}
+		actions = append(actions, testing.DiscoveredAction{
+			Description: text,
+			Element:     text,
+			Selector:    elem.Selector,
+			Action:      "click",
+			Priority:    "low",
+		})
+	}
+	return actions
+}
+
+// finishCapture saves the merged actions to the capture and returns the
+// message ending the capture stream
+func (v *NavigationView) finishCapture(ctx context.Context, captureID int64, actions []testing.DiscoveredAction) CaptureDoneMsg {
+	if ctx.Err() != nil {
+		return CaptureDoneMsg{Error: fmt.Errorf("capture #%d cancelled before its actions were saved", captureID)}
+	}
+
+	db, err := database.New(database.ProjectPath("."))
+	if err != nil {
+		return CaptureDoneMsg{Error: fmt.Errorf("failed to open database: %w", err)}
+	}
+	defer db.Close()
+
+	saved := make([]database.DiscoveredAction, 0, len(actions))
+	for _, action := range actions {
+		saved = append(saved, database.DiscoveredAction{
+			Description:    action.Description,
+			Element:        action.Element,
+			Selector:       action.Selector,
+			Action:         action.Action,
+			IsTested:       action.IsTested,
+			Priority:       action.Priority,
+			ExpectedResult: action.ExpectedResult,
+		})
+	}
+	if err := db.UpsertDiscoveredActions(captureID, saved); err != nil {
+		return CaptureDoneMsg{Error: err}
+	}
+
+	summary := fmt.Sprintf("📸 Captured page #%d with %d actions", captureID, len(saved))
+	if untested, err := db.GetUntestedActionCount(); err == nil {
+		summary += fmt.Sprintf(" (%d unique untested)", untested)
+	}
+	return CaptureDoneMsg{Summary: summary}
+}
+
+// waitForCaptureUpdate reads the next update of a capture in progress
+func waitForCaptureUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return CaptureDoneMsg{}
+		}
+		return msg
+	}
+}
+
+// handleCaptureMsg shows a capture's actions as they arrive. The capture
+// stays in the analyzing state until polling completes and the actions are saved.
+func (v *NavigationView) handleCaptureMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case CaptureStreamMsg:
+		v.isProcessing = false
+		v.isCapturing = true
+		v.captureActionCount = len(v.pageElements)
+		v.captureUpdates = msg.Updates
+		return waitForCaptureUpdate(msg.Updates)
+
+	case CaptureProgressMsg:
+		v.captureActionCount = msg.Total
+		for _, description := range msg.Added {
+			v.addHistory(fmt.Sprintf("   + %s", truncateText(description, 60)))
+		}
+		return waitForCaptureUpdate(v.captureUpdates)
+
+	case CaptureDoneMsg:
+		if !v.isCapturing {
+			return nil
+		}
+		if v.captureCancel != nil {
+			v.captureCancel()
+			v.captureCancel = nil
+		}
+		v.isCapturing = false
+		v.captureUpdates = nil
+		if msg.Error != nil {
+			v.addHistory(fmt.Sprintf("❌ %v", msg.Error))
+		} else if msg.Summary != "" {
+			v.addHistory(msg.Summary)
+		}
+	}
+	return nil
+}
+
+// saveScreenshot writes a screenshot of the page to .tod/screenshots and
+// returns its path
+func (v *NavigationView) saveScreenshot(stamp string) (string, error) {
+	data, err := v.chromeDPManager.Screenshot()
+	if err != nil {
+		return "", fmt.Errorf("failed to take screenshot: %w", err)
+	}
+
+	ext := ".jpg"
+	if bytes.HasPrefix(data, pngSignature) {
+		ext = ".png"
+	}
+	return writeScreenshot(fmt.Sprintf("capture-%s%s", stamp, ext), data)
+}
+
+// writeScreenshot writes a screenshot to .tod/screenshots under name and
+// returns its path
+func writeScreenshot(name string, data []byte) (string, error) {
+	dir := filepath.Join(".tod", "screenshots")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create screenshots directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write screenshot: %w", err)
+	}
+	return path, nil
+}
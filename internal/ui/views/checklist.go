@@ -0,0 +1,81 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// checklistVerbs describes what to verify for each element method
+var checklistVerbs = map[string]string{
+	"navigate":    "Follow link",
+	"click":       "Click",
+	"type":        "Type into",
+	"form_input":  "Fill in",
+	"form_submit": "Submit",
+	"select":      "Choose an option in",
+	"upload":      "Upload a file to",
+}
+
+// renderChecklist renders page elements as a markdown task list for manual QA,
+// grouped by the same categories as the suggestion list
+func (v *NavigationView) renderChecklist(now time.Time) string {
+	var b strings.Builder
+
+	title := v.currentTitle
+	if title == "" {
+		title = v.currentURL
+	}
+	fmt.Fprintf(&b, "# QA checklist: %s\n\n", title)
+	fmt.Fprintf(&b, "- URL: %s\n", v.currentURL)
+	fmt.Fprintf(&b, "- Generated: %s\n", now.Format(time.RFC1123))
+	fmt.Fprintf(&b, "- Actions: %d\n", len(v.pageElements))
+
+	for _, group := range v.groupElements(v.pageElements) {
+		if len(group.Elements) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n## %s\n\n", group.Title)
+		for _, elem := range group.Elements {
+			verb, ok := checklistVerbs[elem.Method]
+			if !ok {
+				verb = "Use"
+			}
+			line := fmt.Sprintf("- [ ] %s \"%s\"", verb, elem.Text)
+			if elem.Selector != "" {
+				line += fmt.Sprintf(" (`%s`)", elem.Selector)
+			}
+			if elem.Disabled {
+				line += " — currently disabled"
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// exportChecklist writes the current page's actions to .tod/checklists as a
+// markdown checklist
+func (v *NavigationView) exportChecklist() error {
+	if len(v.pageElements) == 0 {
+		return fmt.Errorf("no actions discovered on this page yet")
+	}
+
+	dir := filepath.Join(".tod", "checklists")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create checklists directory: %w", err)
+	}
+
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("checklist-%s.md", now.Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(v.renderChecklist(now)), 0644); err != nil {
+		return fmt.Errorf("failed to write checklist: %w", err)
+	}
+
+	v.addHistory(fmt.Sprintf("📋 Checklist of %d actions saved to %s", len(v.pageElements), path))
+	return nil
+}
@@ -0,0 +1,200 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// destructiveGuard tracks whether destructive actions must be confirmed and
+// which have been. It's read by the command goroutines that perform actions
+// as well as the UI, hence the mutex.
+type destructiveGuard struct {
+	mu        sync.Mutex
+	enabled   bool
+	confirmed map[string]bool // actions confirmed to run once, by confirmationKey
+}
+
+func confirmationKey(text, selector string) string {
+	return text + "\x00" + selector
+}
+
+// setEnabled turns confirmation on or off
+func (g *destructiveGuard) setEnabled(on bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.enabled = on
+}
+
+// allow lets the action with key run once without asking again
+func (g *destructiveGuard) allow(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.confirmed == nil {
+		g.confirmed = make(map[string]bool)
+	}
+	g.confirmed[key] = true
+}
+
+// needsConfirming reports whether the action with key has to ask first,
+// using up a confirmation given by allow
+func (g *destructiveGuard) needsConfirming(key string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.enabled {
+		return false
+	}
+	if g.confirmed[key] {
+		delete(g.confirmed, key)
+		return false
+	}
+	return true
+}
+
+// confirmationRequired is the error an action stops with when it looks
+// destructive and hasn't been confirmed
+type confirmationRequired struct {
+	text     string
+	selector string
+	word     string
+}
+
+func (c *confirmationRequired) Error() string {
+	return fmt.Sprintf("%s looks destructive (%q) and needs confirming", describeConfirmation(c.text, c.selector), c.word)
+}
+
+// describeConfirmation shows what a confirmed action will click
+func describeConfirmation(text, selector string) string {
+	description := fmt.Sprintf("\"%s\"", truncateText(text, 40))
+	if selector != "" {
+		description += fmt.Sprintf(" (%s)", truncateText(selector, 60))
+	}
+	return description
+}
+
+// guardDestructive is called by every path that clicks, submits or follows
+// an element, right before it does. It returns a confirmationRequired error
+// if the element's text or selector contains a destructive word, per
+// safety.destructive_words, and confirmation is on and not yet given.
+func (v *NavigationView) guardDestructive(text, selector string) error {
+	if v.config == nil {
+		return nil
+	}
+	word := v.config.Safety.DestructiveWord(text, selector)
+	if word == "" || !v.destructive.needsConfirming(confirmationKey(text, selector)) {
+		return nil
+	}
+	return &confirmationRequired{text: text, selector: selector, word: word}
+}
+
+// guardSubmit is guardDestructive for the current form's submit button
+func (v *NavigationView) guardSubmit() error {
+	if v.formHandler == nil || v.formHandler.currentForm == nil || v.formHandler.currentForm.SubmitButton == nil {
+		return nil
+	}
+	button := v.formHandler.currentForm.SubmitButton
+	return v.guardDestructive(firstNonEmpty(button.Label, button.Name), button.Selector)
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// isConfirmationRequired reports whether err is an action stopping to ask
+// for confirmation
+func isConfirmationRequired(err error) bool {
+	var confirmation *confirmationRequired
+	return errors.As(err, &confirmation)
+}
+
+// errorMsg converts an action's error to a message: a ConfirmDestructiveMsg
+// that runs retry once confirmed if the action stopped to ask, and a
+// NavigationErrorMsg otherwise
+func errorMsg(err error, retry func() tea.Msg) tea.Msg {
+	var confirmation *confirmationRequired
+	if errors.As(err, &confirmation) {
+		return ConfirmDestructiveMsg{
+			Text:     confirmation.text,
+			Selector: confirmation.selector,
+			Word:     confirmation.word,
+			Retry:    retry,
+		}
+	}
+	return NavigationErrorMsg{Error: err}
+}
+
+// pendingConfirmation is a destructive action waiting for the user to type
+// "yes" before it runs again
+type pendingConfirmation struct {
+	description string
+	key         string
+	retry       func() tea.Msg
+}
+
+// askConfirmation holds a destructive action until the user confirms it,
+// showing what will be clicked
+func (v *NavigationView) askConfirmation(msg ConfirmDestructiveMsg) {
+	description := describeConfirmation(msg.Text, msg.Selector)
+	v.pendingConfirm = &pendingConfirmation{
+		description: description,
+		key:         confirmationKey(msg.Text, msg.Selector),
+		retry:       msg.Retry,
+	}
+	v.addHistory(fmt.Sprintf("⚠️  %s looks destructive (%q). Type yes to click it, anything else cancels.", description, msg.Word))
+}
+
+// resolveConfirmation runs the pending action if input confirms it and
+// cancels it otherwise. It reports whether input was consumed as the answer;
+// input that isn't a yes or no is run as usual after cancelling.
+func (v *NavigationView) resolveConfirmation(input string) (tea.Cmd, bool) {
+	pending := v.pendingConfirm
+	if pending == nil {
+		return nil, false
+	}
+	v.pendingConfirm = nil
+
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		v.addHistory(fmt.Sprintf("✓ Confirmed, clicking %s", pending.description))
+		v.destructive.allow(pending.key)
+		return func() tea.Msg { return pending.retry() }, true
+	case "n", "no":
+		v.addHistory(fmt.Sprintf("✗ Cancelled %s", pending.description))
+		return nil, true
+	default:
+		v.addHistory(fmt.Sprintf("✗ Cancelled %s", pending.description))
+		return nil, false
+	}
+}
+
+// cancelConfirmation drops a pending confirmation, reporting whether there
+// was one
+func (v *NavigationView) cancelConfirmation() bool {
+	if v.pendingConfirm == nil {
+		return false
+	}
+	v.addHistory(fmt.Sprintf("✗ Cancelled %s", v.pendingConfirm.description))
+	v.pendingConfirm = nil
+	return true
+}
+
+// setConfirmDestructive turns confirmation of destructive actions on or off
+// for the rest of the session
+func (v *NavigationView) setConfirmDestructive(on bool) error {
+	v.destructive.setEnabled(on)
+	if on {
+		v.addHistory("🛡️  Destructive actions will ask for confirmation")
+	} else {
+		v.addHistory("⚡ Destructive actions will run without confirmation")
+	}
+	return nil
+}
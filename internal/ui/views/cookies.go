@@ -0,0 +1,86 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// cookiesPath returns where the cookies of the current page's domain are saved
+func (v *NavigationView) cookiesPath() (string, error) {
+	u, err := url.Parse(v.currentURL)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("no page loaded to take the cookie domain from")
+	}
+	return filepath.Join(".tod", "cookies", u.Hostname()+".json"), nil
+}
+
+// saveCookies writes the current page's cookies to .tod/cookies/<domain>.json
+func (v *NavigationView) saveCookies() error {
+	path, err := v.cookiesPath()
+	if err != nil {
+		return err
+	}
+
+	cookies, err := v.chromeDPManager.ExportCookies()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cookies directory: %w", err)
+	}
+	data, err := json.MarshalIndent(cookies, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cookies: %w", err)
+	}
+	// Cookies hold session tokens, so keep the file private
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cookies: %w", err)
+	}
+
+	v.addHistory(fmt.Sprintf("🍪 Saved %d cookies to %s", len(cookies), path))
+	return nil
+}
+
+// loadCookies restores the cookies saved for the current page's domain and
+// reloads the page so they take effect
+func (v *NavigationView) loadCookies() error {
+	path, err := v.cookiesPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read cookies: %w", err)
+	}
+	var cookies []browser.Cookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return fmt.Errorf("failed to parse cookies %s: %w", path, err)
+	}
+
+	if err := v.chromeDPManager.ImportCookies(cookies); err != nil {
+		return err
+	}
+
+	expired := 0
+	now := time.Now()
+	for _, c := range cookies {
+		if c.Expired(now) {
+			expired++
+		}
+	}
+	message := fmt.Sprintf("🍪 Loaded %d cookies from %s", len(cookies)-expired, path)
+	if expired > 0 {
+		message += fmt.Sprintf(" (%d expired, skipped)", expired)
+	}
+	v.addHistory(message)
+
+	return v.navigateToURL(v.currentURL)
+}
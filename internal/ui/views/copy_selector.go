@@ -0,0 +1,27 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+)
+
+// copySelectedSelector copies the raw selector of the highlighted suggestion
+// to the clipboard
+func (v *NavigationView) copySelectedSelector() {
+	if !v.showSuggestions || v.selectedIndex < 0 || v.selectedIndex >= len(v.suggestions) {
+		v.addHistory("📋 Nothing to copy: highlight a page element first")
+		return
+	}
+	element := v.suggestions[v.selectedIndex].Element
+	if element == nil || element.Selector == "" {
+		v.addHistory("📋 Nothing to copy: the highlighted suggestion has no selector")
+		return
+	}
+
+	if err := clipboard.WriteAll(element.Selector); err != nil {
+		v.addHistory(fmt.Sprintf("❌ Failed to copy selector: %v", err))
+		return
+	}
+	v.addHistory(fmt.Sprintf("📋 Copied selector: %s", element.Selector))
+}
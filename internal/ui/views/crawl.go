@@ -0,0 +1,147 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/llm"
+	"github.com/lance13c/tod/internal/logging"
+	"github.com/lance13c/tod/internal/testing"
+)
+
+// Limits of a "crawl": how many links deep it follows from the current page,
+// how many pages it visits and how long it may take in total
+const (
+	crawlMaxDepth = 2
+	crawlMaxPages = 20
+	crawlTimeout  = 5 * time.Minute
+)
+
+// startCrawl discovers actions on the current page and the same-origin pages
+// linked from it, reporting each page as it completes. The browser returns to
+// the current page when the crawl ends.
+func (v *NavigationView) startCrawl() error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+	if v.crawlCancel != nil {
+		return fmt.Errorf("a crawl is already in progress")
+	}
+	startURL := v.currentURL
+	if startURL == "" {
+		return fmt.Errorf("no page to crawl from")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), crawlTimeout)
+	v.crawlCancel = cancel
+	updates := make(chan tea.Msg)
+
+	var client llm.Client
+	if !v.aiOffline {
+		client = v.llmClient
+	}
+	discovery := testing.NewActionDiscovery(client, ".")
+	discovery.Browser = v.chromeDPManager
+	discovery.OnPage = func(page testing.PageActions) {
+		select {
+		case updates <- CrawlPageMsg{Page: page}:
+		case <-ctx.Done():
+		}
+	}
+	// The crawl can't stop to ask, so links that would need confirming
+	// when clicked are left unvisited
+	var skipped []string
+	discovery.Follow = func(text, link string) bool {
+		if err := v.guardDestructive(text, link); isConfirmationRequired(err) {
+			skipped = append(skipped, link)
+			return false
+		}
+		return true
+	}
+
+	v.addHistory(fmt.Sprintf("🕸️ Crawling from %s (up to %d pages, %d links deep)...", startURL, crawlMaxPages, crawlMaxDepth))
+	go func() {
+		defer close(updates)
+
+		pages, err := discovery.CrawlAndDiscover(ctx, startURL, crawlMaxDepth, crawlMaxPages)
+		if navErr := v.chromeDPManager.Navigate(startURL); navErr != nil {
+			logging.Warn("Failed to return to %s after crawling: %v", startURL, navErr)
+		}
+
+		actions := 0
+		for _, page := range pages {
+			actions += len(page.Actions)
+		}
+		updates <- CrawlDoneMsg{Pages: len(pages), Actions: actions, Skipped: skipped, Error: err}
+	}()
+
+	v.startedStream = CrawlStreamMsg{Updates: updates}
+	return nil
+}
+
+// stopCrawl cancels a crawl, reporting whether one was running
+func (v *NavigationView) stopCrawl() bool {
+	if v.crawlCancel == nil {
+		return false
+	}
+	v.crawlCancel()
+	v.crawlCancel = nil
+	v.addHistory("🛑 Crawl cancelled.")
+	return true
+}
+
+// waitForCrawlUpdate reads the next update of a crawl in progress
+func waitForCrawlUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return CrawlDoneMsg{}
+		}
+		return msg
+	}
+}
+
+// handleCrawlMsg reports each crawled page as it completes
+func (v *NavigationView) handleCrawlMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case CrawlStreamMsg:
+		v.isProcessing = false
+		v.isCrawling = true
+		v.crawledPages = 0
+		v.crawlUpdates = msg.Updates
+		return waitForCrawlUpdate(msg.Updates)
+
+	case CrawlPageMsg:
+		v.crawledPages++
+		page := msg.Page
+		if page.Error != nil {
+			v.addHistory(fmt.Sprintf("   ❌ %s: %v", truncateText(page.URL, 60), page.Error))
+		} else {
+			v.addHistory(fmt.Sprintf("   ✓ %s — %d actions", truncateText(page.URL, 60), len(page.Actions)))
+		}
+		return waitForCrawlUpdate(v.crawlUpdates)
+
+	case CrawlDoneMsg:
+		if !v.isCrawling {
+			return nil
+		}
+		if v.crawlCancel != nil {
+			v.crawlCancel()
+			v.crawlCancel = nil
+		}
+		v.isCrawling = false
+		v.crawlUpdates = nil
+		if msg.Error != nil {
+			v.addHistory(fmt.Sprintf("❌ %v", msg.Error))
+		}
+		v.addHistory(fmt.Sprintf("🕸️ Crawled %d pages, found %d actions", msg.Pages, msg.Actions))
+		if len(msg.Skipped) > 0 {
+			v.addHistory(fmt.Sprintf("🛡️  Skipped %d links that look destructive: %s", len(msg.Skipped), truncateText(strings.Join(msg.Skipped, ", "), 120)))
+		}
+		return v.analyzeCurrentPage()
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lance13c/tod/internal/browser"
+	"github.com/lance13c/tod/internal/llm"
+	"github.com/lance13c/tod/internal/testing"
+)
+
+// discoverFileTimeout bounds discovering the actions in a saved HTML file
+const discoverFileTimeout = 2 * time.Minute
+
+// discoverFile lists the actions in a saved HTML file, as "tod discover
+// --file" does, without touching the browser
+func (v *NavigationView) discoverFile(path string) error {
+	var client llm.Client
+	if !v.aiOffline {
+		client = v.llmClient
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), discoverFileTimeout)
+	defer cancel()
+	actions, err := testing.NewActionDiscovery(client, ".").DiscoverActionsFromFile(ctx, path, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(actions) == 0 {
+		v.addHistory(fmt.Sprintf("🔎 No actions found in %s", path))
+		return nil
+	}
+	v.addHistory(fmt.Sprintf("🔎 Discovered %d actions in %s:", len(actions), path))
+	for i, action := range actions {
+		line := fmt.Sprintf("   %d. [%s] %s — %s", i+1, action.Priority, truncateText(action.Description, 50), action.Selector)
+		if browser.IsFragileSelector(action.Selector, v.fragileThreshold()) {
+			line += " ⚠"
+		}
+		v.addHistory(line)
+	}
+	v.addHistory("⚠️ Selectors are unverified: they come from the file, not the live page")
+	return nil
+}
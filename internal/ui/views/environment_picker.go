@@ -0,0 +1,142 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/config"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// environmentItem is a configured environment shown in the picker
+type environmentItem struct {
+	name    string
+	homeURL string
+	current bool
+}
+
+func (i environmentItem) Title() string {
+	if i.current {
+		return i.name + " (current)"
+	}
+	return i.name
+}
+
+func (i environmentItem) Description() string { return i.homeURL }
+func (i environmentItem) FilterValue() string { return i.name }
+
+// environmentItems returns the configured environments sorted by name
+func (v *NavigationView) environmentItems() []environmentItem {
+	names := make([]string, 0, len(v.config.Envs))
+	for name := range v.config.Envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]environmentItem, 0, len(names))
+	for _, name := range names {
+		env := v.config.Envs[name]
+		items = append(items, environmentItem{
+			name:    name,
+			homeURL: env.HomeURL(),
+			current: name == v.config.Current,
+		})
+	}
+	return items
+}
+
+// showEnvironments lists the configured environments and opens the picker
+func (v *NavigationView) showEnvironments() error {
+	items := v.environmentItems()
+	if len(items) == 0 {
+		return fmt.Errorf("no environments configured")
+	}
+
+	v.addHistory(fmt.Sprintf("🌐 %d environments configured:", len(items)))
+	listItems := make([]list.Item, 0, len(items))
+	selected := 0
+	for i, item := range items {
+		marker := " "
+		if item.current {
+			marker = "*"
+			selected = i
+		}
+		v.addHistory(fmt.Sprintf("  %s %s → %s", marker, item.name, item.homeURL))
+		listItems = append(listItems, item)
+	}
+
+	picker := list.New(listItems, list.NewDefaultDelegate(), v.width-4, min(len(items)*3+4, 16))
+	picker.Title = "Switch environment"
+	picker.SetShowHelp(false)
+	picker.SetFilteringEnabled(false)
+	picker.SetShowPagination(false)
+	picker.Select(selected)
+
+	v.envPicker = &picker
+	return nil
+}
+
+// handleEnvPickerKey handles keys while the environment picker is open
+func (v *NavigationView) handleEnvPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		v.envPicker = nil
+		return v, nil
+
+	case tea.KeyEnter:
+		item, ok := v.envPicker.SelectedItem().(environmentItem)
+		v.envPicker = nil
+		if !ok || item.current {
+			return v, nil
+		}
+		return v, v.switchEnvironment(item.name)
+	}
+
+	picker, cmd := v.envPicker.Update(msg)
+	v.envPicker = &picker
+	return v, cmd
+}
+
+// switchEnvironment makes another configured environment current for this
+// session and navigates to its home page
+func (v *NavigationView) switchEnvironment(name string) tea.Cmd {
+	env, exists := v.config.Envs[name]
+	if !exists {
+		return func() tea.Msg {
+			return NavigationErrorMsg{Error: fmt.Errorf("unknown environment: %s", name)}
+		}
+	}
+
+	v.config.Current = name
+	v.configuredURL = env.HomeURL()
+	v.addHistory(fmt.Sprintf("🌐 Switched to %s", name))
+	v.applyEnvironment(name, &env)
+
+	return func() tea.Msg {
+		msg := v.navigateToURLMsg(v.configuredURL)
+		v.recordExecutedStep("switch_env", name, "", msg)
+		return msg
+	}
+}
+
+// applyEnvironment sets up the running browser for env: it answers the
+// environment's basic auth challenge, or stops answering, and shows pages as
+// the environment's device
+func (v *NavigationView) applyEnvironment(name string, env *config.EnvConfig) {
+	if v.chromeDPManager == nil {
+		return
+	}
+
+	var user, pass string
+	if env.BasicAuth != nil {
+		user, pass = env.BasicAuth.Username, env.BasicAuth.Password
+	}
+	if err := v.chromeDPManager.SetBasicAuth(user, pass); err != nil {
+		logging.Warn("Failed to set basic auth for %s: %v", name, err)
+	}
+	if err := v.chromeDPManager.SetEmulation(envEmulation(env)); err != nil {
+		logging.Warn("Failed to set emulation for %s: %v", name, err)
+	}
+}
@@ -0,0 +1,104 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// parseFillByLabel splits "fill <label> with <value>" arguments into the
+// field's label and the value, dropping a leading "the" and trailing "field"
+// so "fill the Email field with a@b.c" names the field "Email"
+func parseFillByLabel(args string) (string, string, bool) {
+	index := strings.Index(strings.ToLower(args), " with ")
+	if index < 0 {
+		return "", "", false
+	}
+	label := strings.TrimSpace(args[:index])
+	value := strings.TrimSpace(args[index+len(" with "):])
+
+	lower := strings.ToLower(label)
+	if strings.HasPrefix(lower, "the ") {
+		label = label[len("the "):]
+	}
+	if strings.HasSuffix(strings.ToLower(label), " field") {
+		label = label[:len(label)-len(" field")]
+	}
+	label = strings.Trim(strings.TrimSpace(label), `"'`)
+	value = strings.Trim(value, `"'`)
+	if label == "" {
+		return "", "", false
+	}
+	return label, value, true
+}
+
+// maskedValue is shown in place of a secret value
+const maskedValue = "••••••"
+
+// sensitiveLabelWords are words in a field's label that mark its value as
+// secret
+var sensitiveLabelWords = map[string]bool{
+	"password": true, "passwd": true, "passcode": true, "pin": true, "secret": true,
+	"token": true, "cvv": true, "cvc": true, "ssn": true,
+}
+
+// fillDisplayValue returns the value "fill <label> with <value>" shows: masked
+// if the label names something secret or the page's field with that label
+// is a password input
+func (v *NavigationView) fillDisplayValue(label, value string) string {
+	words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		if sensitiveLabelWords[word] {
+			return maskedValue
+		}
+	}
+	for _, element := range v.pageElements {
+		named := strings.EqualFold(strings.TrimSpace(element.Text), label) || strings.EqualFold(element.Placeholder, label)
+		if element.InputType == "password" && named {
+			return maskedValue
+		}
+	}
+	return value
+}
+
+// fillSecretVar is the environment variable replay reads a masked fill's
+// value from, e.g. TOD_FILL_CONFIRM_PASSWORD for the field "Confirm password"
+func fillSecretVar(label string) string {
+	name := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return '_'
+		}
+		return unicode.ToUpper(r)
+	}, label)
+	return "TOD_FILL_" + strings.Trim(name, "_")
+}
+
+// recordedInput returns input as it's saved with the session. A "fill
+// <label> with <value>" whose value fillDisplayValue masks gets a ${VAR}
+// reference in place of the value, as flows do, so the secret isn't written
+// to disk and replay reads it from the environment.
+func (v *NavigationView) recordedInput(input string) string {
+	if !strings.HasPrefix(strings.ToLower(input), "fill ") {
+		return input
+	}
+	label, value, ok := parseFillByLabel(input[len("fill "):])
+	if !ok || v.fillDisplayValue(label, value) != maskedValue {
+		return input
+	}
+	return fmt.Sprintf("fill %s with ${%s}", label, fillSecretVar(label))
+}
+
+// fillByLabel fills the field labelled label with value, matching the label
+// the way a person reads the form rather than by selector
+func (v *NavigationView) fillByLabel(label, value string) error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+	if err := v.chromeDPManager.FillFieldByLabel(label, value); err != nil {
+		return err
+	}
+	v.addHistory(fmt.Sprintf("→ Filled field: %s", label))
+	return nil
+}
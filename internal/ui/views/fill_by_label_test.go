@@ -0,0 +1,48 @@
+package views
+
+import "testing"
+
+func TestFillDisplayValueMasksSecrets(t *testing.T) {
+	v := &NavigationView{
+		pageElements: []NavigableElement{
+			{Type: FormFieldElement, Text: "Secret phrase", InputType: "text"},
+			{Type: FormFieldElement, Text: "Memorable word", InputType: "password"},
+		},
+	}
+
+	tests := []struct {
+		label string
+		want  string
+	}{
+		{"Email", "ada@example.com"},
+		{"Password", maskedValue},
+		{"Confirm password", maskedValue},
+		{"API token", maskedValue},
+		{"Shipping address", "ada@example.com"},
+		{"Memorable word", maskedValue},
+	}
+	for _, tt := range tests {
+		if got := v.fillDisplayValue(tt.label, "ada@example.com"); got != tt.want {
+			t.Errorf("fillDisplayValue(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestRecordedInputHidesSecrets(t *testing.T) {
+	v := &NavigationView{}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"fill Email with ada@example.com", "fill Email with ada@example.com"},
+		{"fill Password with hunter2", "fill Password with ${TOD_FILL_PASSWORD}"},
+		{"fill the Confirm password field with hunter2", "fill Confirm password with ${TOD_FILL_CONFIRM_PASSWORD}"},
+		{"click Sign in", "click Sign in"},
+	}
+	for _, tt := range tests {
+		if got := v.recordedInput(tt.input); got != tt.want {
+			t.Errorf("recordedInput(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Flow step actions
+const (
+	flowFill   = "fill"
+	flowSubmit = "submit"
+)
+
+// flowNamePattern is what a flow name may contain, since it names a file
+var flowNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// flowParamPattern matches a {{name}} placeholder in a step's value
+var flowParamPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// flowEnvPattern matches a ${VAR} environment variable reference. Bare $VAR
+// isn't expanded so recorded values with a $ in them survive.
+var flowEnvPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Flow is a recorded form fill and submit sequence, like logging in, saved
+// to .tod/flows to replay with "run flow"
+type Flow struct {
+	Name      string            `json:"name"`
+	URL       string            `json:"url"`              // page the form is on
+	Params    map[string]string `json:"params,omitempty"` // defaults for {{name}} placeholders
+	Steps     []FlowStep        `json:"steps"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// FlowStep is one field filled or form submitted in a flow. A value can hold
+// {{name}} placeholders, filled from the flow's params or "run flow"
+// arguments, and ${VAR} references to environment variables, which is how
+// passwords are kept out of the file.
+type FlowStep struct {
+	Action   string `json:"action"`          // flowFill or flowSubmit
+	Field    string `json:"field,omitempty"` // label of the field filled
+	Type     string `json:"type,omitempty"`  // email, password, username, file or text
+	Selector string `json:"selector"`
+	Value    string `json:"value,omitempty"`
+}
+
+// flowPath returns where the flow with the given name is saved
+func flowPath(name string) string {
+	return filepath.Join(".tod", "flows", name+".json")
+}
+
+// LoadFlow reads a flow saved with "save flow"
+func LoadFlow(name string) (*Flow, error) {
+	data, err := os.ReadFile(flowPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no flow named %q, record one with \"record flow %s\"", name, name)
+		}
+		return nil, fmt.Errorf("failed to read flow: %w", err)
+	}
+
+	var flow Flow
+	if err := json.Unmarshal(data, &flow); err != nil {
+		return nil, fmt.Errorf("failed to parse flow %s: %w", name, err)
+	}
+	return &flow, nil
+}
+
+// startFlowRecording starts recording the form fills and submissions that
+// follow into a flow called name
+func (v *NavigationView) startFlowRecording(name string) error {
+	if !flowNamePattern.MatchString(name) {
+		return fmt.Errorf("flow names can only contain letters, digits, - and _")
+	}
+	if v.flowRecording != nil {
+		return fmt.Errorf("already recording flow %q, \"save flow\" it first", v.flowRecording.Name)
+	}
+	v.flowRecording = &Flow{Name: name, Params: map[string]string{}}
+	v.addHistory(fmt.Sprintf("⏺ Recording flow %q, fill and submit a form then \"save flow\"", name))
+	return nil
+}
+
+// saveFlow stops recording and writes the flow to .tod/flows
+func (v *NavigationView) saveFlow() error {
+	flow := v.flowRecording
+	if flow == nil {
+		return fmt.Errorf("not recording a flow, start with \"record flow <name>\"")
+	}
+	if len(flow.Steps) == 0 {
+		return fmt.Errorf("flow %q has no steps yet, fill in a form first", flow.Name)
+	}
+	flow.CreatedAt = time.Now()
+
+	path := flowPath(flow.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create flows directory: %w", err)
+	}
+	data, err := json.MarshalIndent(flow, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode flow: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write flow: %w", err)
+	}
+
+	v.flowRecording = nil
+	v.flowsSaved++
+	v.addHistory(fmt.Sprintf("💾 Flow %q saved to %s (%d steps)", flow.Name, path, len(flow.Steps)))
+	for _, step := range flow.Steps {
+		if step.Type == "password" {
+			v.addHistory(fmt.Sprintf("   🔒 %s is read from %s, set it before \"run flow %s\"", step.Field, step.Value, flow.Name))
+		}
+	}
+	return nil
+}
+
+// recordFlowFill adds a filled field to the flow being recorded. Emails and
+// usernames become {{email}} and {{username}} params so the flow can be run
+// as another user, and passwords an environment variable reference, so
+// they're never written to disk.
+func (v *NavigationView) recordFlowFill(field *FormField, value string) {
+	flow := v.flowRecording
+	if flow == nil || field == nil {
+		return
+	}
+	if flow.URL == "" {
+		flow.URL = v.currentURL
+	}
+
+	step := FlowStep{Action: flowFill, Field: field.Label, Selector: field.Selector, Type: "text", Value: value}
+	switch field.Type {
+	case EmailField:
+		step.Type, step.Value = "email", "{{email}}"
+		flow.Params["email"] = value
+	case UsernameField:
+		step.Type, step.Value = "username", "{{username}}"
+		flow.Params["username"] = value
+	case PasswordField:
+		step.Type, step.Value = "password", "${"+flowSecretVar(flow.Name)+"}"
+	case FileInput:
+		step.Type = "file"
+	}
+	flow.Steps = append(flow.Steps, step)
+}
+
+// recordFlowSubmit adds a form submission to the flow being recorded
+func (v *NavigationView) recordFlowSubmit() {
+	if v.flowRecording == nil || v.formHandler == nil {
+		return
+	}
+	form := v.formHandler.GetCurrentForm()
+	if form == nil || form.SubmitButton == nil {
+		return
+	}
+	v.flowRecording.Steps = append(v.flowRecording.Steps, FlowStep{
+		Action:   flowSubmit,
+		Field:    form.SubmitButton.Label,
+		Selector: form.SubmitButton.Selector,
+	})
+}
+
+// flowSecretVar is the environment variable a flow's password is read from,
+// e.g. TOD_FLOW_ADMIN_LOGIN_PASSWORD for the flow "admin-login"
+func flowSecretVar(name string) string {
+	return "TOD_FLOW_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_PASSWORD"
+}
+
+// parseRunFlow splits the arguments of "run flow <name> [key=value ...]" or
+// "run flow <name> as <email>" into the flow name and param overrides
+func parseRunFlow(args string) (string, map[string]string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	params := map[string]string{}
+	rest := fields[1:]
+	if len(rest) == 2 && strings.EqualFold(rest[0], "as") {
+		params["email"] = rest[1]
+		return fields[0], params
+	}
+	for _, arg := range rest {
+		if key, value, ok := strings.Cut(arg, "="); ok {
+			params[key] = value
+		}
+	}
+	return fields[0], params
+}
+
+// resolveFlowValue fills in a step value's {{name}} placeholders from params
+// and its ${VAR} references from the environment
+func resolveFlowValue(value string, params map[string]string) (string, error) {
+	var missing []string
+	value = flowParamPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := flowParamPattern.FindStringSubmatch(placeholder)[1]
+		param, ok := params[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+		return param
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("flow needs a value for %s, pass %s=<value>", strings.Join(missing, ", "), missing[0])
+	}
+
+	value, missing = expandEnvReferences(value)
+	if len(missing) > 0 {
+		return "", fmt.Errorf("flow needs $%s set", strings.Join(missing, ", $"))
+	}
+	return value, nil
+}
+
+// expandEnvReferences replaces value's ${VAR} references with their values
+// from the environment, returning the names of any that aren't set
+func expandEnvReferences(value string) (string, []string) {
+	var missing []string
+	value = flowEnvPattern.ReplaceAllStringFunc(value, func(reference string) string {
+		name := flowEnvPattern.FindStringSubmatch(reference)[1]
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return env
+	})
+	return value, missing
+}
+
+// runFlow replays a saved flow: it opens the flow's page, fills each field
+// with FillField and submits with SubmitForm
+func (v *NavigationView) runFlow(args string) error {
+	if v.chromeDPManager == nil || v.formHandler == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+	name, overrides := parseRunFlow(args)
+	flow, err := LoadFlow(name)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]string{}
+	for key, value := range flow.Params {
+		params[key] = value
+	}
+	for key, value := range overrides {
+		params[key] = value
+	}
+
+	// Resolve every value up front so a missing secret fails before anything is typed
+	values := make([]string, len(flow.Steps))
+	for i, step := range flow.Steps {
+		if step.Action != flowFill {
+			continue
+		}
+		if values[i], err = resolveFlowValue(step.Value, params); err != nil {
+			return err
+		}
+	}
+
+	if flow.URL != "" && !samePage(v.currentURL, flow.URL) {
+		if err := v.navigateToURL(flow.URL); err != nil {
+			return err
+		}
+		if err := v.chromeDPManager.WaitForPageLoad(v.chromeDPManager.Timeouts().Navigation); err != nil {
+			return fmt.Errorf("failed to load %s: %w", flow.URL, err)
+		}
+	}
+
+	v.addHistory(fmt.Sprintf("▶ Running flow %q%s", flow.Name, flowParamSummary(overrides)))
+	for i, step := range flow.Steps {
+		switch step.Action {
+		case flowFill:
+			field := &FormField{Type: flowFieldType(step.Type), Selector: step.Selector, Label: step.Field}
+			if err := v.formHandler.FillField(field, values[i]); err != nil {
+				return fmt.Errorf("step %d, %s: %w", i+1, step.Field, err)
+			}
+			v.addHistory(fmt.Sprintf("→ Filled field: %s", step.Field))
+
+		case flowSubmit:
+			v.formHandler.UseSubmitButton(step.Selector)
+			if err := v.guardSubmit(); err != nil {
+				return err
+			}
+			if err := v.formHandler.SubmitForm(); err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+			result, err := v.formHandler.WaitForPageChange(10 * time.Second)
+			if err != nil {
+				return fmt.Errorf("step %d: failed to wait for page change: %w", i+1, err)
+			}
+			if result.ErrorDetected {
+				return fmt.Errorf("step %d: %s", i+1, result.Message)
+			}
+			v.addHistory("→ " + result.Message)
+
+		default:
+			return fmt.Errorf("step %d: unknown action %q", i+1, step.Action)
+		}
+	}
+	v.addHistory(fmt.Sprintf("✅ Flow %q done", flow.Name))
+	return nil
+}
+
+// flowFieldType maps a step's field type back to the form field type
+func flowFieldType(name string) FormFieldType {
+	switch name {
+	case "email":
+		return EmailField
+	case "password":
+		return PasswordField
+	case "username":
+		return UsernameField
+	case "file":
+		return FileInput
+	default:
+		return TextInput
+	}
+}
+
+// flowParamSummary describes the params a flow was run with
+func flowParamSummary(params map[string]string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	var parts []string
+	for key, value := range params {
+		parts = append(parts, key+"="+value)
+	}
+	sort.Strings(parts)
+	return " with " + strings.Join(parts, ", ")
+}
@@ -21,6 +21,7 @@ const (
 	SubmitButton
 	TextInput
 	Unknown
+	FileInput
 )
 
 // FormField represents a detected form field
@@ -54,6 +55,9 @@ type FormHandler struct {
 	currentForm     *LoginForm
 	savedUsers      []config.TestUser
 	domain          string
+
+	// Request sent by the last submission, for replaying as an API call
+	lastSubmitRequest *browser.RequestRecord
 }
 
 // NewFormHandler creates a new form handler
@@ -224,6 +228,10 @@ func (f *FormHandler) determineFieldType(raw map[string]interface{}) FormFieldTy
 		return SubmitButton
 	}
 
+	if inputType == "file" {
+		return FileInput
+	}
+
 	// Check for password field
 	if inputType == "password" || 
 		strings.Contains(name, "password") || 
@@ -322,6 +330,15 @@ func (f *FormHandler) FillField(field *FormField, value string) error {
 		return fmt.Errorf("invalid field")
 	}
 
+	// File inputs take paths, and are often hidden behind a styled button
+	if field.Type == FileInput {
+		if err := f.chromeDPManager.UploadFile(field.Selector, value); err != nil {
+			return err
+		}
+		field.Value = value
+		return nil
+	}
+
 	// Wait for the field to be visible
 	if err := f.chromeDPManager.WaitForElement(field.Selector); err != nil {
 		return fmt.Errorf("field not found: %w", err)
@@ -344,13 +361,13 @@ func (f *FormHandler) FillField(field *FormField, value string) error {
 // clearField clears a form field
 func (f *FormHandler) clearField(selector string) error {
 	script := fmt.Sprintf(`
-		const element = document.querySelector('%s');
+		const element = document.querySelector(%s);
 		if (element) {
 			element.value = '';
 			element.dispatchEvent(new Event('input', { bubbles: true }));
 			element.dispatchEvent(new Event('change', { bubbles: true }));
 		}
-	`, selector)
+	`, browser.QuoteJS(selector))
 
 	return f.chromeDPManager.ExecuteScript(script, nil)
 }
@@ -362,13 +379,29 @@ func (f *FormHandler) SubmitForm() error {
 	}
 
 	// Click the submit button
+	submittedAt := time.Now()
 	if err := f.chromeDPManager.Click(f.currentForm.SubmitButton.Selector); err != nil {
 		return fmt.Errorf("failed to submit form: %w", err)
 	}
 
+	// Give the page a moment to send the request the submission triggered
+	f.lastSubmitRequest = nil
+	for i := 0; i < 10 && f.lastSubmitRequest == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		f.lastSubmitRequest = f.chromeDPManager.FindSubmitRequest(submittedAt)
+	}
+	if f.lastSubmitRequest != nil {
+		logging.Debug("Form submission sent %s %s", f.lastSubmitRequest.Method, f.lastSubmitRequest.URL)
+	}
+
 	return nil
 }
 
+// LastSubmitRequest returns the request sent by the last form submission, if any
+func (f *FormHandler) LastSubmitRequest() *browser.RequestRecord {
+	return f.lastSubmitRequest
+}
+
 // WaitForPageChange waits for the page to change after form submission
 func (f *FormHandler) WaitForPageChange(timeout time.Duration) (*PageChangeResult, error) {
 	if f.chromeDPManager == nil {
@@ -482,6 +515,17 @@ func (f *FormHandler) GetCurrentForm() *LoginForm {
 	return f.currentForm
 }
 
+// UseSubmitButton makes SubmitForm click selector, for replaying a form
+// that hasn't been detected on the current page
+func (f *FormHandler) UseSubmitButton(selector string) {
+	var form LoginForm
+	if f.currentForm != nil {
+		form = *f.currentForm
+	}
+	form.SubmitButton = &FormField{Type: SubmitButton, Selector: selector}
+	f.currentForm = &form
+}
+
 // GetDomain returns the current domain
 func (f *FormHandler) GetDomain() string {
 	return f.domain
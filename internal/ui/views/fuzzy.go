@@ -0,0 +1,85 @@
+package views
+
+import "strings"
+
+// fuzzyPrefixScore is the score of a query the target starts with
+const fuzzyPrefixScore = 0.9
+
+// FuzzyScore rates how well query matches target from 0 to 1. Exact matches
+// score 1 and prefixes 0.9; anything else scores by normalized edit
+// distance, against the whole target, its words and its prefix, so typos
+// like "dashbaord" still match "Dashboard" well above 0.6.
+func FuzzyScore(query, target string) float64 {
+	q := []rune(strings.ToLower(normalizeText(query)))
+	t := []rune(strings.ToLower(normalizeText(target)))
+	if len(q) == 0 || len(t) == 0 {
+		return 0
+	}
+
+	if string(q) == string(t) {
+		return 1.0
+	}
+	if strings.HasPrefix(string(t), string(q)) {
+		return fuzzyPrefixScore
+	}
+
+	best := similarity(q, t)
+
+	// Compare against runs of as many target words as the query has, so
+	// "dashbaord" is scored against "Dashboard" in "Open Dashboard"
+	queryWords := len(strings.Fields(string(q)))
+	targetWords := strings.Fields(string(t))
+	for i := 0; i+queryWords <= len(targetWords); i++ {
+		window := []rune(strings.Join(targetWords[i:i+queryWords], " "))
+		if score := similarity(q, window); score > best {
+			best = score
+		}
+	}
+
+	// A typo in a partially typed target counts like a prefix
+	if len(t) > len(q) {
+		if score := similarity(q, t[:len(q)]) * fuzzyPrefixScore; score > best {
+			best = score
+		}
+	}
+
+	return best
+}
+
+// similarity is 1 minus the edit distance normalized by the longer length
+func similarity(a, b []rune) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1.0
+	}
+	return 1.0 - float64(editDistance(a, b))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance between a and b, with swapping
+// two adjacent characters counted as one edit since it's the most common typo
+func editDistance(a, b []rune) int {
+	// Three rolling rows: two back for transpositions, previous and current
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,69 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/lance13c/tod/internal/config"
+)
+
+func TestFuzzyScoreToleratesTypos(t *testing.T) {
+	tests := []struct {
+		query  string
+		target string
+		min    float64
+	}{
+		{"dashboard", "Dashboard", 1.0},
+		{"dash", "Dashboard", fuzzyPrefixScore},
+		{"dashbaord", "Dashboard", fuzzyTypoThreshold},
+		{"dashbaord", "Open Dashboard", fuzzyTypoThreshold},
+		{"setings", "Settings", fuzzyTypoThreshold},
+		{"sign ni", "Sign in", fuzzyTypoThreshold},
+		{"chekout", "Checkout now", fuzzyTypoThreshold},
+	}
+	for _, tt := range tests {
+		if got := FuzzyScore(tt.query, tt.target); got < tt.min {
+			t.Errorf("FuzzyScore(%q, %q) = %.2f, want at least %.2f", tt.query, tt.target, got, tt.min)
+		}
+	}
+}
+
+func TestFuzzyScoreRejectsUnrelated(t *testing.T) {
+	tests := []struct {
+		query  string
+		target string
+	}{
+		{"dashboard", "Log out"},
+		{"settings", "Contact us"},
+		{"cart", "Privacy policy"},
+	}
+	for _, tt := range tests {
+		if got := FuzzyScore(tt.query, tt.target); got >= fuzzyTypoThreshold {
+			t.Errorf("FuzzyScore(%q, %q) = %.2f, want below %.2f", tt.query, tt.target, got, fuzzyTypoThreshold)
+		}
+	}
+}
+
+func TestEditDistanceCountsTranspositionAsOneEdit(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"dashboard", "dashboard", 0},
+		{"dashbaord", "dashboard", 1},
+		{"setings", "settings", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTypoStillMatchesElement(t *testing.T) {
+	v := &NavigationView{weights: config.DefaultMatchingConfig().Weights}
+	if score := v.scoreElement("dashbaord", NavigableElement{Type: LinkElement, Text: "Dashboard"}); score <= 0.5 {
+		t.Errorf("scoreElement(dashbaord, Dashboard) = %.2f, want a match above 0.5", score)
+	}
+}
@@ -0,0 +1,78 @@
+package views
+
+// maxInputHistory is how many submitted inputs Up can recall
+const maxInputHistory = 100
+
+// inputHistory is a ring buffer of submitted inputs recalled with Up and Down
+// like shell history
+type inputHistory struct {
+	entries []string
+	start   int // index of the oldest entry once the buffer is full
+	cursor  int // position being recalled, or -1 when not recalling
+}
+
+// newInputHistory creates an empty input history
+func newInputHistory() *inputHistory {
+	return &inputHistory{cursor: -1}
+}
+
+// add records a submitted input and ends any recall. Repeats of the last
+// input and bare choice numbers, which mean nothing once the choices are
+// gone, aren't recorded.
+func (h *inputHistory) add(input string) {
+	h.cursor = -1
+	if input == "" {
+		return
+	}
+	if _, ok := parseChoiceNumber(input); ok {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.at(n-1) == input {
+		return
+	}
+
+	if len(h.entries) < maxInputHistory {
+		h.entries = append(h.entries, input)
+		return
+	}
+	h.entries[h.start] = input
+	h.start = (h.start + 1) % len(h.entries)
+}
+
+// at returns the i-th oldest entry
+func (h *inputHistory) at(i int) string {
+	return h.entries[(h.start+i)%len(h.entries)]
+}
+
+// recalling reports whether Up has been pressed since the last submit
+func (h *inputHistory) recalling() bool {
+	return h.cursor >= 0
+}
+
+// previous steps back to the next older input. ok is false if there is none.
+func (h *inputHistory) previous() (string, bool) {
+	if len(h.entries) == 0 {
+		return "", false
+	}
+	switch {
+	case h.cursor < 0:
+		h.cursor = len(h.entries) - 1
+	case h.cursor > 0:
+		h.cursor--
+	}
+	return h.at(h.cursor), true
+}
+
+// next steps forward to the next newer input; past the newest it ends the
+// recall and returns an empty input
+func (h *inputHistory) next() string {
+	if h.cursor < 0 {
+		return ""
+	}
+	h.cursor++
+	if h.cursor >= len(h.entries) {
+		h.cursor = -1
+		return ""
+	}
+	return h.at(h.cursor)
+}
@@ -78,6 +78,10 @@ func NewInputModal(fieldType FormFieldType, fieldLabel, placeholder, domain stri
 	ti.Placeholder = placeholder
 	ti.CharLimit = 200
 	ti.Width = 50
+	if fieldType == PasswordField {
+		ti.EchoMode = textinput.EchoPassword
+		ti.EchoCharacter = '•'
+	}
 	ti.Focus()
 
 	// Convert saved users to options
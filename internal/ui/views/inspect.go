@@ -0,0 +1,82 @@
+package views
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/browser"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// toggleInspect turns inspect mode on or off. While it's on, clicking an
+// element in the browser puts its selector in the input instead of clicking it.
+func (v *NavigationView) toggleInspect() error {
+	if v.stopInspect() {
+		return nil
+	}
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+	if v.chromeDPManager.IsHeadless() {
+		return fmt.Errorf("inspect mode needs a visible browser, run \"headful\" first")
+	}
+
+	picks, err := v.chromeDPManager.StartInspect()
+	if err != nil {
+		return err
+	}
+	v.addHistory("🔍 Inspect mode on: click an element in the browser to pick its selector (Esc or \"inspect\" to stop)")
+	v.startedStream = InspectStreamMsg{Picks: picks}
+	return nil
+}
+
+// stopInspect turns inspect mode off, reporting whether it was on
+func (v *NavigationView) stopInspect() bool {
+	if v.inspectPicks == nil {
+		return false
+	}
+	v.inspectPicks = nil
+	if v.chromeDPManager != nil {
+		if err := v.chromeDPManager.StopInspect(); err != nil {
+			logging.Warn("%v", err)
+		}
+	}
+	v.addHistory("🔍 Inspect mode off, the page takes clicks again")
+	return true
+}
+
+// waitForInspectPick reads the next element picked in inspect mode
+func waitForInspectPick(picks <-chan browser.InspectedElement) tea.Cmd {
+	return func() tea.Msg {
+		picked, ok := <-picks
+		if !ok {
+			return InspectDoneMsg{}
+		}
+		return InspectPickMsg{Element: picked}
+	}
+}
+
+// handleInspectMsg puts each picked element's selector in the input
+func (v *NavigationView) handleInspectMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case InspectStreamMsg:
+		v.isProcessing = false
+		v.inspectPicks = msg.Picks
+		return waitForInspectPick(msg.Picks)
+
+	case InspectPickMsg:
+		if v.inspectPicks == nil {
+			return nil
+		}
+		picked := msg.Element
+		label := "<" + picked.Tag + ">"
+		if picked.Text != "" {
+			label += fmt.Sprintf(" %q", truncateText(picked.Text, 40))
+		}
+		v.addHistory(fmt.Sprintf("🔍 Picked %s: %s", label, picked.Selector))
+		v.input.SetValue(picked.Selector)
+		v.input.CursorEnd()
+		return waitForInspectPick(v.inspectPicks)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// navigationKeyMap holds the key bindings of the navigation view. Help text is
+// generated from these bindings so it always matches what the keys do.
+type navigationKeyMap struct {
+	Complete key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Go       key.Binding
+	Clear    key.Binding
+	Analyze  key.Binding
+	Back     key.Binding
+	Undo     key.Binding
+	Copy     key.Binding
+	Actions  key.Binding
+	Export   key.Binding
+	Help     key.Binding
+	Quit     key.Binding
+}
+
+// defaultNavigationKeyMap returns the default navigation key bindings
+func defaultNavigationKeyMap() navigationKeyMap {
+	return navigationKeyMap{
+		Complete: key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "complete")),
+		Up:       key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous suggestion or input")),
+		Down:     key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next suggestion or input")),
+		PageUp:   key.NewBinding(key.WithKeys("pgup"), key.WithHelp("PgUp", "page up through suggestions")),
+		PageDown: key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("PgDn", "page down through suggestions")),
+		Go:       key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "go")),
+		Clear:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "clear")),
+		Analyze:  key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("Ctrl+R", "re-analyze page")),
+		Back:     key.NewBinding(key.WithKeys("ctrl+b"), key.WithHelp("Ctrl+B", "browser back")),
+		Undo:     key.NewBinding(key.WithKeys("ctrl+z"), key.WithHelp("Ctrl+Z", "undo last navigation or fill")),
+		Copy:     key.NewBinding(key.WithKeys("ctrl+y"), key.WithHelp("Ctrl+Y", "copy highlighted element's selector")),
+		Actions:  key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("Ctrl+T", "actions")),
+		Export:   key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("Ctrl+E", "export session JSON")),
+		Help:     key.NewBinding(key.WithKeys("ctrl+h"), key.WithHelp("Ctrl+H", "help")),
+		Quit:     key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("Ctrl+C", "quit")),
+	}
+}
+
+// ShortHelp returns the bindings shown in the one-line help bar
+func (k navigationKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Complete, k.Down, k.Go, k.Clear, k.Actions, k.Help, k.Quit}
+}
+
+// FullHelp returns every binding, in the order shown in the help panel
+func (k navigationKeyMap) FullHelp() []key.Binding {
+	return []key.Binding{k.Complete, k.Up, k.Down, k.PageUp, k.PageDown, k.Go, k.Clear, k.Analyze, k.Back, k.Undo, k.Copy, k.Actions, k.Export, k.Help, k.Quit}
+}
+
+// renderShortHelp renders the one-line help bar from the key map
+func (v *NavigationView) renderShortHelp() string {
+	var parts []string
+	for _, b := range v.keys.ShortHelp() {
+		if !b.Enabled() {
+			continue
+		}
+		h := b.Help()
+		parts = append(parts, fmt.Sprintf("[%s: %s]", h.Key, h.Desc))
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderFullHelp renders every key binding and command, including aliases
+func (v *NavigationView) renderFullHelp() string {
+	lines := []string{v.subtitleStyle.Render(fmt.Sprintf("Keys — %s or %s to close", v.keys.Help.Help().Key, v.keys.Clear.Help().Key))}
+	for _, b := range v.keys.FullHelp() {
+		if !b.Enabled() {
+			continue
+		}
+		h := b.Help()
+		lines = append(lines, fmt.Sprintf("  %-8s %s", h.Key, h.Desc))
+	}
+
+	// Group aliases by the command they expand to
+	aliases := make(map[string][]string)
+	for alias, display := range commandAliases {
+		aliases[display] = append(aliases[display], alias)
+	}
+
+	lines = append(lines, "", v.subtitleStyle.Render("Commands"))
+	for _, command := range v.getAvailableCommands() {
+		line := fmt.Sprintf("  %-30s %s", command.Display, command.Description)
+		if names := aliases[command.Display]; len(names) > 0 {
+			sort.Strings(names)
+			line += fmt.Sprintf(" (aliases: %s)", strings.Join(names, ", "))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines,
+		fmt.Sprintf("  %-30s %s", "go to <page>", "Navigate to a page by name or path"),
+		fmt.Sprintf("  %-30s %s", "click <element>", "Click an element by its text"),
+		fmt.Sprintf("  %-30s %s", "fill <label> with <value>", "Fill a field by its label, aria label or placeholder"),
+		fmt.Sprintf("  %-30s %s", "select <option>", "Choose an option from a dropdown"),
+		fmt.Sprintf("  %-30s %s", "scroll to <element|top|bottom>", "Scroll to an element, loading more content if needed"),
+		fmt.Sprintf("  %-30s %s", "wait <ms> | wait for <sel>", "Pause, or wait for an element to appear"),
+		fmt.Sprintf("  %-30s %s", "discover file <path>", "List the actions in a saved HTML page, without the browser"),
+		fmt.Sprintf("  %-30s %s", "verify <selector>", "Count elements matching a CSS selector or //XPath"),
+		fmt.Sprintf("  %-30s %s", "screenshot of <sel> [as <f>]", "Save a PNG of one element to .tod/screenshots"),
+		fmt.Sprintf("  %-30s %s", "llm log <capture id>", "Browse the LLM prompts and responses of one capture"),
+		fmt.Sprintf("  %-30s %s", "switch to tab <n>", "Switch to a tab listed by \"list tabs\""),
+		fmt.Sprintf("  %-30s %s", "load session <id>", "Resume a session saved with \"save session\""),
+		fmt.Sprintf("  %-30s %s", "record flow <name>", "Record a form fill and submit, then \"save flow\""),
+		fmt.Sprintf("  %-30s %s", "run flow <name> [as <email>]", "Replay a flow, optionally as another user"),
+		fmt.Sprintf("  %-30s %s", "ask <question>", "Ask the LLM about this page (Esc stops the answer)"),
+		fmt.Sprintf("  %-30s %s", "describe", "Have the LLM summarize this page and its key actions"),
+	)
+
+	return v.borderStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,324 @@
+package views
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lance13c/tod/internal/config"
+	"github.com/lance13c/tod/internal/database"
+	"github.com/lance13c/tod/internal/llm"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// llmInspectorLimit is how many recent interactions "llm log" lists
+const llmInspectorLimit = 50
+
+// redactedSecret replaces a secret in a rendered prompt or response
+const redactedSecret = "[redacted]"
+
+// secretPatterns match credentials that look like secrets wherever they
+// appear: provider API keys, bearer tokens and key=value style assignments
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{16,}`),
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{30,}`),
+	regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]{8,}`),
+	regexp.MustCompile(`(?i)\b(api[_-]?key|password|passwd|secret|token|access[_-]?token)(["']?\s*[:=]\s*["']?)[^\s"',}]+`),
+}
+
+var searchMatchStyle = lipgloss.NewStyle().Background(lipgloss.Color("220")).Foreground(lipgloss.Color("0"))
+
+// llmInspector browses recent LLM interactions from the database: a list of
+// them, and the full prompt and response of the one opened in a scrollable
+// viewport that can be searched
+type llmInspector struct {
+	interactions []database.LLMInteraction
+	selected     int
+	secrets      []string // known secrets to redact, from the config and test users
+
+	// Opened interaction, nil while the list is shown
+	open    *database.LLMInteraction
+	detail  viewport.Model
+	lines   []string // redacted, wrapped lines of the opened interaction
+	search  textinput.Model
+	query   string
+	matches []int // line numbers matching query
+	match   int   // current match in matches
+}
+
+// showLLMInspector opens the inspector on the most recent LLM interactions,
+// or with args a capture ID, that capture's
+func (v *NavigationView) showLLMInspector(args string) error {
+	db, err := database.New(database.ProjectPath("."))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	var interactions []database.LLMInteraction
+	if args = strings.TrimPrefix(strings.TrimSpace(args), "#"); args != "" {
+		captureID, err := strconv.ParseInt(args, 10, 64)
+		if err != nil {
+			return fmt.Errorf("expected a capture ID, got %q", args)
+		}
+		interactions, err = db.GetLLMInteractions(captureID)
+		if err != nil {
+			return err
+		}
+	} else if interactions, err = db.GetRecentLLMInteractions(llmInspectorLimit); err != nil {
+		return err
+	}
+	if len(interactions) == 0 {
+		return fmt.Errorf("no LLM interactions recorded yet")
+	}
+
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "search prompt and response"
+	v.llmInspector = &llmInspector{
+		interactions: interactions,
+		secrets:      v.knownSecrets(),
+		search:       search,
+	}
+	v.addHistory(fmt.Sprintf("🔎 Inspecting %d LLM interactions", len(interactions)))
+	return nil
+}
+
+// recordLLMInteraction saves an LLM call so "llm log" can show it
+func recordLLMInteraction(interaction llm.Interaction) {
+	db, err := database.New(database.ProjectPath("."))
+	if err != nil {
+		logging.Warn("Failed to open database to record LLM interaction: %v", err)
+		return
+	}
+	defer db.Close()
+
+	record := &database.LLMInteraction{
+		InteractionType: interaction.Type,
+		Provider:        string(interaction.Provider),
+		Model:           interaction.Model,
+		Prompt:          interaction.Prompt,
+		Response:        interaction.Response,
+		LatencyMs:       interaction.Latency.Milliseconds(),
+	}
+	if usage := interaction.Usage; usage != nil {
+		record.TokensUsed = int(usage.TotalTokens)
+		record.Cost = usage.TotalCost
+	}
+	if interaction.Err != nil {
+		record.Error = interaction.Err.Error()
+	}
+	if _, err := db.SaveLLMInteraction(record); err != nil {
+		logging.Warn("Failed to record LLM interaction: %v", err)
+	}
+}
+
+// knownSecrets returns the configured API key and test user credentials,
+// which are redacted from prompts they leaked into
+func (v *NavigationView) knownSecrets() []string {
+	var secrets []string
+	if v.config != nil {
+		secrets = append(secrets, v.config.AI.APIKey)
+	}
+	if users, err := config.NewTestUserLoader(".").Load(); err == nil {
+		for _, user := range users.Users {
+			secrets = append(secrets, user.Password)
+			if auth := user.AuthConfig; auth != nil {
+				secrets = append(secrets, auth.Password, auth.Token, auth.ClientSecret, auth.AccessToken, auth.RefreshToken)
+			}
+		}
+	}
+
+	known := secrets[:0]
+	for _, secret := range secrets {
+		// Very short values would redact ordinary words
+		if len(secret) >= 6 {
+			known = append(known, secret)
+		}
+	}
+	return known
+}
+
+// redactSecrets replaces known secrets and anything that looks like a
+// credential in text with redactedSecret
+func redactSecrets(text string, known []string) string {
+	for _, secret := range known {
+		text = strings.ReplaceAll(text, secret, redactedSecret)
+	}
+	for i, pattern := range secretPatterns {
+		if i == len(secretPatterns)-1 {
+			// Keep the key name so it's clear what was there
+			text = pattern.ReplaceAllString(text, "${1}${2}"+redactedSecret)
+			continue
+		}
+		text = pattern.ReplaceAllString(text, redactedSecret)
+	}
+	return text
+}
+
+// handleLLMInspectorKey handles keys while the inspector is open: the list
+// is moved through with up and down and opened with enter; an opened
+// interaction scrolls, / searches it and n and N step through matches. Esc
+// backs out a level.
+func (v *NavigationView) handleLLMInspectorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	inspector := v.llmInspector
+
+	if inspector.search.Focused() {
+		switch msg.Type {
+		case tea.KeyEsc:
+			inspector.search.Blur()
+			inspector.search.SetValue(inspector.query)
+		case tea.KeyEnter:
+			inspector.search.Blur()
+			inspector.runSearch(inspector.search.Value())
+		default:
+			var cmd tea.Cmd
+			inspector.search, cmd = inspector.search.Update(msg)
+			return v, cmd
+		}
+		return v, nil
+	}
+
+	if inspector.open == nil {
+		switch msg.String() {
+		case "esc", "q":
+			v.llmInspector = nil
+		case "up", "k":
+			inspector.selected = max(inspector.selected-1, 0)
+		case "down", "j":
+			inspector.selected = min(inspector.selected+1, len(inspector.interactions)-1)
+		case "enter":
+			inspector.openSelected(max(v.width-4, 20), max(v.maxVisibleSuggestions-3, 3))
+		}
+		return v, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		inspector.open = nil
+		inspector.query = ""
+		inspector.matches = nil
+		inspector.search.SetValue("")
+		return v, nil
+	case "/":
+		inspector.search.Focus()
+		return v, textinput.Blink
+	case "n":
+		inspector.stepMatch(1)
+		return v, nil
+	case "N":
+		inspector.stepMatch(-1)
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	inspector.detail, cmd = inspector.detail.Update(msg)
+	return v, cmd
+}
+
+// openSelected opens the selected interaction in the detail viewport
+func (i *llmInspector) openSelected(width, height int) {
+	interaction := i.interactions[i.selected]
+	i.open = &interaction
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "#%d %s · %s/%s · %d tokens · $%.4f · %dms · %s\n",
+		interaction.ID, interaction.InteractionType, interaction.Provider, interaction.Model,
+		interaction.TokensUsed, interaction.Cost, interaction.LatencyMs, interaction.CreatedAt.Format("2006-01-02 15:04:05"))
+	if interaction.Error != "" {
+		fmt.Fprintf(&content, "Error: %s\n", interaction.Error)
+	}
+	fmt.Fprintf(&content, "\n── Prompt ──\n%s\n\n── Response ──\n%s\n", interaction.Prompt, interaction.Response)
+
+	text := redactSecrets(content.String(), i.secrets)
+	wrapped := lipgloss.NewStyle().Width(width).Render(text)
+	i.lines = strings.Split(wrapped, "\n")
+
+	i.detail = viewport.New(width, height)
+	i.detail.SetContent(wrapped)
+}
+
+// runSearch finds the lines of the opened interaction containing query,
+// case-insensitively, and scrolls to the first
+func (i *llmInspector) runSearch(query string) {
+	i.query = query
+	i.matches = nil
+	i.match = 0
+	if query == "" {
+		i.detail.SetContent(strings.Join(i.lines, "\n"))
+		return
+	}
+
+	lower := strings.ToLower(query)
+	highlighted := make([]string, len(i.lines))
+	for n, line := range i.lines {
+		highlighted[n] = line
+		if strings.Contains(strings.ToLower(line), lower) {
+			i.matches = append(i.matches, n)
+			highlighted[n] = searchMatchStyle.Render(line)
+		}
+	}
+	i.detail.SetContent(strings.Join(highlighted, "\n"))
+	if len(i.matches) > 0 {
+		i.detail.SetYOffset(i.matches[0])
+	}
+}
+
+// stepMatch scrolls to the next (1) or previous (-1) search match
+func (i *llmInspector) stepMatch(step int) {
+	if len(i.matches) == 0 {
+		return
+	}
+	i.match = (i.match + step + len(i.matches)) % len(i.matches)
+	i.detail.SetYOffset(i.matches[i.match])
+}
+
+// renderLLMInspector renders the interaction list, or the opened
+// interaction, in place of the suggestions
+func (v *NavigationView) renderLLMInspector() string {
+	inspector := v.llmInspector
+	if inspector.open != nil {
+		header := "LLM interaction — ↑/↓ scroll, / search, n/N next/previous match, esc back"
+		footer := inspector.search.View()
+		if !inspector.search.Focused() && inspector.query != "" {
+			if len(inspector.matches) == 0 {
+				footer = fmt.Sprintf("No matches for %q", inspector.query)
+			} else {
+				footer = fmt.Sprintf("Match %d of %d for %q", inspector.match+1, len(inspector.matches), inspector.query)
+			}
+		} else if !inspector.search.Focused() {
+			footer = fmt.Sprintf("%3.f%%", inspector.detail.ScrollPercent()*100)
+		}
+		return strings.Join([]string{
+			v.subtitleStyle.Render(header),
+			inspector.detail.View(),
+			v.subtitleStyle.Render(footer),
+		}, "\n")
+	}
+
+	lines := []string{v.subtitleStyle.Render("LLM interactions — ↑/↓ select, enter open, esc close")}
+	limit := max(v.maxVisibleSuggestions-2, 3)
+	start := max(0, min(inspector.selected-limit+1, len(inspector.interactions)-limit))
+	width := max(v.width-4, 20)
+	for n := start; n < len(inspector.interactions) && n < start+limit; n++ {
+		interaction := inspector.interactions[n]
+		line := fmt.Sprintf("#%-5d %s  %-18s %-28s %6d tok  $%.4f",
+			interaction.ID, interaction.CreatedAt.Format("01-02 15:04"), truncateText(interaction.InteractionType, 18),
+			truncateText(interaction.Model, 28), interaction.TokensUsed, interaction.Cost)
+		if interaction.Error != "" {
+			line += "  ❌ " + interaction.Error
+		}
+		line = truncateText(line, width)
+		if n == inspector.selected {
+			lines = append(lines, v.selectedStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, v.suggestionStyle.Render("  "+line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
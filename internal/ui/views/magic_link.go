@@ -0,0 +1,169 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/config"
+	"github.com/lance13c/tod/internal/logging"
+	"github.com/lance13c/tod/internal/users"
+)
+
+// magicLinkCheckInterval is how often email is checked for a magic link
+const magicLinkCheckInterval = 5 * time.Second
+
+// handleMagicLinkSent starts polling email for the magic link just sent,
+// reporting each check to the history until the link is found, the wait
+// times out or Esc cancels it
+func (v *NavigationView) handleMagicLinkSent() tea.Cmd {
+	return func() tea.Msg {
+		// Check if we have email checking capability
+		if v.authFlow == nil {
+			v.addHistory("⚠️ Email checking not configured")
+			return NavigationCompleteMsg{
+				URL:     v.currentURL,
+				Success: true,
+			}
+		}
+
+		// Get the current user's email from the form
+		var userEmail string
+		if v.currentForm != nil && v.currentForm.EmailField != nil {
+			userEmail = v.currentForm.EmailField.Value
+		}
+
+		if userEmail == "" {
+			v.addHistory("⚠️ No email address found for magic link checking")
+			return NavigationCompleteMsg{
+				URL:     v.currentURL,
+				Success: true,
+			}
+		}
+
+		timeout := config.DefaultMagicLinkTimeout
+		if v.config != nil {
+			if configured, err := v.config.MagicLinkTimeout(); err == nil {
+				timeout = configured
+			} else {
+				logging.Warn("Using default magic link timeout: %v", err)
+			}
+		}
+
+		v.addHistory(fmt.Sprintf("📧 Checking email for magic link every %v for up to %v (Esc to cancel)...", magicLinkCheckInterval, timeout))
+
+		// Create a test user for the magic link authentication
+		testUser := &config.TestUser{
+			Name:     "Magic Link User",
+			Email:    userEmail,
+			AuthType: "magic_link",
+			AuthConfig: &config.TestUserAuthConfig{
+				EmailCheckEnabled: true,
+				EmailTimeout:      int(timeout.Seconds()),
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		updates := make(chan tea.Msg)
+		v.magicLinkCancel = cancel
+
+		go func() {
+			defer close(updates)
+			progress := func(attempt int, elapsed time.Duration) {
+				select {
+				case updates <- MagicLinkProgressMsg{Text: fmt.Sprintf("📧 Checking email... attempt %d (%v elapsed)", attempt, elapsed.Round(time.Second))}:
+				case <-ctx.Done():
+				}
+			}
+
+			authResult := v.authFlow.AuthenticateWithContinuousEmailSupportContext(ctx, testUser, magicLinkCheckInterval, timeout, progress)
+			updates <- MagicLinkDoneMsg{Result: v.followMagicLink(authResult, testUser)}
+		}()
+
+		msg := MagicLinkWaitMsg{Updates: updates}
+		v.recordExecutedStep("magic_link", userEmail, "", msg)
+		return msg
+	}
+}
+
+// followMagicLink opens the magic link the wait found and returns the
+// message that ends the wait
+func (v *NavigationView) followMagicLink(authResult *users.AuthenticationResult, user *config.TestUser) tea.Msg {
+	if errors.Is(authResult.Error, context.Canceled) {
+		return CommandCompleteMsg{}
+	}
+	link := authResult.RedirectURL
+	if !authResult.Success || link == "" {
+		v.addHistory(fmt.Sprintf("❌ Magic link not found: %s", authResult.Message))
+		return NavigationErrorMsg{Error: authResult.Error}
+	}
+
+	v.addHistory(fmt.Sprintf("✅ Found magic link: %s", truncateText(link, 50)))
+	v.addHistory("🔗 Following magic link...")
+
+	// Navigate to the magic link
+	if err := v.chromeDPManager.Navigate(link); err != nil {
+		v.addHistory(fmt.Sprintf("❌ Failed to navigate to magic link: %v", err))
+		return NavigationErrorMsg{Error: err}
+	}
+
+	// Save this user for future use
+	if v.authConfig != nil {
+		domain := v.formHandler.GetDomain()
+		v.authConfig.SaveMagicLinkUserForDomain(domain, user.Email, user.Name)
+	}
+
+	v.addHistory("🎉 Successfully authenticated with magic link")
+	return NavigationCompleteMsg{
+		URL:     link,
+		Success: true,
+	}
+}
+
+// waitForMagicLinkUpdate reads the next update of the magic link wait
+func waitForMagicLinkUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return MagicLinkDoneMsg{Result: CommandCompleteMsg{}}
+		}
+		return msg
+	}
+}
+
+// handleMagicLinkMsg reports the magic link wait as it progresses
+func (v *NavigationView) handleMagicLinkMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case MagicLinkWaitMsg:
+		v.magicLinkUpdates = msg.Updates
+		return waitForMagicLinkUpdate(msg.Updates)
+
+	case MagicLinkProgressMsg:
+		v.addHistory(msg.Text)
+		return waitForMagicLinkUpdate(v.magicLinkUpdates)
+
+	case MagicLinkDoneMsg:
+		if v.magicLinkCancel != nil {
+			v.magicLinkCancel()
+			v.magicLinkCancel = nil
+		}
+		v.magicLinkUpdates = nil
+		result := msg.Result
+		return func() tea.Msg { return result }
+	}
+	return nil
+}
+
+// stopMagicLinkWait cancels a magic link wait, reporting whether one was running
+func (v *NavigationView) stopMagicLinkWait() bool {
+	if v.magicLinkCancel == nil {
+		return false
+	}
+	logging.Info("Cancelling magic link wait")
+	v.magicLinkCancel()
+	v.magicLinkCancel = nil
+	v.addHistory("🛑 Magic link wait cancelled.")
+	return true
+}
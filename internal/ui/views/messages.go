@@ -1,5 +1,14 @@
 package views
 
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/browser"
+	"github.com/lance13c/tod/internal/llm"
+	"github.com/lance13c/tod/internal/testing"
+)
+
 // Message types used by views
 type ChromeLaunchedMsg struct{}
 type ChromeErrorMsg struct {
@@ -10,5 +19,66 @@ type NavigationCompleteMsg struct {
 	Success bool
 	Error   error
 }
+type CommandCompleteMsg struct{} // a local command finished without navigating
+type PlannedActionMsg struct {   // an action plan-only mode simulated instead of executing
+	Verb   string
+	Target string
+}
+type ConfirmDestructiveMsg struct { // an action looks destructive and waits for a yes
+	Text     string
+	Selector string
+	Word     string         // the destructive word it contains
+	Retry    func() tea.Msg // runs the action again once confirmed
+}
+type SessionTimeoutMsg struct{} // session.max_duration was reached
 type ReturnToMenuMsg struct{}
-type RestartConfigMsg struct{}
\ No newline at end of file
+type RestartConfigMsg struct{}
+type AnswerStreamMsg struct { // an answer about the page started streaming
+	Question string
+	Chunks   <-chan string
+}
+type AnswerChunkMsg struct{ Text string }  // more of the streamed answer arrived
+type AnswerDoneMsg struct{}                // the streamed answer ended or was cancelled
+type AnswerFailedMsg struct{ Error error } // the answer couldn't be started
+type MagicLinkWaitMsg struct { // started polling email for a magic link
+	Updates <-chan tea.Msg
+}
+type MagicLinkProgressMsg struct{ Text string } // the magic link wait checked email again
+type MagicLinkDoneMsg struct{ Result tea.Msg }  // the magic link wait ended or was cancelled
+type LLMHealthMsg struct{ Error error }          // the startup LLM health check finished
+type CaptureStreamMsg struct {                   // a capture started watching the page for new content
+	Updates <-chan tea.Msg
+}
+type CaptureProgressMsg struct { // a capture discovered actions in new content
+	Added []string
+	Total int
+}
+type CaptureDoneMsg struct { // a capture finished polling and saved its actions
+	Summary string
+	Error   error
+}
+type PastedCommandsMsg struct { // pasted lines were interpreted and can run
+	Lines           []string
+	Interpretations []*llm.CommandInterpretation // nil if they couldn't be interpreted
+}
+type CrawlStreamMsg struct { // a crawl started visiting linked pages
+	Updates <-chan tea.Msg
+}
+type CrawlPageMsg struct{ Page testing.PageActions } // a crawled page was analyzed
+type CrawlDoneMsg struct {                           // a crawl finished, was cancelled or ran out of time
+	Pages   int
+	Actions int
+	Skipped []string // links not followed because they look destructive
+	Error   error
+}
+type WaitResultMsg struct { // a "wait" slept, or the element it waited for appeared or timed out
+	Selector string        // empty for a plain sleep
+	Appeared bool          // false if the selector timed out
+	Waited   time.Duration // how long the wait took
+	Timeout  time.Duration
+}
+type InspectStreamMsg struct { // inspect mode was turned on
+	Picks <-chan browser.InspectedElement
+}
+type InspectPickMsg struct{ Element browser.InspectedElement } // an element was picked in the browser
+type InspectDoneMsg struct{}                                   // inspect mode was turned off
@@ -0,0 +1,112 @@
+package views
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// DefaultNavigationLogMaxSize is the size at which the navigation log rotates
+const DefaultNavigationLogMaxSize int64 = 1 << 20
+
+// navigationLogPath is the append-only log of history messages
+var navigationLogPath = filepath.Join(".tod", "navigation.log")
+
+// navigationLogMaxSize returns the configured rotation size of the log
+func (v *NavigationView) navigationLogMaxSize() int64 {
+	if v.config != nil && v.config.Session.HistoryLogMaxSize > 0 {
+		return v.config.Session.HistoryLogMaxSize
+	}
+	return DefaultNavigationLogMaxSize
+}
+
+// logHistory appends a history entry to the navigation log as a JSON line.
+// Once the log reaches its maximum size it's moved to navigation.log.1,
+// replacing the previous one, and a new log is started.
+func (v *NavigationView) logHistory(entry HistoryEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	if info, err := os.Stat(navigationLogPath); err == nil && info.Size()+int64(len(line)) > v.navigationLogMaxSize() {
+		if err := os.Rename(navigationLogPath, navigationLogPath+".1"); err != nil {
+			logging.Warn("Failed to rotate navigation log: %v", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(navigationLogPath), 0755); err != nil {
+		logging.Debug("Navigation log unavailable: %v", err)
+		return
+	}
+	f, err := os.OpenFile(navigationLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logging.Debug("Navigation log unavailable: %v", err)
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		logging.Debug("Failed to write navigation log: %v", err)
+	}
+}
+
+// readNavigationLog returns the entries of the rotated and current
+// navigation logs, oldest first
+func readNavigationLog() []HistoryEntry {
+	entries := readNavigationLogFile(navigationLogPath + ".1")
+	return append(entries, readNavigationLogFile(navigationLogPath)...)
+}
+
+// readNavigationLogFile reads every entry of a log file, skipping bad lines
+func readNavigationLogFile(path string) []HistoryEntry {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// maxLoggedURLs is how many past URLs are offered as history suggestions
+const maxLoggedURLs = 20
+
+// LoadRecentHistory restores the last n history messages from the navigation
+// log, and the URLs visited in earlier sessions so they can be revisited
+// from history suggestions
+func (v *NavigationView) LoadRecentHistory(n int) {
+	entries := readNavigationLog()
+	for _, entry := range entries[max(len(entries)-n, 0):] {
+		v.appendHistory(entry)
+	}
+
+	seen := make(map[string]bool)
+	var urls []string
+	for i := len(entries) - 1; i >= 0 && len(urls) < maxLoggedURLs; i-- {
+		url := entries[i].URL
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		urls = append(urls, url)
+	}
+
+	// navigationHistory is oldest first
+	for i := len(urls) - 1; i >= 0; i-- {
+		v.navigationHistory = append(v.navigationHistory, urls[i])
+	}
+}
@@ -2,20 +2,28 @@ package views
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/chromedp/chromedp"
 	"github.com/lance13c/tod/internal/browser"
 	"github.com/lance13c/tod/internal/config"
+	"github.com/lance13c/tod/internal/database"
 	"github.com/lance13c/tod/internal/llm"
 	"github.com/lance13c/tod/internal/logging"
+	"github.com/lance13c/tod/internal/testing"
+	"github.com/lance13c/tod/internal/types"
 	"github.com/lance13c/tod/internal/users"
 )
 
@@ -62,6 +70,9 @@ type NavigableElement struct {
 	Selector    string
 	Method      string // click, submit, type, etc.
 	JavaScript  string // For complex actions
+	Disabled    bool   // Disabled elements are listed last and can't be acted on
+	InputType   string // type attribute of input elements, e.g. "password"
+	Placeholder string // Placeholder of text inputs, shown as a hint when prompting
 }
 
 // Suggestion represents an autocomplete suggestion
@@ -79,6 +90,7 @@ type Command struct {
 	Display     string
 	Description string
 	Handler     func(*NavigationView) error
+	Local       bool // runs without touching the browser, so no page reload follows
 }
 
 // NavigationView provides a unified navigation interface
@@ -86,11 +98,18 @@ type NavigationView struct {
 	// Configuration
 	config        *config.Config
 	llmClient     llm.Client
+	aiOffline     bool          // llmClient is the mock fallback since no LLM is configured
+	llmChecked    bool          // the startup health check of llmClient finished
+	llmHealthErr  error         // why the health check failed, if it did
+	rankings      *rankingCache // LLM element rankings for the current page
 	configuredURL string
+	weights       config.MatchingWeights
+	matching      config.MatchingConfig
 
 	// Browser management
 	chromeDPManager *browser.ChromeDPManager
 	isConnected     bool
+	isRecovering    bool
 	currentURL      string
 	currentTitle    string
 
@@ -121,6 +140,7 @@ type NavigationView struct {
 	formProcessing  bool
 	awaitingInput   bool
 	pendingField    *FormField
+	fillQueue       []*FormField // fields "fill all" still has to prompt for
 
 	// Authentication flow
 	authFlow       *users.AuthFlowManager
@@ -128,12 +148,99 @@ type NavigationView struct {
 
 	// Navigation history
 	navigationHistory []string
-	historyIndex      int
+
+	// newTabID is the tab an action opened most recently, until switched to
+	newTabID string
+
+	// Low-confidence navigation candidates the user can pick by number
+	pendingChoices []NavigableElement
+
+	// Whether destructive actions must be confirmed, from
+	// safety.confirm_destructive, and the action waiting for a yes
+	destructive    *destructiveGuard
+	pendingConfirm *pendingConfirmation
+
+	// Session bookkeeping for session.max_duration and the exit summary
+	sessionID    string
+	sessionStart time.Time
+	totalTokens  int64
+	totalCost    float64
+	exitSummary  string
+
+	// Flows saved this session
+	flowsSaved int
+
+	// Usage not yet written to the project database, and the cost already there
+	unsavedUsage map[string]*database.UsageRecord
+	previousCost float64
+
+	// Key bindings, also the source of the generated help
+	keys     navigationKeyMap
+	showHelp bool
+
+	// Submitted inputs, recalled with Up and Down while the input is empty
+	inputs *inputHistory
+
+	// Page HTML from before the last action and the diff shown by "diff"
+	beforeActionHTML string
+	diffLines        []string
+
+	// What acting on an element was seen to do, by page URL and selector,
+	// saved with captures as the expected result of the action
+	expectedResults map[string]string
+
+	// Dropdown whose options were last listed, used by "select <option>"
+	pendingSelect *NavigableElement
+
+	// Environment picker, shown by the "environments" command
+	envPicker *list.Model
+
+	// Answer to an "ask" question while it streams in; Esc cancels it
+	answerChunks    <-chan string
+	answerText      string
+	answerCancel    context.CancelFunc
+	answerCancelled bool
+
+	// Wait for a magic link email while it polls; Esc cancels it
+	magicLinkUpdates <-chan tea.Msg
+	magicLinkCancel  context.CancelFunc
+
+	// Capture watching the page for new actions until polling completes
+	isCapturing        bool
+	captureActionCount int
+	captureUpdates     <-chan tea.Msg
+	captureCancel      context.CancelFunc
+
+	// Crawl discovering actions across linked pages; Esc cancels it
+	isCrawling   bool
+	crawledPages int
+	crawlUpdates <-chan tea.Msg
+	crawlCancel  context.CancelFunc
+
+	// Picks from the browser while inspect mode is on; Esc turns it off
+	inspectPicks <-chan browser.InspectedElement
+
+	// Set by a command handler that carries on in the background, such as
+	// "capture page"; runCommand returns it so Update follows its progress
+	startedStream tea.Msg
 
 	// Action history for display (Claude Code style)
-	history    []string
+	history    []HistoryEntry
 	maxHistory int
 
+	// Executed actions, kept separate from display history for auditing
+	executedActions []ExecutedStep
+	showActionPanel bool
+
+	// Navigations and form fills "undo" can reverse, newest last
+	undoStack []undoEntry
+
+	// Flow being recorded by "record flow", nil when not recording
+	flowRecording *Flow
+
+	// LLM interaction inspector opened by "llm log", nil when closed
+	llmInspector *llmInspector
+
 	// UI components
 	viewport     viewport.Model
 	width        int
@@ -162,9 +269,9 @@ func NewNavigationView(cfg *config.Config) *NavigationView {
 	// Create viewport
 	vp := viewport.New(80, 20)
 
-	// Initialize LLM client
+	// Initialize LLM client. Local and mock providers need no API key.
 	var llmClient llm.Client
-	if cfg.AI.APIKey != "" {
+	if cfg.AI.APIKey != "" || cfg.AI.Provider == "local" || cfg.AI.Provider == "mock" {
 		var provider llm.Provider
 		switch cfg.AI.Provider {
 		case "openai":
@@ -182,13 +289,25 @@ func NewNavigationView(cfg *config.Config) *NavigationView {
 		}
 
 		if provider != "" {
-			options := map[string]interface{}{
-				"model": cfg.AI.Model,
+			var err error
+			llmClient, err = llm.NewClient(provider, cfg.AI.APIKey, cfg.AI.ClientOptions())
+			if err != nil {
+				logging.Warn("LLM client unavailable: %v", err)
+			} else {
+				llmClient = llm.NewRecordingClient(llmClient, provider, cfg.AI.Model, recordLLMInteraction)
 			}
-			llmClient, _ = llm.NewClient(provider, cfg.AI.APIKey, options)
 		}
 	}
 
+	// Without a usable LLM, fall back to the mock client's keyword
+	// heuristics and say so rather than failing call by call
+	var aiOffline bool
+	if llmClient == nil {
+		logging.Info("No LLM client configured, using keyword heuristics")
+		llmClient, _ = llm.NewClient(llm.Mock, "", nil)
+		aiOffline = true
+	}
+
 	env := cfg.GetCurrentEnv()
 
 	// Initialize auth config manager (needs project directory)
@@ -197,14 +316,24 @@ func NewNavigationView(cfg *config.Config) *NavigationView {
 
 	// Initialize auth flow manager
 	var authFlow *users.AuthFlowManager
-	if llmClient != nil {
+	if !aiOffline {
 		authFlow, _ = users.NewAuthFlowManager(projectDir, llmClient)
 	}
 
-	return &NavigationView{
+	now := time.Now()
+	v := &NavigationView{
 		config:         cfg,
 		llmClient:      llmClient,
-		configuredURL:  env.BaseURL,
+		configuredURL:  env.HomeURL(),
+		weights:        cfg.Matching.Weights,
+		keys:           defaultNavigationKeyMap(),
+		rankings:       newRankingCache(),
+		aiOffline:      aiOffline,
+		inputs:         newInputHistory(),
+		sessionStart:   now,
+		sessionID:      now.Format(sessionIDFormat),
+		previousCost:   loadCumulativeCost(),
+		matching:       cfg.Matching,
 		input:          ti,
 		viewport:       vp,
 		selectedIndex:  -1,
@@ -217,6 +346,8 @@ func NewNavigationView(cfg *config.Config) *NavigationView {
 		authConfig: authConfig,
 		authFlow:   authFlow,
 
+		destructive: &destructiveGuard{enabled: cfg.Safety.ConfirmDestructive},
+
 		// Styles
 		titleStyle: lipgloss.NewStyle().
 			Bold(true).
@@ -249,6 +380,11 @@ func NewNavigationView(cfg *config.Config) *NavigationView {
 			Foreground(lipgloss.Color("241")).
 			MarginTop(1),
 	}
+	v.LoadRecentHistory(v.maxHistory)
+	if aiOffline {
+		v.addHistory("🔌 AI is offline: no API key is set, so suggestions use keyword matching only. Set ai.api_key in .tod/config.yaml for smarter matching.")
+	}
+	return v
 }
 
 // Init initializes the navigation view
@@ -256,6 +392,8 @@ func (v *NavigationView) Init() tea.Cmd {
 	return tea.Batch(
 		textinput.Blink,
 		v.connectToChrome(),
+		v.sessionTimer(),
+		v.checkLLMHealth(),
 	)
 }
 
@@ -276,19 +414,38 @@ func (v *NavigationView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ChromeLaunchedMsg:
 		v.isConnected = true
+		v.isRecovering = false
 		return v, v.analyzeCurrentPage()
 
+	case PastedCommandsMsg:
+		return v, v.runPastedCommands(msg)
+
+	case LLMHealthMsg:
+		v.llmChecked = true
+		v.llmHealthErr = msg.Error
+		if msg.Error != nil {
+			logging.Warn("LLM health check failed: %v", msg.Error)
+			v.addHistory(fmt.Sprintf("⚠️  AI unavailable: %v", msg.Error))
+		}
+
 	case ChromeErrorMsg:
 		v.isConnected = false
-		// Handle Chrome errors similar to chat adventure
+		if v.isRecovering {
+			v.isRecovering = false
+			v.addHistory(fmt.Sprintf("❌ %v", msg.Error))
+		}
 
 	case NavigationCompleteMsg:
 		v.isProcessing = false
+		v.reportDryRun()
 		if msg.Error == nil {
+			if msg.URL != v.currentURL {
+				v.rankings.clear()
+			}
 			v.currentURL = msg.URL
 			v.addToHistory(msg.URL)
 			// Add history message for successful navigation
-			if msg.URL != v.configuredURL {
+			if msg.URL != browser.StripURLCredentials(v.configuredURL) {
 				v.addHistory(fmt.Sprintf("→ Navigated to %s", msg.URL))
 			} else {
 				v.addHistory("→ Navigated to homepage")
@@ -304,15 +461,58 @@ func (v *NavigationView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		v.isAnalyzing = false
 		if msg.Error == nil {
 			v.pageElements = msg.Elements
-			// Generate initial suggestions (will show even with empty input)
-			v.generateSuggestions()
+			llmElements, _ := v.navigationElements()
+			v.rankings.pageChanged(llmElements)
+			// Generate initial suggestions (will show even with empty input),
+			// keeping the selection if the page still offers it
+			v.regenerateSuggestions()
 		} else {
 			// Clear elements on error
 			v.pageElements = []NavigableElement{}
+			if errors.Is(msg.Error, browser.ErrBrowserCrashed) {
+				return v, v.recoverFromCrash()
+			}
 		}
 
+	case CommandCompleteMsg:
+		v.isProcessing = false
+		v.reportDryRun()
+
+	case ConfirmDestructiveMsg:
+		v.isProcessing = false
+		v.askConfirmation(msg)
+
+	case SessionTimeoutMsg:
+		return v, v.handleSessionTimeout()
+
+	case AnswerStreamMsg, AnswerChunkMsg, AnswerDoneMsg, AnswerFailedMsg:
+		return v, v.handleAnswerMsg(msg)
+
+	case MagicLinkWaitMsg, MagicLinkProgressMsg, MagicLinkDoneMsg:
+		return v, v.handleMagicLinkMsg(msg)
+
+	case CaptureStreamMsg, CaptureProgressMsg, CaptureDoneMsg:
+		return v, v.handleCaptureMsg(msg)
+
+	case CrawlStreamMsg, CrawlPageMsg, CrawlDoneMsg:
+		return v, v.handleCrawlMsg(msg)
+
+	case InspectStreamMsg, InspectPickMsg, InspectDoneMsg:
+		return v, v.handleInspectMsg(msg)
+
+	case WaitResultMsg:
+		return v, v.handleWaitResult(msg)
+
+	case PlannedActionMsg:
+		v.isProcessing = false
+		v.addHistory(fmt.Sprintf("📝 [SIMULATED] Would %s %q", msg.Verb, msg.Target))
+
 	case NavigationErrorMsg:
 		v.isProcessing = false
+		v.reportDryRun()
+		if errors.Is(msg.Error, browser.ErrBrowserCrashed) {
+			return v, v.recoverFromCrash()
+		}
 		// Could show error in status or as temporary message
 
 	case AuthenticationCompleteMsg:
@@ -348,7 +548,14 @@ func (v *NavigationView) View() string {
 	// History section (Claude Code style - simple text)
 	var historyView string
 	if len(v.history) > 0 {
-		historyView = strings.Join(v.history, "\n")
+		lines := make([]string, len(v.history))
+		for i, entry := range v.history {
+			lines[i] = entry.Message
+		}
+		historyView = strings.Join(lines, "\n")
+	}
+	if answer := v.renderAnswer(); answer != "" {
+		historyView = strings.TrimPrefix(historyView+"\n"+answer, "\n")
 	}
 
 	// Input section (always visible)
@@ -367,14 +574,31 @@ func (v *NavigationView) View() string {
 	
 	fixedHeader := lipgloss.JoinVertical(lipgloss.Left, headerSections...)
 
-	// Scrollable suggestions section (uses available space)
+	// Scrollable suggestions section (uses available space), replaced by
+	// the full help while it's open
 	suggestionsView := v.renderSuggestionsViewport()
+	if v.llmInspector != nil {
+		suggestionsView = v.renderLLMInspector()
+	} else if v.showHelp {
+		suggestionsView = v.renderFullHelp()
+	} else if v.diffLines != nil {
+		suggestionsView = v.renderDiff()
+	}
 
 	// Help text (always visible at bottom)
-	help := v.helpStyle.Render("[Tab: complete] [↑↓: navigate] [Enter: go] [Esc: clear] [Ctrl+C: quit]")
+	help := v.helpStyle.Render(v.renderShortHelp())
 
 	// Combine fixed header + scrollable suggestions + help
-	mainView := lipgloss.JoinVertical(lipgloss.Left, fixedHeader, "", suggestionsView, "", help)
+	sections := []string{fixedHeader, "", suggestionsView}
+	if v.showActionPanel {
+		sections = append(sections, "", v.renderActionPanel())
+	}
+	sections = append(sections, "", help)
+	mainView := lipgloss.JoinVertical(lipgloss.Left, sections...)
+
+	if v.envPicker != nil {
+		return mainView + "\n" + v.borderStyle.Render(v.envPicker.View())
+	}
 
 	// If input modal is showing, overlay it
 	if v.inputModal != nil && v.inputModal.IsShowing() {
@@ -388,6 +612,13 @@ func (v *NavigationView) View() string {
 
 // handleKeyPress handles keyboard input
 func (v *NavigationView) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if v.envPicker != nil {
+		return v.handleEnvPickerKey(msg)
+	}
+	if v.llmInspector != nil {
+		return v.handleLLMInspectorKey(msg)
+	}
+
 	// If input modal is showing, handle modal input first
 	if v.inputModal != nil && v.inputModal.IsShowing() {
 		var cmd tea.Cmd
@@ -401,9 +632,33 @@ func (v *NavigationView) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return v, cmd
 	}
 
-	switch msg.Type {
-	case tea.KeyEsc:
-		if v.showSuggestions {
+	// A multi-line paste runs each line as its own command
+	if msg.Paste && strings.Contains(string(msg.Runes), "\n") {
+		v.input.SetValue("")
+		v.showSuggestions = false
+		v.selectedIndex = -1
+		return v, v.executePastedCommands(string(msg.Runes))
+	}
+
+	switch {
+	case key.Matches(msg, v.keys.Clear):
+		if v.stopAnswer() {
+			return v, nil
+		} else if v.cancelConfirmation() {
+			return v, nil
+		} else if v.stopMagicLinkWait() {
+			return v, nil
+		} else if v.stopCrawl() {
+			return v, nil
+		} else if v.stopInspect() {
+			return v, nil
+		} else if v.showHelp {
+			v.showHelp = false
+			return v, nil
+		} else if v.diffLines != nil {
+			v.diffLines = nil
+			return v, nil
+		} else if v.showSuggestions {
 			v.showSuggestions = false
 			v.selectedIndex = -1
 			return v, nil
@@ -415,11 +670,18 @@ func (v *NavigationView) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return v, func() tea.Msg { return ReturnToMenuMsg{} }
 		}
 
-	case tea.KeyCtrlC:
+	case key.Matches(msg, v.keys.Quit):
 		v.cleanup()
 		return v, tea.Quit
 
-	case tea.KeyUp:
+	case key.Matches(msg, v.keys.Up):
+		if !v.showSuggestions && (v.input.Value() == "" || v.inputs.recalling()) {
+			if input, ok := v.inputs.previous(); ok {
+				v.input.SetValue(input)
+				v.input.CursorEnd()
+			}
+			return v, nil
+		}
 		if v.showSuggestions && len(v.suggestions) > 0 {
 			v.selectedIndex = v.findNextSelectableIndex(v.selectedIndex, -1)
 			// Ensure selected item is visible in viewport
@@ -435,7 +697,12 @@ func (v *NavigationView) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return v, nil
 
-	case tea.KeyDown:
+	case key.Matches(msg, v.keys.Down):
+		if !v.showSuggestions && v.inputs.recalling() {
+			v.input.SetValue(v.inputs.next())
+			v.input.CursorEnd()
+			return v, nil
+		}
 		if !v.showSuggestions {
 			v.generateSuggestions()
 			v.showSuggestions = true
@@ -456,7 +723,18 @@ func (v *NavigationView) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return v, nil
 
-	case tea.KeyTab:
+	case key.Matches(msg, v.keys.PageUp), key.Matches(msg, v.keys.PageDown):
+		if !v.showSuggestions {
+			return v, nil
+		}
+		direction := 1
+		if key.Matches(msg, v.keys.PageUp) {
+			direction = -1
+		}
+		v.pageSuggestions(direction)
+		return v, nil
+
+	case key.Matches(msg, v.keys.Complete):
 		if v.showSuggestions && len(v.suggestions) > 0 && v.selectedIndex >= 0 {
 			suggestion := v.suggestions[v.selectedIndex]
 			v.input.SetValue(suggestion.Text)
@@ -466,10 +744,13 @@ func (v *NavigationView) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return v, nil
 
-	case tea.KeyEnter:
+	case key.Matches(msg, v.keys.Go):
 		if v.showSuggestions && len(v.suggestions) > 0 && v.selectedIndex >= 0 {
 			// Store the selected suggestion before resetting state
 			selectedSuggestion := v.suggestions[v.selectedIndex]
+			if selectedSuggestion.Type != SectionHeaderSuggestion {
+				v.inputs.add(selectedSuggestion.Text)
+			}
 			// Clear input before executing suggestion
 			v.input.SetValue("")
 			v.showSuggestions = false
@@ -478,18 +759,40 @@ func (v *NavigationView) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else {
 			// Clear input before executing direct input
 			input := v.input.Value()
+			v.inputs.add(strings.TrimSpace(input))
 			v.input.SetValue("")
 			v.showSuggestions = false
 			v.selectedIndex = -1
 			return v, v.executeInputValue(input)
 		}
 
-	case tea.KeyCtrlR:
+	case key.Matches(msg, v.keys.Analyze):
 		return v, v.analyzeCurrentPage()
 
-	case tea.KeyCtrlB:
+	case key.Matches(msg, v.keys.Back):
 		return v, v.navigateBack()
 
+	case key.Matches(msg, v.keys.Undo):
+		return v, v.executeInputValue("undo")
+
+	case key.Matches(msg, v.keys.Copy):
+		v.copySelectedSelector()
+		return v, nil
+
+	case key.Matches(msg, v.keys.Actions):
+		v.showActionPanel = !v.showActionPanel
+		return v, nil
+
+	case key.Matches(msg, v.keys.Export):
+		if err := v.exportSession(); err != nil {
+			v.addHistory(fmt.Sprintf("❌ %v", err))
+		}
+		return v, nil
+
+	case key.Matches(msg, v.keys.Help):
+		v.showHelp = !v.showHelp
+		return v, nil
+
 	default:
 		// Handle regular typing
 		var cmd tea.Cmd
@@ -515,12 +818,13 @@ func (v *NavigationView) connectToChrome() tea.Cmd {
 			headless = v.config.Browser.Headless
 		}
 		logging.Info("Launching Chrome with headless=%v", headless)
-		manager, err := browser.GetGlobalChromeDPManager(v.configuredURL, headless)
+		manager, err := browser.GetGlobalChromeDPManager(v.configuredURL, v.launchOptions(headless))
 		if err != nil {
 			return ChromeErrorMsg{Error: err}
 		}
 
 		v.chromeDPManager = manager
+		v.installNavigationGuard()
 		
 		// Initialize form handler with the chrome manager
 		v.formHandler = NewFormHandler(manager)
@@ -530,6 +834,22 @@ func (v *NavigationView) connectToChrome() tea.Cmd {
 }
 
 // analyzeCurrentPage analyzes the current page for navigable elements
+// llmPingTimeout bounds the startup LLM health check
+const llmPingTimeout = 15 * time.Second
+
+// checkLLMHealth pings the LLM provider once so a bad API key or model shows
+// up at startup rather than as poor suggestions
+func (v *NavigationView) checkLLMHealth() tea.Cmd {
+	if v.aiOffline || v.llmClient == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), llmPingTimeout)
+		defer cancel()
+		return LLMHealthMsg{Error: v.llmClient.Ping(ctx)}
+	}
+}
+
 func (v *NavigationView) analyzeCurrentPage() tea.Cmd {
 	return func() tea.Msg {
 		if v.chromeDPManager == nil {
@@ -543,6 +863,15 @@ func (v *NavigationView) analyzeCurrentPage() tea.Cmd {
 			logging.Warn("Page load wait failed: %v", err)
 		}
 
+		// Real-time pages keep streaming over WebSocket/SSE after load
+		maxWait := browser.DefaultLiveUpdateMaxWait
+		if v.config != nil && v.config.Browser.LiveUpdateMaxWait > 0 {
+			maxWait = v.config.Browser.LiveUpdateMaxWait
+		}
+		if v.chromeDPManager.WaitForLiveUpdatesToSettle(browser.DefaultLiveUpdateQuietPeriod, maxWait) {
+			v.addHistory("⚡ Live updates detected")
+		}
+
 		// Get page info
 		url, title, _ := v.chromeDPManager.GetPageInfo()
 		v.currentURL = url
@@ -550,8 +879,8 @@ func (v *NavigationView) analyzeCurrentPage() tea.Cmd {
 
 		logging.Info("Analyzing page: %s (title: %s)", url, title)
 
-		// Extract interactive elements
-		interactiveElements, err := v.chromeDPManager.ExtractInteractiveElements()
+		// Extract interactive elements, retrying transient failures
+		interactiveElements, err := v.extractElementsWithRetry()
 		if err != nil {
 			logging.Error("Failed to extract interactive elements: %v", err)
 			return PageAnalysisCompleteMsg{Error: err}
@@ -562,10 +891,12 @@ func (v *NavigationView) analyzeCurrentPage() tea.Cmd {
 		// Convert to NavigableElements
 		var elements []NavigableElement
 		for _, elem := range interactiveElements {
+			text := normalizeText(elem.Text)
 			navElement := NavigableElement{
-				Text:        elem.Text,
-				Description: elem.Text,
+				Text:        text,
+				Description: text,
 				Selector:    elem.Selector,
+				Disabled:    elem.IsDisabled,
 			}
 
 			// Prioritize navigation elements first
@@ -593,17 +924,29 @@ func (v *NavigationView) analyzeCurrentPage() tea.Cmd {
 						navElement.Type = ActionElement
 						navElement.Method = "click"
 						navElement.Description = fmt.Sprintf("Toggle %s", elem.Text)
+					} else if elem.Type == "file" {
+						navElement.Type = FormElement
+						navElement.Method = "upload"
+						navElement.InputType = elem.Type
+						if text == "" {
+							text = "file"
+						}
+						navElement.Text = fmt.Sprintf("upload to %s", text)
+						navElement.Description = fmt.Sprintf("Upload a file to %s", text)
 					} else {
 						navElement.Type = FormElement
 						navElement.Method = "type"
+						navElement.InputType = elem.Type
+						navElement.Placeholder = elem.Placeholder
 					}
 				case "select":
 					navElement.Type = ActionElement
-					navElement.Method = "click" 
+					navElement.Method = "select"
 					navElement.Description = fmt.Sprintf("Select from %s", elem.Text)
 				case "textarea":
 					navElement.Type = FormElement
 					navElement.Method = "type"
+					navElement.Placeholder = elem.Placeholder
 				default:
 					// Handle other interactive elements
 					navElement.Type = ActionElement
@@ -639,6 +982,35 @@ func (v *NavigationView) analyzeCurrentPage() tea.Cmd {
 	}
 }
 
+// extractElementsWithRetry extracts the page's interactive elements once their
+// count is stable, retrying with backoff up to ai.discovery_retries times on
+// retryable failures
+func (v *NavigationView) extractElementsWithRetry() ([]browser.InteractiveElement, error) {
+	retries := 0
+	var interval, timeout time.Duration
+	if v.config != nil {
+		retries = v.config.AI.DiscoveryRetries
+		interval = v.config.Browser.StableElementsInterval
+		timeout = v.config.Browser.StableElementsTimeout
+	}
+
+	backoff := 500 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		elements, err := v.chromeDPManager.ExtractStableInteractiveElements(interval, timeout)
+		if err == nil {
+			return elements, nil
+		}
+		if attempt >= retries || !testing.IsRetryableDiscoveryError(err) {
+			return nil, err
+		}
+
+		logging.Warn("Page analysis failed (attempt %d/%d): %v", attempt+1, retries+1, err)
+		v.addHistory(fmt.Sprintf("🔄 Retrying analysis (%d/%d)...", attempt+1, retries))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
 // generateSuggestions creates suggestions based on current input
 func (v *NavigationView) generateSuggestions() {
 	input := strings.TrimSpace(v.input.Value())
@@ -660,56 +1032,8 @@ func (v *NavigationView) generateSuggestions() {
 			})
 		}
 
-		// Add ALL page elements with smart categorization and prioritization
-		// Separate elements by type for better organization
-		var formFields []NavigableElement
-		var formSubmits []NavigableElement
-		var buttons []NavigableElement
-		var navigationLinks []NavigableElement
-		var otherElements []NavigableElement
-		
-		for _, elem := range v.pageElements {
-			switch elem.Type {
-			case FormFieldElement:
-				formFields = append(formFields, elem)
-			case FormElement:
-				if elem.Method == "form_submit" {
-					formSubmits = append(formSubmits, elem)
-				} else {
-					otherElements = append(otherElements, elem)
-				}
-			case ButtonElement:
-				buttons = append(buttons, elem)
-			case LinkElement:
-				navigationLinks = append(navigationLinks, elem)
-			case ActionElement:
-				otherElements = append(otherElements, elem)
-			default:
-				otherElements = append(otherElements, elem)
-			}
-		}
-		
-		// Create prioritized list based on context
-		prioritizedElements := []NavigableElement{}
-		
-		if v.currentForm != nil {
-			// Form context: prioritize form actions first
-			prioritizedElements = append(prioritizedElements, formFields...)
-			prioritizedElements = append(prioritizedElements, formSubmits...)
-			prioritizedElements = append(prioritizedElements, buttons...)
-			prioritizedElements = append(prioritizedElements, navigationLinks...)
-			prioritizedElements = append(prioritizedElements, otherElements...)
-		} else {
-			// Regular context: prioritize interactive elements
-			prioritizedElements = append(prioritizedElements, buttons...)
-			prioritizedElements = append(prioritizedElements, formFields...)
-			prioritizedElements = append(prioritizedElements, formSubmits...)
-			prioritizedElements = append(prioritizedElements, navigationLinks...)
-			prioritizedElements = append(prioritizedElements, otherElements...)
-		}
-
-		// Add ALL elements as suggestions with section headers
-		v.addSuggestionsWithHeaders(formFields, formSubmits, buttons, navigationLinks, otherElements)
+		// Add ALL page elements as suggestions, grouped by category with section headers
+		v.addSuggestionsWithHeaders(v.groupElements(v.pageElements))
 
 		v.showSuggestions = true
 		v.selectedIndex = v.findFirstSelectableIndex()
@@ -730,7 +1054,7 @@ func (v *NavigationView) generateSuggestions() {
 
 	// Match page elements
 	for _, elem := range v.pageElements {
-		if score := v.fuzzyMatch(input, elem.Text); score > 0.3 {
+		if score := v.scoreElement(input, elem); score > 0.3 {
 			suggestion := Suggestion{
 				Type:       v.elementTypeToSuggestionType(elem.Type),
 				Text:       elem.Text,
@@ -750,6 +1074,12 @@ func (v *NavigationView) generateSuggestions() {
 			case ActionElement:
 				suggestion.Subtitle = "action"
 			}
+			if elem.Disabled {
+				suggestion.Subtitle += " (disabled)"
+			}
+			if elem.Selector != "" && browser.IsFragileSelector(elem.Selector, v.fragileThreshold()) {
+				suggestion.Subtitle += " ⚠ fragile selector"
+			}
 
 			v.suggestions = append(v.suggestions, suggestion)
 		}
@@ -757,7 +1087,7 @@ func (v *NavigationView) generateSuggestions() {
 
 	// Add history matches
 	for _, hist := range v.navigationHistory {
-		if score := v.fuzzyMatch(input, hist); score > 0.5 {
+		if score := v.fuzzyMatch(input, hist) * v.weights.History; score > 0.5 {
 			v.suggestions = append(v.suggestions, Suggestion{
 				Type:       HistorySuggestion,
 				Text:       hist,
@@ -784,7 +1114,7 @@ func (v *NavigationView) generateSuggestions() {
 }
 
 // addSuggestionsWithHeaders adds suggestions organized by category with section headers
-func (v *NavigationView) addSuggestionsWithHeaders(formFields, formSubmits, buttons, navigationLinks, otherElements []NavigableElement) {
+func (v *NavigationView) addSuggestionsWithHeaders(groups []elementGroup) {
 	// Helper function to add section header
 	addHeader := func(title string) {
 		if len(v.suggestions) > 0 { // Don't add header as first item
@@ -827,21 +1157,69 @@ func (v *NavigationView) addSuggestionsWithHeaders(formFields, formSubmits, butt
 		}
 	}
 
-	// Add sections in priority order based on context
+	for _, group := range groups {
+		addElementsOfType(group.Elements, group.Title)
+	}
+}
+
+// elementGroup is a category of page elements with its section title
+type elementGroup struct {
+	Title    string
+	Elements []NavigableElement
+}
+
+// groupElements sorts elements into categories, ordered by what's most
+// likely useful in the current context. Empty categories are included.
+func (v *NavigationView) groupElements(elements []NavigableElement) []elementGroup {
+	var formFields []NavigableElement
+	var formSubmits []NavigableElement
+	var buttons []NavigableElement
+	var navigationLinks []NavigableElement
+	var otherElements []NavigableElement
+
+	for _, elem := range elements {
+		// Disabled elements can't be acted on, so list them last
+		if elem.Disabled {
+			otherElements = append(otherElements, elem)
+			continue
+		}
+
+		switch elem.Type {
+		case FormFieldElement:
+			formFields = append(formFields, elem)
+		case FormElement:
+			if elem.Method == "form_submit" {
+				formSubmits = append(formSubmits, elem)
+			} else {
+				otherElements = append(otherElements, elem)
+			}
+		case ButtonElement:
+			buttons = append(buttons, elem)
+		case LinkElement:
+			navigationLinks = append(navigationLinks, elem)
+		default:
+			otherElements = append(otherElements, elem)
+		}
+	}
+
 	if v.currentForm != nil {
 		// Form context: prioritize form actions
-		addElementsOfType(formFields, "📝 Form Fields")
-		addElementsOfType(formSubmits, "📝 Form Actions")
-		addElementsOfType(buttons, "🎯 Buttons")
-		addElementsOfType(navigationLinks, "🔗 Navigation")
-		addElementsOfType(otherElements, "⚡ Other Actions")
-	} else {
-		// Regular context: prioritize interactive elements
-		addElementsOfType(buttons, "🎯 Buttons")
-		addElementsOfType(formFields, "📝 Form Fields")
-		addElementsOfType(formSubmits, "📝 Form Actions")
-		addElementsOfType(navigationLinks, "🔗 Navigation")
-		addElementsOfType(otherElements, "⚡ Other Actions")
+		return []elementGroup{
+			{"📝 Form Fields", formFields},
+			{"📝 Form Actions", formSubmits},
+			{"🎯 Buttons", buttons},
+			{"🔗 Navigation", navigationLinks},
+			{"⚡ Other Actions", otherElements},
+		}
+	}
+
+	// Regular context: prioritize interactive elements
+	return []elementGroup{
+		{"🎯 Buttons", buttons},
+		{"📝 Form Fields", formFields},
+		{"📝 Form Actions", formSubmits},
+		{"🔗 Navigation", navigationLinks},
+		{"⚡ Other Actions", otherElements},
 	}
 }
 
@@ -884,42 +1262,17 @@ func (v *NavigationView) findFirstSelectableIndex() int {
 	return -1
 }
 
-// scrollToShowSelected ensures the selected item is visible in the viewport
-func (v *NavigationView) scrollToShowSelected() {
-	if v.selectedIndex < 0 || v.selectedIndex >= len(v.suggestions) {
-		return
-	}
-	
-	// Calculate current viewport bounds
-	viewportStart := v.suggestionScrollOffset
-	viewportEnd := viewportStart + v.maxVisibleSuggestions
-	
-	// If selected item is above the viewport, scroll up
-	if v.selectedIndex < viewportStart {
-		v.suggestionScrollOffset = v.selectedIndex
-	}
-	
-	// If selected item is below the viewport, scroll down
-	if v.selectedIndex >= viewportEnd {
-		v.suggestionScrollOffset = v.selectedIndex - v.maxVisibleSuggestions + 1
-	}
-	
-	// Ensure scroll offset stays within valid bounds
-	if v.suggestionScrollOffset < 0 {
-		v.suggestionScrollOffset = 0
-	}
-	
-	maxOffset := max(0, len(v.suggestions) - v.maxVisibleSuggestions)
-	if v.suggestionScrollOffset > maxOffset {
-		v.suggestionScrollOffset = maxOffset
-	}
-}
-
 // renderStatusBar renders the status bar
 func (v *NavigationView) renderStatusBar() string {
 	var parts []string
 
-	if v.isConnected {
+	if v.isConnected && v.chromeDPManager != nil {
+		mode := "headful"
+		if v.chromeDPManager.IsHeadless() {
+			mode = "headless"
+		}
+		parts = append(parts, fmt.Sprintf("Connected (%s, port %d)", mode, v.chromeDPManager.Port()))
+	} else if v.isConnected {
 		parts = append(parts, "Connected")
 	} else {
 		parts = append(parts, "Not connected")
@@ -929,90 +1282,58 @@ func (v *NavigationView) renderStatusBar() string {
 		parts = append(parts, fmt.Sprintf("%s", v.currentTitle))
 	}
 
-	if v.isAnalyzing {
-		parts = append(parts, "Analyzing...")
-	} else if len(v.pageElements) > 0 {
-		parts = append(parts, fmt.Sprintf("[%d elements]", len(v.pageElements)))
+	if v.aiOffline {
+		parts = append(parts, "🔌 AI: offline (heuristics only)")
+	} else if !v.llmChecked {
+		parts = append(parts, "🤖 AI: checking...")
+	} else if v.llmHealthErr != nil {
+		parts = append(parts, "⚠️  AI: unavailable")
 	} else {
-		parts = append(parts, "[0 elements]")
+		parts = append(parts, fmt.Sprintf("🤖 AI: %s ✓", v.config.AI.Provider))
 	}
 
-	status := strings.Join(parts, " | ")
-	return v.subtitleStyle.Render(status)
-}
-
-// renderSuggestions renders the suggestions list
-func (v *NavigationView) renderSuggestions() string {
-	// Always show suggestions if we have page elements (even with empty input)
-	if len(v.suggestions) == 0 && len(v.pageElements) > 0 && !v.showSuggestions {
-		v.generateSuggestions() // Generate initial suggestions
+	if v.config.PlanOnly {
+		parts = append(parts, "📝 PLAN ONLY (simulated)")
 	}
 
-	if len(v.suggestions) == 0 {
-		return v.subtitleStyle.Render("[Analyzing page for navigation options...]")
+	if v.chromeDPManager != nil && v.chromeDPManager.IsDryRun() {
+		parts = append(parts, "🧪 DRY RUN")
 	}
 
-	// First pass: calculate the maximum width needed for alignment
-	maxMainTextWidth := 0
-	for _, suggestion := range v.suggestions {
-		displayText := truncateText(suggestion.Text, 120)
-		// Add 3 for the "├─ " prefix
-		mainTextWidth := len(displayText) + 3
-		if mainTextWidth > maxMainTextWidth {
-			maxMainTextWidth = mainTextWidth
-		}
+	if v.inspectPicks != nil {
+		parts = append(parts, "🔍 INSPECT")
 	}
 
-	var lines []string
-	for i, suggestion := range v.suggestions {
-		var line string
-		var style lipgloss.Style
-
-		// Handle section headers differently
-		if suggestion.Type == SectionHeaderSuggestion {
-			// Section headers use special styling
-			style = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("208")). // Orange color for headers
-				PaddingTop(1).
-				PaddingBottom(0)
-			
-			line = fmt.Sprintf("▶ %s", suggestion.Text)
-			// Headers are not selectable, so skip selection styling
-		} else {
-			// Regular suggestions
-			displayText := truncateText(suggestion.Text, 120)
-
-			if i == v.selectedIndex {
-				style = v.selectedStyle
-				line = fmt.Sprintf("┌─ %s", displayText)
-			} else {
-				style = v.suggestionStyle
-				line = fmt.Sprintf("├─ %s", displayText)
-			}
+	if v.chromeDPManager != nil && v.chromeDPManager.IsOffline() {
+		parts = append(parts, "📴 OFFLINE")
+	}
 
-			// Calculate padding needed to align subtitles
-			currentWidth := len(displayText) + 3                 // Add 3 for prefix
-			paddingNeeded := maxMainTextWidth - currentWidth + 5 // Add 5 for spacing
+	if v.chromeDPManager != nil && v.chromeDPManager.HasLiveConnections() {
+		parts = append(parts, "⚡ Live")
+	}
 
-			// Add subtitle with proper alignment
-			if suggestion.Subtitle != "" {
-				subtitle := truncateText(suggestion.Subtitle, 40)
-				padding := strings.Repeat(" ", paddingNeeded)
-				line += fmt.Sprintf("%s%s", padding, subtitle)
-			}
+	if cost := v.previousCost + v.unsavedCost(); cost > 0 {
+		parts = append(parts, fmt.Sprintf("Σ %s", llm.FormatCost(cost)))
+	}
 
-			// Add type indicator
-			switch suggestion.Type {
-			case CommandSuggestion:
-				line += " ⌘"
-			}
-		}
+	if n := llm.ThrottledCalls(); n > 0 {
+		parts = append(parts, "⏳ Rate limited")
+	}
 
-		lines = append(lines, style.Render(line))
+	if v.isCapturing {
+		parts = append(parts, fmt.Sprintf("📸 Analyzing capture (%d actions)...", v.captureActionCount))
+	} else if v.isCrawling {
+		parts = append(parts, fmt.Sprintf("🕸️ Crawling (%d pages)...", v.crawledPages))
+	} else if v.isAnalyzing {
+		parts = append(parts, "Analyzing...")
+	} else if len(v.pageElements) > 0 {
+		parts = append(parts, fmt.Sprintf("[%d elements]", len(v.pageElements)))
+	} else {
+		parts = append(parts, "[0 elements]")
 	}
 
-	return strings.Join(lines, "\n")
+	status := strings.Join(parts, " | ")
+	return v.subtitleStyle.Render(status)
 }
 
 // calculateViewportDimensions calculates how many suggestions can fit in available space
@@ -1032,8 +1353,12 @@ func (v *NavigationView) calculateViewportDimensions() {
 	if len(v.history) > 0 {
 		historyLines = min(3, len(v.history)) + 1 // +1 for spacing
 	}
+	if v.answerChunks != nil {
+		historyLines += 3 // tail of the streaming answer
+	}
 	
 	fixedHeaderLines += historyLines + 2 + 3 + 3 // input + spacing + help + margins
+	fixedHeaderLines += v.actionPanelHeight()
 	
 	// Calculate available lines for suggestions
 	availableHeight := v.height - fixedHeaderLines
@@ -1042,111 +1367,9 @@ func (v *NavigationView) calculateViewportDimensions() {
 	}
 	
 	v.maxVisibleSuggestions = availableHeight
-	v.visibleSuggestionCount = min(len(v.suggestions), v.maxVisibleSuggestions)
 	
 	// Ensure scroll offset is valid
-	if v.suggestionScrollOffset < 0 {
-		v.suggestionScrollOffset = 0
-	}
-	maxOffset := max(0, len(v.suggestions)-v.maxVisibleSuggestions)
-	if v.suggestionScrollOffset > maxOffset {
-		v.suggestionScrollOffset = maxOffset
-	}
-}
-
-// renderSuggestionsViewport renders only the visible portion of suggestions with scroll indicators
-func (v *NavigationView) renderSuggestionsViewport() string {
-	if len(v.suggestions) == 0 {
-		return v.subtitleStyle.Render("[Analyzing page for navigation options...]")
-	}
-	
-	// Calculate visible range
-	startIdx := v.suggestionScrollOffset
-	endIdx := min(startIdx+v.maxVisibleSuggestions, len(v.suggestions))
-	
-	if startIdx >= len(v.suggestions) {
-		startIdx = max(0, len(v.suggestions)-v.maxVisibleSuggestions)
-		endIdx = len(v.suggestions)
-		v.suggestionScrollOffset = startIdx
-	}
-	
-	var lines []string
-	
-	// Add "more above" indicator if scrolled down
-	if startIdx > 0 {
-		moreAbove := fmt.Sprintf("▲ %d more above", startIdx)
-		lines = append(lines, v.subtitleStyle.Render(moreAbove))
-	}
-	
-	// Render visible suggestions using the same logic as original renderSuggestions
-	visibleSuggestions := v.suggestions[startIdx:endIdx]
-	
-	// First pass: calculate the maximum width needed for alignment
-	maxMainTextWidth := 0
-	for _, suggestion := range visibleSuggestions {
-		if suggestion.Type != SectionHeaderSuggestion {
-			displayText := truncateText(suggestion.Text, 120)
-			mainTextWidth := len(displayText) + 3
-			if mainTextWidth > maxMainTextWidth {
-				maxMainTextWidth = mainTextWidth
-			}
-		}
-	}
-	
-	// Render each visible suggestion
-	for i, suggestion := range visibleSuggestions {
-		globalIndex := startIdx + i
-		var line string
-		var style lipgloss.Style
-		
-		// Handle section headers differently
-		if suggestion.Type == SectionHeaderSuggestion {
-			style = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("208")).
-				PaddingTop(1).
-				PaddingBottom(0)
-			
-			line = fmt.Sprintf("▶ %s", suggestion.Text)
-		} else {
-			displayText := truncateText(suggestion.Text, 120)
-			
-			if globalIndex == v.selectedIndex {
-				style = v.selectedStyle
-				line = fmt.Sprintf("┌─ %s", displayText)
-			} else {
-				style = v.suggestionStyle
-				line = fmt.Sprintf("├─ %s", displayText)
-			}
-			
-			// Calculate padding needed to align subtitles
-			currentWidth := len(displayText) + 3
-			paddingNeeded := maxMainTextWidth - currentWidth + 5
-			
-			// Add subtitle with proper alignment
-			if suggestion.Subtitle != "" {
-				subtitle := truncateText(suggestion.Subtitle, 40)
-				padding := strings.Repeat(" ", paddingNeeded)
-				line += fmt.Sprintf("%s%s", padding, subtitle)
-			}
-			
-			// Add type indicator
-			switch suggestion.Type {
-			case CommandSuggestion:
-				line += " ⌘"
-			}
-		}
-		
-		lines = append(lines, style.Render(line))
-	}
-	
-	// Add "more below" indicator if there are items below viewport
-	if endIdx < len(v.suggestions) {
-		moreBelow := fmt.Sprintf("▼ %d more below", len(v.suggestions)-endIdx)
-		lines = append(lines, v.subtitleStyle.Render(moreBelow))
-	}
-	
-	return strings.Join(lines, "\n")
+	v.suggestionScrollOffset = max(0, min(v.suggestionScrollOffset, v.maxSuggestionOffset()))
 }
 
 // Helper functions are available from input_modal.go
@@ -1165,6 +1388,17 @@ func (v *NavigationView) Cleanup() {
 }
 
 func (v *NavigationView) cleanup() {
+	v.stopAnswer()
+	if v.captureCancel != nil {
+		v.captureCancel()
+		v.captureCancel = nil
+	}
+	if v.crawlCancel != nil {
+		v.crawlCancel()
+		v.crawlCancel = nil
+	}
+	v.stopInspect()
+	v.saveUsage()
 	if v.chromeDPManager != nil {
 		browser.CloseGlobalChromeDPManager()
 		v.chromeDPManager = nil
@@ -1182,13 +1416,9 @@ func (v *NavigationView) executeSuggestion(suggestion Suggestion) tea.Cmd {
 				// Add history for command execution
 				v.addHistory(fmt.Sprintf("→ Executed: %s", suggestion.Command.Display))
 
-				if err := suggestion.Command.Handler(v); err != nil {
-					return NavigationErrorMsg{Error: err}
-				}
-				return NavigationCompleteMsg{
-					URL:     v.currentURL,
-					Success: true,
-				}
+				msg := v.runCommand(suggestion.Command)
+				v.recordExecutedStep("command", v.recordedInput(suggestion.Command.Display), "", msg)
+				return msg
 			}
 
 		case LinkSuggestion, ActionSuggestion, FormSuggestion, FormFieldSuggestion:
@@ -1197,13 +1427,9 @@ func (v *NavigationView) executeSuggestion(suggestion Suggestion) tea.Cmd {
 			}
 
 		case HistorySuggestion:
-			if err := v.navigateToURL(suggestion.Text); err != nil {
-				return NavigationErrorMsg{Error: err}
-			}
-			return NavigationCompleteMsg{
-				URL:     suggestion.Text,
-				Success: true,
-			}
+			msg := v.navigateToURLMsg(suggestion.Text)
+			v.recordExecutedStep("navigate", suggestion.Text, "", msg)
+			return msg
 		}
 
 		return NavigationErrorMsg{Error: fmt.Errorf("unknown suggestion type")}
@@ -1220,19 +1446,40 @@ func (v *NavigationView) executeInputValue(inputValue string) tea.Cmd {
 		return nil
 	}
 
+	// A destructive action waits for a yes or no
+	if cmd, answered := v.resolveConfirmation(input); answered {
+		return cmd
+	}
+
+	// A number picks from the last set of offered navigation choices
+	if n, ok := parseChoiceNumber(input); ok && n >= 1 && n <= len(v.pendingChoices) {
+		choice := v.pendingChoices[n-1]
+		v.pendingChoices = nil
+		return v.executeElement(choice)
+	}
+
+	// "ask <question>" streams an answer about the current page
+	if question, ok := parseAskQuestion(input); ok {
+		return v.askAboutPage(question)
+	}
+	// "describe" streams a summary of the current page
+	if isDescribePage(input) {
+		return v.describePage()
+	}
+	// "wait <ms>" and "wait for <selector>" pause scripted actions
+	if request, ok := parseWait(input); ok {
+		return v.wait(input, request)
+	}
+
 	return func() tea.Msg {
 		v.isProcessing = true
 
 		// First try to match as a command
 		if command := v.matchCommand(input); command != nil {
 			if command.Handler != nil {
-				if err := command.Handler(v); err != nil {
-					return NavigationErrorMsg{Error: err}
-				}
-				return NavigationCompleteMsg{
-					URL:     v.currentURL,
-					Success: true,
-				}
+				msg := v.runCommand(command)
+				v.recordExecutedStep("command", v.recordedInput(input), "", msg)
+				return msg
 			}
 		}
 
@@ -1241,7 +1488,7 @@ func (v *NavigationView) executeInputValue(inputValue string) tea.Cmd {
 		bestScore := 0.0
 
 		for _, elem := range v.pageElements {
-			score := v.fuzzyMatch(input, elem.Text)
+			score := v.scoreElement(input, elem)
 			if score > bestScore && score > 0.3 {
 				bestScore = score
 				bestMatch = &elem
@@ -1255,24 +1502,510 @@ func (v *NavigationView) executeInputValue(inputValue string) tea.Cmd {
 		// If no matches found, try interpreting as URL or search
 		if strings.HasPrefix(input, "http") || strings.Contains(input, ".") {
 			// Looks like a URL
-			if err := v.navigateToURL(input); err != nil {
-				return NavigationErrorMsg{Error: err}
+			msg := v.navigateToURLMsg(input)
+			v.recordExecutedStep("navigate", input, "", msg)
+			return msg
+		}
+
+		return NavigationErrorMsg{Error: fmt.Errorf("no matches found for: %s", input)}
+	}
+}
+
+// ordinalWords maps spelled-out ordinals to 0-based indexes
+var ordinalWords = map[string]int{
+	"first":   0,
+	"second":  1,
+	"third":   2,
+	"fourth":  3,
+	"fifth":   4,
+	"sixth":   5,
+	"seventh": 6,
+	"eighth":  7,
+	"ninth":   8,
+	"tenth":   9,
+}
+
+// ordinalElementTypes maps the element types an ordinal can count, as in
+// "the second button", to the elements SmartClickNth counts for them
+var ordinalElementTypes = map[string]string{
+	"button":   `button, [role="button"], input[type="submit"], input[type="button"]`,
+	"link":     `a[href], [role="link"]`,
+	"checkbox": `input[type="checkbox"], [role="checkbox"]`,
+	"tab":      `[role="tab"]`,
+}
+
+// ordinalTargetPattern matches "the 2nd edit", "3rd delete" or "second edit"
+var ordinalTargetPattern = regexp.MustCompile(`^(?i)(?:the\s+)?(\d+(?:st|nd|rd|th)|[a-z]+)\s+(.+)$`)
+
+// ordinalTarget is an element picked by its position among several alike
+type ordinalTarget struct {
+	index    int    // 0-based
+	text     string // text the elements have, "" to count every one of kind
+	kind     string // element type named after the ordinal, if any
+	selector string // the elements of kind, see ordinalElementTypes
+}
+
+// describe names the elements counted, for messages
+func (o ordinalTarget) describe() string {
+	return strings.TrimSpace(o.text + " " + o.kind)
+}
+
+// parseOrdinalTarget splits "the 2nd edit" into a 0-based index and the text
+// "edit", and "the second delete button" into index 1, the text "delete" and
+// the kind "button". ok is false if target doesn't start with an ordinal.
+func parseOrdinalTarget(target string) (ordinal ordinalTarget, ok bool) {
+	m := ordinalTargetPattern.FindStringSubmatch(strings.TrimSpace(target))
+	if m == nil {
+		return ordinalTarget{}, false
+	}
+
+	word := strings.ToLower(m[1])
+	if i, known := ordinalWords[word]; known {
+		ordinal.index = i
+	} else if n, err := strconv.Atoi(strings.TrimRight(word, "stndrh")); err == nil && n >= 1 {
+		ordinal.index = n - 1
+	} else {
+		return ordinalTarget{}, false
+	}
+
+	words := strings.Fields(m[2])
+	last := strings.ToLower(words[len(words)-1])
+	if selector, known := ordinalElementTypes[last]; known {
+		ordinal.kind, ordinal.selector = last, selector
+		words = words[:len(words)-1]
+	}
+	ordinal.text = strings.Join(words, " ")
+	return ordinal, true
+}
+
+// isOrdinalClick reports whether target picks an element by position. Text
+// like "first name" or "second address" starts with an ordinal word too, so
+// it's only taken as one when an element type follows or no element on the
+// page has the whole text.
+func (v *NavigationView) isOrdinalClick(target string) (ordinalTarget, bool) {
+	ordinal, ok := parseOrdinalTarget(target)
+	if !ok {
+		return ordinalTarget{}, false
+	}
+	if ordinal.kind != "" {
+		return ordinal, true
+	}
+	whole := strings.TrimPrefix(strings.ToLower(normalizeText(target)), "the ")
+	for _, elem := range v.pageElements {
+		if strings.Contains(strings.ToLower(normalizeText(elem.Text)), whole) {
+			return ordinalTarget{}, false
+		}
+	}
+	return ordinal, true
+}
+
+// choiceNumberPattern matches phrasings that pick a numbered choice, such as
+// "3", "#2", "3.", "3)", "select 3", "do #2" or "option 4"
+var choiceNumberPattern = regexp.MustCompile(`^(?i)(?:(?:select|choose|pick|do|go|take|option|number|no\.?)\s*)?(?:number\s*|option\s*)?#?\s*(\d+)\s*[.):]?$`)
+
+// parseChoiceNumber extracts the choice number from a selection phrase. Input
+// with anything beyond the number and selection words is left alone, so
+// numeric text like "404 page" or "2024 report" isn't treated as a choice.
+func parseChoiceNumber(input string) (int, bool) {
+	matches := choiceNumberPattern.FindStringSubmatch(strings.TrimSpace(input))
+	if matches == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// runCommand runs a command handler and converts the outcome to a message
+func (v *NavigationView) runCommand(command *Command) tea.Msg {
+	if v.planOnly() && !command.Local {
+		return PlannedActionMsg{Verb: "command", Target: command.Display}
+	}
+	if err := v.ensureChrome(); err != nil {
+		return NavigationErrorMsg{Error: err}
+	}
+	if err := command.Handler(v); err != nil {
+		return errorMsg(err, func() tea.Msg { return v.runCommand(command) })
+	}
+	if msg := v.startedStream; msg != nil {
+		v.startedStream = nil
+		return msg
+	}
+	if command.Local {
+		return CommandCompleteMsg{}
+	}
+	return NavigationCompleteMsg{
+		URL:     v.currentURL,
+		Success: true,
+	}
+}
+
+// navigateToURLMsg navigates to a URL and converts the outcome to a message
+func (v *NavigationView) navigateToURLMsg(url string) tea.Msg {
+	if v.planOnly() {
+		return PlannedActionMsg{Verb: "navigate", Target: browser.StripURLCredentials(url)}
+	}
+	if err := v.ensureChrome(); err != nil {
+		return NavigationErrorMsg{Error: err}
+	}
+	if err := v.navigateToURL(url); err != nil {
+		return NavigationErrorMsg{Error: err}
+	}
+	return NavigationCompleteMsg{
+		URL:     browser.StripURLCredentials(url),
+		Success: true,
+	}
+}
+
+// executePastedCommands interprets pasted lines in one batch and then runs
+// them in order, see runPastedCommands
+func (v *NavigationView) executePastedCommands(pasted string) tea.Cmd {
+	var lines []string
+	for _, line := range strings.Split(pasted, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	v.addHistory(fmt.Sprintf("📋 Running %d pasted commands", len(lines)))
+	return v.interpretPastedCommands(lines)
+}
+
+// interpretPastedCommands sends all pasted lines to the LLM in a single
+// batch, along with the current page's elements so each interpretation can
+// name the element it acts on
+func (v *NavigationView) interpretPastedCommands(lines []string) tea.Cmd {
+	elements := append([]NavigableElement(nil), v.pageElements...)
+	return func() tea.Msg {
+		msg := PastedCommandsMsg{Lines: lines}
+		// The offline mock can't judge typed commands, so run them all
+		if v.llmClient == nil || v.aiOffline {
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		interpretations, err := v.llmClient.InterpretCommands(ctx, lines, elementActions(elements), nil)
+		if err != nil {
+			logging.Warn("Batch command interpretation failed: %v", err)
+			return msg
+		}
+		for _, interpretation := range interpretations {
+			if interpretation == nil {
+				continue
 			}
-			return NavigationCompleteMsg{
-				URL:     input,
-				Success: true,
+			v.trackUsage(interpretation.Usage)
+			resolveInterpretedElement(interpretation, elements)
+		}
+		msg.Interpretations = interpretations
+		return msg
+	}
+}
+
+// elementActions describes page elements to the LLM as actions whose IDs
+// are their index in elements
+func elementActions(elements []NavigableElement) []types.CodeAction {
+	actions := make([]types.CodeAction, 0, len(elements))
+	for i, element := range elements {
+		if element.Disabled {
+			continue
+		}
+		actions = append(actions, types.CodeAction{
+			ID:          strconv.Itoa(i),
+			Name:        element.Text,
+			Type:        element.Method,
+			Description: firstNonEmpty(element.Description, element.Selector),
+		})
+	}
+	return actions
+}
+
+// resolveInterpretedElement records the selector and text of the element an
+// interpretation's action ID names, so it can still be found after earlier
+// pasted commands have changed the page
+func resolveInterpretedElement(interpretation *llm.CommandInterpretation, elements []NavigableElement) {
+	index, err := strconv.Atoi(interpretation.ActionID)
+	if err != nil || index < 0 || index >= len(elements) {
+		return
+	}
+	if interpretation.Parameters == nil {
+		interpretation.Parameters = make(map[string]string)
+	}
+	interpretation.Parameters["selector"] = elements[index].Selector
+	if interpretation.Parameters["target"] == "" {
+		interpretation.Parameters["target"] = elements[index].Text
+	}
+}
+
+// runInterpretation runs a pasted line as the batch interpreted it, without
+// interpreting it again. Lines it couldn't pin to an action run as typed.
+func (v *NavigationView) runInterpretation(line string, interpretation *llm.CommandInterpretation) tea.Cmd {
+	params := interpretation.Parameters
+	target := params["target"]
+
+	var command *Command
+	switch interpretation.CommandType {
+	case llm.CommandNavigate, llm.CommandClick:
+		if selector := params["selector"]; selector != "" {
+			return func() tea.Msg {
+				for _, element := range v.pageElements {
+					if element.Selector == selector {
+						return v.executeElement(element)()
+					}
+				}
+				msg := v.runCommand(&Command{
+					Display: "click " + target,
+					Handler: func(v *NavigationView) error { return v.clickTarget(target) },
+				})
+				v.recordExecutedStep("command", line, selector, msg)
+				return msg
+			}
+		}
+		if url := params["url"]; url != "" && interpretation.CommandType == llm.CommandNavigate {
+			return func() tea.Msg {
+				msg := v.navigateToURLMsg(url)
+				v.recordExecutedStep("navigate", url, "", msg)
+				return msg
+			}
+		}
+		if target != "" && interpretation.CommandType == llm.CommandClick {
+			command = &Command{
+				Display: "click " + target,
+				Handler: func(v *NavigationView) error { return v.clickTarget(target) },
+			}
+		}
+	case llm.CommandFill:
+		if field := params["field"]; field != "" {
+			value := params["value"]
+			command = &Command{
+				Display: "fill " + field,
+				Handler: func(v *NavigationView) error { return v.fillByLabel(field, value) },
+			}
+		}
+	case llm.CommandSelect:
+		if option := params["value"]; option != "" {
+			command = &Command{
+				Display: "select " + option,
+				Handler: func(v *NavigationView) error { return v.selectOption(option) },
 			}
 		}
+	}
+	if command == nil {
+		return v.executeInputValue(line)
+	}
 
-		return NavigationErrorMsg{Error: fmt.Errorf("no matches found for: %s", input)}
+	return func() tea.Msg {
+		v.isProcessing = true
+		msg := v.runCommand(command)
+		v.recordExecutedStep("command", v.recordedInput(line), "", msg)
+		return msg
+	}
+}
+
+// runPastedCommands runs the pasted lines the LLM understood confidently
+// enough, per ai.confidence_threshold, as it interpreted them. Lines in the
+// band below it, down to ai.confidence_ask_threshold, are answered with
+// "Did you mean ...?" and lines below that are skipped. Without
+// interpretations every line runs as typed.
+func (v *NavigationView) runPastedCommands(msg PastedCommandsMsg) tea.Cmd {
+	ask, execute := config.DefaultConfidenceAskThreshold, config.DefaultConfidenceThreshold
+	if v.config != nil {
+		ask, execute = v.config.AI.ConfidenceBands()
+	}
+
+	var cmds []tea.Cmd
+	for i, line := range msg.Lines {
+		if i < len(msg.Interpretations) && msg.Interpretations[i] != nil {
+			interpretation := msg.Interpretations[i]
+			v.addHistory(fmt.Sprintf("  %d. %s → %s (%.0f%%)", i+1, truncateText(line, 40), interpretation.CommandType, interpretation.Confidence*100))
+			switch {
+			case interpretation.Confidence < ask:
+				v.addHistory("     ✗ Not understood, skipped")
+				continue
+			case interpretation.Confidence < execute:
+				v.addHistory("     " + didYouMean(line, interpretation))
+				continue
+			}
+			cmds = append(cmds, v.runInterpretation(line, interpretation))
+			continue
+		}
+		cmds = append(cmds, v.executeInputValue(line))
+	}
+	if len(cmds) == 0 {
+		v.isProcessing = false
+		return nil
+	}
+	return tea.Sequence(cmds...)
+}
+
+// didYouMean asks about an interpreted command that wasn't confident enough
+// to run, offering its top suggestions
+func didYouMean(line string, interpretation *llm.CommandInterpretation) string {
+	suggestions := interpretation.Suggestions
+	if len(suggestions) == 0 && interpretation.Intent != "" {
+		suggestions = []string{interpretation.Intent}
+	}
+	if len(suggestions) == 0 {
+		return fmt.Sprintf("🤔 Not sure what \"%s\" means, rephrase it to run it", truncateText(line, 40))
 	}
+
+	quoted := make([]string, 0, 3)
+	for _, suggestion := range suggestions[:min(len(suggestions), 3)] {
+		quoted = append(quoted, fmt.Sprintf("\"%s\"", suggestion))
+	}
+	return fmt.Sprintf("🤔 Did you mean: %s? Type it to run it", strings.Join(quoted, ", "))
 }
 
 func (v *NavigationView) executeElement(element NavigableElement) tea.Cmd {
+	if v.planOnly() {
+		return func() tea.Msg {
+			return v.simulate(element.Method, element.Text, element.Selector)
+		}
+	}
+	perform := v.performElement(element)
+	return func() tea.Msg {
+		before, after := v.config.Hooks.ForHost(hostOf(v.currentURL))
+
+		if err := v.runHook("before_action", before); err != nil && v.config.Hooks.Fatal {
+			return NavigationErrorMsg{Error: err}
+		}
+		if err := v.ensureChrome(); err != nil {
+			return NavigationErrorMsg{Error: err}
+		}
+
+		msg := perform()
+		if failed, ok := msg.(NavigationErrorMsg); ok && isConfirmationRequired(failed.Error) {
+			// Asking first, so the action hasn't happened
+			return errorMsg(failed.Error, v.executeElement(element))
+		}
+
+		if err := v.runHook("after_action", after); err != nil && v.config.Hooks.Fatal {
+			msg = NavigationErrorMsg{Error: err}
+		}
+
+		v.recordExecutedStep(element.Method, element.Text, element.Selector, msg)
+		return msg
+	}
+}
+
+// runHook executes a configured hook script in the page. Failures are logged
+// and returned so the caller can decide whether they are fatal.
+func (v *NavigationView) runHook(name, script string) error {
+	if script == "" || v.chromeDPManager == nil {
+		return nil
+	}
+
+	var result interface{}
+	if err := v.chromeDPManager.ExecuteScript(script, &result); err != nil {
+		logging.Warn("Hook %s failed: %v", name, err)
+		v.addHistory(fmt.Sprintf("⚠️  %s hook failed: %v", name, err))
+		return fmt.Errorf("%s hook failed: %w", name, err)
+	}
+
+	logging.Debug("Hook %s completed", name)
+	return nil
+}
+
+// hostOf returns the host portion of a URL, or "" if it can't be parsed
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// actionWaitTimeout bounds how long to wait for browser.action_wait_condition
+const actionWaitTimeout = 10 * time.Second
+
+// waitForPageUpdate gives the page time to react to an action. With a
+// condition (a JS expression) it waits until the condition holds, otherwise
+// it waits the fixed delay.
+func (v *NavigationView) waitForPageUpdate(condition string, delay time.Duration) {
+	if condition == "" {
+		time.Sleep(delay)
+		return
+	}
+
+	if _, err := v.chromeDPManager.WaitForCondition(condition, actionWaitTimeout); err != nil {
+		if errors.Is(err, browser.ErrConditionTimeout) {
+			v.addHistory(fmt.Sprintf("⏱ Page didn't settle within %v", actionWaitTimeout))
+		}
+		logging.Warn("Waiting for page update: %v", err)
+	}
+}
+
+// reportConsoleErrors notes JS errors the page logged during the last action,
+// which otherwise look like a click that silently did nothing
+func (v *NavigationView) reportConsoleErrors() {
+	errs := v.chromeDPManager.DrainConsoleErrors()
+	if len(errs) == 0 {
+		return
+	}
+
+	noun := "error"
+	if len(errs) > 1 {
+		noun = "errors"
+	}
+	v.addHistory(fmt.Sprintf("⚠️  Action completed but page logged %d JS %s: %s", len(errs), noun, truncateText(strings.SplitN(errs[0], "\n", 2)[0], 80)))
+	for _, e := range errs {
+		logging.Warn("Page JS error: %s", e)
+	}
+}
+
+// reportDryRun notes the actions the browser skipped in dry-run mode, like
+// the ones simulated before reaching it
+func (v *NavigationView) reportDryRun() {
+	if v.chromeDPManager == nil {
+		return
+	}
+	for _, action := range v.chromeDPManager.DrainDryRun() {
+		v.addHistory(fmt.Sprintf("📝 [SIMULATED] Would %s", action))
+	}
+}
+
+// reportDialogs notes the JS dialogs answered during the last action
+func (v *NavigationView) reportDialogs() {
+	for _, d := range v.chromeDPManager.DrainDialogs() {
+		verb := "Accepted"
+		if !d.Accepted {
+			verb = "Dismissed"
+		}
+		v.addHistory(fmt.Sprintf("→ %s %s dialog: '%s'", verb, d.Type, truncateText(d.Message, 60)))
+	}
+}
+
+// actionWaitCondition returns the configured condition to wait for after actions
+func (v *NavigationView) actionWaitCondition() string {
+	if v.config == nil {
+		return ""
+	}
+	return v.config.Browser.ActionWaitCondition
+}
+
+// performElement carries out an element's action in the browser
+func (v *NavigationView) performElement(element NavigableElement) tea.Cmd {
 	return func() tea.Msg {
 		if v.chromeDPManager == nil {
 			return NavigationErrorMsg{Error: fmt.Errorf("Chrome not connected")}
 		}
+		switch element.Method {
+		case "navigate", "click", "submit":
+			if err := v.guardDestructive(element.Text, firstNonEmpty(element.Selector, element.URL)); err != nil {
+				return NavigationErrorMsg{Error: err}
+			}
+		case "form_submit":
+			if err := v.guardSubmit(); err != nil {
+				return NavigationErrorMsg{Error: err}
+			}
+		}
+		v.snapshotBeforeAction()
 
 		switch element.Method {
 		case "navigate":
@@ -1304,27 +2037,38 @@ func (v *NavigationView) executeElement(element NavigableElement) tea.Cmd {
 					return NavigationErrorMsg{Error: fmt.Errorf("element not found: %w", err)}
 				}
 
-				if err := v.chromeDPManager.Click(element.Selector); err != nil {
+				// Only report errors and dialogs the click causes
+				v.chromeDPManager.DrainConsoleErrors()
+				v.chromeDPManager.DrainDialogs()
+				requests, err := v.clickCapturingNetwork(element.Selector)
+				if err != nil {
 					return NavigationErrorMsg{Error: err}
 				}
 
-				// Wait a bit for any navigation or changes
-				time.Sleep(500 * time.Millisecond)
+				// Wait for any navigation or changes
+				v.waitForPageUpdate(v.actionWaitCondition(), 500*time.Millisecond)
+				v.reportDialogs()
+				v.reportConsoleErrors()
+				v.reportNewTabs()
 
 				// Get updated page info
 				url, _, _ := v.chromeDPManager.GetPageInfo()
+				v.recordExpectedResult(element, url)
 
 				// Add history message for the click action
 				elementText := truncateText(element.Text, 30)
 				if url != v.currentURL {
 					// Navigation occurred after click
+					if len(requests) > 0 {
+						v.reportClick(elementText, requests)
+					}
 					return NavigationCompleteMsg{
 						URL:     url,
 						Success: true,
 					}
 				} else {
 					// No navigation, just clicked element
-					v.addHistory(fmt.Sprintf("→ Clicked \"%s\"", elementText))
+					v.reportClick(elementText, requests)
 					return NavigationCompleteMsg{
 						URL:     url,
 						Success: true,
@@ -1349,19 +2093,44 @@ func (v *NavigationView) executeElement(element NavigableElement) tea.Cmd {
 			}
 			return NavigationErrorMsg{Error: fmt.Errorf("form handler not available")}
 
-		case "type":
-			// For form fields, focus and wait for user input
-			return NavigationErrorMsg{Error: fmt.Errorf("form input not yet supported")}
+		case "select":
+			// List the options so the user can pick one with "select <option>"
+			options, err := v.chromeDPManager.GetSelectOptions(element.Selector)
+			if err != nil {
+				return NavigationErrorMsg{Error: err}
+			}
+			var names []string
+			for _, o := range options {
+				names = append(names, o.Text)
+			}
+			v.pendingSelect = &element
+			v.addHistory(fmt.Sprintf("▾ Options for \"%s\": %s", truncateText(element.Text, 30), strings.Join(names, ", ")))
+			v.addHistory("  Type \"select <option>\" to choose one")
+			return CommandCompleteMsg{}
+
+		case "type", "upload":
+			// Prompt for the value to type, or the files to upload, instead
+			// of guessing one
+			return v.promptForInput(element)
 
 		case "submit":
 			if element.Selector != "" {
-				if err := v.chromeDPManager.Click(element.Selector); err != nil {
+				v.chromeDPManager.DrainConsoleErrors()
+				v.chromeDPManager.DrainDialogs()
+				requests, err := v.clickCapturingNetwork(element.Selector)
+				if err != nil {
 					return NavigationErrorMsg{Error: err}
 				}
+				if len(requests) > 0 {
+					v.reportClick(truncateText(element.Text, 30), requests)
+				}
 
-				time.Sleep(1 * time.Second) // Wait for form submission
+				v.waitForPageUpdate(v.actionWaitCondition(), 1*time.Second) // Wait for form submission
+				v.reportDialogs()
+				v.reportConsoleErrors()
 
 				url, _, _ := v.chromeDPManager.GetPageInfo()
+				v.recordExpectedResult(element, url)
 				return NavigationCompleteMsg{
 					URL:     url,
 					Success: true,
@@ -1383,6 +2152,20 @@ func (v *NavigationView) getAvailableCommands() []Command {
 				return v.goBack()
 			},
 		},
+		{
+			Display:     "go forward",
+			Description: "Navigate forward in browser history",
+			Handler: func(v *NavigationView) error {
+				return v.goForward()
+			},
+		},
+		{
+			Display:     "undo",
+			Description: "Undo the last navigation or form fill",
+			Handler: func(v *NavigationView) error {
+				return v.undo()
+			},
+		},
 		{
 			Display:     "go to home",
 			Description: "Navigate to homepage",
@@ -1404,27 +2187,243 @@ func (v *NavigationView) getAvailableCommands() []Command {
 				return v.reconnectChrome()
 			},
 		},
+		{
+			Display:     "headful",
+			Description: "Relaunch Chrome with a visible window on the current page",
+			Handler: func(v *NavigationView) error {
+				return v.setHeadless(false)
+			},
+		},
+		{
+			Display:     "headless",
+			Description: "Relaunch Chrome without a window on the current page",
+			Handler: func(v *NavigationView) error {
+				return v.setHeadless(true)
+			},
+		},
+		{
+			Display:     "environments",
+			Description: "List environments and switch between them",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.showEnvironments()
+			},
+		},
+		{
+			Display:     "help",
+			Description: "Show key bindings and commands",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				v.showHelp = true
+				return nil
+			},
+		},
+		{
+			Display:     "actions",
+			Description: "List the actions discovered on this page, numbered",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.listActions()
+			},
+		},
+		{
+			Display:     "diff",
+			Description: "Show what changed on the page after the last action",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.ShowLastDiff()
+			},
+		},
+		{
+			Display:     "confirm on",
+			Description: "Ask before clicking anything that looks destructive",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.setConfirmDestructive(true)
+			},
+		},
+		{
+			Display:     "confirm off",
+			Description: "Click destructive-looking elements without asking",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.setConfirmDestructive(false)
+			},
+		},
+		{
+			Display:     "offline",
+			Description: "Cut the page's network access to test offline behavior",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.setOffline(true)
+			},
+		},
+		{
+			Display:     "online",
+			Description: "Restore network access and previous network conditions",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.setOffline(false)
+			},
+		},
+		{
+			Display:     "export checklist",
+			Description: "Save this page's actions as a markdown checklist for manual QA",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.exportChecklist()
+			},
+		},
+		{
+			Display:     "fill all",
+			Description: "Fill every field of the detected form, one prompt at a time",
+			Handler: func(v *NavigationView) error {
+				return v.fillAllFields()
+			},
+		},
+		{
+			Display:     "capture page",
+			Description: "Save this page's HTML, a screenshot and its actions for review",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.capturePage()
+			},
+		},
+		{
+			Display:     "save flow",
+			Description: "Stop recording and save the flow to .tod/flows",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.saveFlow()
+			},
+		},
+		{
+			Display:     "inspect",
+			Description: "Toggle inspect mode: click an element in the browser to pick its selector",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.toggleInspect()
+			},
+		},
+		{
+			Display:     "llm log",
+			Description: "Browse recent LLM prompts and responses, with secrets redacted",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.showLLMInspector("")
+			},
+		},
+		{
+			Display:     "screenshot",
+			Description: "Save a PNG of this page to .tod/screenshots",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.takeScreenshot("")
+			},
+		},
+		{
+			Display:     "crawl",
+			Description: "Discover actions on this page and the pages it links to",
+			Handler: func(v *NavigationView) error {
+				return v.startCrawl()
+			},
+		},
+		{
+			Display:     "switch to new tab",
+			Description: "Follow the tab the last click opened",
+			Handler: func(v *NavigationView) error {
+				return v.switchToNewTab()
+			},
+		},
+		{
+			Display:     "list tabs",
+			Description: "Show the open browser tabs",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.listTabs()
+			},
+		},
+		{
+			Display:     "close tab",
+			Description: "Close this tab and return to the original one",
+			Handler: func(v *NavigationView) error {
+				return v.closeTab()
+			},
+		},
+		{
+			Display:     "save cookies",
+			Description: "Save this site's cookies to .tod/cookies to skip logging in next time",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.saveCookies()
+			},
+		},
+		{
+			Display:     "load cookies",
+			Description: "Restore this site's saved cookies and reload the page",
+			Handler: func(v *NavigationView) error {
+				return v.loadCookies()
+			},
+		},
+		{
+			Display:     "save session",
+			Description: "Save this session's history to .tod/sessions to resume later",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				path, err := v.saveSession()
+				if err != nil {
+					return err
+				}
+				v.addHistory(fmt.Sprintf("💾 Session %s saved to %s", v.sessionID, path))
+				return nil
+			},
+		},
+		{
+			Display:     "replay submit",
+			Description: "Re-send the last form submission as an API call",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.replaySubmit(false)
+			},
+		},
+		{
+			Display:     "replay submit without session",
+			Description: "Re-send the last form submission without cookies or auth headers",
+			Local:       true,
+			Handler: func(v *NavigationView) error {
+				return v.replaySubmit(true)
+			},
+		},
 	}
 }
 
+// commandAliases maps shorthand input to the command it runs
+var commandAliases = map[string]string{
+	"go":         "go to home",
+	"back":       "go back",
+	"forward":    "go forward",
+	"refresh":    "refresh",
+	"reload":     "refresh",
+	"connect":    "connect",
+	"home":       "go to home",
+	"homepage":   "go to home",
+	"env":        "environments",
+	"envs":       "environments",
+	"replay":     "replay submit",
+	"checklist":  "export checklist",
+	"capture":    "capture page",
+	"go offline": "offline",
+	"go online":  "online",
+	"?":          "help",
+}
+
 func (v *NavigationView) matchCommand(input string) *Command {
-	inputLower := strings.ToLower(strings.TrimSpace(input))
+	inputLower := strings.ToLower(normalizeText(input))
 
 	commands := v.getAvailableCommands()
 
-	// Check for prefix matches with common command patterns
-	commandPatterns := map[string]string{
-		"go":       "go to home",
-		"back":     "go back",
-		"refresh":  "refresh",
-		"reload":   "refresh",
-		"connect":  "connect",
-		"home":     "go to home",
-		"homepage": "go to home",
-	}
-
 	// Direct pattern matching
-	if cmd, exists := commandPatterns[inputLower]; exists {
+	if cmd, exists := commandAliases[inputLower]; exists {
 		for _, command := range commands {
 			if command.Display == cmd {
 				return &command
@@ -1452,8 +2451,163 @@ func (v *NavigationView) matchCommand(input string) *Command {
 				Display:     fmt.Sprintf("go to %s", target),
 				Description: fmt.Sprintf("Navigate to %s", target),
 				Handler: func(v *NavigationView) error {
-					return v.navigateToTarget(target)
+					return v.navigateToTarget(target)
+				},
+			}
+		}
+	}
+
+	// Check for "select [option]" or "select [option] in [dropdown]" pattern
+	if strings.HasPrefix(inputLower, "select ") {
+		target := strings.TrimSpace(input[len("select "):])
+		if target != "" {
+			return &Command{
+				Display:     fmt.Sprintf("select %s", target),
+				Description: fmt.Sprintf("Choose %s from a dropdown", target),
+				Handler: func(v *NavigationView) error {
+					return v.selectOption(target)
+				},
+			}
+		}
+	}
+
+	// Check for "load session [id]" pattern
+	if strings.HasPrefix(inputLower, "load session ") {
+		id := strings.TrimSpace(input[len("load session "):])
+		if id != "" {
+			return &Command{
+				Display:     fmt.Sprintf("load session %s", id),
+				Description: fmt.Sprintf("Resume saved session %s", id),
+				Handler: func(v *NavigationView) error {
+					return v.resumeSession(id)
+				},
+			}
+		}
+	}
+
+	// Check for "switch to tab [n]" pattern
+	if strings.HasPrefix(inputLower, "switch to tab ") {
+		if n, err := strconv.Atoi(strings.TrimSpace(input[len("switch to tab "):])); err == nil {
+			return &Command{
+				Display:     fmt.Sprintf("switch to tab %d", n),
+				Description: fmt.Sprintf("Switch to tab %d from \"list tabs\"", n),
+				Handler: func(v *NavigationView) error {
+					return v.switchToTabNumber(n)
+				},
+			}
+		}
+	}
+
+	// Check for "scroll to [element]" pattern
+	if strings.HasPrefix(inputLower, "scroll to ") {
+		target := strings.TrimSpace(input[len("scroll to "):])
+		if target != "" {
+			return &Command{
+				Display:     fmt.Sprintf("scroll to %s", target),
+				Description: fmt.Sprintf("Scroll %s into view", target),
+				Handler: func(v *NavigationView) error {
+					return v.scrollTo(target)
+				},
+			}
+		}
+	}
+
+	// Check for "discover file [path]" pattern
+	if strings.HasPrefix(inputLower, "discover file ") {
+		path := strings.TrimSpace(input[len("discover file "):])
+		if path != "" {
+			return &Command{
+				Display:     fmt.Sprintf("discover file %s", path),
+				Description: fmt.Sprintf("Discover the actions in %s", path),
+				Handler: func(v *NavigationView) error {
+					return v.discoverFile(path)
+				},
+				Local: true,
+			}
+		}
+	}
+
+	// Check for "screenshot [filename]" and "screenshot of [selector]" patterns
+	if strings.HasPrefix(inputLower, "screenshot ") {
+		args := strings.TrimSpace(input[len("screenshot "):])
+		if args != "" {
+			return &Command{
+				Display:     fmt.Sprintf("screenshot %s", args),
+				Description: "Save a PNG of this page or an element to .tod/screenshots",
+				Handler: func(v *NavigationView) error {
+					return v.takeScreenshot(args)
+				},
+				Local: true,
+			}
+		}
+	}
+
+	// Check for "record flow [name]" and "run flow [name]" patterns
+	if strings.HasPrefix(inputLower, "record flow ") {
+		name := strings.TrimSpace(input[len("record flow "):])
+		if name != "" {
+			return &Command{
+				Display:     fmt.Sprintf("record flow %s", name),
+				Description: fmt.Sprintf("Record the next form fill and submit as flow %s", name),
+				Handler: func(v *NavigationView) error {
+					return v.startFlowRecording(name)
+				},
+				Local: true,
+			}
+		}
+	}
+	if strings.HasPrefix(inputLower, "run flow ") {
+		args := strings.TrimSpace(input[len("run flow "):])
+		if args != "" {
+			return &Command{
+				Display:     fmt.Sprintf("run flow %s", args),
+				Description: "Replay a flow saved with \"save flow\"",
+				Handler: func(v *NavigationView) error {
+					return v.runFlow(args)
+				},
+			}
+		}
+	}
+
+	// Check for "fill <label> with <value>" pattern
+	if strings.HasPrefix(inputLower, "fill ") {
+		if label, value, ok := parseFillByLabel(input[len("fill "):]); ok {
+			return &Command{
+				Display:     fmt.Sprintf("fill %s with %s", label, v.fillDisplayValue(label, value)),
+				Description: fmt.Sprintf("Fill the field labelled %q", label),
+				Handler: func(v *NavigationView) error {
+					return v.fillByLabel(label, value)
+				},
+			}
+		}
+	}
+
+	// Check for "llm log [capture id]" pattern
+	if strings.HasPrefix(inputLower, "llm log ") {
+		captureID := strings.TrimSpace(input[len("llm log "):])
+		if captureID != "" {
+			return &Command{
+				Display:     fmt.Sprintf("llm log %s", captureID),
+				Description: fmt.Sprintf("Browse the LLM prompts and responses of capture %s", captureID),
+				Handler: func(v *NavigationView) error {
+					return v.showLLMInspector(captureID)
+				},
+				Local: true,
+			}
+		}
+	}
+
+	// Check for "verify [selector]" pattern
+	if strings.HasPrefix(inputLower, "verify ") {
+		selector := strings.TrimSpace(input[len("verify "):])
+		if selector != "" {
+			return &Command{
+				Display:     fmt.Sprintf("verify %s", selector),
+				Description: fmt.Sprintf("Count elements matching %s", selector),
+				Handler: func(v *NavigationView) error {
+					return v.verifySelector(selector)
 				},
+				Local: true,
 			}
 		}
 	}
@@ -1475,22 +2629,60 @@ func (v *NavigationView) matchCommand(input string) *Command {
 	return bestMatch
 }
 
+// fragileThreshold is the selector stability score below which an action is
+// flagged with ⚠, from testing.fragile_selector_threshold
+func (v *NavigationView) fragileThreshold() float64 {
+	if v.config == nil {
+		return 0
+	}
+	return v.config.Testing.FragileSelectorThreshold
+}
+
+// scoreElement ranks an element against the input using the configured
+// matching weights for text, description and element type
+func (v *NavigationView) scoreElement(input string, elem NavigableElement) float64 {
+	score := v.fuzzyMatch(input, elem.Text) * v.weights.Text
+
+	if v.weights.Description > 0 && elem.Description != "" {
+		if descScore := v.fuzzyMatch(input, elem.Description) * v.weights.Description; descScore > score {
+			score = descScore
+		}
+	}
+
+	if score > 0 {
+		score += v.weights.TypeBonus[v.elementTypeToString(elem.Type)]
+	}
+
+	// Deprioritize elements that can't be acted on
+	if elem.Disabled {
+		score *= 0.5
+	}
+
+	return score
+}
+
+// fuzzyTypoThreshold is the FuzzyScore below which a non-substring match is
+// too different to count as a typo
+const fuzzyTypoThreshold = 0.6
+
 func (v *NavigationView) fuzzyMatch(input, text string) float64 {
 	if input == "" {
 		return 0.0
 	}
 
-	inputLower := strings.ToLower(strings.TrimSpace(input))
-	textLower := strings.ToLower(strings.TrimSpace(text))
+	inputLower := strings.ToLower(normalizeText(input))
+	textLower := strings.ToLower(normalizeText(text))
+
+	w := v.weights
 
 	// Exact match gets highest score
 	if inputLower == textLower {
-		return 1.0
+		return w.ExactMatch
 	}
 
 	// Prefix match gets high score
 	if strings.HasPrefix(textLower, inputLower) {
-		return 0.9
+		return w.PrefixMatch
 	}
 
 	// Contains match gets medium score
@@ -1499,7 +2691,7 @@ func (v *NavigationView) fuzzyMatch(input, text string) float64 {
 		pos := strings.Index(textLower, inputLower)
 		posScore := 1.0 - float64(pos)/float64(len(textLower))
 		lengthScore := float64(len(inputLower)) / float64(len(textLower))
-		return 0.6 + (posScore+lengthScore)/5.0
+		return w.ContainsMatch + (posScore+lengthScore)/5.0
 	}
 
 	// Word boundary matching
@@ -1516,9 +2708,18 @@ func (v *NavigationView) fuzzyMatch(input, text string) float64 {
 		}
 	}
 
+	var best float64
 	if matchCount > 0 {
 		wordScore := float64(matchCount) / float64(len(textWords))
-		return 0.3 + wordScore*0.3
+		best = w.WordMatch + wordScore*w.WordMatch
+	}
+
+	// Edit distance catches typos like "dashbaord"; weak matches are noise
+	if typoScore := FuzzyScore(inputLower, textLower); typoScore >= fuzzyTypoThreshold && typoScore > best {
+		best = typoScore
+	}
+	if best > 0 {
+		return best
 	}
 
 	// Character-based fuzzy matching for typos
@@ -1531,7 +2732,7 @@ func (v *NavigationView) fuzzyMatch(input, text string) float64 {
 		}
 
 		if charMatches >= len(inputLower)/2 {
-			return float64(charMatches) / float64(len(inputLower)) * 0.2
+			return float64(charMatches) / float64(len(inputLower)) * w.CharMatch
 		}
 	}
 
@@ -1562,26 +2763,52 @@ func (v *NavigationView) addToHistory(url string) {
 
 func (v *NavigationView) navigateBack() tea.Cmd {
 	return func() tea.Msg {
-		if v.historyIndex > 0 {
-			v.historyIndex--
-			url := v.navigationHistory[v.historyIndex]
-			if err := v.navigateToURL(url); err != nil {
-				return NavigationErrorMsg{Error: err}
+		if v.planOnly() {
+			return v.simulate("back", "previous page", "")
+		}
+		if v.chromeDPManager == nil {
+			return NavigationErrorMsg{Error: fmt.Errorf("browser not connected")}
+		}
+		if err := v.chromeDPManager.Back(); err != nil {
+			if errors.Is(err, browser.ErrNoPreviousPage) {
+				return NavigationErrorMsg{Error: fmt.Errorf("No previous page")}
 			}
-			return NavigationCompleteMsg{URL: url, Success: true}
+			return NavigationErrorMsg{Error: err}
 		}
-		return NavigationErrorMsg{Error: fmt.Errorf("no back history available")}
+		url, _, _ := v.chromeDPManager.GetPageInfo()
+		return NavigationCompleteMsg{URL: url, Success: true}
 	}
 }
 
 // addHistory adds a simple text message to the history display
 func (v *NavigationView) addHistory(message string) {
-	v.history = append(v.history, message)
+	entry := HistoryEntry{Message: message, Timestamp: time.Now(), URL: v.currentURL}
+	v.appendHistory(entry)
+	v.logHistory(entry)
+	// Also log the history message to file
+	logging.Info("[UI] %s", message)
+}
+
+// appendHistory adds an entry to the display history, dropping the oldest
+// once maxHistory is reached
+func (v *NavigationView) appendHistory(entry HistoryEntry) {
+	v.history = append(v.history, entry)
 	if len(v.history) > v.maxHistory {
 		v.history = v.history[1:] // Remove oldest message
 	}
-	// Also log the history message to file
-	logging.Info("[UI] %s", message)
+}
+
+// normalizeText collapses runs of whitespace to single spaces, strips
+// zero-width characters and trims the result, so "Sign\n  In" becomes "Sign In"
+func normalizeText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
 }
 
 // truncateText truncates text to maxLen and adds "..." if needed
@@ -1621,12 +2848,12 @@ func (v *NavigationView) navigateToURL(url string) error {
 		if strings.Contains(url, ".") {
 			url = "https://" + url
 		} else {
-			// Try relative to current domain
-			if v.currentURL != "" {
-				url = v.currentURL + "/" + strings.TrimPrefix(url, "/")
-			} else {
-				url = v.configuredURL + "/" + strings.TrimPrefix(url, "/")
+			// Relative paths resolve against the app's home, including any base_path
+			base := v.configuredURL
+			if base == "" {
+				base = v.currentURL
 			}
+			url = strings.TrimRight(base, "/") + "/" + strings.TrimPrefix(url, "/")
 		}
 	}
 
@@ -1638,10 +2865,11 @@ func (v *NavigationView) goBack() error {
 		return fmt.Errorf("browser not connected")
 	}
 	
-	// Use chromedp's NavigateBack action
-	ctx := v.chromeDPManager.GetContext()
-	if err := chromedp.Run(ctx, chromedp.NavigateBack()); err != nil {
-		return fmt.Errorf("failed to navigate back: %w", err)
+	if err := v.chromeDPManager.Back(); err != nil {
+		if errors.Is(err, browser.ErrNoPreviousPage) {
+			return fmt.Errorf("No previous page")
+		}
+		return err
 	}
 	
 	// Update current URL after navigation
@@ -1651,9 +2879,29 @@ func (v *NavigationView) goBack() error {
 		v.addHistory(fmt.Sprintf("⬅️ Navigated back to: %s", url))
 	}
 	
-	// Trigger page analysis for the new page
-	go v.analyzeCurrentPage()
-	
+	// The NavigationCompleteMsg runCommand returns has the new page analyzed
+	return nil
+}
+
+func (v *NavigationView) goForward() error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("browser not connected")
+	}
+
+	if err := v.chromeDPManager.Forward(); err != nil {
+		if errors.Is(err, browser.ErrNoNextPage) {
+			return fmt.Errorf("No next page")
+		}
+		return err
+	}
+
+	url, _, err := v.chromeDPManager.GetPageInfo()
+	if err == nil {
+		v.currentURL = url
+		v.addHistory(fmt.Sprintf("➡️ Navigated forward to: %s", url))
+	}
+
+	// The NavigationCompleteMsg runCommand returns has the new page analyzed
 	return nil
 }
 
@@ -1662,17 +2910,105 @@ func (v *NavigationView) refreshPage() error {
 		return fmt.Errorf("browser not connected")
 	}
 	
-	// Use chromedp's Reload action
-	ctx := v.chromeDPManager.GetContext()
-	if err := chromedp.Run(ctx, chromedp.Reload()); err != nil {
+	if err := v.chromeDPManager.Reload(); err != nil {
 		return fmt.Errorf("failed to refresh page: %w", err)
 	}
 	
 	v.addHistory("🔄 Page refreshed")
 	
-	// Trigger page analysis after refresh
-	go v.analyzeCurrentPage()
-	
+	// The NavigationCompleteMsg runCommand returns has the page analyzed again
+	return nil
+}
+
+// selectOption chooses an option in a dropdown. The target is "<option>" or
+// "<option> in <dropdown>"; without a dropdown the last listed one is used,
+// then every dropdown on the page is tried in turn.
+func (v *NavigationView) selectOption(target string) error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("browser not connected")
+	}
+
+	option, dropdown := target, ""
+	for _, sep := range []string{" in ", " from "} {
+		if i := strings.LastIndex(strings.ToLower(target), sep); i > 0 {
+			option, dropdown = strings.TrimSpace(target[:i]), strings.TrimSpace(target[i+len(sep):])
+			break
+		}
+	}
+
+	var candidates []NavigableElement
+	if dropdown == "" && v.pendingSelect != nil {
+		candidates = append(candidates, *v.pendingSelect)
+	}
+	for _, elem := range v.pageElements {
+		if elem.Method != "select" {
+			continue
+		}
+		if dropdown == "" || v.fuzzyMatch(dropdown, elem.Text) > 0.3 {
+			candidates = append(candidates, elem)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no dropdown found for: %s", target)
+	}
+
+	var lastErr error
+	for _, elem := range candidates {
+		if lastErr = v.chromeDPManager.SelectOption(elem.Selector, option); lastErr == nil {
+			v.pendingSelect = nil
+			v.addHistory(fmt.Sprintf("→ Selected \"%s\" in \"%s\"", option, truncateText(elem.Text, 30)))
+			return nil
+		}
+	}
+	return lastErr
+}
+
+// setOffline switches the page's network off or back on
+func (v *NavigationView) setOffline(offline bool) error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("browser not connected")
+	}
+
+	if offline {
+		if err := v.chromeDPManager.GoOffline(); err != nil {
+			return err
+		}
+		v.addHistory("📴 Network offline")
+		return nil
+	}
+
+	if err := v.chromeDPManager.GoOnline(); err != nil {
+		return err
+	}
+	v.addHistory("📶 Network back online")
+	return nil
+}
+
+// replaySubmit re-issues the request captured from the last form submission
+// and reports the response status and a snippet of the body
+func (v *NavigationView) replaySubmit(stripSession bool) error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("browser not connected")
+	}
+	if v.planOnly() {
+		return fmt.Errorf("replay is disabled in plan-only mode")
+	}
+	if v.formHandler == nil || v.formHandler.LastSubmitRequest() == nil {
+		return fmt.Errorf("no form submission captured yet")
+	}
+
+	record := *v.formHandler.LastSubmitRequest()
+	if stripSession {
+		record = record.StripSessionTokens()
+	}
+
+	v.addHistory(fmt.Sprintf("🔁 Replaying %s %s", record.Method, record.URL))
+	status, body, err := v.chromeDPManager.ReplayRequest(record)
+	if err != nil {
+		return err
+	}
+
+	v.addHistory(fmt.Sprintf("   → %d %s", status, truncateText(strings.TrimSpace(body), 80)))
 	return nil
 }
 
@@ -1686,12 +3022,10 @@ func (v *NavigationView) goHome() error {
 		return fmt.Errorf("failed to navigate home: %w", err)
 	}
 	
-	v.currentURL = v.configuredURL
-	v.addHistory(fmt.Sprintf("🏠 Navigated home to: %s", v.configuredURL))
-	
-	// Trigger page analysis for the home page
-	go v.analyzeCurrentPage()
+	v.currentURL = browser.StripURLCredentials(v.configuredURL)
+	v.addHistory(fmt.Sprintf("🏠 Navigated home to: %s", v.currentURL))
 	
+	// The NavigationCompleteMsg runCommand returns has the home page analyzed
 	return nil
 }
 
@@ -1707,18 +3041,220 @@ func (v *NavigationView) reconnectChrome() error {
 	if v.config != nil {
 		headless = v.config.Browser.Headless
 	}
-	manager, err := browser.GetGlobalChromeDPManager(v.configuredURL, headless)
+	manager, err := browser.GetGlobalChromeDPManager(v.configuredURL, v.launchOptions(headless))
 	if err != nil {
 		return err
 	}
 
 	v.chromeDPManager = manager
+	v.installNavigationGuard()
 	v.isConnected = true
 	return nil
 }
 
-func (v *NavigationView) navigateToTarget(target string) error {
-	// Convert NavigableElements to NavigationElements for LLM
+// launchOptions returns the options to launch Chrome with for the current
+// config and environment
+func (v *NavigationView) launchOptions(headless bool) browser.LaunchOptions {
+	return BrowserLaunchOptions(v.config, headless)
+}
+
+// BrowserLaunchOptions returns the options to launch Chrome with for cfg and
+// its current environment
+func BrowserLaunchOptions(cfg *config.Config, headless bool) browser.LaunchOptions {
+	opts := browser.LaunchOptions{Headless: headless}
+	if cfg == nil {
+		return opts
+	}
+	opts.Port = cfg.Browser.DebugPort
+	opts.DismissDialogs = cfg.Browser.DismissDialogs
+	opts.DryRun = cfg.DryRun
+	opts.InteractiveSelectors = cfg.Browser.InteractiveSelectors
+	opts.AutoDismissConsent = cfg.Browser.AutoDismissConsent
+	opts.ConsentSelectors = cfg.Browser.ConsentSelectors
+	opts.ConsentTexts = cfg.Browser.ConsentTexts
+	timeouts := cfg.Browser.Timeouts
+	opts.Timeouts = browser.Timeouts{
+		Action:     timeouts.Action,
+		Element:    timeouts.Element,
+		Navigation: timeouts.Navigation,
+		Capture:    timeouts.Capture,
+		Scale:      timeouts.Scale,
+	}
+	if env := cfg.GetCurrentEnv(); env != nil {
+		if env.BasicAuth != nil {
+			opts.BasicAuth = &browser.BasicAuth{Username: env.BasicAuth.Username, Password: env.BasicAuth.Password}
+		}
+		opts.Emulation = envEmulation(env)
+	}
+	return opts
+}
+
+// envEmulation is the device an environment is shown as: its device preset,
+// with the viewport and user agent it sets on top
+func envEmulation(env *config.EnvConfig) browser.Emulation {
+	var emulation browser.Emulation
+	if env.Device != "" {
+		preset, err := browser.DevicePreset(env.Device)
+		if err != nil {
+			logging.Warn("Ignoring device of environment %s: %v", env.Name, err)
+		} else {
+			emulation = preset
+		}
+	}
+	if env.Viewport != nil {
+		emulation.Width, emulation.Height = env.Viewport.Width, env.Viewport.Height
+	}
+	if env.UserAgent != "" {
+		emulation.UserAgent = env.UserAgent
+	}
+	return emulation
+}
+
+// setHeadless relaunches Chrome in headless or headful mode and returns to
+// the current page
+func (v *NavigationView) setHeadless(headless bool) error {
+	if v.chromeDPManager != nil && v.chromeDPManager.IsHeadless() == headless {
+		return nil
+	}
+
+	returnURL := v.currentURL
+	if returnURL == "" {
+		returnURL = v.configuredURL
+	}
+
+	// Carry the cookies over so switching modes doesn't log the user out
+	var cookies []browser.Cookie
+	if v.chromeDPManager != nil {
+		var err error
+		if cookies, err = v.chromeDPManager.ExportCookies(); err != nil {
+			logging.Warn("Relaunching without cookies: %v", err)
+		}
+	}
+
+	browser.CloseGlobalChromeDPManager()
+	v.chromeDPManager = nil
+	v.isConnected = false
+
+	manager, err := browser.GetGlobalChromeDPManager("", v.launchOptions(headless))
+	if err != nil {
+		return err
+	}
+	if len(cookies) > 0 {
+		if err := manager.ImportCookies(cookies); err != nil {
+			logging.Warn("Failed to restore cookies after relaunch: %v", err)
+		}
+	}
+	if returnURL != "" {
+		if err := manager.Navigate(returnURL); err != nil {
+			logging.Warn("Failed to return to %s after relaunch: %v", browser.StripURLCredentials(returnURL), err)
+		}
+	}
+
+	v.chromeDPManager = manager
+	v.installNavigationGuard()
+	v.formHandler = NewFormHandler(manager)
+	v.isConnected = true
+	if v.config != nil {
+		v.config.Browser.Headless = headless
+	}
+
+	mode := "headful"
+	if headless {
+		mode = "headless"
+	}
+	v.addHistory(fmt.Sprintf("🖥️ Relaunched Chrome in %s mode", mode))
+	return nil
+}
+
+// installNavigationGuard keeps the browser on allowed domains when
+// safety.allowed_domains or safety.restrict_domains is configured
+func (v *NavigationView) installNavigationGuard() {
+	if v.config == nil || !v.config.Safety.RestrictsDomains() {
+		return
+	}
+
+	safety := v.config.Safety
+	v.chromeDPManager.SetNavigationGuard(func(rawURL string) error {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return nil
+		}
+		if safety.AllowsHost(parsed.Hostname(), v.configuredURL) {
+			return nil
+		}
+
+		logging.Warn("Blocked navigation to %s: host not in safety.allowed_domains", rawURL)
+		v.addHistory(fmt.Sprintf("🛑 Blocked navigation to %s (not an allowed domain)", parsed.Hostname()))
+		return fmt.Errorf("%w: %s is not in safety.allowed_domains", browser.ErrNavigationBlocked, parsed.Hostname())
+	})
+}
+
+// offerNavigationChoices lists low-confidence ranked elements as numbered
+// choices instead of clicking one; typing the number runs that element
+func (v *NavigationView) offerNavigationChoices(target string, ranked []llm.RankedNavigationElement, clickable []NavigableElement) error {
+	v.pendingChoices = nil
+	var lines []string
+	for _, rankedElem := range ranked {
+		if rankedElem.Confidence <= v.matching.NavSuggestConfidence || len(v.pendingChoices) >= 5 {
+			continue
+		}
+		for _, elem := range clickable {
+			if elem.Text == rankedElem.Text && elem.Selector == rankedElem.Selector {
+				v.pendingChoices = append(v.pendingChoices, elem)
+				lines = append(lines, fmt.Sprintf("  %d. \"%s\" (%.0f%% match)", len(v.pendingChoices), truncateText(elem.Text, 30), rankedElem.Confidence*100))
+				break
+			}
+		}
+	}
+
+	if len(v.pendingChoices) == 0 {
+		return fmt.Errorf("no confident match for \"%s\"", target)
+	}
+
+	v.addHistory(fmt.Sprintf("🤔 Not sure which element is \"%s\" — type a number:", target))
+	for _, line := range lines {
+		v.addHistory(line)
+	}
+	return fmt.Errorf("not confident enough to navigate to \"%s\" — type a number to choose", target)
+}
+
+// recoverFromCrash relaunches Chrome after a crash and returns to the last page
+func (v *NavigationView) recoverFromCrash() tea.Cmd {
+	if v.isRecovering || v.chromeDPManager == nil {
+		return nil
+	}
+	v.isRecovering = true
+	v.isConnected = false
+	v.addHistory("💥 Chrome crashed — reconnecting...")
+
+	return func() tea.Msg {
+		if err := v.chromeDPManager.EnsureAlive(); err != nil {
+			return ChromeErrorMsg{Error: fmt.Errorf("failed to relaunch Chrome: %w", err)}
+		}
+		v.formHandler = NewFormHandler(v.chromeDPManager)
+		v.addHistory("✓ Chrome restarted, reconnected")
+		return ChromeLaunchedMsg{}
+	}
+}
+
+// ensureChrome relaunches Chrome before an operation if it has crashed since
+// the last one, so the operation runs instead of failing with a cancelled
+// context
+func (v *NavigationView) ensureChrome() error {
+	if v.chromeDPManager == nil || !v.chromeDPManager.IsCrashed() {
+		return nil
+	}
+	if err := v.chromeDPManager.EnsureAlive(); err != nil {
+		return err
+	}
+	v.formHandler = NewFormHandler(v.chromeDPManager)
+	v.addHistory("✓ Chrome restarted, reconnected")
+	return nil
+}
+
+// navigationElements returns the page's clickable elements, both as they are
+// and converted for the LLM
+func (v *NavigationView) navigationElements() ([]llm.NavigationElement, []NavigableElement) {
 	var llmElements []llm.NavigationElement
 	var clickableElements []NavigableElement
 	
@@ -1736,6 +3272,11 @@ func (v *NavigationView) navigateToTarget(target string) error {
 			})
 		}
 	}
+	return llmElements, clickableElements
+}
+
+func (v *NavigationView) navigateToTarget(target string) error {
+	llmElements, clickableElements := v.navigationElements()
 
 	if len(llmElements) == 0 {
 		// No clickable elements found, try URL navigation
@@ -1747,13 +3288,20 @@ func (v *NavigationView) navigateToTarget(target string) error {
 
 	// Try LLM ranking if available
 	if v.llmClient != nil {
-		ctx := context.Background()
-		ranking, err := v.llmClient.RankNavigationElements(ctx, target, llmElements)
+		ranking, cached, err := v.rankElements(target, llmElements)
+		if err == nil && !cached {
+			v.trackUsage(ranking.Usage)
+		}
 		
 		if err == nil && len(ranking.Elements) > 0 {
+			// Nothing clears the auto-click bar: offer numbered choices instead
+			if ranking.Elements[0].Confidence <= v.matching.NavMinConfidence {
+				return v.offerNavigationChoices(target, ranking.Elements, clickableElements)
+			}
+
 			// Try elements in ranked order using SmartClick
 			for _, rankedElem := range ranking.Elements {
-				if rankedElem.Confidence > 0.3 { // Only try elements with reasonable confidence
+				if rankedElem.Confidence > v.matching.NavMinConfidence { // Only try elements with reasonable confidence
 					// Find the corresponding NavigableElement
 					var targetElement *NavigableElement
 					for _, elem := range clickableElements {
@@ -1772,6 +3320,9 @@ func (v *NavigationView) navigateToTarget(target string) error {
 							return nil
 						}
 						
+						if isConfirmationRequired(err) {
+							return err
+						}
 						// Log attempt but continue to next option
 						if err != nil {
 							logging.Debug("SmartClick failed for %s: %v", rankedElem.Text, err)
@@ -1785,7 +3336,7 @@ func (v *NavigationView) navigateToTarget(target string) error {
 				topSuggestions := []string{}
 				for i, elem := range ranking.Elements {
 					if i >= 3 { break } // Show top 3
-					if elem.Confidence > 0.1 {
+					if elem.Confidence > v.matching.NavSuggestConfidence {
 						topSuggestions = append(topSuggestions, fmt.Sprintf("\"%s\" (%.0f%% match)", 
 							elem.Text, elem.Confidence*100))
 					}
@@ -1808,6 +3359,10 @@ func (v *NavigationView) trySmartClick(element NavigableElement, rankedElem llm.
 		return false, fmt.Errorf("Chrome not connected")
 	}
 
+	if err := v.guardDestructive(element.Text, firstNonEmpty(element.Selector, element.URL)); err != nil {
+		return false, err
+	}
+
 	// For navigation links, prefer direct URL navigation if available
 	if element.Type == LinkElement && element.URL != "" {
 		if err := v.navigateToURL(element.URL); err == nil {
@@ -1824,7 +3379,7 @@ func (v *NavigationView) fallbackNavigateToTarget(target string, elements []Navi
 	var potentialMatches []NavigableElement
 
 	for _, elem := range elements {
-		score := v.fuzzyMatch(target, elem.Text)
+		score := v.scoreElement(target, elem)
 		if score > 0.3 {
 			potentialMatches = append(potentialMatches, elem)
 		}
@@ -1833,10 +3388,10 @@ func (v *NavigationView) fallbackNavigateToTarget(target string, elements []Navi
 	// If we have potential matches, use the best one
 	if len(potentialMatches) > 0 {
 		bestMatch := potentialMatches[0]
-		bestScore := v.fuzzyMatch(target, bestMatch.Text)
+		bestScore := v.scoreElement(target, bestMatch)
 
 		for _, match := range potentialMatches[1:] {
-			score := v.fuzzyMatch(target, match.Text)
+			score := v.scoreElement(target, match)
 			if score > bestScore {
 				bestMatch = match
 				bestScore = score
@@ -1844,6 +3399,9 @@ func (v *NavigationView) fallbackNavigateToTarget(target string, elements []Navi
 		}
 
 		// Try SmartClick on the best match
+		if err := v.guardDestructive(bestMatch.Text, bestMatch.Selector); err != nil {
+			return err
+		}
 		success, err := v.chromeDPManager.SmartClick(bestMatch.Selector, bestMatch.Text)
 		if success {
 			elementText := truncateText(bestMatch.Text, 30)
@@ -1887,10 +3445,18 @@ func (v *NavigationView) clickTarget(target string) error {
 		return fmt.Errorf("Chrome not connected")
 	}
 
+	// "the 2nd edit" picks among several elements with the same text
+	if ordinal, ok := v.isOrdinalClick(target); ok {
+		return v.clickNth(ordinal)
+	}
+
 	// Find matching clickable element
 	for _, elem := range v.pageElements {
-		if v.fuzzyMatch(target, elem.Text) > 0.5 {
+		if v.scoreElement(target, elem) > 0.5 {
 			if elem.Method == "click" && elem.Selector != "" {
+				if err := v.guardDestructive(elem.Text, elem.Selector); err != nil {
+					return err
+				}
 				if err := v.chromeDPManager.WaitForElement(elem.Selector); err != nil {
 					return fmt.Errorf("element not found: %w", err)
 				}
@@ -1902,6 +3468,23 @@ func (v *NavigationView) clickTarget(target string) error {
 	return fmt.Errorf("no clickable element found matching: %s", target)
 }
 
+// clickNth clicks the element ordinal picks, counting the elements with its
+// text or, without text, those of its kind
+func (v *NavigationView) clickNth(ordinal ordinalTarget) error {
+	if err := v.guardDestructive(ordinal.text, ""); err != nil {
+		return err
+	}
+	clicked, matches, err := v.chromeDPManager.SmartClickNth(ordinal.selector, ordinal.text, ordinal.index)
+	if err != nil {
+		return err
+	}
+	if !clicked {
+		return fmt.Errorf("asked for match %d of %q but only %d found", ordinal.index+1, ordinal.describe(), matches)
+	}
+	v.addHistory(fmt.Sprintf("→ Clicked match %d of %d for \"%s\"", ordinal.index+1, matches, ordinal.describe()))
+	return nil
+}
+
 
 // Additional message types
 type NavigationErrorMsg struct {
@@ -1994,6 +3577,10 @@ func (v *NavigationView) handleModalComplete() (tea.Model, tea.Cmd) {
 	if result.Cancelled {
 		v.awaitingInput = false
 		v.pendingField = nil
+		if len(v.fillQueue) > 0 {
+			v.addHistory(fmt.Sprintf("→ Skipped the remaining %d fields", len(v.fillQueue)))
+			v.fillQueue = nil
+		}
 		return v, nil
 	}
 
@@ -2014,6 +3601,7 @@ func (v *NavigationView) fillFormField(result InputResult) tea.Cmd {
 
 		// Fill the field
 		if err := v.formHandler.FillField(v.pendingField, result.Value); err != nil {
+			v.fillQueue = nil
 			return NavigationErrorMsg{Error: fmt.Errorf("failed to fill field: %w", err)}
 		}
 
@@ -2029,12 +3617,25 @@ func (v *NavigationView) fillFormField(result InputResult) tea.Cmd {
 		}
 
 		// Continue with form filling - add history before clearing state
-		v.addHistory(fmt.Sprintf("→ Filled field: %s", fieldLabel))
+		if fieldType == FileInput {
+			v.addHistory(fmt.Sprintf("→ Uploaded %s: %s", result.Value, fieldLabel))
+		} else {
+			v.addHistory(fmt.Sprintf("→ Filled field: %s", fieldLabel))
+		}
+		v.pushUndo(undoEntry{Kind: undoFill, Selector: v.pendingField.Selector, Label: fieldLabel})
+		v.recordFlowFill(v.pendingField, result.Value)
 
 		// Reset modal state
 		v.awaitingInput = false
 		v.pendingField = nil
 
+		// "fill all" moves on to the next field before submitting
+		if len(v.fillQueue) > 0 {
+			next := v.fillQueue[0]
+			v.fillQueue = v.fillQueue[1:]
+			return v.openFieldModal(next)
+		}
+
 		// Check if we can submit the form now
 		if v.canSubmitForm() {
 			return v.submitForm()()
@@ -2072,8 +3673,15 @@ func (v *NavigationView) submitForm() tea.Cmd {
 		if v.formHandler == nil {
 			return NavigationErrorMsg{Error: fmt.Errorf("form handler not available")}
 		}
+		if v.planOnly() {
+			return v.simulate("submit", "form", "")
+		}
 
+		if err := v.guardSubmit(); err != nil {
+			return errorMsg(err, v.submitForm())
+		}
 		v.addHistory("→ Submitting form...")
+		v.recordFlowSubmit()
 		
 		// Submit the form
 		if err := v.formHandler.SubmitForm(); err != nil {
@@ -2103,7 +3711,7 @@ func (v *NavigationView) submitForm() tea.Cmd {
 
 		if result.ErrorDetected {
 			v.addHistory("❌ " + result.Message)
-			return NavigationErrorMsg{Error: fmt.Errorf(result.Message)}
+			return NavigationErrorMsg{Error: fmt.Errorf("%s", result.Message)}
 		}
 
 		return NavigationCompleteMsg{
@@ -2134,132 +3742,92 @@ func (v *NavigationView) handleFormInput(element NavigableElement) tea.Cmd {
 			return NavigationErrorMsg{Error: fmt.Errorf("form field not found")}
 		}
 
-		// Get saved users for this domain
-		var savedUsers []config.TestUser
-		if v.authConfig != nil {
-			domain := v.formHandler.GetDomain()
-			savedUsers, _ = v.authConfig.GetRecentUsersForDomain(domain, 5)
-		}
+		return v.openFieldModal(field)
+	}
+}
 
-		// Create and show input modal
-		v.inputModal = NewInputModal(field.Type, field.Label, field.Placeholder, v.formHandler.GetDomain(), savedUsers)
-		v.inputModal.Show()
-		v.awaitingInput = true
-		v.pendingField = field
+// openFieldModal shows the input modal for a form field, offering the
+// domain's saved users
+func (v *NavigationView) openFieldModal(field *FormField) tea.Msg {
+	// Get saved users for this domain
+	var savedUsers []config.TestUser
+	if v.authConfig != nil {
+		domain := v.formHandler.GetDomain()
+		savedUsers, _ = v.authConfig.GetRecentUsersForDomain(domain, 5)
+	}
 
-		v.addHistory(fmt.Sprintf("→ Opening input for: %s", field.Label))
-		
-		return FormInputModalReadyMsg{
-			Field: field,
-		}
+	// Create and show input modal
+	v.inputModal = NewInputModal(field.Type, field.Label, field.Placeholder, v.formHandler.GetDomain(), savedUsers)
+	v.inputModal.Show()
+	v.awaitingInput = true
+	v.pendingField = field
+
+	v.addHistory(fmt.Sprintf("→ Opening input for: %s", field.Label))
+
+	return FormInputModalReadyMsg{
+		Field: field,
 	}
 }
 
-// handleMagicLinkSent handles magic link detection and email checking
-func (v *NavigationView) handleMagicLinkSent() tea.Cmd {
-	return func() tea.Msg {
-		// Check if we have email checking capability
-		if v.authFlow == nil {
-			v.addHistory("⚠️ Email checking not configured")
-			return NavigationCompleteMsg{
-				URL:     v.currentURL,
-				Success: true,
-			}
-		}
+// fillAllFields prompts for every field of the detected form in turn
+func (v *NavigationView) fillAllFields() error {
+	if v.currentForm == nil || v.formHandler == nil {
+		return fmt.Errorf("no form detected on this page")
+	}
 
-		// Get the current user's email from the form
-		var userEmail string
-		if v.currentForm != nil && v.currentForm.EmailField != nil {
-			userEmail = v.currentForm.EmailField.Value
+	var fields []*FormField
+	for _, field := range []*FormField{v.currentForm.EmailField, v.currentForm.UsernameField, v.currentForm.PasswordField} {
+		if field != nil {
+			fields = append(fields, field)
 		}
+	}
+	for i := range v.currentForm.OtherFields {
+		fields = append(fields, &v.currentForm.OtherFields[i])
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("the form has no fields to fill")
+	}
 
-		if userEmail == "" {
-			v.addHistory("⚠️ No email address found for magic link checking")
-			return NavigationCompleteMsg{
-				URL:     v.currentURL,
-				Success: true,
-			}
-		}
+	v.addHistory(fmt.Sprintf("→ Filling %d fields", len(fields)))
+	v.fillQueue = fields[1:]
+	v.openFieldModal(fields[0])
+	return nil
+}
 
-		v.addHistory("📧 Checking email for magic link (scanning every 5 seconds)...")
+// promptForInput opens the input modal for a text input so the user can
+// enter the value to fill, masking the entry for password inputs
+func (v *NavigationView) promptForInput(element NavigableElement) tea.Msg {
+	if v.formHandler == nil {
+		return NavigationErrorMsg{Error: fmt.Errorf("form handler not available")}
+	}
 
-		// Create a test user for the magic link authentication
-		testUser := &config.TestUser{
-			Name:     "Magic Link User",
-			Email:    userEmail,
-			AuthType: "magic_link",
-			AuthConfig: &config.TestUserAuthConfig{
-				EmailCheckEnabled: true,
-				EmailTimeout:      30,
-			},
-		}
+	field := &FormField{
+		Type:        TextInput,
+		Selector:    element.Selector,
+		Label:       element.Text,
+		Placeholder: element.Placeholder,
+		IsVisible:   true,
+	}
+	switch element.InputType {
+	case "password":
+		field.Type = PasswordField
+	case "file":
+		field.Type = FileInput
+		field.Placeholder = "path/to/file (separate several with commas)"
+	}
+	if field.Label == "" {
+		field.Label = element.Selector
+	}
 
-		// Create a channel to stop the update goroutine
-		stopUpdates := make(chan struct{})
-		
-		// Start periodic update messages in a goroutine
-		go func() {
-			ticker := time.NewTicker(5 * time.Second)
-			defer ticker.Stop()
-			
-			attempts := 0
-			startTime := time.Now()
-			maxDuration := 2 * time.Minute
-			
-			for {
-				select {
-				case <-stopUpdates:
-					// Stop immediately when magic link is found
-					return
-				case <-ticker.C:
-					attempts++
-					elapsed := time.Since(startTime)
-					if elapsed > maxDuration {
-						return
-					}
-					// Send update message (this will be handled in Update method)
-					v.addHistory(fmt.Sprintf("📧 Still scanning... (attempt %d, %v elapsed)", attempts, elapsed.Round(time.Second)))
-				case <-time.After(maxDuration):
-					return
-				}
-			}
-		}()
-
-		// Use the auth flow manager to authenticate with continuous email support
-		// Check every 5 seconds for up to 2 minutes
-		authResult := v.authFlow.AuthenticateWithContinuousEmailSupport(
-			testUser,
-			5*time.Second,  // Check every 5 seconds
-			2*time.Minute,  // Max timeout of 2 minutes
-		)
-
-		// Stop the update goroutine as soon as we get a result
-		close(stopUpdates)
-
-		if authResult.Success && authResult.RedirectURL != "" {
-			v.addHistory(fmt.Sprintf("✅ Found magic link: %s", authResult.RedirectURL[:50]+"..."))
-			v.addHistory("🔗 Following magic link...")
-			
-			// Navigate to the magic link
-			if err := v.chromeDPManager.Navigate(authResult.RedirectURL); err != nil {
-				v.addHistory(fmt.Sprintf("❌ Failed to navigate to magic link: %v", err))
-				return NavigationErrorMsg{Error: err}
-			}
+	v.inputModal = NewInputModal(field.Type, field.Label, field.Placeholder, v.formHandler.GetDomain(), nil)
+	v.inputModal.Show()
+	v.awaitingInput = true
+	v.pendingField = field
 
-			// Save this user for future use
-			if v.authConfig != nil {
-				domain := v.formHandler.GetDomain()
-				v.authConfig.SaveMagicLinkUserForDomain(domain, userEmail, testUser.Name)
-			}
+	v.addHistory(fmt.Sprintf("→ Opening input for: %s", field.Label))
 
-			v.addHistory("🎉 Successfully authenticated with magic link")
-			return NavigationCompleteMsg{
-				URL:     authResult.RedirectURL,
-				Success: true,
-			}
-		} else {
-			v.addHistory(fmt.Sprintf("❌ Magic link not found: %s", authResult.Message))
-			return NavigationErrorMsg{Error: authResult.Error}
-		}
+	return FormInputModalReadyMsg{
+		Field: field,
 	}
 }
+
@@ -0,0 +1,95 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/lance13c/tod/internal/config"
+)
+
+func TestParseOrdinalTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		ok     bool
+		want   ordinalTarget
+	}{
+		{"the 2nd edit", true, ordinalTarget{index: 1, text: "edit"}},
+		{"3rd delete", true, ordinalTarget{index: 2, text: "delete"}},
+		{"second button", true, ordinalTarget{index: 1, kind: "button", selector: ordinalElementTypes["button"]}},
+		{"the first delete link", true, ordinalTarget{index: 0, text: "delete", kind: "link", selector: ordinalElementTypes["link"]}},
+		{"edit", false, ordinalTarget{}},
+		{"sign in", false, ordinalTarget{}},
+	}
+	for _, tt := range tests {
+		got, ok := parseOrdinalTarget(tt.target)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseOrdinalTarget(%q) = %+v, %v, want %+v, %v", tt.target, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOrdinalClickYieldsToMatchingText(t *testing.T) {
+	v := &NavigationView{
+		weights: config.DefaultMatchingConfig().Weights,
+		pageElements: []NavigableElement{
+			{Type: FormFieldElement, Text: "First name"},
+			{Type: FormFieldElement, Text: "Second address line"},
+			{Type: ButtonElement, Text: "Edit"},
+			{Type: ButtonElement, Text: "Edit"},
+		},
+	}
+
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"first name", false},
+		{"the first name", false},
+		{"second address line", false},
+		{"second edit", true},
+		{"the 2nd edit", true},
+		{"first button", true},
+	}
+	for _, tt := range tests {
+		if _, got := v.isOrdinalClick(tt.target); got != tt.want {
+			t.Errorf("isOrdinalClick(%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Sign\n  In", "Sign In"},
+		{"  Add\tto   cart  ", "Add to cart"},
+		{"Check\u200bout", "Checkout"},
+		{"\ufeffLog\u00a0out\u2060", "Log out"},
+		{"\n\t ", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeText(tt.in); got != tt.want {
+			t.Errorf("normalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyMatchMessyButtonText(t *testing.T) {
+	v := &NavigationView{weights: config.DefaultMatchingConfig().Weights}
+
+	tests := []struct {
+		input string
+		text  string
+	}{
+		{"sign in", "Sign\n        In"},
+		{"add to cart", "\n  Add   to\tcart\n"},
+		{"checkout", "Check\u200bout"},
+		{"log out", "Log\u00a0out"},
+		{"  Sign   In ", "sign in"},
+	}
+	for _, tt := range tests {
+		if got := v.fuzzyMatch(tt.input, tt.text); got != v.weights.ExactMatch {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want an exact match (%v)", tt.input, tt.text, got, v.weights.ExactMatch)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// clickCapturingNetwork clicks selector and returns the requests the click
+// triggered, leaving out static assets
+func (v *NavigationView) clickCapturingNetwork(selector string) ([]browser.NetworkRequest, error) {
+	return v.chromeDPManager.CaptureNetworkDuring(func() error {
+		return v.chromeDPManager.Click(selector)
+	})
+}
+
+// reportClick adds a history line for a click and the requests it
+// triggered, e.g. → Clicked "Submit", triggered POST /api/login (200).
+// With several requests each gets its own line.
+func (v *NavigationView) reportClick(elementText string, requests []browser.NetworkRequest) {
+	switch len(requests) {
+	case 0:
+		v.addHistory(fmt.Sprintf("→ Clicked \"%s\"", elementText))
+	case 1:
+		v.addHistory(fmt.Sprintf("→ Clicked \"%s\", triggered %s", elementText, describeRequest(requests[0])))
+	default:
+		v.addHistory(fmt.Sprintf("→ Clicked \"%s\", triggered %d requests:", elementText, len(requests)))
+		for _, request := range requests {
+			v.addHistory("   " + describeRequest(request))
+		}
+	}
+}
+
+// describeRequest summarizes a request and its outcome, e.g. "POST /api/login (200)"
+func describeRequest(request browser.NetworkRequest) string {
+	outcome := fmt.Sprint(request.Status)
+	switch {
+	case request.Failed != "":
+		outcome = request.Failed
+	case request.Status == 0:
+		outcome = "no response"
+	}
+	return fmt.Sprintf("%s %s (%s)", request.Method, truncateText(request.Path(), 60), outcome)
+}
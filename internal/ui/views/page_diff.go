@@ -0,0 +1,145 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lance13c/tod/internal/browser"
+	"github.com/lance13c/tod/internal/logging"
+	"github.com/lance13c/tod/internal/testing"
+)
+
+var (
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+)
+
+// snapshotBeforeAction remembers the page HTML so "diff" can show what the
+// next action changed
+func (v *NavigationView) snapshotBeforeAction() {
+	html, err := v.chromeDPManager.GetPageHTML()
+	if err != nil {
+		logging.Debug("Failed to snapshot page before action: %v", err)
+		return
+	}
+	v.beforeActionHTML = simplifyForDiff(html)
+}
+
+// simplifyForDiff strips the HTML down to what matters to a reader, keeping
+// the raw HTML if it can't be simplified
+func simplifyForDiff(html string) string {
+	simplified, err := browser.SimplifyHTML(html)
+	if err != nil {
+		return html
+	}
+	return simplified
+}
+
+// maxDiffHTMLSize caps the simplified HTML diffed on each side, since the
+// diff is quadratic in the lines that changed
+const maxDiffHTMLSize = 512 * 1024
+
+// diffSinceAction diffs html against the snapshot from before the last
+// action, refusing pages too large to diff quickly
+func (v *NavigationView) diffSinceAction(html string) ([]browser.DiffLine, error) {
+	after := simplifyForDiff(html)
+	if len(v.beforeActionHTML) > maxDiffHTMLSize || len(after) > maxDiffHTMLSize {
+		return nil, fmt.Errorf("page is too large to diff (over %d KB)", maxDiffHTMLSize/1024)
+	}
+	return browser.NewHTMLDiffer().Diff(v.beforeActionHTML, after), nil
+}
+
+// expectedResultKey identifies an element on a page in expectedResults
+func expectedResultKey(pageURL, selector string) string {
+	pageURL, _, _ = strings.Cut(pageURL, "#")
+	return pageURL + "\x00" + selector
+}
+
+// recordExpectedResult notes what acting on element did to the page since
+// snapshotBeforeAction, so a capture of the page can give generated tests
+// something to assert
+func (v *NavigationView) recordExpectedResult(element NavigableElement, afterURL string) {
+	if element.Selector == "" || v.beforeActionHTML == "" {
+		return
+	}
+	html, err := v.chromeDPManager.GetPageHTML()
+	if err != nil {
+		logging.Debug("Failed to read page after action: %v", err)
+		return
+	}
+
+	diff, err := v.diffSinceAction(html)
+	if err != nil {
+		logging.Debug("Not recording expected result: %v", err)
+		return
+	}
+	result := testing.DescribeExpectedResult(v.currentURL, afterURL, diff)
+	if result == "" {
+		return
+	}
+	if v.expectedResults == nil {
+		v.expectedResults = make(map[string]string)
+	}
+	v.expectedResults[expectedResultKey(v.currentURL, element.Selector)] = result
+}
+
+// ShowLastDiff shows the DOM sections added and removed since before the last
+// action, whether or not the URL changed
+func (v *NavigationView) ShowLastDiff() error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+	if v.beforeActionHTML == "" {
+		return fmt.Errorf("no action performed yet")
+	}
+
+	html, err := v.chromeDPManager.GetPageHTML()
+	if err != nil {
+		return fmt.Errorf("failed to get page HTML: %w", err)
+	}
+
+	diff, err := v.diffSinceAction(html)
+	if err != nil {
+		return err
+	}
+	if len(diff) == 0 {
+		v.diffLines = nil
+		v.addHistory("≡ No changes since the last action")
+		return nil
+	}
+
+	v.diffLines = make([]string, 0, len(diff))
+	for _, line := range diff {
+		if line.Added {
+			v.diffLines = append(v.diffLines, "+ "+line.Text)
+		} else {
+			v.diffLines = append(v.diffLines, "- "+line.Text)
+		}
+	}
+	v.addHistory(fmt.Sprintf("± %d lines changed since the last action", len(v.diffLines)))
+	return nil
+}
+
+// renderDiff renders the last diff to fit the viewport, truncating it with a
+// footer when it's too long
+func (v *NavigationView) renderDiff() string {
+	lines := []string{v.subtitleStyle.Render(fmt.Sprintf("Changes since the last action — %s to close", v.keys.Clear.Help().Key))}
+
+	limit := max(v.maxVisibleSuggestions-2, 3)
+	width := max(v.width-4, 20)
+	for i, line := range v.diffLines {
+		if i == limit {
+			lines = append(lines, v.subtitleStyle.Render(fmt.Sprintf("... %d more lines", len(v.diffLines)-limit)))
+			break
+		}
+		line = truncateText(line, width)
+		if strings.HasPrefix(line, "+") {
+			line = diffAddedStyle.Render(line)
+		} else {
+			line = diffRemovedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
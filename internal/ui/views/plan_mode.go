@@ -0,0 +1,20 @@
+package views
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// planOnly reports whether actions should be simulated instead of executed,
+// as they are with --plan-only and --dry-run. Dry run also has the browser
+// skip actions, in case one reaches it without passing this check.
+func (v *NavigationView) planOnly() bool {
+	return v.config != nil && (v.config.PlanOnly || v.config.DryRun)
+}
+
+// simulate records an action plan-only mode skipped and returns the message
+// reporting it. Nothing is sent to the browser.
+func (v *NavigationView) simulate(verb, target, selector string) tea.Msg {
+	msg := PlannedActionMsg{Verb: verb, Target: target}
+	v.recordExecutedStep(verb, target, selector, msg)
+	return msg
+}
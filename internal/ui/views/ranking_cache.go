@@ -0,0 +1,131 @@
+package views
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lance13c/tod/internal/llm"
+)
+
+const (
+	// rankingCacheTTL is how long an LLM ranking is reused
+	rankingCacheTTL = 5 * time.Minute
+
+	// rankingCacheSize bounds the cached rankings; the oldest goes first
+	rankingCacheSize = 64
+)
+
+// rankingCacheEntry is a cached LLM ranking
+type rankingCacheEntry struct {
+	ranking *llm.NavigationRanking
+	expires time.Time
+}
+
+// rankingCache remembers LLM element rankings so repeating a command on an
+// unchanged page doesn't call the LLM again. Rankings are keyed by the target
+// and the page's elements, and dropped when the page's elements change.
+type rankingCache struct {
+	mu      sync.Mutex
+	pageKey string // elements of the page the cached rankings are for
+	entries map[string]rankingCacheEntry
+	order   []string // keys, oldest first
+}
+
+// newRankingCache creates an empty ranking cache
+func newRankingCache() *rankingCache {
+	return &rankingCache{entries: make(map[string]rankingCacheEntry)}
+}
+
+// elementsKey hashes the page's elements, ignoring their order
+func elementsKey(elements []llm.NavigationElement) string {
+	keys := make([]string, len(elements))
+	for i, e := range elements {
+		keys[i] = e.Selector + "\x00" + e.Text + "\x00" + e.URL
+	}
+	sort.Strings(keys)
+
+	hash := sha256.Sum256([]byte(strings.Join(keys, "\x01")))
+	return hex.EncodeToString(hash[:])
+}
+
+// rankingKey is the cache key of a target on a page
+func rankingKey(target, pageKey string) string {
+	return strings.ToLower(strings.Join(strings.Fields(target), " ")) + "\x00" + pageKey
+}
+
+// get returns the cached ranking of target among elements, if it hasn't expired
+func (c *rankingCache) get(target string, elements []llm.NavigationElement) (*llm.NavigationRanking, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := rankingKey(target, elementsKey(elements))
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.ranking, true
+}
+
+// put caches the ranking of target among elements
+func (c *rankingCache) put(target string, elements []llm.NavigationElement, ranking *llm.NavigationRanking) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := rankingKey(target, elementsKey(elements))
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = rankingCacheEntry{ranking: ranking, expires: time.Now().Add(rankingCacheTTL)}
+
+	for len(c.order) > rankingCacheSize {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// pageChanged drops the cached rankings unless elements are the ones they
+// were made for
+func (c *rankingCache) pageChanged(elements []llm.NavigationElement) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pageKey := elementsKey(elements)
+	if pageKey == c.pageKey {
+		return
+	}
+	c.pageKey = pageKey
+	c.clearLocked()
+}
+
+// clear drops every cached ranking
+func (c *rankingCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clearLocked()
+}
+
+func (c *rankingCache) clearLocked() {
+	c.entries = make(map[string]rankingCacheEntry)
+	c.order = nil
+}
+
+// rankElements ranks elements for target with the LLM, reusing the ranking
+// from an earlier identical request on the same page. cached reports whether
+// the LLM was skipped.
+func (v *NavigationView) rankElements(target string, elements []llm.NavigationElement) (ranking *llm.NavigationRanking, cached bool, err error) {
+	if ranking, ok := v.rankings.get(target, elements); ok {
+		return ranking, true, nil
+	}
+
+	ranking, err = v.llmClient.RankNavigationElements(context.Background(), target, elements)
+	if err != nil {
+		return nil, false, err
+	}
+	v.rankings.put(target, elements, ranking)
+	return ranking, false, nil
+}
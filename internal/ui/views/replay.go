@@ -0,0 +1,201 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/config"
+)
+
+// Replay step statuses
+const (
+	ReplayPassed  = "passed"
+	ReplayFailed  = "failed"
+	ReplaySkipped = "skipped"
+)
+
+// ReplayResult is the outcome of replaying one step of a saved session
+type ReplayResult struct {
+	Step   ExecutedStep
+	Status string // ReplayPassed, ReplayFailed or ReplaySkipped
+	Detail string // why the step failed or was skipped
+}
+
+// ReplayOptions controls how Replay runs a saved session
+type ReplayOptions struct {
+	ContinueOnFailure bool                         // keep going after a failed step
+	Skip              func(int, ExecutedStep) bool // steps to skip besides skippable ones, by 1-based number
+	OnResult          func(int, ReplayResult)      // called as each step finishes
+}
+
+// LoadSavedSession reads a session saved by SaveSession, by ID or path
+func LoadSavedSession(idOrPath string) (*SavedSession, error) {
+	path := sessionPath(idOrPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var session SavedSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session %s: %w", path, err)
+	}
+	return &session, nil
+}
+
+// Replay connects to Chrome and re-issues the steps of a saved session through
+// the same path typed input takes, checking each lands where it did when it
+// was recorded. Steps that failed when recorded and skippable steps, like
+// waiting for a magic link, are skipped. Replay stops at the first failure
+// unless opts.ContinueOnFailure is set.
+func (v *NavigationView) Replay(session *SavedSession, opts ReplayOptions) ([]ReplayResult, error) {
+	var switchedEnv *config.EnvConfig
+	if session.Environment != "" && v.config != nil && session.Environment != v.config.Current {
+		env, exists := v.config.Envs[session.Environment]
+		if !exists {
+			return nil, fmt.Errorf("session was recorded against unknown environment %q", session.Environment)
+		}
+		v.config.Current = session.Environment
+		v.configuredURL = env.HomeURL()
+		switchedEnv = &env
+	}
+
+	// Steps were confirmed when they were recorded
+	v.destructive.setEnabled(false)
+
+	switch msg := v.connectToChrome()().(type) {
+	case ChromeErrorMsg:
+		return nil, fmt.Errorf("failed to connect to Chrome: %w", msg.Error)
+	default:
+		// Chrome may already be running with the previous environment's setup
+		if switchedEnv != nil {
+			v.applyEnvironment(session.Environment, switchedEnv)
+		}
+		v.applyReplayMsg(msg)
+	}
+
+	var results []ReplayResult
+	for i, step := range session.Actions {
+		result := v.replayStep(i+1, step, opts)
+		results = append(results, result)
+		if opts.OnResult != nil {
+			opts.OnResult(i+1, result)
+		}
+		if result.Status == ReplayFailed && !opts.ContinueOnFailure {
+			break
+		}
+	}
+	return results, nil
+}
+
+// replayStep re-issues a single recorded step and judges its outcome
+func (v *NavigationView) replayStep(number int, step ExecutedStep, opts ReplayOptions) ReplayResult {
+	result := ReplayResult{Step: step}
+	switch {
+	case !step.Success:
+		result.Status, result.Detail = ReplaySkipped, "failed when recorded"
+		return result
+	case step.Skippable:
+		result.Status, result.Detail = ReplaySkipped, "marked skippable"
+		return result
+	case opts.Skip != nil && opts.Skip(number, step):
+		result.Status, result.Detail = ReplaySkipped, "skipped by request"
+		return result
+	}
+
+	var cmd tea.Cmd
+	if step.Verb == "switch_env" {
+		cmd = v.switchEnvironment(step.Target)
+	} else {
+		// Secrets are recorded as ${VAR} references, see recordedInput
+		target, missing := expandEnvReferences(step.Target)
+		if len(missing) > 0 {
+			result.Status, result.Detail = ReplayFailed, fmt.Sprintf("needs $%s set", strings.Join(missing, ", $"))
+			return result
+		}
+		cmd = v.executeInputValue(target)
+	}
+	if cmd == nil {
+		result.Status, result.Detail = ReplayFailed, "nothing to run"
+		return result
+	}
+
+	msg := cmd()
+	v.applyReplayMsg(msg)
+
+	// Only results known to mean the step worked pass
+	result.Status = ReplayFailed
+	switch m := msg.(type) {
+	case NavigationErrorMsg:
+		result.Detail = fmt.Sprint(m.Error)
+	case ChromeErrorMsg:
+		result.Detail = fmt.Sprint(m.Error)
+	case AnswerFailedMsg:
+		result.Detail = fmt.Sprint(m.Error)
+	case AuthenticationCompleteMsg:
+		switch {
+		case m.Success:
+			result.Status = ReplayPassed
+		case m.Error != nil:
+			result.Detail = fmt.Sprintf("authentication failed: %v", m.Error)
+		default:
+			result.Detail = "authentication failed"
+		}
+	case ConfirmDestructiveMsg:
+		result.Detail = fmt.Sprintf("stopped to confirm %s", describeConfirmation(m.Text, m.Selector))
+	case NavigationCompleteMsg:
+		switch {
+		case m.Error != nil:
+			result.Detail = m.Error.Error()
+		case step.URL != "" && !samePage(m.URL, step.URL):
+			result.Detail = fmt.Sprintf("landed on %s, expected %s", m.URL, step.URL)
+		default:
+			result.Status = ReplayPassed
+		}
+	case WaitResultMsg:
+		if m.Appeared {
+			result.Status = ReplayPassed
+		} else {
+			result.Detail = fmt.Sprintf("%s didn't appear within %v", m.Selector, m.Timeout)
+		}
+	case PlannedActionMsg:
+		result.Status, result.Detail = ReplaySkipped, "simulated in plan-only mode"
+	case CommandCompleteMsg, ChromeLaunchedMsg, PageAnalysisCompleteMsg,
+		AnswerStreamMsg, CaptureStreamMsg, CrawlStreamMsg, InspectStreamMsg, MagicLinkWaitMsg:
+		result.Status = ReplayPassed
+	case nil:
+		result.Detail = "no result"
+	default:
+		result.Detail = fmt.Sprintf("unexpected result %T", msg)
+	}
+	return result
+}
+
+// applyReplayMsg feeds msg through Update as the TUI would and refreshes the
+// page elements the next step is matched against. Commands Update returns,
+// like spinners and timers, aren't run.
+func (v *NavigationView) applyReplayMsg(msg tea.Msg) {
+	v.Update(msg)
+	switch m := msg.(type) {
+	case ChromeLaunchedMsg, NavigationCompleteMsg, CommandCompleteMsg:
+		if nav, ok := m.(NavigationCompleteMsg); ok && nav.Error != nil {
+			return
+		}
+		v.Update(v.analyzeCurrentPage()())
+	}
+}
+
+// samePage reports whether two URLs point to the same page, ignoring the
+// query and fragment, which often carry per-run tokens
+func samePage(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(ua.Host, ub.Host) && strings.TrimSuffix(ua.Path, "/") == strings.TrimSuffix(ub.Path, "/")
+}
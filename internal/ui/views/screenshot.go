@@ -0,0 +1,88 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxScreenshotTitle caps how much of the page title goes in a screenshot's
+// default file name
+const maxScreenshotTitle = 50
+
+// nonFileNameChars are runs of characters left out of a title-based file name
+var nonFileNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// screenshotRequest is a parsed "screenshot" command
+type screenshotRequest struct {
+	Selector string // element to screenshot, the whole page when empty
+	FileName string // name under .tod/screenshots, generated when empty
+}
+
+// parseScreenshot parses the arguments of "screenshot [filename]" and
+// "screenshot of <selector> [as <filename>]"
+func parseScreenshot(args string) screenshotRequest {
+	args = strings.TrimSpace(args)
+	lower := strings.ToLower(args)
+	if !strings.HasPrefix(lower, "of ") {
+		return screenshotRequest{FileName: args}
+	}
+
+	args = strings.TrimSpace(args[len("of "):])
+	var request screenshotRequest
+	if i := strings.LastIndex(strings.ToLower(args), " as "); i >= 0 {
+		request.FileName = strings.TrimSpace(args[i+len(" as "):])
+		args = args[:i]
+	}
+	request.Selector = strings.TrimSpace(args)
+	return request
+}
+
+// takeScreenshot saves a PNG of the page, or of one element, to
+// .tod/screenshots and adds its path to the history
+func (v *NavigationView) takeScreenshot(args string) error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+
+	request := parseScreenshot(args)
+	var data []byte
+	var err error
+	if request.Selector != "" {
+		data, err = v.chromeDPManager.ScreenshotElement(request.Selector)
+	} else {
+		data, err = v.chromeDPManager.Screenshot()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to take screenshot: %w", err)
+	}
+
+	name := screenshotFileName(request.FileName, v.currentTitle, time.Now())
+	path, err := writeScreenshot(name, data)
+	if err != nil {
+		return err
+	}
+	v.addHistory(fmt.Sprintf("📷 Saved screenshot to %s", path))
+	return nil
+}
+
+// screenshotFileName returns the file name to save a screenshot under: the
+// requested name without any directory and with a .png extension, or the
+// page title and a timestamp like captures use, e.g.
+// "sign-in-20250101-120000.png"
+func screenshotFileName(requested, title string, now time.Time) string {
+	if requested = filepath.Base(strings.TrimSpace(requested)); requested != "." && requested != string(filepath.Separator) {
+		return strings.TrimSuffix(requested, filepath.Ext(requested)) + ".png"
+	}
+
+	slug := strings.Trim(nonFileNameChars.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxScreenshotTitle {
+		slug = strings.TrimRight(slug[:maxScreenshotTitle], "-")
+	}
+	if slug == "" {
+		slug = "page"
+	}
+	return fmt.Sprintf("%s-%s.png", slug, now.Format("20060102-150405"))
+}
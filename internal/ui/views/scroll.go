@@ -0,0 +1,96 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// maxScrollSearches bounds how many times "scroll to" scrolls an
+// infinite-scroll page looking for an element that hasn't loaded yet
+const maxScrollSearches = 5
+
+// scrollTo scrolls to the top or bottom of the page, or to the element best
+// matching target. An element not on the page yet is searched for by
+// scrolling to the bottom while more content keeps loading.
+func (v *NavigationView) scrollTo(target string) error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+
+	switch strings.ToLower(target) {
+	case "top":
+		if err := v.chromeDPManager.ScrollToTop(); err != nil {
+			return err
+		}
+		v.addHistory("↑ Scrolled to the top")
+		return nil
+	case "bottom", "end":
+		loaded, err := v.chromeDPManager.ScrollToBottom()
+		if err != nil {
+			return err
+		}
+		if loaded {
+			v.addHistory("↓ Scrolled to the bottom and more content loaded")
+		} else {
+			v.addHistory("↓ Scrolled to the bottom")
+		}
+		return nil
+	}
+
+	if elem := v.findScrollTarget(target, v.pageElements); elem != nil {
+		return v.scrollToElement(*elem)
+	}
+
+	for i := 0; i < maxScrollSearches; i++ {
+		loaded, err := v.chromeDPManager.ScrollToBottom()
+		if err != nil {
+			return err
+		}
+		if !loaded {
+			break
+		}
+
+		elements, err := v.chromeDPManager.ExtractInteractiveElements()
+		if err != nil {
+			return err
+		}
+		if elem := v.findScrollTarget(target, scrollCandidates(elements)); elem != nil {
+			return v.scrollToElement(*elem)
+		}
+	}
+	return fmt.Errorf("no element matching \"%s\" found, even after scrolling", target)
+}
+
+// findScrollTarget returns the element best matching target, or nil
+func (v *NavigationView) findScrollTarget(target string, elements []NavigableElement) *NavigableElement {
+	var best *NavigableElement
+	bestScore := 0.3
+	for i := range elements {
+		if score := v.fuzzyMatch(target, elements[i].Text); score > bestScore {
+			bestScore = score
+			best = &elements[i]
+		}
+	}
+	return best
+}
+
+// scrollCandidates turns freshly extracted elements into ones to match
+// against, before the page has been re-analyzed
+func scrollCandidates(elements []browser.InteractiveElement) []NavigableElement {
+	candidates := make([]NavigableElement, 0, len(elements))
+	for _, e := range elements {
+		candidates = append(candidates, NavigableElement{Text: e.Text, Selector: e.Selector})
+	}
+	return candidates
+}
+
+// scrollToElement scrolls an element into view
+func (v *NavigationView) scrollToElement(elem NavigableElement) error {
+	if err := v.chromeDPManager.ScrollIntoView(elem.Selector); err != nil {
+		return err
+	}
+	v.addHistory(fmt.Sprintf("↕ Scrolled to \"%s\"", truncateText(elem.Text, 40)))
+	return nil
+}
@@ -0,0 +1,270 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/database"
+	"github.com/lance13c/tod/internal/llm"
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// sessionIDFormat formats a session's start time as its ID
+const sessionIDFormat = "20060102-150405"
+
+// HistoryEntry is a message shown in the history section
+type HistoryEntry struct {
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+	URL       string    `json:"url,omitempty"` // page the browser was on
+}
+
+// SavedSession is the record written when a session is saved
+type SavedSession struct {
+	ID          string         `json:"id"`
+	Environment string         `json:"environment"`
+	StartTime   time.Time      `json:"start_time"`
+	EndTime     time.Time      `json:"end_time"`
+	CurrentURL  string         `json:"current_url"`
+	History     []HistoryEntry `json:"history"`
+	Actions     []ExecutedStep `json:"actions"`
+	TotalTokens int64          `json:"total_tokens"`
+	TotalCost   float64        `json:"total_cost"`
+
+	// FlowsSaved counts the flows saved with "save flow"
+	FlowsSaved int `json:"flows_saved,omitempty"`
+}
+
+// trackUsage adds the usage of an LLM call to the session totals
+func (v *NavigationView) trackUsage(usage *llm.UsageStats) {
+	// The offline fallback reports made-up usage
+	if usage == nil || v.aiOffline {
+		return
+	}
+	v.totalTokens += usage.TotalTokens
+	v.totalCost += usage.TotalCost
+
+	// Accumulate per provider and model until the usage is persisted
+	key := usage.Provider + "/" + usage.Model
+	if v.unsavedUsage == nil {
+		v.unsavedUsage = make(map[string]*database.UsageRecord)
+	}
+	record, ok := v.unsavedUsage[key]
+	if !ok {
+		record = &database.UsageRecord{Provider: usage.Provider, Model: usage.Model}
+		v.unsavedUsage[key] = record
+	}
+	record.InputTokens += usage.InputTokens
+	record.OutputTokens += usage.OutputTokens
+	record.TotalTokens += usage.TotalTokens
+	record.Cost += usage.TotalCost
+	record.RequestCount++
+}
+
+// loadCumulativeCost reads the project's spend from previous sessions
+func loadCumulativeCost() float64 {
+	db, err := database.New(database.ProjectPath("."))
+	if err != nil {
+		logging.Debug("Usage history unavailable: %v", err)
+		return 0
+	}
+	defer db.Close()
+
+	total, err := db.GetTotalUsage()
+	if err != nil {
+		logging.Debug("Usage history unavailable: %v", err)
+		return 0
+	}
+	return total.Cost
+}
+
+// saveUsage writes the usage accumulated since the last save to the project
+// database so spend can be tracked across sessions
+func (v *NavigationView) saveUsage() {
+	if len(v.unsavedUsage) == 0 {
+		return
+	}
+
+	db, err := database.New(database.ProjectPath("."))
+	if err != nil {
+		logging.Warn("Failed to open usage database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	now := time.Now()
+	for key, record := range v.unsavedUsage {
+		record.StartedAt = v.sessionStart
+		record.EndedAt = now
+		if _, err := db.SaveUsageRecord(record); err != nil {
+			logging.Warn("Failed to save usage for %s: %v", key, err)
+			continue
+		}
+		v.previousCost += record.Cost
+		delete(v.unsavedUsage, key)
+	}
+}
+
+// sessionTimer fires a SessionTimeoutMsg once session.max_duration has passed
+func (v *NavigationView) sessionTimer() tea.Cmd {
+	if v.config == nil || v.config.Session.MaxDuration <= 0 {
+		return nil
+	}
+	return tea.Tick(v.config.Session.MaxDuration, func(time.Time) tea.Msg {
+		return SessionTimeoutMsg{}
+	})
+}
+
+// sessionPath returns where the session with the given ID is saved. A path
+// to a .json file is returned as is.
+func sessionPath(id string) string {
+	if strings.HasSuffix(id, ".json") {
+		return id
+	}
+	return filepath.Join(".tod", "sessions", fmt.Sprintf("session-%s.json", strings.TrimPrefix(id, "session-")))
+}
+
+// saveSession writes the session to .tod/sessions and returns the path of
+// the saved file. Saving again overwrites the same file.
+func (v *NavigationView) saveSession() (string, error) {
+	path := sessionPath(v.sessionID)
+	if err := v.SaveSession(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// SaveSession writes the session's history, actions and usage to path as JSON
+func (v *NavigationView) SaveSession(path string) error {
+	session := SavedSession{
+		ID:          v.sessionID,
+		StartTime:   v.sessionStart,
+		EndTime:     time.Now(),
+		CurrentURL:  v.currentURL,
+		History:     v.history,
+		Actions:     v.executedActions,
+		TotalTokens: v.totalTokens,
+		TotalCost:   v.totalCost,
+
+		FlowsSaved: v.flowsSaved,
+	}
+	if v.config != nil {
+		session.Environment = v.config.Current
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSession restores a session saved by SaveSession, merging it into the
+// current one: its history and actions come before the current ones, its
+// usage is added to the totals and it started when the earlier of the two
+// did. Saving afterwards updates the loaded session.
+func (v *NavigationView) LoadSession(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var session SavedSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return fmt.Errorf("failed to parse session %s: %w", path, err)
+	}
+
+	if session.ID != "" {
+		// Its totals are already in the current ones
+		if session.ID == v.sessionID {
+			return fmt.Errorf("session %s is already loaded", session.ID)
+		}
+		v.sessionID = session.ID
+	}
+	if !session.StartTime.IsZero() && (v.sessionStart.IsZero() || session.StartTime.Before(v.sessionStart)) {
+		v.sessionStart = session.StartTime
+	}
+	v.executedActions = append(append([]ExecutedStep{}, session.Actions...), v.executedActions...)
+	v.totalTokens += session.TotalTokens
+	v.totalCost += session.TotalCost
+	v.flowsSaved += session.FlowsSaved
+	v.currentURL = session.CurrentURL
+
+	history := append(append([]HistoryEntry{}, session.History...), v.history...)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Timestamp.Before(history[j].Timestamp)
+	})
+	v.history = nil
+	for _, entry := range history {
+		v.appendHistory(entry)
+	}
+
+	return nil
+}
+
+// resumeSession loads a saved session by ID and returns to the page it was on
+func (v *NavigationView) resumeSession(id string) error {
+	path := sessionPath(id)
+	if err := v.LoadSession(path); err != nil {
+		return err
+	}
+	v.addHistory(fmt.Sprintf("📂 Resumed session %s from %s", v.sessionID, path))
+
+	if v.currentURL == "" || v.chromeDPManager == nil {
+		return nil
+	}
+	return v.navigateToURL(v.currentURL)
+}
+
+// handleSessionTimeout saves the session, closes Chrome and quits
+func (v *NavigationView) handleSessionTimeout() tea.Cmd {
+	logging.Info("Session reached max duration of %v, shutting down", v.config.Session.MaxDuration)
+
+	lines := []string{
+		fmt.Sprintf("⏰ Session reached its maximum duration of %v", v.config.Session.MaxDuration),
+		fmt.Sprintf("   Actions executed: %d", len(v.executedActions)),
+		fmt.Sprintf("   Flows saved: %d", v.flowsSaved),
+		fmt.Sprintf("   LLM usage: %d tokens, %s", v.totalTokens, llm.FormatCost(v.totalCost)),
+	}
+
+	if path, err := v.saveSession(); err != nil {
+		logging.Warn("Failed to save session: %v", err)
+		lines = append(lines, fmt.Sprintf("   ⚠️  Session not saved: %v", err))
+	} else {
+		lines = append(lines, fmt.Sprintf("   Session saved to %s", path))
+	}
+
+	v.exitSummary = strings.Join(lines, "\n")
+	v.cleanup()
+	return tea.Quit
+}
+
+// ExitSummary returns the summary to print after the TUI exits, if the
+// session ended on its own
+func (v *NavigationView) ExitSummary() string {
+	return v.exitSummary
+}
+
+// unsavedCost returns the cost of usage not yet written to the database
+func (v *NavigationView) unsavedCost() float64 {
+	var cost float64
+	for _, record := range v.unsavedUsage {
+		cost += record.Cost
+	}
+	return cost
+}
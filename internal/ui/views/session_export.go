@@ -0,0 +1,104 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// sessionExportVersion is bumped whenever the exported schema changes in a
+// way readers need to know about
+const sessionExportVersion = 1
+
+// elementTypeNames names element types in exported sessions
+var elementTypeNames = map[ElementType]string{
+	LinkElement:      "link",
+	ButtonElement:    "button",
+	FormElement:      "form",
+	ActionElement:    "action",
+	FormFieldElement: "form_field",
+}
+
+// SessionExport is the portable JSON transcript of a session
+type SessionExport struct {
+	SchemaVersion     int              `json:"schema_version"`
+	ExportedAt        time.Time        `json:"exported_at"`
+	SessionID         string           `json:"session_id"`
+	Environment       string           `json:"environment,omitempty"`
+	CurrentURL        string           `json:"current_url"`
+	Messages          []HistoryEntry   `json:"messages"`
+	DiscoveredActions []ExportedAction `json:"discovered_actions"`
+	TotalTokens       int64            `json:"total_tokens"`
+	TotalCost         float64          `json:"total_cost"`
+}
+
+// ExportedAction is an action discovered on the current page
+type ExportedAction struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Method   string `json:"method,omitempty"`
+	Selector string `json:"selector,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// ExportSessionJSON serializes the session's messages, discovered actions and
+// usage as a versioned JSON transcript
+func (v *NavigationView) ExportSessionJSON() ([]byte, error) {
+	export := SessionExport{
+		SchemaVersion:     sessionExportVersion,
+		ExportedAt:        time.Now(),
+		SessionID:         v.sessionID,
+		CurrentURL:        v.currentURL,
+		Messages:          v.history,
+		DiscoveredActions: make([]ExportedAction, 0, len(v.pageElements)),
+		TotalTokens:       v.totalTokens,
+		TotalCost:         v.totalCost,
+	}
+	if export.Messages == nil {
+		export.Messages = []HistoryEntry{}
+	}
+	if v.config != nil {
+		export.Environment = v.config.Current
+	}
+
+	for _, elem := range v.pageElements {
+		export.DiscoveredActions = append(export.DiscoveredActions, ExportedAction{
+			Type:     elementTypeNames[elem.Type],
+			Text:     elem.Text,
+			Method:   elem.Method,
+			Selector: elem.Selector,
+			URL:      elem.URL,
+			Disabled: elem.Disabled,
+		})
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session: %w", err)
+	}
+	return data, nil
+}
+
+// exportSession writes the JSON transcript to .tod/sessions
+func (v *NavigationView) exportSession() error {
+	data, err := v.ExportSessionJSON()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(".tod", "sessions")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("chat-%s.json", time.Now().Format(sessionIDFormat)))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session export: %w", err)
+	}
+
+	v.addHistory(fmt.Sprintf("📤 Session exported to %s", path))
+	return nil
+}
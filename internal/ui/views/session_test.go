@@ -0,0 +1,76 @@
+package views
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSessionRoundTripKeepsTimestamps(t *testing.T) {
+	start := time.Date(2025, 3, 14, 9, 26, 53, 589793000, time.FixedZone("CET", 3600))
+	saved := &NavigationView{
+		sessionID:    start.Format(sessionIDFormat),
+		sessionStart: start,
+		maxHistory:   100,
+		currentURL:   "http://localhost:3000/dashboard",
+		totalTokens:  120,
+		totalCost:    0.25,
+		flowsSaved:   2,
+		executedActions: []ExecutedStep{
+			{Verb: "click", Target: "Sign in", Success: true, Timestamp: start.Add(time.Second)},
+		},
+	}
+	saved.appendHistory(HistoryEntry{Message: "→ Clicked Sign in", Timestamp: start.Add(time.Second)})
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := saved.SaveSession(path); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	now := start.Add(time.Hour)
+	loaded := &NavigationView{
+		sessionID:    now.Format(sessionIDFormat),
+		sessionStart: now,
+		maxHistory:   100,
+		totalTokens:  30,
+		totalCost:    0.05,
+		flowsSaved:   1,
+		executedActions: []ExecutedStep{
+			{Verb: "navigate", Target: "/settings", Success: true, Timestamp: now.Add(time.Second)},
+		},
+	}
+	loaded.appendHistory(HistoryEntry{Message: "→ Opened settings", Timestamp: now.Add(time.Second)})
+
+	if err := loaded.LoadSession(path); err != nil {
+		t.Fatalf("LoadSession failed: %v", err)
+	}
+
+	if !loaded.sessionStart.Equal(start) {
+		t.Errorf("sessionStart = %v, want the saved session's %v", loaded.sessionStart, start)
+	}
+	if loaded.sessionID != saved.sessionID {
+		t.Errorf("sessionID = %q, want %q", loaded.sessionID, saved.sessionID)
+	}
+	if loaded.totalTokens != 150 || loaded.totalCost < 0.2999 || loaded.totalCost > 0.3001 {
+		t.Errorf("totals = %d tokens, %v, want 150 tokens, 0.30", loaded.totalTokens, loaded.totalCost)
+	}
+	if loaded.flowsSaved != 3 {
+		t.Errorf("flowsSaved = %d, want 3", loaded.flowsSaved)
+	}
+	if len(loaded.executedActions) != 2 || loaded.executedActions[0].Target != "Sign in" {
+		t.Fatalf("executedActions = %+v, want the saved action before the current one", loaded.executedActions)
+	}
+	if got := loaded.executedActions[0].Timestamp; !got.Equal(start.Add(time.Second)) {
+		t.Errorf("action timestamp = %v, want %v", got, start.Add(time.Second))
+	}
+	if len(loaded.history) != 2 || loaded.history[0].Message != "→ Clicked Sign in" {
+		t.Fatalf("history = %+v, want the saved entry before the current one", loaded.history)
+	}
+	if got := loaded.history[0].Timestamp; !got.Equal(start.Add(time.Second)) {
+		t.Errorf("history timestamp = %v, want %v", got, start.Add(time.Second))
+	}
+
+	if err := loaded.LoadSession(path); err == nil {
+		t.Error("loading the same session twice succeeded, counting its usage twice")
+	}
+}
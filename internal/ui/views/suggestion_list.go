@@ -0,0 +1,300 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Layout of the suggestion list. Subtitles get a column of their own, and
+// text and subtitles wrap within their columns rather than being cut off.
+const (
+	suggestionPrefixWidth   = 3   // "├─ "
+	suggestionColumnGap     = 5   // between the text and subtitle columns
+	suggestionSubtitleWidth = 40  // subtitle column
+	suggestionMinTextWidth  = 20  // text column on narrow terminals
+	suggestionDefaultWidth  = 160 // terminal width until the first resize
+)
+
+// suggestionIndicatorLines are the lines kept for the "more above" and
+// "more below" indicators
+const suggestionIndicatorLines = 2
+
+// suggestionLayout is the width of the text and subtitle columns
+type suggestionLayout struct {
+	text     int
+	subtitle int
+}
+
+// suggestionLayout fits the text column to the longest suggestion, up to
+// what the terminal leaves beside the subtitles
+func (v *NavigationView) suggestionLayout() suggestionLayout {
+	width := v.width
+	if width <= 0 {
+		width = suggestionDefaultWidth
+	}
+	width -= v.suggestionStyle.GetPaddingLeft()
+
+	longest := 0
+	for _, suggestion := range v.suggestions {
+		if suggestion.Type != SectionHeaderSuggestion {
+			longest = max(longest, lipgloss.Width(suggestion.Text))
+		}
+	}
+	text := max(suggestionMinTextWidth, width-suggestionPrefixWidth-suggestionColumnGap-suggestionSubtitleWidth)
+	return suggestionLayout{text: max(1, min(longest, text)), subtitle: suggestionSubtitleWidth}
+}
+
+// renderSuggestion renders one suggestion as one or more lines
+func (v *NavigationView) renderSuggestion(index int, layout suggestionLayout) []string {
+	suggestion := v.suggestions[index]
+	if suggestion.Type == SectionHeaderSuggestion {
+		header := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("208")). // Orange color for headers
+			PaddingTop(1).
+			Render("▶ " + suggestion.Text)
+		return strings.Split(header, "\n")
+	}
+
+	style, prefix := v.suggestionStyle, "├─ "
+	if index == v.selectedIndex {
+		style, prefix = v.selectedStyle, "┌─ "
+	}
+
+	subtitle := suggestion.Subtitle
+	if suggestion.Type == CommandSuggestion {
+		subtitle += " ⌘"
+	}
+	texts := wrapText(suggestion.Text, layout.text)
+	var subtitles []string
+	if subtitle = strings.TrimSpace(subtitle); subtitle != "" {
+		subtitles = wrapText(subtitle, layout.subtitle)
+	}
+
+	lines := make([]string, max(len(texts), len(subtitles)))
+	for i := range lines {
+		var text string
+		if i < len(texts) {
+			text = texts[i]
+		}
+		line := prefix + text
+		if i < len(subtitles) {
+			line += strings.Repeat(" ", layout.text-lipgloss.Width(text)+suggestionColumnGap) + subtitles[i]
+		}
+		lines[i] = style.Render(line)
+		prefix = "│  "
+	}
+	return lines
+}
+
+// suggestionLineBudget is how many lines the suggestions themselves may use
+func (v *NavigationView) suggestionLineBudget() int {
+	return max(3, v.maxVisibleSuggestions-suggestionIndicatorLines)
+}
+
+// maxSuggestionOffset is the scroll offset that shows the last suggestion at
+// the bottom of the list
+func (v *NavigationView) maxSuggestionOffset() int {
+	layout := v.suggestionLayout()
+	budget := v.suggestionLineBudget()
+	used := 0
+	for i := len(v.suggestions) - 1; i >= 0; i-- {
+		used += len(v.renderSuggestion(i, layout))
+		if used > budget {
+			return min(i+1, len(v.suggestions)-1)
+		}
+	}
+	return 0
+}
+
+// scrollToShowSelected scrolls the list just far enough to show the
+// selected suggestion in full
+func (v *NavigationView) scrollToShowSelected() {
+	if v.selectedIndex < 0 || v.selectedIndex >= len(v.suggestions) {
+		return
+	}
+	if v.selectedIndex < v.suggestionScrollOffset {
+		v.suggestionScrollOffset = v.selectedIndex
+		return
+	}
+
+	layout := v.suggestionLayout()
+	budget := v.suggestionLineBudget()
+	heights := make([]int, 0, v.selectedIndex-v.suggestionScrollOffset+1)
+	used := 0
+	for i := v.suggestionScrollOffset; i <= v.selectedIndex; i++ {
+		height := len(v.renderSuggestion(i, layout))
+		heights = append(heights, height)
+		used += height
+	}
+	for used > budget && v.suggestionScrollOffset < v.selectedIndex {
+		used -= heights[0]
+		heights = heights[1:]
+		v.suggestionScrollOffset++
+	}
+}
+
+// pageSuggestions moves the selection a page up or down without wrapping
+// around, keeping the selection in view
+func (v *NavigationView) pageSuggestions(direction int) {
+	if len(v.suggestions) == 0 {
+		return
+	}
+	target := v.selectedIndex + direction*max(1, v.visibleSuggestionCount)
+	target = max(0, min(target, len(v.suggestions)-1))
+	for target >= 0 && target < len(v.suggestions) && v.suggestions[target].Type == SectionHeaderSuggestion {
+		target += direction
+	}
+	if target < 0 || target >= len(v.suggestions) {
+		// Ran past the end on a header: settle on the nearest item back
+		target = v.findNextSelectableIndex(max(0, min(target, len(v.suggestions)-1)), -direction)
+	}
+	if target < 0 {
+		return
+	}
+
+	v.selectedIndex = target
+	v.scrollToShowSelected()
+	if suggestion := v.suggestions[target]; suggestion.Type != SectionHeaderSuggestion {
+		v.input.SetValue(suggestion.Text)
+		v.input.CursorEnd()
+	}
+}
+
+// suggestionPosition reports the selected suggestion as "N of M", counting
+// only selectable suggestions
+func (v *NavigationView) suggestionPosition() string {
+	position, total := 0, 0
+	for i, suggestion := range v.suggestions {
+		if suggestion.Type == SectionHeaderSuggestion {
+			continue
+		}
+		total++
+		if i == v.selectedIndex {
+			position = total
+		}
+	}
+	if position == 0 {
+		return fmt.Sprintf("%d items", total)
+	}
+	return fmt.Sprintf("%d of %d", position, total)
+}
+
+// renderSuggestionsViewport renders the suggestions that fit from the scroll
+// offset, with indicators for those above and below
+func (v *NavigationView) renderSuggestionsViewport() string {
+	if len(v.suggestions) == 0 {
+		return v.subtitleStyle.Render("[Analyzing page for navigation options...]")
+	}
+
+	layout := v.suggestionLayout()
+	budget := v.suggestionLineBudget()
+	start := max(0, min(v.suggestionScrollOffset, len(v.suggestions)-1))
+	v.suggestionScrollOffset = start
+
+	var body []string
+	end := start
+	for ; end < len(v.suggestions); end++ {
+		item := v.renderSuggestion(end, layout)
+		if len(body) > 0 && len(body)+len(item) > budget {
+			break
+		}
+		body = append(body, item...)
+	}
+	v.visibleSuggestionCount = end - start
+
+	var lines []string
+	if start > 0 {
+		lines = append(lines, v.subtitleStyle.Render(fmt.Sprintf("▲ %d more above", start)))
+	}
+	lines = append(lines, body...)
+	if start > 0 || end < len(v.suggestions) {
+		footer := v.suggestionPosition()
+		if end < len(v.suggestions) {
+			footer = fmt.Sprintf("▼ %d more below · %s", len(v.suggestions)-end, footer)
+		}
+		lines = append(lines, v.subtitleStyle.Render(footer))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapText breaks text into lines of at most width cells at spaces, breaking
+// words longer than a line
+func wrapText(text string, width int) []string {
+	if width <= 0 || lipgloss.Width(text) <= width {
+		return []string{text}
+	}
+
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	flush := func() {
+		lines = append(lines, line.String())
+		line.Reset()
+		lineWidth = 0
+	}
+	for _, word := range strings.Fields(text) {
+		wordWidth := lipgloss.Width(word)
+		if lineWidth > 0 && lineWidth+1+wordWidth > width {
+			flush()
+		}
+		if lineWidth > 0 {
+			line.WriteByte(' ')
+			lineWidth++
+		}
+		for wordWidth > width-lineWidth {
+			// Split a word too long for a line of its own
+			runes := []rune(word)
+			cut := 0
+			for cellWidth := 0; cut < len(runes); cut++ {
+				w := lipgloss.Width(string(runes[cut]))
+				if lineWidth+cellWidth+w > width {
+					break
+				}
+				cellWidth += w
+			}
+			if cut == 0 && lineWidth > 0 {
+				flush()
+				continue
+			}
+			cut = max(cut, 1)
+			line.WriteString(string(runes[:cut]))
+			flush()
+			word = string(runes[cut:])
+			wordWidth = lipgloss.Width(word)
+		}
+		line.WriteString(word)
+		lineWidth += wordWidth
+	}
+	if lineWidth > 0 {
+		flush()
+	}
+	return lines
+}
+
+// regenerateSuggestions rebuilds the suggestions, as generateSuggestions
+// does, but keeps the selected suggestion and its place in the list if it is
+// still offered
+func (v *NavigationView) regenerateSuggestions() {
+	var selected *Suggestion
+	if v.selectedIndex >= 0 && v.selectedIndex < len(v.suggestions) {
+		s := v.suggestions[v.selectedIndex]
+		selected = &s
+	}
+	rowOnScreen := v.selectedIndex - v.suggestionScrollOffset
+
+	v.generateSuggestions()
+	if selected == nil {
+		return
+	}
+	for i, suggestion := range v.suggestions {
+		if suggestion.Type == selected.Type && suggestion.Text == selected.Text {
+			v.selectedIndex = i
+			v.suggestionScrollOffset = max(0, i-max(0, rowOnScreen))
+			v.scrollToShowSelected()
+			return
+		}
+	}
+}
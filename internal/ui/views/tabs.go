@@ -0,0 +1,100 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/lance13c/tod/internal/logging"
+)
+
+// reportNewTabs notes tabs the last action opened, e.g. a target="_blank"
+// link, so they can be followed with "switch to new tab"
+func (v *NavigationView) reportNewTabs() {
+	opened, err := v.chromeDPManager.NewTargets()
+	if err != nil {
+		logging.Debug("Failed to check for new tabs: %v", err)
+		return
+	}
+	for _, t := range opened {
+		v.newTabID = t.ID
+		v.addHistory(fmt.Sprintf("🗂️  Opened a new tab \"%s\" — type \"switch to new tab\" to follow it", truncateText(tabLabel(t.Title, t.URL), 40)))
+	}
+}
+
+// tabLabel names a tab by its title, or its URL while it has none
+func tabLabel(title, url string) string {
+	if title != "" {
+		return title
+	}
+	return url
+}
+
+// switchToTab makes the tab with the given ID the one actions run against
+func (v *NavigationView) switchToTab(id string) error {
+	if err := v.chromeDPManager.SwitchToTarget(id); err != nil {
+		return err
+	}
+	if id == v.newTabID {
+		v.newTabID = ""
+	}
+
+	url, title, err := v.chromeDPManager.GetPageInfo()
+	if err != nil {
+		return fmt.Errorf("switched tab but failed to read its page: %w", err)
+	}
+	v.currentURL = url
+	v.addHistory(fmt.Sprintf("🗂️  Switched to tab \"%s\"", truncateText(tabLabel(title, url), 40)))
+	return nil
+}
+
+// switchToNewTab follows the tab most recently opened by an action
+func (v *NavigationView) switchToNewTab() error {
+	if v.newTabID == "" {
+		return fmt.Errorf("no new tab has been opened")
+	}
+	return v.switchToTab(v.newTabID)
+}
+
+// switchToTabNumber switches to a tab by its 1-based position in "list tabs"
+func (v *NavigationView) switchToTabNumber(n int) error {
+	tabs, err := v.chromeDPManager.ListTargets()
+	if err != nil {
+		return err
+	}
+	if n < 1 || n > len(tabs) {
+		return fmt.Errorf("no tab %d (there are %d)", n, len(tabs))
+	}
+	return v.switchToTab(tabs[n-1].ID)
+}
+
+// listTabs shows the open tabs in the history
+func (v *NavigationView) listTabs() error {
+	tabs, err := v.chromeDPManager.ListTargets()
+	if err != nil {
+		return err
+	}
+
+	v.addHistory(fmt.Sprintf("🗂️  %d open tabs:", len(tabs)))
+	for i, t := range tabs {
+		marker := ""
+		if t.Active {
+			marker = " (active)"
+		}
+		v.addHistory(fmt.Sprintf("   %d. %s%s", i+1, truncateText(tabLabel(t.Title, t.URL), 50), marker))
+	}
+	return nil
+}
+
+// closeTab closes the active tab and returns to the original one
+func (v *NavigationView) closeTab() error {
+	if err := v.chromeDPManager.CloseTarget(v.chromeDPManager.ActiveTargetID()); err != nil {
+		return err
+	}
+
+	url, _, err := v.chromeDPManager.GetPageInfo()
+	if err != nil {
+		return fmt.Errorf("closed tab but failed to read the original one: %w", err)
+	}
+	v.currentURL = url
+	v.addHistory("🗂️  Closed the tab and returned to the original one")
+	return nil
+}
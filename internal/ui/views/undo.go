@@ -0,0 +1,75 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// maxUndo caps how many actions "undo" can step back through
+const maxUndo = 20
+
+// Kinds of undoable action
+const (
+	undoNavigation = "navigation"
+	undoFill       = "fill"
+)
+
+// undoEntry is an action "undo" can reverse
+type undoEntry struct {
+	Kind     string // undoNavigation or undoFill
+	URL      string // page the navigation left
+	Selector string // field that was filled
+	Label    string // field label, for messages
+}
+
+// pushUndo records an undoable action, dropping the oldest once maxUndo is
+// reached
+func (v *NavigationView) pushUndo(entry undoEntry) {
+	v.undoStack = append(v.undoStack, entry)
+	if len(v.undoStack) > maxUndo {
+		v.undoStack = v.undoStack[1:]
+	}
+}
+
+// undo reverses the last navigation, by going back in browser history, or
+// the last form fill, by clearing the field
+func (v *NavigationView) undo() error {
+	if len(v.undoStack) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("browser not connected")
+	}
+
+	entry := v.undoStack[len(v.undoStack)-1]
+	switch entry.Kind {
+	case undoNavigation:
+		if err := v.chromeDPManager.Back(); err != nil {
+			if errors.Is(err, browser.ErrNoPreviousPage) {
+				v.undoStack = nil
+				return fmt.Errorf("nothing to undo: no previous page in browser history")
+			}
+			return err
+		}
+		url, _, err := v.chromeDPManager.GetPageInfo()
+		if err != nil {
+			url = entry.URL
+		}
+		v.currentURL = url
+		v.addHistory(fmt.Sprintf("↩️ Undid navigation, back on %s", url))
+
+	case undoFill:
+		if v.formHandler == nil {
+			return fmt.Errorf("form handler not available")
+		}
+		if err := v.formHandler.clearField(entry.Selector); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", entry.Label, err)
+		}
+		v.addHistory(fmt.Sprintf("↩️ Undid fill, cleared %s", entry.Label))
+	}
+
+	v.undoStack = v.undoStack[:len(v.undoStack)-1]
+	return nil
+}
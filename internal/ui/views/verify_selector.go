@@ -0,0 +1,44 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// verifyPreviewCount is how many matches "verify" shows the text of
+const verifyPreviewCount = 5
+
+// verifySelector reports how many elements a CSS selector or XPath matches on
+// the live page, with the text of the first few
+func (v *NavigationView) verifySelector(selector string) error {
+	if v.chromeDPManager == nil {
+		return fmt.Errorf("Chrome not connected")
+	}
+
+	matches, err := v.chromeDPManager.MatchSelector(selector, verifyPreviewCount)
+	if err != nil {
+		return err
+	}
+
+	kind := "CSS"
+	if browser.IsXPath(selector) {
+		kind = "XPath"
+	}
+	switch matches.Count {
+	case 0:
+		v.addHistory(fmt.Sprintf("🔎 %s %s matches no elements", kind, selector))
+		return nil
+	case 1:
+		v.addHistory(fmt.Sprintf("🔎 %s %s matches 1 element:", kind, selector))
+	default:
+		v.addHistory(fmt.Sprintf("🔎 %s %s matches %d elements:", kind, selector, matches.Count))
+	}
+	for i, text := range matches.Texts {
+		v.addHistory(fmt.Sprintf("   %d. %s", i+1, truncateText(text, 60)))
+	}
+	if more := matches.Count - len(matches.Texts); more > 0 {
+		v.addHistory(fmt.Sprintf("   ... %d more", more))
+	}
+	return nil
+}
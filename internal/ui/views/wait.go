@@ -0,0 +1,117 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lance13c/tod/internal/browser"
+)
+
+// waitRequest is a parsed "wait <duration>" or "wait for <selector>" input
+type waitRequest struct {
+	Duration time.Duration // how long to sleep, or the timeout of a selector wait
+	Selector string        // element to wait for; empty to just sleep
+}
+
+// parseWait parses "wait <duration>", "wait for <selector>" and "wait for
+// <selector> <timeout>". Durations are milliseconds, or Go durations like
+// "2s". A selector wait without a timeout uses browser.timeouts.element.
+func parseWait(input string) (waitRequest, bool) {
+	lower := strings.ToLower(input)
+	if strings.HasPrefix(lower, "wait for ") {
+		fields := strings.Fields(input[len("wait for "):])
+		if len(fields) == 0 {
+			return waitRequest{}, false
+		}
+		var request waitRequest
+		if len(fields) > 1 {
+			if d, ok := parseWaitDuration(fields[len(fields)-1]); ok {
+				request.Duration = d
+				fields = fields[:len(fields)-1]
+			}
+		}
+		request.Selector = strings.Join(fields, " ")
+		return request, true
+	}
+	if strings.HasPrefix(lower, "wait ") {
+		if d, ok := parseWaitDuration(strings.TrimSpace(input[len("wait "):])); ok {
+			return waitRequest{Duration: d}, true
+		}
+	}
+	return waitRequest{}, false
+}
+
+// parseWaitDuration parses milliseconds, like "500", or a Go duration
+func parseWaitDuration(s string) (time.Duration, bool) {
+	if ms, err := strconv.Atoi(s); err == nil {
+		return time.Duration(ms) * time.Millisecond, ms > 0
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil && d > 0
+}
+
+// wait sleeps or waits for an element to appear, recording the wait as a step
+// so replayed sessions keep their timing
+func (v *NavigationView) wait(input string, request waitRequest) tea.Cmd {
+	return func() tea.Msg {
+		if v.planOnly() {
+			return v.simulate("wait", input, request.Selector)
+		}
+		msg := v.runWait(request)
+		v.recordExecutedStep("wait", input, request.Selector, msg)
+		return msg
+	}
+}
+
+func (v *NavigationView) runWait(request waitRequest) tea.Msg {
+	if request.Selector == "" {
+		time.Sleep(request.Duration)
+		return WaitResultMsg{Appeared: true, Waited: request.Duration}
+	}
+
+	if v.chromeDPManager == nil {
+		return NavigationErrorMsg{Error: fmt.Errorf("Chrome not connected")}
+	}
+	timeout := request.Duration
+	if timeout == 0 {
+		timeout = v.chromeDPManager.Timeouts().Element
+	}
+
+	condition := fmt.Sprintf("document.querySelector(%s) !== null", browser.QuoteJS(request.Selector))
+	if browser.IsXPath(request.Selector) {
+		condition = fmt.Sprintf("document.evaluate(%s, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue !== null", browser.QuoteJS(request.Selector))
+	}
+
+	start := time.Now()
+	_, err := v.chromeDPManager.WaitForCondition(condition, timeout)
+	msg := WaitResultMsg{Selector: request.Selector, Timeout: timeout, Waited: time.Since(start)}
+	switch {
+	case err == nil:
+		msg.Appeared = true
+	case errors.Is(err, browser.ErrConditionTimeout):
+	default:
+		return NavigationErrorMsg{Error: err}
+	}
+	return msg
+}
+
+// handleWaitResult reports how a wait ended. An element that appeared may
+// have brought new actions with it, so the page is analyzed again.
+func (v *NavigationView) handleWaitResult(msg WaitResultMsg) tea.Cmd {
+	v.isProcessing = false
+	switch {
+	case msg.Selector == "":
+		v.addHistory(fmt.Sprintf("⏱️ Waited %v", msg.Waited))
+		return nil
+	case msg.Appeared:
+		v.addHistory(fmt.Sprintf("⏱️ %s appeared after %v", msg.Selector, msg.Waited.Round(time.Millisecond)))
+		return v.analyzeCurrentPage()
+	default:
+		v.addHistory(fmt.Sprintf("⌛ Timed out after %v waiting for %s", msg.Timeout, msg.Selector))
+		return nil
+	}
+}
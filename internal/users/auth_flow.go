@@ -1,6 +1,7 @@
 package users
 
 import (
+	stdcontext "context"
 	"fmt"
 	"time"
 
@@ -33,6 +34,7 @@ func NewAuthFlowManager(projectDir string, llmClient llm.Client) (*AuthFlowManag
 	var extractor *email.ExtractorService
 	if emailClient != nil {
 		extractor = email.NewExtractorService(llmClient)
+		extractor.SetLinkRules(email.LoadLinkRules(projectDir))
 	}
 
 	return &AuthFlowManager{
@@ -51,7 +53,7 @@ func (a *AuthFlowManager) AuthenticateWithEmailSupport(user *config.TestUser) *A
 	
 	// If email is configured and user has email, enhance with email checking
 	if a.emailClient != nil && user.Email != "" && a.needsEmailCheck(user.AuthType) {
-		fmt.Printf("📧 Email checking enabled for %s (%s)\n", user.Name, user.Email)
+		logging.Info("Email checking enabled for %s (%s)", user.Name, user.Email)
 		
 		// Perform email-enhanced authentication
 		emailResult := a.performEmailAuthentication(user, result)
@@ -89,7 +91,7 @@ func (a *AuthFlowManager) performEmailAuthentication(user *config.TestUser, base
 
 // handleMagicLinkAuth handles magic link authentication with email checking
 func (a *AuthFlowManager) handleMagicLinkAuth(user *config.TestUser, baseResult *AuthenticationResult) *AuthenticationResult {
-	fmt.Printf("🔗 Waiting for magic link email for %s...\n", user.Email)
+	logging.Info("Waiting for magic link email for %s...", user.Email)
 	
 	// Wait for magic link email
 	context := fmt.Sprintf("User '%s' just clicked 'Send Magic Link' button. Looking for magic link email.", user.Name)
@@ -110,7 +112,7 @@ func (a *AuthFlowManager) handleMagicLinkAuth(user *config.TestUser, baseResult
 		return result
 	}
 	
-	fmt.Printf("✅ Found magic link: %s\n", extractResult.Value)
+	logging.Info("Found magic link: %s", extractResult.Value)
 	
 	// Update the result with magic link
 	enhancedResult := *baseResult
@@ -129,21 +131,30 @@ func (a *AuthFlowManager) handleMagicLinkAuth(user *config.TestUser, baseResult
 }
 
 // handleMagicLinkAuthContinuous handles magic link authentication with continuous email checking
-func (a *AuthFlowManager) handleMagicLinkAuthContinuous(user *config.TestUser, baseResult *AuthenticationResult, checkInterval time.Duration, maxTimeout time.Duration) *AuthenticationResult {
-	logging.Info("🔗 Starting continuous scan for magic link email for %s (checking every %v)...", user.Email, checkInterval)
+func (a *AuthFlowManager) handleMagicLinkAuthContinuous(ctx stdcontext.Context, user *config.TestUser, baseResult *AuthenticationResult, checkInterval time.Duration, maxTimeout time.Duration, progress func(attempt int, elapsed time.Duration)) *AuthenticationResult {
+	logging.Info("Starting continuous scan for magic link email for %s (checking every %v)...", user.Email, checkInterval)
 	
 	// Wait for magic link email with continuous scanning
 	context := fmt.Sprintf("User '%s' just clicked 'Send Magic Link' button. Looking for magic link email.", user.Name)
 	
-	extractResult, err := a.extractor.WaitForAuthEmailContinuous(
+	extractResult, err := a.extractor.WaitForAuthEmailContext(
+		ctx,
 		a.emailClient,
 		email.AuthTypeMagicLink,
 		context,
 		checkInterval,
 		maxTimeout,
+		progress,
 	)
 	
-	if err != nil || !extractResult.Success {
+	if err != nil {
+		return &AuthenticationResult{
+			Success: false,
+			Message: "Magic link wait cancelled",
+			Error:   err,
+		}
+	}
+	if !extractResult.Success {
 		result := &AuthenticationResult{
 			Success: false,
 			Message: fmt.Sprintf("Magic link email not found after %v: %s", maxTimeout, extractResult.Error),
@@ -152,7 +163,7 @@ func (a *AuthFlowManager) handleMagicLinkAuthContinuous(user *config.TestUser, b
 		return result
 	}
 	
-	logging.Info("✅ Found magic link: %s", extractResult.Value)
+	logging.Info("Found magic link: %s", extractResult.Value)
 	
 	// Update the result with magic link
 	enhancedResult := *baseResult
@@ -172,6 +183,12 @@ func (a *AuthFlowManager) handleMagicLinkAuthContinuous(user *config.TestUser, b
 
 // AuthenticateWithContinuousEmailSupport performs authentication with continuous email scanning
 func (a *AuthFlowManager) AuthenticateWithContinuousEmailSupport(user *config.TestUser, checkInterval time.Duration, maxTimeout time.Duration) *AuthenticationResult {
+	return a.AuthenticateWithContinuousEmailSupportContext(stdcontext.Background(), user, checkInterval, maxTimeout, nil)
+}
+
+// AuthenticateWithContinuousEmailSupportContext is AuthenticateWithContinuousEmailSupport
+// stopping when ctx is done, with progress called before each email check
+func (a *AuthFlowManager) AuthenticateWithContinuousEmailSupportContext(ctx stdcontext.Context, user *config.TestUser, checkInterval time.Duration, maxTimeout time.Duration, progress func(attempt int, elapsed time.Duration)) *AuthenticationResult {
 	// Start with basic authentication simulation
 	result := a.sessionManager.SimulateAuthentication(user)
 	
@@ -186,11 +203,11 @@ func (a *AuthFlowManager) AuthenticateWithContinuousEmailSupport(user *config.Te
 	
 	// If email is configured and user has email, enhance with continuous email checking
 	if user.Email != "" && a.needsEmailCheck(user.AuthType) {
-		logging.Info("📧 Continuous email checking enabled for %s (%s)", user.Name, user.Email)
+		logging.Info("Continuous email checking enabled for %s (%s)", user.Name, user.Email)
 		
 		// Perform continuous email-enhanced authentication
 		if user.AuthType == "magic_link" {
-			return a.handleMagicLinkAuthContinuous(user, result, checkInterval, maxTimeout)
+			return a.handleMagicLinkAuthContinuous(ctx, user, result, checkInterval, maxTimeout, progress)
 		}
 		// Fall back to regular handling for other auth types
 		emailResult := a.performEmailAuthentication(user, result)
@@ -204,7 +221,7 @@ func (a *AuthFlowManager) AuthenticateWithContinuousEmailSupport(user *config.Te
 
 // handleEmailVerification handles email verification code authentication
 func (a *AuthFlowManager) handleEmailVerification(user *config.TestUser, baseResult *AuthenticationResult) *AuthenticationResult {
-	fmt.Printf("📨 Waiting for verification code email for %s...\n", user.Email)
+	logging.Info("Waiting for verification code email for %s...", user.Email)
 	
 	context := fmt.Sprintf("User '%s' just requested an email verification code. Looking for verification code email.", user.Name)
 	
@@ -224,7 +241,7 @@ func (a *AuthFlowManager) handleEmailVerification(user *config.TestUser, baseRes
 		return result
 	}
 	
-	fmt.Printf("✅ Found verification code: %s\n", extractResult.Value)
+	logging.Info("Found verification code: %s", extractResult.Value)
 	
 	// Update the result with verification code
 	enhancedResult := *baseResult
@@ -243,7 +260,7 @@ func (a *AuthFlowManager) handleEmailVerification(user *config.TestUser, baseRes
 
 // handle2FAAuth handles 2FA/SMS code authentication
 func (a *AuthFlowManager) handle2FAAuth(user *config.TestUser, baseResult *AuthenticationResult) *AuthenticationResult {
-	fmt.Printf("🔐 Waiting for 2FA code email for %s...\n", user.Email)
+	logging.Info("Waiting for 2FA code email for %s...", user.Email)
 	
 	context := fmt.Sprintf("User '%s' just triggered 2FA authentication. Looking for 2FA code email or SMS forwarded to email.", user.Name)
 	
@@ -263,7 +280,7 @@ func (a *AuthFlowManager) handle2FAAuth(user *config.TestUser, baseResult *Authe
 		return result
 	}
 	
-	fmt.Printf("✅ Found 2FA code: %s\n", extractResult.Value)
+	logging.Info("Found 2FA code: %s", extractResult.Value)
 	
 	// Update the result with 2FA code
 	enhancedResult := *baseResult
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/lance13c/tod/internal/config"
+	"github.com/lance13c/tod/internal/logging"
 )
 
 // SessionManager handles user session management and authentication state
@@ -178,7 +179,7 @@ func (sm *SessionManager) CleanupExpiredSessions() error {
 	}
 
 	if cleaned > 0 {
-		fmt.Printf("🧹 Cleaned up %d expired sessions\n", cleaned)
+		logging.Info("Cleaned up %d expired sessions", cleaned)
 	}
 
 	return nil